@@ -2,12 +2,14 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/republicprotocol/republic-go/contract"
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/order"
 )
 
 type Config struct {
@@ -15,13 +17,24 @@ type Config struct {
 	Ethereum contract.Config `json:"ethereum"` // TODO: Darknode package should not be dependent on blockchain/ethereum
 	Logs     logger.Options  `json:"logs"`
 
-	Address                 identity.Address        `json:"address"`
-	OracleAddress           identity.Address        `json:"oracleAddress"`
-	BootstrapMultiAddresses identity.MultiAddresses `json:"bootstrapMultiAddresses"`
-	SentryDSN               string                  `json:"sentry,omitempty"`
-	Host                    string                  `json:"host"`
-	Port                    string                  `json:"port"`
-	Alpha                   int                     `json:"alpha"`
+	Address                  identity.Address        `json:"address"`
+	OracleAddress            identity.Address        `json:"oracleAddress"`
+	BootstrapMultiAddresses  identity.MultiAddresses `json:"bootstrapMultiAddresses"`
+	SentryDSN                string                  `json:"sentry,omitempty"`
+	Host                     string                  `json:"host"`
+	Port                     string                  `json:"port"`
+	Alpha                    int                     `json:"alpha"`
+	FaultTolerance           int64                   `json:"faultTolerance"`
+	JoinBatchWindowMs        int64                   `json:"joinBatchWindowMs"`
+	AllowUnverifiedJoins     bool                    `json:"allowUnverifiedJoins"` // Deprecated: only for orders opened without commitments
+	InsecureMatching         bool                    `json:"insecureMatching"`     // Deprecated: opens price and volume differences during matching
+	MatchingPolicy           string                  `json:"matchingPolicy"`
+	ShadowMode               bool                    `json:"shadowMode"`
+	Tokens                   []order.TokenDetails    `json:"tokens,omitempty"`
+	TokenPairs               []string                `json:"tokenPairs,omitempty"`
+	SyncTokens               bool                    `json:"syncTokens"`
+	MidpointPriceStalenessMs int64                   `json:"midpointPriceStalenessMs"`
+	OrderbookEvents          bool                    `json:"orderbookEvents"`
 }
 
 func NewConfigFromJSONFile(filename string) (Config, error) {
@@ -38,6 +51,44 @@ func NewConfigFromJSONFile(filename string) (Config, error) {
 	if conf.Alpha == 0 {
 		conf.Alpha = 8
 	}
+	if conf.FaultTolerance == 0 && !conf.AllowUnverifiedJoins {
+		// Masked Joins cannot be verified against commitments, and are only
+		// accepted by networks that tolerate faulty Joins
+		conf.FaultTolerance = 1
+	}
 
 	return conf, nil
 }
+
+// TokenRegistry returns the order.TokenRegistry described by the Config. When
+// no tokens are configured, the default tokens of the Ethereum network are
+// used. When no token pairs are configured, the default token pairs are
+// enabled.
+func (conf *Config) TokenRegistry() (*order.TokenRegistry, error) {
+	var registry *order.TokenRegistry
+	if len(conf.Tokens) == 0 {
+		registry = contract.NewTokenRegistry(conf.Ethereum.Network)
+	} else {
+		registry = order.NewTokenRegistry()
+		for _, details := range conf.Tokens {
+			if err := registry.RegisterToken(details); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(conf.TokenPairs) > 0 {
+		for _, tokens := range registry.Pairs() {
+			registry.DisablePair(tokens)
+		}
+		for _, pair := range conf.TokenPairs {
+			tokens, err := registry.ParsePair(pair)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse token pair %v: %v", pair, err)
+			}
+			if err := registry.EnablePair(tokens); err != nil {
+				return nil, fmt.Errorf("cannot enable token pair %v: %v", pair, err)
+			}
+		}
+	}
+	return registry, nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -16,6 +18,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/getsentry/raven-go"
 	"github.com/republicprotocol/republic-go/cmd/darknode/config"
 	"github.com/republicprotocol/republic-go/contract"
@@ -28,6 +31,8 @@ import (
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/registry"
 	"github.com/republicprotocol/republic-go/smpc"
@@ -45,13 +50,27 @@ func main() {
 	// Parse command-line arguments
 	configParam := flag.String("config", path.Join(os.Getenv("HOME"), ".darknode/config.json"), "JSON configuration file")
 	dataParam := flag.String("data", path.Join(os.Getenv("HOME"), ".darknode/data"), "Data directory")
+	failedSettlementsParam := flag.Bool("failedSettlements", false, "Print the failed settlements and exit")
+	replaySettlementParam := flag.String("replaySettlement", "", "Replay the failed settlement of a hex encoded computation ID and exit")
 	flag.Parse()
 
+	// Inspect, or replay, failed settlements without starting the darknode
+	if *failedSettlementsParam || *replaySettlementParam != "" {
+		if err := manageSettlements(*dataParam, *failedSettlementsParam, *replaySettlementParam); err != nil {
+			log.Fatalf("cannot manage settlements: %v", err)
+		}
+		return
+	}
+
 	// Load configuration file
 	config, err := config.NewConfigFromJSONFile(*configParam)
 	if err != nil {
 		log.Fatalf("cannot load config: %v", err)
 	}
+	policy, err := ome.NewMatchingPolicy(config.MatchingPolicy)
+	if err != nil {
+		log.Fatalf("cannot load matching policy %v: %v", config.MatchingPolicy, err)
+	}
 
 	// Configure Sentry and log an initial event
 	if config.SentryDSN != "" {
@@ -89,6 +108,18 @@ func main() {
 		log.Fatalf("cannot get ethereum bindings: %v", err)
 	}
 
+	// Load the tokens, and the token pairs that can be traded
+	tokenRegistry, err := config.TokenRegistry()
+	if err != nil {
+		log.Fatalf("cannot load token registry: %v", err)
+	}
+	if config.SyncTokens {
+		if err := contractBinder.SyncTokenRegistry(tokenRegistry); err != nil {
+			log.Printf("[error] (tokens) cannot sync token registry: %v", err)
+		}
+	}
+	order.SetDefaultTokenRegistry(tokenRegistry)
+
 	// New database for persistent storage
 	store, err := leveldb.NewStore(*dataParam, 25*time.Hour, time.Hour)
 	if err != nil {
@@ -139,13 +170,39 @@ func main() {
 	swarmService := grpc.NewSwarmService(swarm.NewServer(swarmer, store.SwarmMultiAddressStore(), config.Alpha, &crypter))
 	swarmService.Register(server)
 
-	// oracleClient := grpc.NewOracleClient(multiAddr.Address(), store.SwarmMultiAddressStore())
-	// oracler := oracle.NewOracler(oracleClient, &config.Keystore.EcdsaKey, store.SwarmMultiAddressStore(), config.Alpha)
-	// oracleService := grpc.NewOracleService(oracle.NewServer(oracler, config.OracleAddress, store.SwarmMultiAddressStore(), midpointPriceStorer, config.Alpha), time.Millisecond)
-	// oracleService.Register(server)
+	oracleClient := grpc.NewOracleClient(multiAddr.Address(), store.SwarmMultiAddressStore())
+	oracler := oracle.NewOracler(oracleClient, &config.Keystore.EcdsaKey, store.SwarmMultiAddressStore(), config.Alpha)
+	oracleService := grpc.NewOracleService(oracle.NewServer(oracler, config.OracleAddress, store.SwarmMultiAddressStore(), store.OracleMidpointPriceStore(), config.Alpha), time.Millisecond)
+	oracleService.Register(server)
 
-	orderbook := orderbook.NewOrderbook(config.Address, config.Keystore.RsaKey, store.OrderbookPointerStore(), store.OrderbookOrderStore(), store.OrderbookOrderFragmentStore(), &contractBinder, 5*time.Second, 32)
-	orderbookService := grpc.NewOrderbookService(orderbook)
+	midpointPriceStalenessWindow := oracle.MidpointPriceStalenessWindow
+	if config.MidpointPriceStalenessMs > 0 {
+		midpointPriceStalenessWindow = time.Duration(config.MidpointPriceStalenessMs) * time.Millisecond
+	}
+	midpointPrices := oracle.NewMidpointPriceFeed(store.OracleMidpointPriceStore(), midpointPriceStalenessWindow)
+
+	// Sync orders by polling the Orderbook contract, or from the events that
+	// it logs
+	orderbookSyncer := orderbook.NewSyncer(store.OrderbookPointerStore(), store.OrderbookOrderStore(), &contractBinder, 32)
+	if config.OrderbookEvents {
+		orderbookSyncer = orderbook.NewEventSyncer(store.OrderbookPointerStore(), store.OrderbookOrderStore(), &contractBinder, 10000)
+	}
+	// Attest to order fragments, and find the darknodes in the pod that are
+	// missing order fragments, so that traders know which darknodes need an
+	// order fragment to be resent
+	availability := orderbook.NewAvailability(config.Address, &config.Keystore.EcdsaKey, store.OrderbookOrderStore(), store.OrderbookOrderFragmentStore(), grpc.NewAvailabilityClient(swarmer), time.Minute)
+	availabilityService := grpc.NewAvailabilityService(availability)
+	availabilityService.Register(server)
+
+	book := orderbook.NewOrderbookWithSyncer(config.Address, config.Keystore.RsaKey, store.OrderbookPointerStore(), store.OrderbookOrderStore(), store.OrderbookOrderFragmentStore(), &contractBinder, orderbookSyncer, 5*time.Second)
+	// Sign receipts for the order fragments received from traders, and track
+	// the lifecycle of their orders
+	receipter := orderbook.NewReceipter(config.Address, &config.Keystore.EcdsaKey, book, store.OrderbookOrderFragmentStore())
+	tracker := ome.NewTracker(store.OrderbookOrderFragmentStore(), store.SomerOrderFragmentStore(), store.SomerComputationStore())
+	// Market makers open many order fragments on one stream, limited by a
+	// quota for each trader instead of each IP address
+	traderLimiter := grpc.NewRateLimiter(rate.NewLimiter(400, 4000), 20, 500)
+	orderbookService := grpc.NewOrderbookServiceWithTraderLimiter(receipter, tracker, store.OrderbookOrderStore(), traderLimiter)
 	orderbookService.Register(server)
 
 	connectorListener := grpc.NewConnectorListener(config.Address, &crypter, &crypter)
@@ -168,7 +225,11 @@ func main() {
 	statusProvider.WriteDarknodeRegistryAddress(conn.Config.DarknodeRegistryAddress)
 	statusProvider.WriteRewardVaultAddress(conn.Config.DarknodeRewardVaultAddress)
 	statusProvider.WriteInfuraURL(conn.Config.URI)
-	statusProvider.WriteTokens(contract.TokenAddresses(conn.Config.Network))
+	tokens := map[string]string{}
+	for _, details := range tokenRegistry.Tokens() {
+		tokens[details.Name] = details.Address
+	}
+	statusProvider.WriteTokens(tokens)
 
 	pk, err := crypto.BytesFromRsaPublicKey(&config.Keystore.RsaKey.PublicKey)
 	if err != nil {
@@ -183,7 +244,13 @@ func main() {
 		log.Printf("HTTP listening on %v:%v...", bindParam, portParam)
 
 		statusAdapter := adapter.NewStatusAdapter(statusProvider)
-		if err := netHttp.ListenAndServe(fmt.Sprintf("%v:%v", bindParam, portParam), http.NewStatusServer(statusAdapter)); err != nil {
+		var journalAdapter *adapter.JournalAdapter
+		if config.ShadowMode {
+			journal := adapter.NewJournalAdapter(store.SomerJournalStore())
+			journalAdapter = &journal
+		}
+		statusServer := http.NewStatusServerWithReceipts(statusAdapter, adapter.NewAvailabilityAdapter(availability), adapter.NewReceiptAdapter(receipter, tracker), journalAdapter)
+		if err := netHttp.ListenAndServe(fmt.Sprintf("%v:%v", bindParam, portParam), statusServer); err != nil {
 			log.Fatalf("error listening and serving: %v", err)
 		}
 	}()
@@ -192,6 +259,9 @@ func main() {
 	go func() {
 		// Wait for the gRPC server to boot
 		time.Sleep(time.Second)
+
+		// The math/rand package is only used to randomise peer selection, and
+		// must never be used to generate shares, blindings, or nonces
 		rand.Seed(time.Now().UnixNano())
 
 		// Wait until registration
@@ -245,18 +315,61 @@ func main() {
 		}
 
 		// New secure multi-party computer
-		smpcer := smpc.NewSmpcer(connectorListener, swarmer)
+		faultScorer := smpc.NewFaultScorer()
+		smpcer := smpc.NewSmpcerWithOptions(connectorListener, swarmer, smpc.SmpcerOptions{
+			FaultTolerance:       config.FaultTolerance,
+			FaultCallback:        faultScorer.Record,
+			JoinBatchWindow:      time.Duration(config.JoinBatchWindowMs) * time.Millisecond,
+			AllowUnverifiedJoins: config.AllowUnverifiedJoins,
+		})
 
 		// New OME
 		epoch, err := contractBinder.PreviousEpoch()
 		if err != nil {
 			logger.Error(fmt.Sprintf("cannot get previous epoch: %v", err))
 		}
-		gen := ome.NewComputationGenerator(config.Address, store.SomerOrderFragmentStore())
-		matcher := ome.NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), smpcer)
-		confirmer := ome.NewConfirmer(store.SomerComputationStore(), store.SomerOrderFragmentStore(), &contractBinder, 5*time.Second, 6)
-		settler := ome.NewSettler(store.SomerComputationStore(), smpcer, &contractBinder, 1e12)
-		ome := ome.NewOme(config.Address, gen, matcher, confirmer, settler, orderbook, smpcer, epoch)
+		gen := ome.NewComputationGeneratorWithMatchingPolicy(config.Address, store.SomerOrderFragmentStore(), policy)
+		// The Smpcer generates the random material used by comparisons
+		comparer := smpc.NewComparer(smpcer, smpcer)
+		matcher := ome.NewSecureMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), smpcer, comparer)
+		if config.InsecureMatching {
+			logger.Warn("insecure matching is deprecated: price and volume differences will be opened")
+			matcher = ome.NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), smpcer)
+		}
+		if !config.AllowUnverifiedJoins {
+			// Order fragments without commitments cannot be verified, and the
+			// commitments must be the same across the pod
+			matcher = ome.NewCommittedMatcherWithVerifier(matcher, store.SomerComputationStore(), availability)
+		}
+		if config.InsecureMatching {
+			matcher = ome.NewMidpointMatcher(matcher, store.SomerComputationStore(), smpcer, midpointPrices)
+		} else {
+			matcher = ome.NewSecureMidpointMatcher(matcher, store.SomerComputationStore(), smpcer, comparer, midpointPrices)
+		}
+		var omeBinder ome.ContractBinder = &contractBinder
+		if config.ShadowMode {
+			// Record transactions in the journal instead of submitting them
+			omeBinder = ome.NewShadowContractBinder(&contractBinder, store.SomerJournalStore())
+		}
+		confirmer := ome.NewConfirmer(store.SomerComputationStore(), store.SomerOrderFragmentStore(), omeBinder, 5*time.Second, 6)
+		backends := ome.NewSettlementBackendRegistry()
+		if config.ShadowMode {
+			backends.Register(order.SettlementRenEx, ome.NewShadowSettlementBackend(store.SomerJournalStore()))
+			backends.Register(order.SettlementRenExAtomic, ome.NewShadowSettlementBackend(store.SomerJournalStore()))
+		} else {
+			backends.Register(order.SettlementRenEx, &contractBinder)
+			if atomicBackend, err := newRenExAtomicBackend(auth, &contractBinder, conn.Config); err != nil {
+				log.Printf("[error] (settle) cannot settle RenEx Atomic orders: %v", err)
+			} else {
+				backends.Register(order.SettlementRenExAtomic, atomicBackend)
+			}
+		}
+		settler := ome.NewSettler(store.SomerComputationStore(), store.SomerSettlementStore(), smpcer, omeBinder, backends, midpointPrices, 1e12)
+		resharer := ome.NewResharer(config.Address, book, smpcer, store.SomerOrderFragmentStore(), 5*time.Second)
+		ome := ome.NewOme(config.Address, gen, matcher, confirmer, settler, resharer, book, smpcer, store.SomerComputationStore(), epoch)
+		availability.OnChangeEpoch(epoch)
+		receipter.OnChangeEpoch(epoch)
+		tracker.OnChangeEpoch(epoch)
 
 		dispatch.CoBegin(func() {
 			// Synchronizing the OME
@@ -285,7 +398,16 @@ func main() {
 
 				// Notify the Ome
 				ome.OnChangeEpoch(epoch)
+				availability.OnChangeEpoch(epoch)
+				receipter.OnChangeEpoch(epoch)
+				tracker.OnChangeEpoch(epoch)
 			}
+		}, func() {
+			// Find the darknodes that are missing order fragments
+			availability.Run(done)
+		}, func() {
+			// Forget the receipts of rejected order fragments that expired
+			receipter.Run(done)
 		}, func() {
 			// Prune the database every hour and update the network with the
 			// darknode address
@@ -300,6 +422,17 @@ func main() {
 					continue
 				}
 			}
+		}, func() {
+			// Periodically report the state held for joins in each network
+			for {
+				time.Sleep(time.Minute)
+				for networkID, metrics := range smpcer.JoinMetrics() {
+					log.Printf("[info] (smpc) network %v: join sets = %v, evicted join sets = %v, self joins = %v, commitments = %v, deferred joins = %v", networkID, metrics.JoinSets, metrics.EvictedJoinSets, metrics.SelfJoins, metrics.Commitments, metrics.DeferredJoins)
+				}
+				for addr, score := range faultScorer.Scores() {
+					log.Printf("[info] (smpc) darknode %v: faulty joins = %v", addr, score)
+				}
+			}
 		})
 	}()
 
@@ -339,3 +472,68 @@ func pingNetwork(swarmer swarm.Swarmer) error {
 
 	return nil
 }
+
+// newRenExAtomicBackend binds to the RenEx Atomic settlement contract that is
+// registered in the SettlementRegistry, and to the RenExAtomicSwapper contract
+// from the Ethereum config.
+func newRenExAtomicBackend(auth *bind.TransactOpts, contractBinder *contract.Binder, config contract.Config) (*contract.RenExAtomicBackend, error) {
+	if config.RenExAtomicSwapperAddress == "" {
+		return nil, fmt.Errorf("renExAtomicSwapperAddress not set")
+	}
+	settlementAddress, err := contractBinder.SettlementContract(order.SettlementRenExAtomic)
+	if err != nil {
+		return nil, err
+	}
+	return contract.NewRenExAtomicBackend(auth, contractBinder.Client(), settlementAddress, common.HexToAddress(config.RenExAtomicSwapperAddress))
+}
+
+// manageSettlements prints the failed settlements in the store, and replays a
+// failed settlement. The darknode must be stopped, because the store can only
+// be opened by one process.
+func manageSettlements(dataDir string, printFailed bool, replayID string) error {
+	store, err := leveldb.NewStore(dataDir, 25*time.Hour, time.Hour)
+	if err != nil {
+		return err
+	}
+	defer store.Release()
+
+	if replayID != "" {
+		idBytes, err := hex.DecodeString(strings.TrimPrefix(replayID, "0x"))
+		if err != nil || len(idBytes) != 32 {
+			return fmt.Errorf("invalid computation id %v", replayID)
+		}
+		id := ome.ComputationID{}
+		copy(id[:], idBytes)
+		if err := ome.ReplaySettlement(store.SomerSettlementStore(), id); err != nil {
+			return err
+		}
+		log.Printf("[info] replaying settlement %v", replayID)
+	}
+
+	if printFailed {
+		settlements, err := ome.FailedSettlements(store.SomerSettlementStore())
+		if err != nil {
+			return err
+		}
+		for _, settlement := range settlements {
+			data, err := json.Marshal(struct {
+				ID       string `json:"id"`
+				Buy      string `json:"buy"`
+				Sell     string `json:"sell"`
+				Attempts int    `json:"attempts"`
+				Err      string `json:"err"`
+			}{
+				ID:       hex.EncodeToString(settlement.Computation.ID[:]),
+				Buy:      settlement.Buy.ID.String(),
+				Sell:     settlement.Sell.ID.String(),
+				Attempts: settlement.Attempts,
+				Err:      settlement.Err,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		}
+	}
+	return nil
+}
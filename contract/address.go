@@ -1,5 +1,7 @@
 package contract
 
+import "github.com/republicprotocol/republic-go/order"
+
 // TokenAddresses returns the tokens for the provided network
 func TokenAddresses(network Network) map[string]string {
 	tokens := map[string]string{}
@@ -141,3 +143,20 @@ func SettlementRegistryAddress(network Network) string {
 		panic("unknown network")
 	}
 }
+
+// NewTokenRegistry returns the default order.TokenRegistry with the addresses
+// of the tokens on the provided network.
+func NewTokenRegistry(network Network) *order.TokenRegistry {
+	registry := order.NewDefaultTokenRegistry()
+	addresses := TokenAddresses(network)
+	addresses["ETH"] = EthereumAddress
+	for name, address := range addresses {
+		details, err := registry.TokenByName(name)
+		if err != nil {
+			continue
+		}
+		details.Address = address
+		registry.RegisterToken(details)
+	}
+	return registry
+}
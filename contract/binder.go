@@ -312,8 +312,15 @@ func (binder *Binder) submitMatch(buy, sell order.ID) (*types.Transaction, error
 	return binder.renExSettlement.Settle(binder.transactOpts, buy, sell)
 }
 
-// Settle the order pair that has been confirmed by the Orderbook.
-func (binder *Binder) Settle(buy order.Order, sell order.Order) error {
+// Settle the order pair that has been confirmed by the Orderbook. The RenEx
+// Settlement contract executes orders at the midpoint of their prices, so an
+// order pair that must be executed at any other price is not settled and
+// ErrUnsupportedSettlementPrice is returned.
+func (binder *Binder) Settle(buy order.Order, sell order.Order, price uint64) error {
+	if price != executionPrice(buy, sell) {
+		return fmt.Errorf("cannot settle buy = %v, sell = %v at %v: %v", buy.ID, sell.ID, price, ErrUnsupportedSettlementPrice)
+	}
+
 	if binder.conn.Config.SentryDSN != "" {
 		binder.checkBalance()
 	}
@@ -488,6 +495,75 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 	return nil
 }
 
+// SettlementContract returns the address of the contract that is registered
+// in the SettlementRegistry for an order.Settlement.
+func (binder *Binder) SettlementContract(settlement order.Settlement) (common.Address, error) {
+	binder.mu.RLock()
+	defer binder.mu.RUnlock()
+
+	address, err := binder.settlementRegistry.SettlementContract(binder.callOpts, uint64(settlement))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("cannot get %v settlement address: %v", settlement, err)
+	}
+	if address == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("cannot get %v settlement address: not registered", settlement)
+	}
+	return address, nil
+}
+
+// Client returns the Ethereum client used by the Binder, so that other
+// contracts can be bound using the same connection.
+func (binder *Binder) Client() ChainBackend {
+	return binder.conn.Client
+}
+
+// SyncTokenRegistry updates the addresses and decimals of the Tokens in an
+// order.TokenRegistry using the RenExTokens contract. The RenExTokens contract
+// cannot be enumerated, so only Tokens that are already in the TokenRegistry
+// are updated. Tokens that are not registered in the contract, such as BTC,
+// are left unchanged.
+func (binder *Binder) SyncTokenRegistry(registry *order.TokenRegistry) error {
+	binder.mu.RLock()
+	defer binder.mu.RUnlock()
+
+	return binder.syncTokenRegistry(registry)
+}
+
+func (binder *Binder) syncTokenRegistry(registry *order.TokenRegistry) error {
+	renExSettlementAddress, err := binder.settlementRegistry.SettlementContract(binder.callOpts, uint64(order.SettlementRenEx))
+	if err != nil {
+		return fmt.Errorf("cannot get RenExSettlement address: %v", err)
+	}
+	renExSettlement, err := bindings.NewRenExSettlement(renExSettlementAddress, bind.ContractBackend(binder.conn.Client))
+	if err != nil {
+		return fmt.Errorf("cannot bind to RenExSettlement: %v", err)
+	}
+	renExTokensAddress, err := renExSettlement.RenExTokensContract(binder.callOpts)
+	if err != nil {
+		return fmt.Errorf("cannot get RenExTokens address: %v", err)
+	}
+	renExTokens, err := bindings.NewRenExTokens(renExTokensAddress, bind.ContractBackend(binder.conn.Client))
+	if err != nil {
+		return fmt.Errorf("cannot bind to RenExTokens: %v", err)
+	}
+
+	for _, details := range registry.Tokens() {
+		token, err := renExTokens.Tokens(binder.callOpts, uint32(details.Token))
+		if err != nil {
+			return fmt.Errorf("cannot get details of token %v: %v", details.Name, err)
+		}
+		if !token.Registered {
+			continue
+		}
+		details.Address = token.Addr.Hex()
+		details.Decimals = token.Decimals
+		if err := registry.RegisterToken(details); err != nil {
+			return fmt.Errorf("cannot update details of token %v: %v", details.Name, err)
+		}
+	}
+	return nil
+}
+
 // Register a new dark node with the dark node registrar
 func (binder *Binder) Register(darknodeID []byte, publicKey []byte, bond *stackint.Int1024) error {
 	darknodeIDByte, err := toByte(darknodeID)
@@ -28,6 +28,7 @@ type Config struct {
 	DarknodeSlasherAddress     string  `json:"darknodeSlasherAddress"`
 	OrderbookAddress           string  `json:"orderbookAddress"`
 	SettlementRegistryAddress  string  `json:"settlementRegistryAddress"`
+	RenExAtomicSwapperAddress  string  `json:"renExAtomicSwapperAddress,omitempty"`
 }
 
 // IsNil returns true if Config or any of its fields are nil.
@@ -0,0 +1,168 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+// Topics of the events logged by the Orderbook contract when the status of an
+// order changes. The generated Orderbook bindings do not include these events,
+// so they are decoded directly from their topics. Orderbook contracts that do
+// not log these events cannot be synchronised using events.
+var (
+	OrderbookLogOrderOpenedTopic    = crypto.Keccak256Hash([]byte("LogOrderOpened(bytes32,address)"))
+	OrderbookLogOrderConfirmedTopic = crypto.Keccak256Hash([]byte("LogOrderConfirmed(bytes32,bytes32)"))
+	OrderbookLogOrderCanceledTopic  = crypto.Keccak256Hash([]byte("LogOrderCanceled(bytes32)"))
+)
+
+// LatestBlockNumber implements the orderbook.ContractEventBinder interface.
+func (binder *Binder) LatestBlockNumber() (uint64, error) {
+	header, err := binder.conn.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// BlockHash implements the orderbook.ContractEventBinder interface.
+func (binder *Binder) BlockHash(blockNumber uint64) ([32]byte, error) {
+	header, err := binder.conn.Client.HeaderByNumber(context.Background(), big.NewInt(0).SetUint64(blockNumber))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return header.Hash(), nil
+}
+
+// OrderEvents implements the orderbook.ContractEventBinder interface.
+func (binder *Binder) OrderEvents(fromBlock, toBlock uint64) ([]orderbook.OrderEvent, error) {
+	logs, err := binder.conn.Client.FilterLogs(context.Background(), binder.orderEventsQuery(big.NewInt(0).SetUint64(fromBlock), big.NewInt(0).SetUint64(toBlock)))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]orderbook.OrderEvent, 0, len(logs))
+	for _, ethLog := range logs {
+		if ethLog.Removed {
+			continue
+		}
+		event, err := binder.orderEvent(ethLog)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// SubscribeOrderEvents implements the orderbook.ContractEventBinder interface.
+func (binder *Binder) SubscribeOrderEvents(events chan<- orderbook.OrderEvent) (orderbook.OrderEventSubscription, error) {
+	logs := make(chan types.Log, 128)
+	logsSubscription, err := binder.conn.Client.SubscribeFilterLogs(context.Background(), binder.orderEventsQuery(nil, nil), logs)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &orderEventSubscription{
+		logsSubscription: logsSubscription,
+		done:             make(chan struct{}),
+		errs:             make(chan error, 1),
+	}
+	go func() {
+		for {
+			select {
+			case <-subscription.done:
+				return
+			case err := <-logsSubscription.Err():
+				subscription.errs <- err
+				return
+			case ethLog := <-logs:
+				if ethLog.Removed {
+					continue
+				}
+				event, err := binder.orderEvent(ethLog)
+				if err != nil {
+					subscription.errs <- err
+					return
+				}
+				select {
+				case <-subscription.done:
+					return
+				case events <- event:
+				default:
+					subscription.errs <- fmt.Errorf("cannot deliver order event: too many order events")
+					return
+				}
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+func (binder *Binder) orderEventsQuery(fromBlock, toBlock *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{common.HexToAddress(binder.conn.Config.OrderbookAddress)},
+		Topics: [][]common.Hash{{
+			OrderbookLogOrderOpenedTopic,
+			OrderbookLogOrderConfirmedTopic,
+			OrderbookLogOrderCanceledTopic,
+		}},
+	}
+}
+
+func (binder *Binder) orderEvent(ethLog types.Log) (orderbook.OrderEvent, error) {
+	if len(ethLog.Topics) < 2 {
+		return orderbook.OrderEvent{}, fmt.Errorf("cannot decode order event in tx = %v: missing topics", ethLog.TxHash.Hex())
+	}
+	event := orderbook.OrderEvent{
+		BlockNumber: ethLog.BlockNumber,
+		BlockHash:   ethLog.BlockHash,
+	}
+	copy(event.OrderID[:], ethLog.Topics[1][:])
+
+	switch ethLog.Topics[0] {
+	case OrderbookLogOrderOpenedTopic:
+		if len(ethLog.Topics) < 3 {
+			return orderbook.OrderEvent{}, fmt.Errorf("cannot decode order event in tx = %v: missing trader", ethLog.TxHash.Hex())
+		}
+		priority, err := binder.Priority(event.OrderID)
+		if err != nil {
+			return orderbook.OrderEvent{}, fmt.Errorf("cannot load priority of order = %v: %v", event.OrderID, err)
+		}
+		event.Status = order.Open
+		event.Trader = common.BytesToAddress(ethLog.Topics[2][:]).String()
+		event.Priority = uint(priority)
+	case OrderbookLogOrderConfirmedTopic:
+		event.Status = order.Confirmed
+	case OrderbookLogOrderCanceledTopic:
+		event.Status = order.Canceled
+	default:
+		event.Status = order.Nil
+	}
+	return event, nil
+}
+
+type orderEventSubscription struct {
+	logsSubscription ethereum.Subscription
+	done             chan struct{}
+	errs             chan error
+}
+
+// Unsubscribe implements the orderbook.OrderEventSubscription interface.
+func (subscription *orderEventSubscription) Unsubscribe() {
+	subscription.logsSubscription.Unsubscribe()
+	close(subscription.done)
+}
+
+// Err implements the orderbook.OrderEventSubscription interface.
+func (subscription *orderEventSubscription) Err() <-chan error {
+	return subscription.errs
+}
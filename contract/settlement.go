@@ -0,0 +1,289 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/republicprotocol/republic-go/contract/bindings"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrTransactionFailed is returned when a transaction is mined, but its
+// execution was reverted.
+var ErrTransactionFailed = errors.New("transaction failed")
+
+// ErrUnsupportedSettlementPrice is returned when orders are settled at a price
+// that the Settlement contract will not execute them at.
+var ErrUnsupportedSettlementPrice = errors.New("unsupported settlement price")
+
+// SettlementTimeout is the maximum time that a SettlementBackend will wait for
+// one of its transactions to be mined.
+const SettlementTimeout = 2 * time.Minute
+
+// Values returned by the orderStatus function of a Settlement contract.
+const (
+	settlementStatusNil       = uint8(0)
+	settlementStatusSubmitted = uint8(1)
+	settlementStatusSettled   = uint8(2)
+)
+
+// ChainBackend is a connection to an Ethereum chain that can call contracts,
+// send transactions, and wait for transactions to be mined. It is implemented
+// by an ethclient.Client, and by the simulated chains used for testing.
+type ChainBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// SettlementBackend settles orders using a contract that implements the
+// Settlement interface. The orders are submitted to the contract, and then
+// their match is settled. It implements the ome.SettlementBackend interface.
+type SettlementBackend struct {
+	mu           *sync.Mutex
+	client       ChainBackend
+	settlement   order.Settlement
+	transactOpts *bind.TransactOpts
+	callOpts     *bind.CallOpts
+	contract     *bindings.Settlement
+}
+
+// NewSettlementBackend returns a SettlementBackend that settles orders with
+// the given order.Settlement using the Settlement contract at the given
+// address. Nonces are taken from the pending state of the ChainBackend when
+// each transaction is sent.
+func NewSettlementBackend(auth *bind.TransactOpts, client ChainBackend, settlement order.Settlement, address common.Address) (*SettlementBackend, error) {
+	transactOpts := *auth
+	transactOpts.Nonce = nil
+	if transactOpts.GasLimit == 0 {
+		transactOpts.GasLimit = 500000
+	}
+
+	contract, err := bindings.NewSettlement(address, bind.ContractBackend(client))
+	if err != nil {
+		return nil, fmt.Errorf("cannot bind to %v settlement: %v", settlement, err)
+	}
+
+	return &SettlementBackend{
+		mu:           new(sync.Mutex),
+		client:       client,
+		settlement:   settlement,
+		transactOpts: &transactOpts,
+		callOpts:     &bind.CallOpts{},
+		contract:     contract,
+	}, nil
+}
+
+// Settle the orders by submitting the orders that have not been submitted, and
+// then settling their match. Orders that have already been settled are
+// ignored. The Settlement contract executes orders at the midpoint of their
+// prices, so orders that must be executed at any other price, such as the
+// mid-point price of a midpoint order, are not settled and
+// ErrUnsupportedSettlementPrice is returned.
+func (backend *SettlementBackend) Settle(buy order.Order, sell order.Order, price uint64) error {
+	if buy.Settlement != backend.settlement || sell.Settlement != backend.settlement {
+		return fmt.Errorf("cannot settle buy = %v, sell = %v: expected %v settlement", buy.ID, sell.ID, backend.settlement)
+	}
+	if price != executionPrice(buy, sell) {
+		return fmt.Errorf("cannot settle buy = %v, sell = %v at %v: %v", buy.ID, sell.ID, price, ErrUnsupportedSettlementPrice)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	buyStatus, err := backend.contract.OrderStatus(backend.callOpts, buy.ID)
+	if err != nil {
+		return fmt.Errorf("cannot get settlement status of buy = %v: %v", buy.ID, err)
+	}
+	sellStatus, err := backend.contract.OrderStatus(backend.callOpts, sell.ID)
+	if err != nil {
+		return fmt.Errorf("cannot get settlement status of sell = %v: %v", sell.ID, err)
+	}
+	if buyStatus >= settlementStatusSettled || sellStatus >= settlementStatusSettled {
+		log.Printf("[info] (settle) already settled buy = %v, sell = %v", buy.ID, sell.ID)
+		return nil
+	}
+
+	if buyStatus == settlementStatusNil {
+		if err := backend.submitOrder(buy); err != nil {
+			return fmt.Errorf("cannot submit buy = %v: %v", buy.ID, err)
+		}
+	}
+	if sellStatus == settlementStatusNil {
+		if err := backend.submitOrder(sell); err != nil {
+			return fmt.Errorf("cannot submit sell = %v: %v", sell.ID, err)
+		}
+	}
+
+	if err := backend.sendTx(func(transactOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return backend.contract.Settle(transactOpts, buy.ID, sell.ID)
+	}); err != nil {
+		return fmt.Errorf("cannot settle buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+	}
+
+	log.Printf("[info] (settle) 💰💰💰 %v buy = %v, sell = %v 💰💰💰", backend.settlement, buy.ID, sell.ID)
+	return nil
+}
+
+// Status returns the status of an order in the Settlement contract:
+//     0  - Order not seen before
+//     1  - Order details submitted
+//     >1 - Order settled, or settlement no longer possible
+func (backend *SettlementBackend) Status(id order.ID) (uint8, error) {
+	return backend.contract.OrderStatus(backend.callOpts, id)
+}
+
+// executionPrice returns the price at which a Settlement contract executes a
+// buy and sell order, which is the midpoint of their prices.
+func executionPrice(buy, sell order.Order) uint64 {
+	x := big.NewInt(0).SetUint64(buy.Price)
+	x.Add(x, big.NewInt(0).SetUint64(sell.Price))
+	x.Div(x, big.NewInt(2))
+	return x.Uint64()
+}
+
+func (backend *SettlementBackend) submitOrder(ord order.Order) error {
+	tokens := uint64(ord.Tokens)
+	if ord.Parity == order.ParitySell {
+		tokens = (tokens << 32) | (tokens >> 32)
+	}
+	return backend.sendTx(func(transactOpts *bind.TransactOpts) (*types.Transaction, error) {
+		// If the gas price is greater than the gas price limit, lower the gas
+		// price for this transaction
+		submissionGasPriceLimit, err := backend.contract.SubmissionGasPriceLimit(backend.callOpts)
+		if err == nil && transactOpts.GasPrice != nil && transactOpts.GasPrice.Cmp(submissionGasPriceLimit) == 1 {
+			transactOpts.GasPrice = submissionGasPriceLimit
+		}
+		return backend.contract.SubmitOrder(transactOpts, ord.PrefixHash(), uint64(ord.Settlement), tokens, big.NewInt(0).SetUint64(ord.Price), big.NewInt(0).SetUint64(ord.Volume), big.NewInt(0).SetUint64(ord.MinimumVolume))
+	})
+}
+
+// sendTx sends the transaction returned by f, and waits for it to be mined. A
+// transaction that is rejected because of its nonce is sent again, with the
+// nonce of the pending state, for up to one minute.
+func (backend *SettlementBackend) sendTx(f func(transactOpts *bind.TransactOpts) (*types.Transaction, error)) error {
+	var tx *types.Transaction
+	var err error
+	for try := 0; try < 60; try++ {
+		transactOpts := *backend.transactOpts
+		if tx, err = f(&transactOpts); err == nil || !strings.Contains(err.Error(), "nonce") {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SettlementTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, backend.client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return ErrTransactionFailed
+	}
+	return nil
+}
+
+// SwapStatus is the status of an atomic swap in the RenExAtomicSwapper
+// contract.
+type SwapStatus uint8
+
+// Values for a SwapStatus.
+const (
+	SwapStatusNil SwapStatus = iota
+	SwapStatusOpen
+	SwapStatusRefundable
+	SwapStatusClosed
+)
+
+// String returns a human-readable representation of the SwapStatus.
+func (status SwapStatus) String() string {
+	switch status {
+	case SwapStatusNil:
+		return "nil"
+	case SwapStatusOpen:
+		return "open"
+	case SwapStatusRefundable:
+		return "refundable"
+	case SwapStatusClosed:
+		return "closed"
+	default:
+		return "unsupported status"
+	}
+}
+
+// RenExAtomicBackend settles RenEx Atomic orders. Matches are settled in the
+// RenEx Atomic Settlement contract, and the traders then swap their tokens
+// using the RenExAtomicSwapper contract, where the swap of each order is
+// identified by its order.ID. The RenExAtomicBackend can also follow the
+// swaps, and refund swaps that have expired.
+type RenExAtomicBackend struct {
+	*SettlementBackend
+	swapper *bindings.RenExAtomicSwapper
+}
+
+// NewRenExAtomicBackend returns a RenExAtomicBackend that uses the RenEx
+// Atomic Settlement contract, and the RenExAtomicSwapper contract, at the
+// given addresses.
+func NewRenExAtomicBackend(auth *bind.TransactOpts, client ChainBackend, settlementAddress, swapperAddress common.Address) (*RenExAtomicBackend, error) {
+	settlementBackend, err := NewSettlementBackend(auth, client, order.SettlementRenExAtomic, settlementAddress)
+	if err != nil {
+		return nil, err
+	}
+	swapper, err := bindings.NewRenExAtomicSwapper(swapperAddress, bind.ContractBackend(client))
+	if err != nil {
+		return nil, fmt.Errorf("cannot bind to RenExAtomicSwapper: %v", err)
+	}
+	return &RenExAtomicBackend{
+		SettlementBackend: settlementBackend,
+		swapper:           swapper,
+	}, nil
+}
+
+// SwapStatus returns the SwapStatus of the atomic swap for an order.
+func (backend *RenExAtomicBackend) SwapStatus(id order.ID) (SwapStatus, error) {
+	initiatable, err := backend.swapper.Initiatable(backend.callOpts, id)
+	if err != nil {
+		return SwapStatusNil, fmt.Errorf("cannot get swap status of order = %v: %v", id, err)
+	}
+	if initiatable {
+		return SwapStatusNil, nil
+	}
+	refundable, err := backend.swapper.Refundable(backend.callOpts, id)
+	if err != nil {
+		return SwapStatusNil, fmt.Errorf("cannot get swap status of order = %v: %v", id, err)
+	}
+	if refundable {
+		return SwapStatusRefundable, nil
+	}
+	redeemable, err := backend.swapper.Redeemable(backend.callOpts, id)
+	if err != nil {
+		return SwapStatusNil, fmt.Errorf("cannot get swap status of order = %v: %v", id, err)
+	}
+	if redeemable {
+		return SwapStatusOpen, nil
+	}
+	return SwapStatusClosed, nil
+}
+
+// Refund the atomic swap for an order after it has expired. The funds are
+// returned to the trader that opened the swap.
+func (backend *RenExAtomicBackend) Refund(id order.ID) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	return backend.sendTx(func(transactOpts *bind.TransactOpts) (*types.Transaction, error) {
+		return backend.swapper.Refund(transactOpts, id)
+	})
+}
@@ -0,0 +1,162 @@
+package contract_test
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/contract"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/contract/bindings"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Settlement backends", func() {
+
+	var auth, trader *bind.TransactOpts
+	var chain *minedBackend
+	var settlementAddress common.Address
+
+	BeforeEach(func() {
+		key, err := crypto.GenerateKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		auth = bind.NewKeyedTransactor(key)
+		traderKey, err := crypto.GenerateKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		trader = bind.NewKeyedTransactor(traderKey)
+
+		ether := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(18), nil)
+		chain = &minedBackend{backends.NewSimulatedBackend(core.GenesisAlloc{
+			auth.From:   {Balance: big.NewInt(0).Mul(ether, big.NewInt(100))},
+			trader.From: {Balance: big.NewInt(0).Mul(ether, big.NewInt(100))},
+		})}
+
+		// The RenEx Settlement contract implements the Settlement interface.
+		// It is deployed without an Orderbook, so orders cannot be submitted
+		// to it.
+		settlementAddress, _, _, err = bindings.DeployRenExSettlement(auth, chain, "test", common.Address{}, common.Address{}, common.Address{}, common.Address{}, big.NewInt(1e11))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when settling orders", func() {
+
+		var backend *SettlementBackend
+		var buy, sell order.Order
+
+		BeforeEach(func() {
+			var err error
+			backend, err = NewSettlementBackend(auth, chain, order.SettlementRenEx, settlementAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expiry := time.Now().Add(time.Hour)
+			buy = order.NewOrder(order.ParityBuy, order.TypeLimit, expiry, order.SettlementRenEx, order.TokensETHREN, 1200000000000, 1000000000000, 1000000000000, 1)
+			sell = order.NewOrder(order.ParitySell, order.TypeLimit, expiry, order.SettlementRenEx, order.TokensETHREN, 800000000000, 1000000000000, 1000000000000, 2)
+		})
+
+		It("should return the status of orders that have not been submitted", func() {
+			status, err := backend.Status(buy.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status).Should(Equal(uint8(0)))
+		})
+
+		It("should not settle orders at a price that the contract does not execute them at", func() {
+			nonce, err := chain.PendingNonceAt(context.Background(), auth.From)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = backend.Settle(buy, sell, 1100000000000)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(ErrUnsupportedSettlementPrice.Error()))
+			Expect(chain.PendingNonceAt(context.Background(), auth.From)).Should(Equal(nonce))
+		})
+
+		It("should not settle orders with a different settlement layer", func() {
+			buy.Settlement = order.SettlementRenExAtomic
+			Expect(backend.Settle(buy, sell, 1000000000000)).Should(HaveOccurred())
+		})
+
+		It("should return an error when the contract reverts the submission of an order", func() {
+			err := backend.Settle(buy, sell, 1000000000000)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(ErrTransactionFailed.Error()))
+
+			status, err := backend.Status(buy.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status).Should(Equal(uint8(0)))
+		})
+	})
+
+	Context("when following atomic swaps", func() {
+
+		var backend *RenExAtomicBackend
+		var swapper *bindings.RenExAtomicSwapper
+		var swapID order.ID
+
+		BeforeEach(func() {
+			swapperAddress, _, swapperContract, err := bindings.DeployRenExAtomicSwapper(auth, chain, "test")
+			Expect(err).ShouldNot(HaveOccurred())
+			swapper = swapperContract
+			backend, err = NewRenExAtomicBackend(auth, chain, settlementAddress, swapperAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = rand.Read(swapID[:])
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		initiate := func(timelock int64) {
+			secretLock := [32]byte{}
+			_, err := rand.Read(secretLock[:])
+			Expect(err).ShouldNot(HaveOccurred())
+			opts := *trader
+			opts.Value = big.NewInt(1000)
+			_, err = swapper.Initiate(&opts, swapID, auth.From, secretLock, big.NewInt(timelock))
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		It("should return the status of swaps that have not been initiated", func() {
+			Expect(backend.SwapStatus(swapID)).Should(Equal(SwapStatusNil))
+		})
+
+		It("should return the status of swaps that have been initiated", func() {
+			initiate(time.Hour.Nanoseconds() / int64(time.Second))
+			Expect(backend.SwapStatus(swapID)).Should(Equal(SwapStatusOpen))
+		})
+
+		It("should refund swaps that have expired", func() {
+			initiate(time.Hour.Nanoseconds() / int64(time.Second))
+			Expect(chain.AdjustTime(2 * time.Hour)).ShouldNot(HaveOccurred())
+			chain.Commit()
+			Expect(backend.SwapStatus(swapID)).Should(Equal(SwapStatusRefundable))
+
+			Expect(backend.Refund(swapID)).ShouldNot(HaveOccurred())
+			Expect(backend.SwapStatus(swapID)).Should(Equal(SwapStatusClosed))
+		})
+
+		It("should not refund swaps that have not expired", func() {
+			initiate(time.Hour.Nanoseconds() / int64(time.Second))
+			Expect(backend.Refund(swapID)).Should(Equal(ErrTransactionFailed))
+			Expect(backend.SwapStatus(swapID)).Should(Equal(SwapStatusOpen))
+		})
+	})
+})
+
+// minedBackend is a simulated chain that mines every transaction as soon as
+// it is sent.
+type minedBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (backend *minedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := backend.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	backend.Commit()
+	return nil
+}
@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/swarm"
+	"golang.org/x/net/context"
+)
+
+// ErrAttestOrderFragmentRequestIsNil is returned when a gRPC request is nil or
+// has nil fields.
+var ErrAttestOrderFragmentRequestIsNil = errors.New("attest order fragment request is nil")
+
+type availabilityClient struct {
+	swarmer swarm.Swarmer
+}
+
+// NewAvailabilityClient returns an implementation of the
+// orderbook.AvailabilityClient interface that uses gRPC, and a swarm.Swarmer
+// to find the identity.MultiAddresses of other Darknodes.
+func NewAvailabilityClient(swarmer swarm.Swarmer) orderbook.AvailabilityClient {
+	return &availabilityClient{
+		swarmer: swarmer,
+	}
+}
+
+// AttestOrderFragment implements the orderbook.AvailabilityClient interface.
+func (client *availabilityClient) AttestOrderFragment(ctx context.Context, to identity.Address, orderID order.ID) (orderbook.FragmentAttestation, error) {
+	multiAddr, err := client.swarmer.Query(ctx, to)
+	if err != nil {
+		return orderbook.FragmentAttestation{}, fmt.Errorf("cannot query %v: %v", to, err)
+	}
+	conn, err := Dial(ctx, multiAddr)
+	if err != nil {
+		return orderbook.FragmentAttestation{}, fmt.Errorf("cannot dial %v: %v", multiAddr, err)
+	}
+	defer conn.Close()
+
+	request := &AttestOrderFragmentRequest{
+		OrderId: orderID[:],
+	}
+	var response *AttestOrderFragmentResponse
+	if err := Backoff(ctx, func() error {
+		response, err = NewAvailabilityServiceClient(conn).AttestOrderFragment(ctx, request)
+		return err
+	}); err != nil {
+		return orderbook.FragmentAttestation{}, err
+	}
+
+	attestation := orderbook.FragmentAttestation{
+		Darknode:  identity.Address(response.GetDarknode()),
+		Signature: response.GetSignature(),
+	}
+	copy(attestation.OrderID[:], response.GetOrderId())
+	copy(attestation.FragmentID[:], response.GetFragmentId())
+	copy(attestation.CommitmentsHash[:], response.GetCommitmentsHash())
+
+	// The attestation must be for the requested order, and must be signed by
+	// the requested Darknode
+	if !attestation.OrderID.Equal(orderID) || attestation.Darknode != to {
+		return orderbook.FragmentAttestation{}, orderbook.ErrInvalidFragmentAttestation
+	}
+	if err := attestation.Verify(); err != nil {
+		return orderbook.FragmentAttestation{}, fmt.Errorf("cannot verify fragment attestation from %v: %v", to, err)
+	}
+	return attestation, nil
+}
+
+// AvailabilityService is a Service that implements the gRPC
+// AvailabilityService defined in protobuf. It delegates responsibility for
+// handling the AttestOrderFragment RPCs to an orderbook.AvailabilityServer.
+type AvailabilityService struct {
+	server orderbook.AvailabilityServer
+}
+
+// NewAvailabilityService returns an AvailabilityService that uses the
+// orderbook.AvailabilityServer as a delegate.
+func NewAvailabilityService(server orderbook.AvailabilityServer) AvailabilityService {
+	return AvailabilityService{
+		server: server,
+	}
+}
+
+// Register implements the Service interface.
+func (service *AvailabilityService) Register(server *Server) {
+	if server == nil {
+		logger.Network(logger.LevelError, "server is nil")
+		return
+	}
+	RegisterAvailabilityServiceServer(server.Server, service)
+}
+
+// AttestOrderFragment is an RPC used by the other Darknodes in a Pod to find
+// out which order.Fragment this Darknode holds for an order. The
+// AvailabilityService delegates the responsibility of signing a
+// FragmentAttestation to its orderbook.AvailabilityServer.
+func (service *AvailabilityService) AttestOrderFragment(ctx context.Context, request *AttestOrderFragmentRequest) (*AttestOrderFragmentResponse, error) {
+	// Check for empty or invalid request fields.
+	if request == nil || len(request.OrderId) != 32 {
+		return nil, ErrAttestOrderFragmentRequestIsNil
+	}
+
+	orderID := order.ID{}
+	copy(orderID[:], request.OrderId)
+	attestation, err := service.server.AttestOrderFragment(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &AttestOrderFragmentResponse{
+		OrderId:         attestation.OrderID[:],
+		FragmentId:      attestation.FragmentID[:],
+		Darknode:        attestation.Darknode.String(),
+		Signature:       attestation.Signature,
+		CommitmentsHash: attestation.CommitmentsHash[:],
+	}, nil
+}
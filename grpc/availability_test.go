@@ -0,0 +1,125 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/grpc"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/swarm"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Availability", func() {
+
+	var db *leveldb.Store
+	var serverMock *mockAvailabilityServer
+	var server *Server
+	var service AvailabilityService
+	var serviceAddr identity.Address
+	var client orderbook.AvailabilityClient
+
+	BeforeEach(func() {
+		var err error
+
+		serviceEcdsaKey, err := crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		serviceAddr = identity.Address(serviceEcdsaKey.Address())
+		serviceMultiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18514/republic/%v", serviceAddr))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		db, err = leveldb.NewStore("./tmp/availability.1.out", 10*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(db.SwarmMultiAddressStore().InsertMultiAddress(serviceMultiAddr)).ShouldNot(HaveOccurred())
+
+		clientAddr, err := testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		swarmer := swarm.NewSwarmer(NewSwarmClient(db.SwarmMultiAddressStore(), clientAddr), db.SwarmMultiAddressStore(), 1, nil)
+		client = NewAvailabilityClient(swarmer)
+
+		serverMock = &mockAvailabilityServer{
+			key:  serviceEcdsaKey,
+			addr: serviceAddr,
+		}
+		server = NewServer()
+		service = NewAvailabilityService(serverMock)
+		service.Register(server)
+
+		go server.Start("0.0.0.0:18514")
+		time.Sleep(time.Millisecond)
+	})
+
+	AfterEach(func() {
+		server.Stop()
+		db.Release()
+		os.RemoveAll("./tmp")
+	})
+
+	Context("when requesting a fragment attestation", func() {
+
+		It("should return the signed fragment attestation", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			serverMock.fragmentID = order.FragmentID(testutils.Random32Bytes())
+			serverMock.commitmentsHash = testutils.Random32Bytes()
+
+			attestation, err := client.AttestOrderFragment(context.Background(), serviceAddr, orderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(attestation.OrderID).Should(Equal(orderID))
+			Expect(attestation.FragmentID).Should(Equal(serverMock.fragmentID))
+			Expect(attestation.CommitmentsHash).Should(Equal(serverMock.commitmentsHash))
+			Expect(attestation.Darknode).Should(Equal(serviceAddr))
+			Expect(attestation.IsHeld()).Should(BeTrue())
+		})
+
+		It("should return an error for an attestation from a different darknode", func() {
+			var err error
+			serverMock.addr, err = testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = client.AttestOrderFragment(context.Background(), serviceAddr, order.ID(testutils.Random32Bytes()))
+			Expect(err).Should(Equal(orderbook.ErrInvalidFragmentAttestation))
+		})
+
+		It("should return an error for an attestation with an invalid signature", func() {
+			serverMock.tamper = true
+
+			_, err := client.AttestOrderFragment(context.Background(), serviceAddr, order.ID(testutils.Random32Bytes()))
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
+
+type mockAvailabilityServer struct {
+	key             crypto.EcdsaKey
+	addr            identity.Address
+	fragmentID      order.FragmentID
+	commitmentsHash [32]byte
+	tamper          bool
+}
+
+func (server *mockAvailabilityServer) AttestOrderFragment(ctx context.Context, orderID order.ID) (orderbook.FragmentAttestation, error) {
+	attestation := orderbook.FragmentAttestation{
+		OrderID:         orderID,
+		FragmentID:      server.fragmentID,
+		CommitmentsHash: server.commitmentsHash,
+		Darknode:        server.addr,
+	}
+	signature, err := server.key.Sign(attestation.Hash())
+	if err != nil {
+		return orderbook.FragmentAttestation{}, err
+	}
+	attestation.Signature = signature
+	if server.tamper {
+		attestation.FragmentID[0]++
+	}
+	return attestation, nil
+}
@@ -22,10 +22,20 @@ It has these top-level messages:
 	EncryptedCoExpShare
 	OrderFragmentCommitment
 	CoExpCommitment
+	OrderCoefficientCommitments
+	EncryptedOrderFragmentOpenings
 	StatusRequest
 	StatusResponse
 	UpdateMidpointRequest
 	UpdateMidpointResponse
+	AttestOrderFragmentRequest
+	AttestOrderFragmentResponse
+	OrderFragmentReceiptRequest
+	OrderFragmentReceiptResponse
+	OrderLifecycleRequest
+	OrderLifecycleResponse
+	OpenOrdersRequest
+	OpenOrdersAck
 */
 package grpc
 
@@ -121,6 +131,39 @@ func (x OrderSettlement) String() string {
 }
 func (OrderSettlement) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
+type OrderStage int32
+
+const (
+	OrderStage_Unknown   OrderStage = 0
+	OrderStage_Received  OrderStage = 1
+	OrderStage_InMatrix  OrderStage = 2
+	OrderStage_Matched   OrderStage = 3
+	OrderStage_Confirmed OrderStage = 4
+	OrderStage_Settled   OrderStage = 5
+)
+
+var OrderStage_name = map[int32]string{
+	0: "Unknown",
+	1: "Received",
+	2: "InMatrix",
+	3: "Matched",
+	4: "Confirmed",
+	5: "Settled",
+}
+var OrderStage_value = map[string]int32{
+	"Unknown":   0,
+	"Received":  1,
+	"InMatrix":  2,
+	"Matched":   3,
+	"Confirmed": 4,
+	"Settled":   5,
+}
+
+func (x OrderStage) String() string {
+	return proto.EnumName(OrderStage_name, int32(x))
+}
+func (OrderStage) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
 type MultiAddress struct {
 	Signature         []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	MultiAddress      string `protobuf:"bytes,2,opt,name=multiAddress" json:"multiAddress,omitempty"`
@@ -298,20 +341,22 @@ func (*OpenOrderResponse) ProtoMessage()               {}
 func (*OpenOrderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 type EncryptedOrderFragment struct {
-	OrderId         []byte                              `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
-	OrderType       OrderType                           `protobuf:"varint,2,opt,name=orderType,enum=grpc.OrderType" json:"orderType,omitempty"`
-	OrderParity     OrderParity                         `protobuf:"varint,3,opt,name=orderParity,enum=grpc.OrderParity" json:"orderParity,omitempty"`
-	OrderSettlement OrderSettlement                     `protobuf:"varint,4,opt,name=orderSettlement,enum=grpc.OrderSettlement" json:"orderSettlement,omitempty"`
-	OrderExpiry     int64                               `protobuf:"varint,5,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
-	Id              []byte                              `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
-	EpochDepth      int32                               `protobuf:"varint,7,opt,name=epochDepth" json:"epochDepth,omitempty"`
-	Tokens          []byte                              `protobuf:"bytes,8,opt,name=tokens,proto3" json:"tokens,omitempty"`
-	Price           *EncryptedCoExpShare                `protobuf:"bytes,9,opt,name=price" json:"price,omitempty"`
-	Volume          *EncryptedCoExpShare                `protobuf:"bytes,10,opt,name=volume" json:"volume,omitempty"`
-	MinimumVolume   *EncryptedCoExpShare                `protobuf:"bytes,11,opt,name=minimumVolume" json:"minimumVolume,omitempty"`
-	Nonce           []byte                              `protobuf:"bytes,12,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Blinding        []byte                              `protobuf:"bytes,13,opt,name=blinding,proto3" json:"blinding,omitempty"`
-	Commitments     map[uint64]*OrderFragmentCommitment `protobuf:"bytes,14,rep,name=commitments" json:"commitments,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OrderId                []byte                              `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	OrderType              OrderType                           `protobuf:"varint,2,opt,name=orderType,enum=grpc.OrderType" json:"orderType,omitempty"`
+	OrderParity            OrderParity                         `protobuf:"varint,3,opt,name=orderParity,enum=grpc.OrderParity" json:"orderParity,omitempty"`
+	OrderSettlement        OrderSettlement                     `protobuf:"varint,4,opt,name=orderSettlement,enum=grpc.OrderSettlement" json:"orderSettlement,omitempty"`
+	OrderExpiry            int64                               `protobuf:"varint,5,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
+	Id                     []byte                              `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
+	EpochDepth             int32                               `protobuf:"varint,7,opt,name=epochDepth" json:"epochDepth,omitempty"`
+	Tokens                 []byte                              `protobuf:"bytes,8,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Price                  *EncryptedCoExpShare                `protobuf:"bytes,9,opt,name=price" json:"price,omitempty"`
+	Volume                 *EncryptedCoExpShare                `protobuf:"bytes,10,opt,name=volume" json:"volume,omitempty"`
+	MinimumVolume          *EncryptedCoExpShare                `protobuf:"bytes,11,opt,name=minimumVolume" json:"minimumVolume,omitempty"`
+	Nonce                  []byte                              `protobuf:"bytes,12,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Blinding               []byte                              `protobuf:"bytes,13,opt,name=blinding,proto3" json:"blinding,omitempty"`
+	Commitments            map[uint64]*OrderFragmentCommitment `protobuf:"bytes,14,rep,name=commitments" json:"commitments,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CoefficientCommitments *OrderCoefficientCommitments        `protobuf:"bytes,15,opt,name=coefficientCommitments" json:"coefficientCommitments,omitempty"`
+	Openings               *EncryptedOrderFragmentOpenings     `protobuf:"bytes,16,opt,name=openings" json:"openings,omitempty"`
 }
 
 func (m *EncryptedOrderFragment) Reset()                    { *m = EncryptedOrderFragment{} }
@@ -417,6 +462,20 @@ func (m *EncryptedOrderFragment) GetCommitments() map[uint64]*OrderFragmentCommi
 	return nil
 }
 
+func (m *EncryptedOrderFragment) GetCoefficientCommitments() *OrderCoefficientCommitments {
+	if m != nil {
+		return m.CoefficientCommitments
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragment) GetOpenings() *EncryptedOrderFragmentOpenings {
+	if m != nil {
+		return m.Openings
+	}
+	return nil
+}
+
 type EncryptedCoExpShare struct {
 	Co  []byte `protobuf:"bytes,1,opt,name=co,proto3" json:"co,omitempty"`
 	Exp []byte `protobuf:"bytes,2,opt,name=exp,proto3" json:"exp,omitempty"`
@@ -448,6 +507,8 @@ type OrderFragmentCommitment struct {
 	VolumeExp        []byte `protobuf:"bytes,4,opt,name=volumeExp,proto3" json:"volumeExp,omitempty"`
 	MinimumVolumeCo  []byte `protobuf:"bytes,5,opt,name=minimumVolumeCo,proto3" json:"minimumVolumeCo,omitempty"`
 	MinimumVolumeExp []byte `protobuf:"bytes,6,opt,name=minimumVolumeExp,proto3" json:"minimumVolumeExp,omitempty"`
+	Tokens           []byte `protobuf:"bytes,7,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Nonce            []byte `protobuf:"bytes,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
 }
 
 func (m *OrderFragmentCommitment) Reset()                    { *m = OrderFragmentCommitment{} }
@@ -497,6 +558,20 @@ func (m *OrderFragmentCommitment) GetMinimumVolumeExp() []byte {
 	return nil
 }
 
+func (m *OrderFragmentCommitment) GetTokens() []byte {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *OrderFragmentCommitment) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
 type CoExpCommitment struct {
 	Co  []byte `protobuf:"bytes,1,opt,name=co,proto3" json:"co,omitempty"`
 	Exp []byte `protobuf:"bytes,2,opt,name=exp,proto3" json:"exp,omitempty"`
@@ -521,13 +596,159 @@ func (m *CoExpCommitment) GetExp() []byte {
 	return nil
 }
 
+type OrderCoefficientCommitments struct {
+	Tokens           [][]byte `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	PriceCo          [][]byte `protobuf:"bytes,2,rep,name=priceCo,proto3" json:"priceCo,omitempty"`
+	PriceExp         [][]byte `protobuf:"bytes,3,rep,name=priceExp,proto3" json:"priceExp,omitempty"`
+	VolumeCo         [][]byte `protobuf:"bytes,4,rep,name=volumeCo,proto3" json:"volumeCo,omitempty"`
+	VolumeExp        [][]byte `protobuf:"bytes,5,rep,name=volumeExp,proto3" json:"volumeExp,omitempty"`
+	MinimumVolumeCo  [][]byte `protobuf:"bytes,6,rep,name=minimumVolumeCo,proto3" json:"minimumVolumeCo,omitempty"`
+	MinimumVolumeExp [][]byte `protobuf:"bytes,7,rep,name=minimumVolumeExp,proto3" json:"minimumVolumeExp,omitempty"`
+	Nonce            [][]byte `protobuf:"bytes,8,rep,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *OrderCoefficientCommitments) Reset()                    { *m = OrderCoefficientCommitments{} }
+func (m *OrderCoefficientCommitments) String() string            { return proto.CompactTextString(m) }
+func (*OrderCoefficientCommitments) ProtoMessage()               {}
+func (*OrderCoefficientCommitments) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *OrderCoefficientCommitments) GetTokens() [][]byte {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetPriceCo() [][]byte {
+	if m != nil {
+		return m.PriceCo
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetPriceExp() [][]byte {
+	if m != nil {
+		return m.PriceExp
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetVolumeCo() [][]byte {
+	if m != nil {
+		return m.VolumeCo
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetVolumeExp() [][]byte {
+	if m != nil {
+		return m.VolumeExp
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetMinimumVolumeCo() [][]byte {
+	if m != nil {
+		return m.MinimumVolumeCo
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetMinimumVolumeExp() [][]byte {
+	if m != nil {
+		return m.MinimumVolumeExp
+	}
+	return nil
+}
+
+func (m *OrderCoefficientCommitments) GetNonce() [][]byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+type EncryptedOrderFragmentOpenings struct {
+	Tokens           []byte `protobuf:"bytes,1,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	PriceCo          []byte `protobuf:"bytes,2,opt,name=priceCo,proto3" json:"priceCo,omitempty"`
+	PriceExp         []byte `protobuf:"bytes,3,opt,name=priceExp,proto3" json:"priceExp,omitempty"`
+	VolumeCo         []byte `protobuf:"bytes,4,opt,name=volumeCo,proto3" json:"volumeCo,omitempty"`
+	VolumeExp        []byte `protobuf:"bytes,5,opt,name=volumeExp,proto3" json:"volumeExp,omitempty"`
+	MinimumVolumeCo  []byte `protobuf:"bytes,6,opt,name=minimumVolumeCo,proto3" json:"minimumVolumeCo,omitempty"`
+	MinimumVolumeExp []byte `protobuf:"bytes,7,opt,name=minimumVolumeExp,proto3" json:"minimumVolumeExp,omitempty"`
+	Nonce            []byte `protobuf:"bytes,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (m *EncryptedOrderFragmentOpenings) Reset()         { *m = EncryptedOrderFragmentOpenings{} }
+func (m *EncryptedOrderFragmentOpenings) String() string { return proto.CompactTextString(m) }
+func (*EncryptedOrderFragmentOpenings) ProtoMessage()    {}
+func (*EncryptedOrderFragmentOpenings) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{15}
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetTokens() []byte {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetPriceCo() []byte {
+	if m != nil {
+		return m.PriceCo
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetPriceExp() []byte {
+	if m != nil {
+		return m.PriceExp
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetVolumeCo() []byte {
+	if m != nil {
+		return m.VolumeCo
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetVolumeExp() []byte {
+	if m != nil {
+		return m.VolumeExp
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetMinimumVolumeCo() []byte {
+	if m != nil {
+		return m.MinimumVolumeCo
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetMinimumVolumeExp() []byte {
+	if m != nil {
+		return m.MinimumVolumeExp
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragmentOpenings) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
 type StatusRequest struct {
 }
 
 func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
 func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*StatusRequest) ProtoMessage()               {}
-func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 type StatusResponse struct {
 	Address      string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
@@ -538,7 +759,7 @@ type StatusResponse struct {
 func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
 func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*StatusResponse) ProtoMessage()               {}
-func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *StatusResponse) GetAddress() string {
 	if m != nil {
@@ -570,7 +791,7 @@ type UpdateMidpointRequest struct {
 func (m *UpdateMidpointRequest) Reset()                    { *m = UpdateMidpointRequest{} }
 func (m *UpdateMidpointRequest) String() string            { return proto.CompactTextString(m) }
 func (*UpdateMidpointRequest) ProtoMessage()               {}
-func (*UpdateMidpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*UpdateMidpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *UpdateMidpointRequest) GetSignature() []byte {
 	if m != nil {
@@ -599,7 +820,247 @@ type UpdateMidpointResponse struct {
 func (m *UpdateMidpointResponse) Reset()                    { *m = UpdateMidpointResponse{} }
 func (m *UpdateMidpointResponse) String() string            { return proto.CompactTextString(m) }
 func (*UpdateMidpointResponse) ProtoMessage()               {}
-func (*UpdateMidpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*UpdateMidpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+type AttestOrderFragmentRequest struct {
+	OrderId []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+}
+
+func (m *AttestOrderFragmentRequest) Reset()                    { *m = AttestOrderFragmentRequest{} }
+func (m *AttestOrderFragmentRequest) String() string            { return proto.CompactTextString(m) }
+func (*AttestOrderFragmentRequest) ProtoMessage()               {}
+func (*AttestOrderFragmentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *AttestOrderFragmentRequest) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+type AttestOrderFragmentResponse struct {
+	OrderId         []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	FragmentId      []byte `protobuf:"bytes,2,opt,name=fragmentId,proto3" json:"fragmentId,omitempty"`
+	Darknode        string `protobuf:"bytes,3,opt,name=darknode" json:"darknode,omitempty"`
+	Signature       []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	CommitmentsHash []byte `protobuf:"bytes,5,opt,name=commitmentsHash,proto3" json:"commitmentsHash,omitempty"`
+}
+
+func (m *AttestOrderFragmentResponse) Reset()                    { *m = AttestOrderFragmentResponse{} }
+func (m *AttestOrderFragmentResponse) String() string            { return proto.CompactTextString(m) }
+func (*AttestOrderFragmentResponse) ProtoMessage()               {}
+func (*AttestOrderFragmentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *AttestOrderFragmentResponse) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *AttestOrderFragmentResponse) GetFragmentId() []byte {
+	if m != nil {
+		return m.FragmentId
+	}
+	return nil
+}
+
+func (m *AttestOrderFragmentResponse) GetDarknode() string {
+	if m != nil {
+		return m.Darknode
+	}
+	return ""
+}
+
+func (m *AttestOrderFragmentResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *AttestOrderFragmentResponse) GetCommitmentsHash() []byte {
+	if m != nil {
+		return m.CommitmentsHash
+	}
+	return nil
+}
+
+type OrderFragmentReceiptRequest struct {
+	OrderId []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+}
+
+func (m *OrderFragmentReceiptRequest) Reset()                    { *m = OrderFragmentReceiptRequest{} }
+func (m *OrderFragmentReceiptRequest) String() string            { return proto.CompactTextString(m) }
+func (*OrderFragmentReceiptRequest) ProtoMessage()               {}
+func (*OrderFragmentReceiptRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *OrderFragmentReceiptRequest) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+type OrderFragmentReceiptResponse struct {
+	OrderId    []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	FragmentId []byte `protobuf:"bytes,2,opt,name=fragmentId,proto3" json:"fragmentId,omitempty"`
+	EpochDepth int32  `protobuf:"varint,3,opt,name=epochDepth" json:"epochDepth,omitempty"`
+	Decrypted  bool   `protobuf:"varint,4,opt,name=decrypted" json:"decrypted,omitempty"`
+	Darknode   string `protobuf:"bytes,5,opt,name=darknode" json:"darknode,omitempty"`
+	Signature  []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *OrderFragmentReceiptResponse) Reset()                    { *m = OrderFragmentReceiptResponse{} }
+func (m *OrderFragmentReceiptResponse) String() string            { return proto.CompactTextString(m) }
+func (*OrderFragmentReceiptResponse) ProtoMessage()               {}
+func (*OrderFragmentReceiptResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *OrderFragmentReceiptResponse) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *OrderFragmentReceiptResponse) GetFragmentId() []byte {
+	if m != nil {
+		return m.FragmentId
+	}
+	return nil
+}
+
+func (m *OrderFragmentReceiptResponse) GetEpochDepth() int32 {
+	if m != nil {
+		return m.EpochDepth
+	}
+	return 0
+}
+
+func (m *OrderFragmentReceiptResponse) GetDecrypted() bool {
+	if m != nil {
+		return m.Decrypted
+	}
+	return false
+}
+
+func (m *OrderFragmentReceiptResponse) GetDarknode() string {
+	if m != nil {
+		return m.Darknode
+	}
+	return ""
+}
+
+func (m *OrderFragmentReceiptResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type OrderLifecycleRequest struct {
+	OrderId []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+}
+
+func (m *OrderLifecycleRequest) Reset()                    { *m = OrderLifecycleRequest{} }
+func (m *OrderLifecycleRequest) String() string            { return proto.CompactTextString(m) }
+func (*OrderLifecycleRequest) ProtoMessage()               {}
+func (*OrderLifecycleRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *OrderLifecycleRequest) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+type OrderLifecycleResponse struct {
+	OrderId       []byte     `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Stage         OrderStage `protobuf:"varint,2,opt,name=stage,enum=grpc.OrderStage" json:"stage,omitempty"`
+	ComputationId []byte     `protobuf:"bytes,3,opt,name=computationId,proto3" json:"computationId,omitempty"`
+}
+
+func (m *OrderLifecycleResponse) Reset()                    { *m = OrderLifecycleResponse{} }
+func (m *OrderLifecycleResponse) String() string            { return proto.CompactTextString(m) }
+func (*OrderLifecycleResponse) ProtoMessage()               {}
+func (*OrderLifecycleResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *OrderLifecycleResponse) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *OrderLifecycleResponse) GetStage() OrderStage {
+	if m != nil {
+		return m.Stage
+	}
+	return OrderStage_Unknown
+}
+
+func (m *OrderLifecycleResponse) GetComputationId() []byte {
+	if m != nil {
+		return m.ComputationId
+	}
+	return nil
+}
+
+type OpenOrdersRequest struct {
+	OrderFragment *EncryptedOrderFragment `protobuf:"bytes,1,opt,name=orderFragment" json:"orderFragment,omitempty"`
+	Signature     []byte                  `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *OpenOrdersRequest) Reset()                    { *m = OpenOrdersRequest{} }
+func (m *OpenOrdersRequest) String() string            { return proto.CompactTextString(m) }
+func (*OpenOrdersRequest) ProtoMessage()               {}
+func (*OpenOrdersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *OpenOrdersRequest) GetOrderFragment() *EncryptedOrderFragment {
+	if m != nil {
+		return m.OrderFragment
+	}
+	return nil
+}
+
+func (m *OpenOrdersRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type OpenOrdersAck struct {
+	OrderId    []byte `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	FragmentId []byte `protobuf:"bytes,2,opt,name=fragmentId,proto3" json:"fragmentId,omitempty"`
+	Error      string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *OpenOrdersAck) Reset()                    { *m = OpenOrdersAck{} }
+func (m *OpenOrdersAck) String() string            { return proto.CompactTextString(m) }
+func (*OpenOrdersAck) ProtoMessage()               {}
+func (*OpenOrdersAck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *OpenOrdersAck) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *OpenOrdersAck) GetFragmentId() []byte {
+	if m != nil {
+		return m.FragmentId
+	}
+	return nil
+}
+
+func (m *OpenOrdersAck) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
 
 func init() {
 	proto.RegisterType((*MultiAddress)(nil), "grpc.MultiAddress")
@@ -616,13 +1077,24 @@ func init() {
 	proto.RegisterType((*EncryptedCoExpShare)(nil), "grpc.EncryptedCoExpShare")
 	proto.RegisterType((*OrderFragmentCommitment)(nil), "grpc.OrderFragmentCommitment")
 	proto.RegisterType((*CoExpCommitment)(nil), "grpc.CoExpCommitment")
+	proto.RegisterType((*OrderCoefficientCommitments)(nil), "grpc.OrderCoefficientCommitments")
+	proto.RegisterType((*EncryptedOrderFragmentOpenings)(nil), "grpc.EncryptedOrderFragmentOpenings")
 	proto.RegisterType((*StatusRequest)(nil), "grpc.StatusRequest")
 	proto.RegisterType((*StatusResponse)(nil), "grpc.StatusResponse")
 	proto.RegisterType((*UpdateMidpointRequest)(nil), "grpc.UpdateMidpointRequest")
 	proto.RegisterType((*UpdateMidpointResponse)(nil), "grpc.UpdateMidpointResponse")
+	proto.RegisterType((*AttestOrderFragmentRequest)(nil), "grpc.AttestOrderFragmentRequest")
+	proto.RegisterType((*AttestOrderFragmentResponse)(nil), "grpc.AttestOrderFragmentResponse")
+	proto.RegisterType((*OrderFragmentReceiptRequest)(nil), "grpc.OrderFragmentReceiptRequest")
+	proto.RegisterType((*OrderFragmentReceiptResponse)(nil), "grpc.OrderFragmentReceiptResponse")
+	proto.RegisterType((*OrderLifecycleRequest)(nil), "grpc.OrderLifecycleRequest")
+	proto.RegisterType((*OrderLifecycleResponse)(nil), "grpc.OrderLifecycleResponse")
+	proto.RegisterType((*OpenOrdersRequest)(nil), "grpc.OpenOrdersRequest")
+	proto.RegisterType((*OpenOrdersAck)(nil), "grpc.OpenOrdersAck")
 	proto.RegisterEnum("grpc.OrderType", OrderType_name, OrderType_value)
 	proto.RegisterEnum("grpc.OrderParity", OrderParity_name, OrderParity_value)
 	proto.RegisterEnum("grpc.OrderSettlement", OrderSettlement_name, OrderSettlement_value)
+	proto.RegisterEnum("grpc.OrderStage", OrderStage_name, OrderStage_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -863,6 +1335,9 @@ var _StreamService_serviceDesc = grpc1.ServiceDesc{
 
 type OrderbookServiceClient interface {
 	OpenOrder(ctx context.Context, in *OpenOrderRequest, opts ...grpc1.CallOption) (*OpenOrderResponse, error)
+	OrderFragmentReceipt(ctx context.Context, in *OrderFragmentReceiptRequest, opts ...grpc1.CallOption) (*OrderFragmentReceiptResponse, error)
+	OrderLifecycle(ctx context.Context, in *OrderLifecycleRequest, opts ...grpc1.CallOption) (*OrderLifecycleResponse, error)
+	OpenOrders(ctx context.Context, opts ...grpc1.CallOption) (OrderbookService_OpenOrdersClient, error)
 }
 
 type orderbookServiceClient struct {
@@ -882,10 +1357,62 @@ func (c *orderbookServiceClient) OpenOrder(ctx context.Context, in *OpenOrderReq
 	return out, nil
 }
 
+func (c *orderbookServiceClient) OrderFragmentReceipt(ctx context.Context, in *OrderFragmentReceiptRequest, opts ...grpc1.CallOption) (*OrderFragmentReceiptResponse, error) {
+	out := new(OrderFragmentReceiptResponse)
+	err := grpc1.Invoke(ctx, "/grpc.OrderbookService/OrderFragmentReceipt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) OrderLifecycle(ctx context.Context, in *OrderLifecycleRequest, opts ...grpc1.CallOption) (*OrderLifecycleResponse, error) {
+	out := new(OrderLifecycleResponse)
+	err := grpc1.Invoke(ctx, "/grpc.OrderbookService/OrderLifecycle", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) OpenOrders(ctx context.Context, opts ...grpc1.CallOption) (OrderbookService_OpenOrdersClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_OrderbookService_serviceDesc.Streams[0], c.cc, "/grpc.OrderbookService/OpenOrders", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderbookServiceOpenOrdersClient{stream}
+	return x, nil
+}
+
+type OrderbookService_OpenOrdersClient interface {
+	Send(*OpenOrdersRequest) error
+	Recv() (*OpenOrdersAck, error)
+	grpc1.ClientStream
+}
+
+type orderbookServiceOpenOrdersClient struct {
+	grpc1.ClientStream
+}
+
+func (x *orderbookServiceOpenOrdersClient) Send(m *OpenOrdersRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *orderbookServiceOpenOrdersClient) Recv() (*OpenOrdersAck, error) {
+	m := new(OpenOrdersAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for OrderbookService service
 
 type OrderbookServiceServer interface {
 	OpenOrder(context.Context, *OpenOrderRequest) (*OpenOrderResponse, error)
+	OrderFragmentReceipt(context.Context, *OrderFragmentReceiptRequest) (*OrderFragmentReceiptResponse, error)
+	OrderLifecycle(context.Context, *OrderLifecycleRequest) (*OrderLifecycleResponse, error)
+	OpenOrders(OrderbookService_OpenOrdersServer) error
 }
 
 func RegisterOrderbookServiceServer(s *grpc1.Server, srv OrderbookServiceServer) {
@@ -910,6 +1437,68 @@ func _OrderbookService_OpenOrder_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderbookService_OrderFragmentReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderFragmentReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).OrderFragmentReceipt(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.OrderbookService/OrderFragmentReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).OrderFragmentReceipt(ctx, req.(*OrderFragmentReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_OrderLifecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderLifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).OrderLifecycle(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.OrderbookService/OrderLifecycle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).OrderLifecycle(ctx, req.(*OrderLifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_OpenOrders_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	return srv.(OrderbookServiceServer).OpenOrders(&orderbookServiceOpenOrdersServer{stream})
+}
+
+type OrderbookService_OpenOrdersServer interface {
+	Send(*OpenOrdersAck) error
+	Recv() (*OpenOrdersRequest, error)
+	grpc1.ServerStream
+}
+
+type orderbookServiceOpenOrdersServer struct {
+	grpc1.ServerStream
+}
+
+func (x *orderbookServiceOpenOrdersServer) Send(m *OpenOrdersAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *orderbookServiceOpenOrdersServer) Recv() (*OpenOrdersRequest, error) {
+	m := new(OpenOrdersRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _OrderbookService_serviceDesc = grpc1.ServiceDesc{
 	ServiceName: "grpc.OrderbookService",
 	HandlerType: (*OrderbookServiceServer)(nil),
@@ -918,8 +1507,23 @@ var _OrderbookService_serviceDesc = grpc1.ServiceDesc{
 			MethodName: "OpenOrder",
 			Handler:    _OrderbookService_OpenOrder_Handler,
 		},
+		{
+			MethodName: "OrderFragmentReceipt",
+			Handler:    _OrderbookService_OrderFragmentReceipt_Handler,
+		},
+		{
+			MethodName: "OrderLifecycle",
+			Handler:    _OrderbookService_OrderLifecycle_Handler,
+		},
+	},
+	Streams: []grpc1.StreamDesc{
+		{
+			StreamName:    "OpenOrders",
+			Handler:       _OrderbookService_OpenOrders_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc1.StreamDesc{},
 	Metadata: "grpc.proto",
 }
 
@@ -1051,75 +1655,170 @@ var _OracleService_serviceDesc = grpc1.ServiceDesc{
 	Metadata: "grpc.proto",
 }
 
+// Client API for AvailabilityService service
+
+type AvailabilityServiceClient interface {
+	AttestOrderFragment(ctx context.Context, in *AttestOrderFragmentRequest, opts ...grpc1.CallOption) (*AttestOrderFragmentResponse, error)
+}
+
+type availabilityServiceClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewAvailabilityServiceClient(cc *grpc1.ClientConn) AvailabilityServiceClient {
+	return &availabilityServiceClient{cc}
+}
+
+func (c *availabilityServiceClient) AttestOrderFragment(ctx context.Context, in *AttestOrderFragmentRequest, opts ...grpc1.CallOption) (*AttestOrderFragmentResponse, error) {
+	out := new(AttestOrderFragmentResponse)
+	err := grpc1.Invoke(ctx, "/grpc.AvailabilityService/AttestOrderFragment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for AvailabilityService service
+
+type AvailabilityServiceServer interface {
+	AttestOrderFragment(context.Context, *AttestOrderFragmentRequest) (*AttestOrderFragmentResponse, error)
+}
+
+func RegisterAvailabilityServiceServer(s *grpc1.Server, srv AvailabilityServiceServer) {
+	s.RegisterService(&_AvailabilityService_serviceDesc, srv)
+}
+
+func _AvailabilityService_AttestOrderFragment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttestOrderFragmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AvailabilityServiceServer).AttestOrderFragment(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.AvailabilityService/AttestOrderFragment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AvailabilityServiceServer).AttestOrderFragment(ctx, req.(*AttestOrderFragmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AvailabilityService_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "grpc.AvailabilityService",
+	HandlerType: (*AvailabilityServiceServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "AttestOrderFragment",
+			Handler:    _AvailabilityService_AttestOrderFragment_Handler,
+		},
+	},
+	Streams:  []grpc1.StreamDesc{},
+	Metadata: "grpc.proto",
+}
+
 func init() { proto.RegisterFile("grpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1059 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x6f, 0xe3, 0x44,
-	0x10, 0x3f, 0xe7, 0x5f, 0x9b, 0x89, 0x93, 0xb8, 0xd3, 0x5e, 0xcf, 0x84, 0x82, 0x22, 0xbf, 0x10,
-	0x55, 0xb4, 0x1c, 0xa9, 0x74, 0x07, 0x27, 0xa4, 0xea, 0x2e, 0x97, 0x13, 0xa8, 0xf4, 0x52, 0x36,
-	0x70, 0x4f, 0x20, 0xe4, 0xc6, 0xab, 0xd4, 0x6a, 0xec, 0x35, 0xeb, 0x4d, 0xaf, 0x79, 0xe1, 0xa3,
-	0xf0, 0x75, 0xe0, 0x5b, 0xf0, 0x55, 0xd0, 0xee, 0xda, 0xc9, 0x3a, 0x4d, 0xdb, 0x17, 0xde, 0x76,
-	0x66, 0x7e, 0xf3, 0xf3, 0xec, 0xcc, 0xec, 0x8c, 0x01, 0xa6, 0x3c, 0x99, 0x1c, 0x27, 0x9c, 0x09,
-	0x86, 0x15, 0x79, 0xf6, 0xfe, 0x04, 0xfb, 0x7c, 0x3e, 0x13, 0xe1, 0xeb, 0x20, 0xe0, 0x34, 0x4d,
-	0xf1, 0x00, 0xea, 0x69, 0x38, 0x8d, 0x7d, 0x31, 0xe7, 0xd4, 0xb5, 0xba, 0x56, 0xcf, 0x26, 0x2b,
-	0x05, 0x7a, 0x60, 0x47, 0x06, 0xda, 0x2d, 0x75, 0xad, 0x5e, 0x9d, 0x14, 0x74, 0xf8, 0x25, 0xec,
-	0x98, 0xf2, 0x7b, 0x16, 0x4f, 0xa8, 0x5b, 0xee, 0x5a, 0xbd, 0x0a, 0xb9, 0x6b, 0xf0, 0x86, 0xd0,
-	0xb8, 0x08, 0xe3, 0x29, 0xa1, 0x7f, 0xcc, 0x69, 0x2a, 0xf0, 0xc5, 0xda, 0x07, 0x64, 0x04, 0x8d,
-	0x3e, 0x1e, 0xab, 0xb8, 0xcd, 0x40, 0x8b, 0x1f, 0xf5, 0x5a, 0x60, 0x6b, 0x9a, 0x34, 0x61, 0x71,
-	0xaa, 0x69, 0xd9, 0xff, 0x43, 0xcb, 0x0c, 0xda, 0x1e, 0xd8, 0x3f, 0xcd, 0x29, 0x5f, 0xe4, 0xbc,
-	0x2e, 0x6c, 0xf9, 0x06, 0x65, 0x9d, 0xe4, 0xa2, 0x77, 0x06, 0xcd, 0x0c, 0xa9, 0x5d, 0xf1, 0x15,
-	0xb4, 0x4c, 0x6a, 0x2a, 0x3d, 0xca, 0xf7, 0x04, 0xb1, 0x86, 0xf4, 0xe6, 0xd0, 0x1c, 0x0b, 0x4e,
-	0xfd, 0xe8, 0x9c, 0xa6, 0xa9, 0x3f, 0xa5, 0x8f, 0x54, 0xc9, 0x88, 0xaa, 0x54, 0x88, 0x4a, 0x5a,
-	0x62, 0x2a, 0x3e, 0x32, 0x7e, 0xad, 0x2a, 0x62, 0x93, 0x5c, 0x44, 0x84, 0x4a, 0xe0, 0x0b, 0xdf,
-	0xad, 0x28, 0xb5, 0x3a, 0x7b, 0x1f, 0xc0, 0x19, 0x25, 0x34, 0x1e, 0xf1, 0x80, 0xf2, 0xfc, 0xc6,
-	0x6f, 0xa0, 0xc9, 0xa4, 0xfc, 0x8e, 0xfb, 0xd3, 0x88, 0xc6, 0x22, 0x4b, 0xe5, 0x81, 0xbe, 0xc5,
-	0x30, 0x9e, 0xf0, 0x45, 0x22, 0x68, 0x30, 0x32, 0x31, 0xa4, 0xe8, 0xe2, 0xed, 0xc2, 0x8e, 0xc1,
-	0x9b, 0xa5, 0xf6, 0x9f, 0x2a, 0xec, 0x6f, 0x76, 0x97, 0x51, 0x2b, 0x82, 0x1f, 0x82, 0xec, 0xae,
-	0xb9, 0x88, 0x47, 0x50, 0x57, 0xc7, 0x9f, 0x17, 0x09, 0x55, 0x77, 0x6d, 0xf5, 0xdb, 0x3a, 0x92,
-	0x51, 0xae, 0x26, 0x2b, 0x04, 0x9e, 0x40, 0x43, 0x09, 0x17, 0x3e, 0x0f, 0xc5, 0x42, 0xa5, 0xa0,
-	0xd5, 0xdf, 0x31, 0x1c, 0xb4, 0x81, 0x98, 0x28, 0x3c, 0x85, 0xb6, 0x12, 0xc7, 0x54, 0x88, 0x19,
-	0x55, 0x77, 0xae, 0x28, 0xc7, 0xa7, 0x86, 0xe3, 0xca, 0x48, 0xd6, 0xd1, 0xd8, 0xcd, 0xbe, 0x3a,
-	0xbc, 0x4d, 0x42, 0xbe, 0x70, 0xab, 0x5d, 0xab, 0x57, 0x26, 0xa6, 0x0a, 0x5b, 0x50, 0x0a, 0x03,
-	0xb7, 0xa6, 0xee, 0x56, 0x0a, 0x03, 0xfc, 0x1c, 0x80, 0x26, 0x6c, 0x72, 0xf5, 0x96, 0x26, 0xe2,
-	0xca, 0xdd, 0xea, 0x5a, 0xbd, 0x2a, 0x31, 0x34, 0xb8, 0x0f, 0x35, 0xc1, 0xae, 0x69, 0x9c, 0xba,
-	0xdb, 0xca, 0x27, 0x93, 0xf0, 0x2b, 0xa8, 0x26, 0x3c, 0x9c, 0x50, 0xb7, 0xae, 0x8a, 0xf2, 0xc9,
-	0x5a, 0x51, 0x06, 0x6c, 0x78, 0x9b, 0x8c, 0xaf, 0x7c, 0x4e, 0x89, 0xc6, 0xe1, 0xd7, 0x50, 0xbb,
-	0x61, 0xb3, 0x79, 0x44, 0x5d, 0x78, 0xcc, 0x23, 0x03, 0xe2, 0x29, 0x34, 0xa3, 0x30, 0x0e, 0xa3,
-	0x79, 0xf4, 0x41, 0x7b, 0x36, 0x1e, 0xf3, 0x2c, 0xe2, 0x71, 0x0f, 0xaa, 0xb1, 0x9a, 0x09, 0xb6,
-	0x8a, 0x5d, 0x0b, 0xd8, 0x81, 0xed, 0xcb, 0x59, 0x18, 0x07, 0x61, 0x3c, 0x75, 0x9b, 0xca, 0xb0,
-	0x94, 0x71, 0x04, 0x8d, 0x09, 0x8b, 0xa2, 0x50, 0xc8, 0x74, 0xa6, 0x6e, 0x4b, 0xbd, 0x9b, 0xa3,
-	0x87, 0x3a, 0xee, 0x78, 0xb0, 0xc2, 0x0f, 0x63, 0xc1, 0x17, 0xc4, 0x64, 0xe8, 0xfc, 0x06, 0xce,
-	0x3a, 0x00, 0x1d, 0x28, 0x5f, 0xd3, 0x85, 0x6a, 0xb0, 0x0a, 0x91, 0x47, 0x3c, 0x81, 0xea, 0x8d,
-	0x3f, 0x9b, 0xeb, 0xc6, 0x6a, 0xf4, 0x3f, 0x33, 0xca, 0x9d, 0x7f, 0x67, 0xc5, 0x42, 0x34, 0xf6,
-	0x55, 0xe9, 0x1b, 0xcb, 0x7b, 0x09, 0xbb, 0x1b, 0xf2, 0x20, 0xab, 0x3c, 0x61, 0x59, 0x07, 0x97,
-	0x26, 0x4c, 0x7e, 0x91, 0xde, 0x26, 0x8a, 0xdd, 0x26, 0xf2, 0xe8, 0xfd, 0x6b, 0xc1, 0xb3, 0x7b,
-	0xf8, 0xe5, 0x23, 0x50, 0x35, 0x1b, 0xe4, 0x14, 0xb9, 0x28, 0x53, 0xa7, 0x8e, 0xc3, 0x25, 0xd9,
-	0x52, 0x96, 0x36, 0x5d, 0xb7, 0x01, 0xcb, 0x5e, 0xfc, 0x52, 0x96, 0x43, 0x44, 0x9f, 0xa5, 0xa3,
-	0x7e, 0xf7, 0x2b, 0x05, 0xf6, 0xa0, 0x5d, 0xa8, 0xdb, 0x80, 0xa9, 0xce, 0xb5, 0xc9, 0xba, 0x1a,
-	0x0f, 0xc1, 0x29, 0xa8, 0x24, 0x9d, 0xee, 0xe5, 0x3b, 0x7a, 0xef, 0x04, 0xda, 0x2a, 0x23, 0xc6,
-	0xc5, 0x1e, 0x4f, 0x4b, 0x5b, 0x8e, 0x3f, 0x5f, 0xcc, 0xd3, 0x6c, 0x08, 0x79, 0x01, 0xb4, 0x72,
-	0x45, 0x36, 0x5d, 0xef, 0x1d, 0xc4, 0x72, 0x65, 0x5d, 0x32, 0x26, 0x52, 0xc1, 0xfd, 0x24, 0xa1,
-	0x81, 0xe2, 0xdd, 0x26, 0x05, 0x9d, 0x6c, 0xc9, 0x84, 0x52, 0x9e, 0xaa, 0x14, 0x95, 0x89, 0x16,
-	0xbc, 0xbf, 0x2d, 0x78, 0xfa, 0x4b, 0x12, 0xf8, 0x82, 0x9e, 0x87, 0x41, 0xc2, 0xc2, 0x58, 0xe4,
-	0x43, 0xf0, 0xe1, 0xf1, 0x7b, 0x0a, 0x35, 0x95, 0x7f, 0x39, 0x7d, 0x65, 0xa7, 0x7e, 0xa1, 0x1b,
-	0x67, 0x23, 0xd5, 0xf1, 0x85, 0x42, 0xea, 0x1e, 0xcd, 0xdc, 0x56, 0x2f, 0x44, 0x6f, 0x4d, 0x2d,
-	0x74, 0xbe, 0x85, 0x86, 0x01, 0xde, 0xd0, 0xaf, 0x7b, 0x66, 0xbf, 0x56, 0xcc, 0x86, 0x74, 0x61,
-	0x7f, 0xfd, 0xeb, 0x3a, 0x6f, 0x87, 0x43, 0xa8, 0x2f, 0x27, 0x25, 0xda, 0xb0, 0x9d, 0x03, 0x9c,
-	0x27, 0x58, 0x87, 0xea, 0x8f, 0x61, 0x14, 0x0a, 0xc7, 0x42, 0x07, 0xec, 0xdc, 0xf0, 0xfb, 0xbb,
-	0xd1, 0x99, 0x53, 0xc2, 0x26, 0xd4, 0x95, 0x51, 0x89, 0xe5, 0xc3, 0x2e, 0x34, 0x8c, 0xf9, 0x89,
-	0x5b, 0x50, 0x7e, 0x33, 0x5f, 0x38, 0x4f, 0x70, 0x1b, 0x2a, 0x63, 0x3a, 0x9b, 0x39, 0xd6, 0xe1,
-	0x0b, 0x68, 0xaf, 0x0d, 0x4a, 0x89, 0x7a, 0x1f, 0xce, 0xf4, 0x97, 0x08, 0x8d, 0x87, 0xb7, 0x8e,
-	0x85, 0x6d, 0x68, 0xa8, 0xe3, 0x6b, 0xc1, 0xa2, 0x70, 0xe2, 0x94, 0xfa, 0x7f, 0x59, 0x60, 0x8f,
-	0x3f, 0xfa, 0x3c, 0x1a, 0x53, 0x7e, 0x23, 0x47, 0xd6, 0x11, 0x54, 0xe4, 0xa6, 0xc7, 0x6c, 0x6c,
-	0x1b, 0x3f, 0x0f, 0x1d, 0x34, 0x55, 0x59, 0x63, 0x48, 0x38, 0x33, 0xe0, 0xec, 0x2e, 0xdc, 0x58,
-	0xf0, 0xf8, 0x1c, 0xaa, 0x6a, 0x6d, 0x63, 0x66, 0x34, 0xb7, 0x7d, 0x67, 0xb7, 0xa0, 0xd3, 0x1e,
-	0xfd, 0xef, 0xf3, 0xdd, 0x9c, 0x07, 0xf8, 0x12, 0xb6, 0x06, 0x2c, 0x8e, 0xe9, 0x44, 0x60, 0xe6,
-	0x50, 0xd8, 0xdd, 0x9d, 0x4d, 0xca, 0x9e, 0xf5, 0xdc, 0xea, 0x5f, 0x80, 0xa3, 0x52, 0x74, 0xc9,
-	0xd8, 0x75, 0x4e, 0xf6, 0x1d, 0xd4, 0x97, 0xab, 0x12, 0xf7, 0xb3, 0x09, 0xb4, 0xb6, 0x93, 0x3b,
-	0xcf, 0xee, 0xe8, 0xb3, 0xd8, 0xde, 0xe6, 0x0f, 0x27, 0xa7, 0x3b, 0x81, 0x9a, 0x56, 0xac, 0x42,
-	0x33, 0xde, 0x55, 0x67, 0xaf, 0xa8, 0xcc, 0x58, 0x7e, 0x85, 0xe6, 0x88, 0xfb, 0x93, 0x19, 0xcd,
-	0x59, 0xce, 0xa0, 0x55, 0x6c, 0x27, 0xfc, 0xf4, 0x81, 0x16, 0xef, 0x1c, 0x6c, 0x36, 0x6a, 0xf6,
-	0xcb, 0x9a, 0xfa, 0x1b, 0x3d, 0xf9, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xe8, 0xd5, 0xd1, 0x24, 0x9b,
-	0x0a, 0x00, 0x00,
+	// 1568 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xdd, 0x6e, 0xdb, 0x46,
+	0x16, 0x0e, 0xf5, 0x67, 0xe9, 0xe8, 0x8f, 0x19, 0x3b, 0x0e, 0x57, 0xf6, 0x06, 0x0a, 0x11, 0xec,
+	0x0a, 0xc6, 0xc6, 0x9b, 0xc8, 0x40, 0xb2, 0x1b, 0x2c, 0x36, 0xeb, 0x28, 0x0a, 0x62, 0x38, 0x8e,
+	0xb2, 0xf4, 0x26, 0xc0, 0x16, 0x4d, 0x03, 0x9a, 0x1c, 0xcb, 0x03, 0x4b, 0x1c, 0x86, 0x1c, 0x39,
+	0xd6, 0x4d, 0xd1, 0x17, 0xe8, 0x45, 0x81, 0x5e, 0xf7, 0x45, 0xda, 0xfb, 0xde, 0xf6, 0x25, 0xfa,
+	0x1e, 0xc5, 0xcc, 0x90, 0xe2, 0x90, 0xa6, 0x65, 0x03, 0xc9, 0x1d, 0xcf, 0xef, 0x9c, 0xf3, 0xcd,
+	0x99, 0x33, 0x67, 0x08, 0x30, 0x0e, 0x7c, 0x67, 0xdb, 0x0f, 0x28, 0xa3, 0xa8, 0xc4, 0xbf, 0xcd,
+	0x6f, 0xa1, 0x71, 0x30, 0x9b, 0x30, 0xb2, 0xeb, 0xba, 0x01, 0x0e, 0x43, 0xb4, 0x09, 0xb5, 0x90,
+	0x8c, 0x3d, 0x9b, 0xcd, 0x02, 0x6c, 0x68, 0x5d, 0xad, 0xd7, 0xb0, 0x12, 0x06, 0x32, 0xa1, 0x31,
+	0x55, 0xb4, 0x8d, 0x42, 0x57, 0xeb, 0xd5, 0xac, 0x14, 0x0f, 0xfd, 0x0d, 0x6e, 0xaa, 0xf4, 0x6b,
+	0xea, 0x39, 0xd8, 0x28, 0x76, 0xb5, 0x5e, 0xc9, 0xba, 0x28, 0x30, 0x87, 0x50, 0x7f, 0x43, 0xbc,
+	0xb1, 0x85, 0x3f, 0xce, 0x70, 0xc8, 0xd0, 0xa3, 0xcc, 0x02, 0x3c, 0x82, 0x7a, 0x1f, 0x6d, 0x8b,
+	0xb8, 0xd5, 0x40, 0xd3, 0x8b, 0x9a, 0x2d, 0x68, 0x48, 0x37, 0xa1, 0x4f, 0xbd, 0x50, 0xba, 0xa5,
+	0x5f, 0xc6, 0x2d, 0x55, 0xdc, 0xf6, 0xa0, 0xf1, 0xdf, 0x19, 0x0e, 0xe6, 0xb1, 0x5f, 0x03, 0x56,
+	0x6c, 0xc5, 0x65, 0xcd, 0x8a, 0x49, 0x73, 0x1f, 0x9a, 0x91, 0xa6, 0x34, 0x45, 0x4f, 0xa0, 0xa5,
+	0xba, 0xc6, 0xdc, 0xa2, 0x78, 0x49, 0x10, 0x19, 0x4d, 0x73, 0x06, 0xcd, 0x43, 0x16, 0x60, 0x7b,
+	0x7a, 0x80, 0xc3, 0xd0, 0x1e, 0xe3, 0x2b, 0x76, 0x49, 0x89, 0xaa, 0x90, 0x8a, 0x8a, 0x4b, 0x3c,
+	0xcc, 0x3e, 0xd1, 0xe0, 0x54, 0xec, 0x48, 0xc3, 0x8a, 0x49, 0x84, 0xa0, 0xe4, 0xda, 0xcc, 0x36,
+	0x4a, 0x82, 0x2d, 0xbe, 0xcd, 0x77, 0xa0, 0x8f, 0x7c, 0xec, 0x8d, 0x02, 0x17, 0x07, 0x71, 0xc6,
+	0xcf, 0xa0, 0x49, 0x39, 0xfd, 0x22, 0xb0, 0xc7, 0x53, 0xec, 0xb1, 0x08, 0xca, 0x4d, 0x99, 0xc5,
+	0xd0, 0x73, 0x82, 0xb9, 0xcf, 0xb0, 0x3b, 0x52, 0x75, 0xac, 0xb4, 0x89, 0xb9, 0x0a, 0x37, 0x15,
+	0xbf, 0x11, 0xb4, 0xbf, 0x57, 0x60, 0x3d, 0xdf, 0x9c, 0x47, 0x2d, 0x1c, 0xec, 0xb9, 0x51, 0xae,
+	0x31, 0x89, 0xee, 0x43, 0x4d, 0x7c, 0xfe, 0x6f, 0xee, 0x63, 0x91, 0x6b, 0xab, 0xdf, 0x96, 0x91,
+	0x8c, 0x62, 0xb6, 0x95, 0x68, 0xa0, 0x1d, 0xa8, 0x0b, 0xe2, 0x8d, 0x1d, 0x10, 0x36, 0x17, 0x10,
+	0xb4, 0xfa, 0x37, 0x15, 0x03, 0x29, 0xb0, 0x54, 0x2d, 0xf4, 0x14, 0xda, 0x82, 0x3c, 0xc4, 0x8c,
+	0x4d, 0xb0, 0xc8, 0xb9, 0x24, 0x0c, 0x6f, 0x29, 0x86, 0x89, 0xd0, 0xca, 0x6a, 0xa3, 0x6e, 0xb4,
+	0xea, 0xf0, 0xdc, 0x27, 0xc1, 0xdc, 0x28, 0x77, 0xb5, 0x5e, 0xd1, 0x52, 0x59, 0xa8, 0x05, 0x05,
+	0xe2, 0x1a, 0x15, 0x91, 0x5b, 0x81, 0xb8, 0xe8, 0x0e, 0x00, 0xf6, 0xa9, 0x73, 0xf2, 0x1c, 0xfb,
+	0xec, 0xc4, 0x58, 0xe9, 0x6a, 0xbd, 0xb2, 0xa5, 0x70, 0xd0, 0x3a, 0x54, 0x18, 0x3d, 0xc5, 0x5e,
+	0x68, 0x54, 0x85, 0x4d, 0x44, 0xa1, 0xbf, 0x43, 0xd9, 0x0f, 0x88, 0x83, 0x8d, 0x9a, 0xd8, 0x94,
+	0x3f, 0x65, 0x36, 0x65, 0x40, 0x87, 0xe7, 0xfe, 0xe1, 0x89, 0x1d, 0x60, 0x4b, 0xea, 0xa1, 0x87,
+	0x50, 0x39, 0xa3, 0x93, 0xd9, 0x14, 0x1b, 0x70, 0x95, 0x45, 0xa4, 0x88, 0x9e, 0x42, 0x73, 0x4a,
+	0x3c, 0x32, 0x9d, 0x4d, 0xdf, 0x49, 0xcb, 0xfa, 0x55, 0x96, 0x69, 0x7d, 0xb4, 0x06, 0x65, 0x4f,
+	0xf4, 0x84, 0x86, 0x88, 0x5d, 0x12, 0xa8, 0x03, 0xd5, 0xa3, 0x09, 0xf1, 0x5c, 0xe2, 0x8d, 0x8d,
+	0xa6, 0x10, 0x2c, 0x68, 0x34, 0x82, 0xba, 0x43, 0xa7, 0x53, 0xc2, 0x38, 0x9c, 0xa1, 0xd1, 0x12,
+	0xe7, 0xe6, 0xfe, 0xb2, 0x8a, 0xdb, 0x1e, 0x24, 0xfa, 0x43, 0x8f, 0x05, 0x73, 0x4b, 0xf5, 0x80,
+	0xfe, 0x0f, 0xeb, 0x0e, 0xc5, 0xc7, 0xc7, 0xc4, 0x21, 0xd8, 0x63, 0x8a, 0xae, 0xd1, 0x16, 0xc9,
+	0xdc, 0x55, 0x76, 0x76, 0x90, 0xab, 0x68, 0x5d, 0xe2, 0x00, 0xfd, 0x07, 0xaa, 0xd4, 0xc7, 0x1e,
+	0xf1, 0xc6, 0xa1, 0xa1, 0x0b, 0x67, 0xf7, 0x96, 0x05, 0x3a, 0x8a, 0x74, 0xad, 0x85, 0x55, 0xe7,
+	0x3d, 0xe8, 0xd9, 0xe8, 0x91, 0x0e, 0xc5, 0x53, 0x3c, 0x17, 0xd5, 0x5f, 0xb2, 0xf8, 0x27, 0xda,
+	0x81, 0xf2, 0x99, 0x3d, 0x99, 0xc9, 0xaa, 0xaf, 0xf7, 0xff, 0xac, 0x44, 0x1c, 0xfb, 0x4e, 0xbc,
+	0x58, 0x52, 0xf7, 0x49, 0xe1, 0x1f, 0x9a, 0xf9, 0x18, 0x56, 0x73, 0x36, 0x89, 0x97, 0xa0, 0x43,
+	0xa3, 0xe3, 0x55, 0x70, 0x28, 0x5f, 0x11, 0x9f, 0xfb, 0xc2, 0x7b, 0xc3, 0xe2, 0x9f, 0xe6, 0xf7,
+	0x05, 0xb8, 0x7d, 0x89, 0x7f, 0x7e, 0x42, 0x45, 0x41, 0x0d, 0x62, 0x17, 0x31, 0xc9, 0xf7, 0x55,
+	0x7c, 0x0e, 0x17, 0xce, 0x16, 0x34, 0x97, 0xc9, 0xa2, 0x1a, 0xd0, 0xa8, 0x1d, 0x2d, 0x68, 0xde,
+	0xe1, 0xe4, 0x37, 0x37, 0x94, 0x4d, 0x29, 0x61, 0xa0, 0x1e, 0xb4, 0x53, 0x45, 0x35, 0xa0, 0xe2,
+	0x58, 0x35, 0xac, 0x2c, 0x1b, 0x6d, 0x81, 0x9e, 0x62, 0x71, 0x77, 0xf2, 0xa0, 0x5d, 0xe0, 0x2b,
+	0xc7, 0x6a, 0x25, 0x75, 0xac, 0x16, 0x15, 0x5b, 0x55, 0x2a, 0xd6, 0xdc, 0x81, 0xb6, 0xc0, 0x4f,
+	0x81, 0xe1, 0x6a, 0x10, 0x7f, 0x28, 0xc0, 0xc6, 0x92, 0xb2, 0x52, 0x42, 0xe0, 0xb7, 0x43, 0x12,
+	0x82, 0x02, 0x70, 0x41, 0x08, 0x72, 0x01, 0x2e, 0x0a, 0x51, 0x3e, 0xc0, 0x25, 0x29, 0xcb, 0x07,
+	0xb8, 0x2c, 0x84, 0xcb, 0x01, 0xae, 0x08, 0x9d, 0x6b, 0x01, 0xbc, 0x22, 0x54, 0x2f, 0x02, 0xac,
+	0x00, 0x59, 0x4c, 0x80, 0xfc, 0xb1, 0x00, 0x77, 0x96, 0x9f, 0x8e, 0x14, 0x2c, 0xda, 0x65, 0xb0,
+	0x68, 0x97, 0xc3, 0xa2, 0x2d, 0x81, 0x45, 0x5b, 0x06, 0x8b, 0x76, 0x0d, 0x58, 0xb4, 0xeb, 0xc3,
+	0xa2, 0x5d, 0x05, 0x8b, 0x52, 0x5f, 0x6d, 0x7e, 0xe9, 0xdb, 0x6c, 0x16, 0x46, 0x57, 0xaf, 0xe9,
+	0x42, 0x2b, 0x66, 0x44, 0x33, 0xc5, 0xa5, 0xe3, 0x07, 0x1f, 0xd4, 0x8e, 0x28, 0x65, 0x21, 0x0b,
+	0x6c, 0xdf, 0xc7, 0xae, 0x40, 0xa7, 0x6a, 0xa5, 0x78, 0x7c, 0x59, 0x1f, 0xe3, 0x20, 0x14, 0xf8,
+	0x14, 0x2d, 0x49, 0x98, 0xbf, 0x6a, 0x70, 0xeb, 0xad, 0xef, 0xda, 0x0c, 0x1f, 0x10, 0xd7, 0xa7,
+	0xc4, 0x63, 0xf1, 0xd5, 0xbf, 0x7c, 0xe8, 0x78, 0x0a, 0x15, 0x01, 0x70, 0x28, 0x0a, 0xb4, 0xde,
+	0xff, 0xab, 0xec, 0x48, 0xb9, 0xae, 0xb6, 0xdf, 0x08, 0x4d, 0xd9, 0x99, 0x23, 0xb3, 0x04, 0x05,
+	0x39, 0x2b, 0x4a, 0xa2, 0xf3, 0x4f, 0xa8, 0x2b, 0xca, 0x39, 0x8d, 0x70, 0x4d, 0x6d, 0x84, 0x25,
+	0xb5, 0xd3, 0x19, 0xb0, 0x9e, 0x5d, 0x3d, 0x9a, 0x35, 0x1e, 0x41, 0x67, 0x97, 0x31, 0x1c, 0xb2,
+	0xf4, 0x98, 0x92, 0x0c, 0x75, 0xf9, 0xe3, 0x86, 0xf9, 0xb3, 0x06, 0x1b, 0xb9, 0x86, 0xc9, 0x7e,
+	0xe4, 0x5b, 0xf2, 0x1b, 0xfd, 0x38, 0xd2, 0xde, 0x73, 0xa3, 0x5a, 0x55, 0x38, 0xbc, 0x24, 0x5d,
+	0x3b, 0x38, 0xf5, 0xa8, 0x2b, 0xf3, 0xaf, 0x59, 0x0b, 0x3a, 0x8d, 0x7b, 0x29, 0x8b, 0x7b, 0x0f,
+	0xda, 0xca, 0xd5, 0xf6, 0xd2, 0x0e, 0x4f, 0xe2, 0x56, 0x98, 0x61, 0x9b, 0x8f, 0x61, 0x23, 0x13,
+	0xb6, 0x83, 0x89, 0x7f, 0x8d, 0xb4, 0x7f, 0xd3, 0x60, 0x33, 0xdf, 0xf2, 0xb3, 0xf3, 0x4e, 0x4f,
+	0x3a, 0xc5, 0x0b, 0x93, 0xce, 0x26, 0xd4, 0x5c, 0x1c, 0xb5, 0x06, 0x91, 0x7b, 0xd5, 0x4a, 0x18,
+	0x29, 0xd4, 0xca, 0xcb, 0x50, 0xab, 0x64, 0x50, 0x33, 0x1f, 0xc2, 0x2d, 0x91, 0xd1, 0x2b, 0x72,
+	0x8c, 0x9d, 0xb9, 0x33, 0xc1, 0x57, 0xa3, 0xf0, 0x9d, 0x06, 0xeb, 0x59, 0x9b, 0x2b, 0xf3, 0xff,
+	0x0b, 0x94, 0x43, 0x66, 0x8f, 0xe3, 0xe1, 0x54, 0x57, 0x47, 0x46, 0xce, 0xb7, 0xa4, 0x18, 0xdd,
+	0x83, 0xa6, 0x43, 0xa7, 0xfe, 0x8c, 0xd9, 0x8c, 0x50, 0x6f, 0xcf, 0x8d, 0x7a, 0x56, 0x9a, 0x69,
+	0xce, 0x94, 0xc1, 0x39, 0xfc, 0x82, 0x13, 0x79, 0x1a, 0xac, 0x42, 0x16, 0xac, 0x0f, 0xd0, 0x4c,
+	0x96, 0xdd, 0x75, 0x4e, 0x3f, 0x63, 0xbf, 0xd7, 0xa0, 0x8c, 0x83, 0x80, 0x06, 0x51, 0x91, 0x4b,
+	0x62, 0x6b, 0x08, 0xb5, 0xc5, 0xbc, 0x8e, 0x1a, 0x50, 0x8d, 0x0f, 0xac, 0x7e, 0x03, 0xd5, 0xa0,
+	0xfc, 0x8a, 0x4c, 0x09, 0xd3, 0x35, 0xa4, 0x43, 0x23, 0x16, 0x7c, 0x78, 0x31, 0xda, 0xd7, 0x0b,
+	0xa8, 0x09, 0x35, 0x21, 0x14, 0x64, 0x71, 0xab, 0x0b, 0x75, 0x65, 0x8a, 0x47, 0x2b, 0x50, 0x7c,
+	0x36, 0x9b, 0xeb, 0x37, 0x50, 0x15, 0x4a, 0x87, 0x78, 0x32, 0xd1, 0xb5, 0xad, 0x47, 0xd0, 0xce,
+	0x8c, 0xeb, 0x5c, 0xeb, 0x35, 0x99, 0xc8, 0x95, 0x2c, 0xec, 0x0d, 0xcf, 0x75, 0x0d, 0xb5, 0xa1,
+	0x2e, 0x3e, 0x77, 0x19, 0x9d, 0x12, 0x47, 0x2f, 0x6c, 0x7d, 0x03, 0x90, 0xec, 0x19, 0xaa, 0xc3,
+	0xca, 0x5b, 0xef, 0xd4, 0xa3, 0x9f, 0x3c, 0xfd, 0x06, 0x0f, 0x57, 0x1c, 0x87, 0x33, 0xec, 0xea,
+	0x1a, 0xa7, 0xf6, 0xbc, 0x03, 0x9b, 0x05, 0xe4, 0x5c, 0x2f, 0x70, 0xc5, 0x03, 0x9b, 0x39, 0x27,
+	0xd8, 0xd5, 0x8b, 0x3c, 0xd8, 0x01, 0xf5, 0x8e, 0x49, 0x30, 0xc5, 0xae, 0x5e, 0xe2, 0x32, 0x19,
+	0x85, 0xab, 0x97, 0xfb, 0x3f, 0x69, 0xd0, 0x38, 0xfc, 0x64, 0x07, 0xd3, 0x43, 0x1c, 0x9c, 0xf1,
+	0xc1, 0xfc, 0x3e, 0x94, 0xf8, 0x7b, 0x16, 0x45, 0x8f, 0x13, 0xe5, 0x89, 0xdc, 0x41, 0x2a, 0x2b,
+	0x2a, 0x40, 0xae, 0x4e, 0x15, 0x75, 0x7a, 0x51, 0x5d, 0x79, 0xc6, 0xa2, 0x07, 0x50, 0x16, 0x8f,
+	0x53, 0x14, 0x09, 0xd5, 0x37, 0x6d, 0x67, 0x35, 0xc5, 0x93, 0x16, 0xfd, 0x97, 0xf1, 0x0b, 0x34,
+	0x0e, 0xf0, 0x31, 0xac, 0x0c, 0xa8, 0xe7, 0x61, 0x87, 0xa1, 0xc8, 0x20, 0xf5, 0x42, 0xed, 0xe4,
+	0x31, 0x7b, 0xda, 0x03, 0xad, 0xff, 0x4b, 0x01, 0x74, 0x81, 0xe5, 0x11, 0xa5, 0xa7, 0xb1, 0xb7,
+	0x7f, 0x41, 0x6d, 0x51, 0x61, 0x68, 0x3d, 0x3a, 0x24, 0x99, 0xa7, 0x67, 0xe7, 0xf6, 0x05, 0x7e,
+	0x94, 0xce, 0x7b, 0x58, 0xcb, 0x6b, 0x4f, 0xe8, 0x6e, 0xce, 0x50, 0x9c, 0x6e, 0x7a, 0x1d, 0x73,
+	0x99, 0x4a, 0xe4, 0x7e, 0x1f, 0x5a, 0xe9, 0x73, 0x8f, 0x36, 0x14, 0xab, 0x6c, 0x07, 0xe9, 0x6c,
+	0xe6, 0x0b, 0x23, 0x67, 0xff, 0x06, 0x48, 0xce, 0x12, 0xca, 0xa6, 0x14, 0x66, 0x36, 0x21, 0x75,
+	0xec, 0x04, 0x7c, 0xcf, 0xe3, 0xa9, 0x20, 0x86, 0x6e, 0x07, 0x2a, 0x92, 0x91, 0xec, 0x83, 0x32,
+	0x34, 0x74, 0xd6, 0xd2, 0xcc, 0x68, 0x3b, 0xbf, 0x86, 0xe6, 0x28, 0xb0, 0x9d, 0x09, 0x8e, 0xbd,
+	0xec, 0x43, 0x2b, 0x7d, 0x57, 0xc6, 0x39, 0xe6, 0xde, 0xdf, 0x9d, 0xcd, 0x7c, 0x61, 0xe4, 0xfd,
+	0x23, 0xac, 0xee, 0x9e, 0xd9, 0x64, 0x62, 0x1f, 0x91, 0x09, 0x61, 0xf3, 0x78, 0x8d, 0xaf, 0x60,
+	0x35, 0xe7, 0xf2, 0x44, 0x5d, 0xe9, 0xeb, 0xf2, 0x0b, 0xb9, 0x73, 0x77, 0x89, 0x86, 0x5c, 0xf2,
+	0xa8, 0x22, 0xfe, 0x69, 0xed, 0xfc, 0x31, 0x00, 0x69, 0x10, 0x04, 0x9d, 0xe1, 0x12, 0x00, 0x00,
 }
@@ -3,15 +3,20 @@ package grpc
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/ome"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/shamir"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 // ErrOpenOrderRequestIsNil is returned when a gRPC request is nil or has nil
@@ -29,6 +34,42 @@ var ErrEncryptedCoExpShareIsNil = errors.New("encrypted coexp is nil")
 // is nil.
 var ErrEncryptedOrderFragmentIsNil = errors.New("encrypted order fragment is nil")
 
+// ErrOrderFragmentReceiptRequestIsNil is returned when a gRPC request is nil
+// or has nil fields.
+var ErrOrderFragmentReceiptRequestIsNil = errors.New("order fragment receipt request is nil")
+
+// ErrOrderLifecycleRequestIsNil is returned when a gRPC request is nil or has
+// nil fields.
+var ErrOrderLifecycleRequestIsNil = errors.New("order lifecycle request is nil")
+
+// ErrReceiptsUnavailable is returned when an OrderbookService was not given
+// an orderbook.Receipter, and an ome.Tracker, to serve receipts.
+var ErrReceiptsUnavailable = errors.New("receipts are unavailable")
+
+// ErrOpenOrdersRequestIsNil is returned when a gRPC request streamed to the
+// OpenOrders RPC is nil or has nil fields.
+var ErrOpenOrdersRequestIsNil = errors.New("open orders request is nil")
+
+// ErrTraderQuotaExceeded is returned when a trader has opened more order
+// fragments than its quota allows.
+var ErrTraderQuotaExceeded = errors.New("429: Too Many Requests")
+
+// ErrTooManyOrderFragments is returned when more order fragments are opened
+// on one stream than MaxOpenOrdersPerStream allows.
+var ErrTooManyOrderFragments = errors.New("too many order fragments")
+
+// ErrUnexpectedAcks is returned when the OpenOrders RPC is closed before an
+// acknowledgement is returned for each order fragment sent.
+var ErrUnexpectedAcks = errors.New("unexpected number of acknowledgements")
+
+// ErrUnexpectedTrader is returned when an order fragment is signed by an
+// address that is not the trader that opened its order.
+var ErrUnexpectedTrader = errors.New("unexpected trader")
+
+// MaxOpenOrdersPerStream is the maximum number of order fragments that can be
+// opened on one stream by the OpenOrders RPC.
+const MaxOpenOrdersPerStream = 1024
+
 type orderbookClient struct {
 }
 
@@ -58,11 +99,116 @@ func (client *orderbookClient) OpenOrder(ctx context.Context, multiAddr identity
 	})
 }
 
+// OpenOrders implements the orderbook.Client interface.
+func (client *orderbookClient) OpenOrders(ctx context.Context, multiAddr identity.MultiAddress, signer crypto.Signer, orderFragments []order.EncryptedFragment) ([]error, error) {
+	if len(orderFragments) > MaxOpenOrdersPerStream {
+		return nil, ErrTooManyOrderFragments
+	}
+	requests := make([]*OpenOrdersRequest, len(orderFragments))
+	for i, orderFragment := range orderFragments {
+		if orderFragment.IsNil() {
+			return nil, ErrOrderFragmentIsNil
+		}
+		signature, err := signer.Sign(orderbook.OpenOrderHash(orderFragment))
+		if err != nil {
+			return nil, fmt.Errorf("cannot sign order fragment: %v", err)
+		}
+		requests[i] = &OpenOrdersRequest{
+			OrderFragment: marshalEncryptedOrderFragment(orderFragment),
+			Signature:     signature,
+		}
+	}
+
+	conn, err := Dial(ctx, multiAddr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial %v: %v", multiAddr, err)
+	}
+	defer conn.Close()
+
+	// Order fragments that have been acknowledged are not sent again when the
+	// stream is retried
+	errs := make([]error, len(orderFragments))
+	acked := make([]bool, len(orderFragments))
+	if err := Backoff(ctx, func() error {
+		pending := make([]int, 0, len(requests))
+		for i := range requests {
+			if !acked[i] {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		return openOrders(ctx, conn, requests, pending, errs, acked)
+	}); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+// openOrders opens one stream and sends the pending OpenOrdersRequests. The
+// OpenOrdersAcks are received while the requests are sent, and the order
+// fragments are marked as acknowledged as their OpenOrdersAcks arrive.
+func openOrders(ctx context.Context, conn *grpc.ClientConn, requests []*OpenOrdersRequest, pending []int, errs []error, acked []bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := NewOrderbookServiceClient(conn).OpenOrders(ctx)
+	if err != nil {
+		return err
+	}
+
+	recvErrs := make(chan error, 1)
+	go func() {
+		defer close(recvErrs)
+		for _, i := range pending {
+			ack, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					err = ErrUnexpectedAcks
+				}
+				recvErrs <- err
+				return
+			}
+			if ack.Error != "" {
+				errs[i] = errors.New(ack.Error)
+			}
+			acked[i] = true
+		}
+	}()
+
+	var sendErr error
+	for _, i := range pending {
+		if sendErr = stream.Send(requests[i]); sendErr != nil {
+			break
+		}
+	}
+	if sendErr == nil {
+		sendErr = stream.CloseSend()
+	}
+	if sendErr != nil && sendErr != io.EOF {
+		// The OpenOrdersAcks that will never arrive are not waited for
+		cancel()
+		<-recvErrs
+		return sendErr
+	}
+	// When the server has closed the stream the reason is returned by Recv
+	return <-recvErrs
+}
+
 // OrderbookService is a Service that implements the gRPC OrderbookService
 // defined in protobuf. It exposes an RPC that accepts OpenOrderRequests and
-// delegates control to an orderbook.Server.
+// delegates control to an orderbook.Server. Traders can request receipts for
+// the order fragments that they have sent, and the lifecycle of their orders,
+// if the OrderbookService has an orderbook.Receipter and an ome.Tracker. Many
+// order fragments can be opened on one stream, subject to a quota for each
+// trader.
 type OrderbookService struct {
-	server orderbook.Server
+	server        orderbook.Server
+	receipter     orderbook.Receipter
+	tracker       ome.Tracker
+	orderStore    orderbook.OrderStorer
+	traderLimiter *RateLimiter
 }
 
 // NewOrderbookService returns a gRPC service that unmarshals OpenOrderRequests
@@ -74,6 +220,37 @@ func NewOrderbookService(server orderbook.Server) OrderbookService {
 	}
 }
 
+// NewOrderbookServiceWithReceipts returns a gRPC service that is the same as
+// the service returned by NewOrderbookService, but that uses the
+// orderbook.Receipter as its orderbook.Server, and serves receipts for order
+// fragments and the lifecycle of orders.
+func NewOrderbookServiceWithReceipts(receipter orderbook.Receipter, tracker ome.Tracker) OrderbookService {
+	return OrderbookService{
+		server:    receipter,
+		receipter: receipter,
+		tracker:   tracker,
+	}
+}
+
+// NewOrderbookServiceWithTraderLimiter returns a gRPC service that is the same
+// as the service returned by NewOrderbookServiceWithReceipts, but that limits
+// the number of order fragments that each trader can open using the
+// OpenOrders RPC. Order fragments must be signed by the trader that is stored
+// for their order in the orderbook.OrderStorer, and are counted against the
+// quota of that trader. Order fragments for orders that have not been synced
+// yet are counted against the quota of their signatory, and are opened so
+// that they can be matched with their order once it is synced, the same as
+// order fragments opened by the OpenOrder RPC.
+func NewOrderbookServiceWithTraderLimiter(receipter orderbook.Receipter, tracker ome.Tracker, orderStore orderbook.OrderStorer, traderLimiter *RateLimiter) OrderbookService {
+	return OrderbookService{
+		server:        receipter,
+		receipter:     receipter,
+		tracker:       tracker,
+		orderStore:    orderStore,
+		traderLimiter: traderLimiter,
+	}
+}
+
 // Register implements the Service interface.
 func (service *OrderbookService) Register(server *Server) {
 	if server == nil {
@@ -101,6 +278,137 @@ func (service *OrderbookService) OpenOrder(ctx context.Context, request *OpenOrd
 	return &OpenOrderResponse{}, service.server.OpenOrder(ctx, fragment)
 }
 
+// OrderFragmentReceipt implements the gRPC service for requesting a signed
+// receipt for the order fragment that was received for an order.
+func (service *OrderbookService) OrderFragmentReceipt(ctx context.Context, request *OrderFragmentReceiptRequest) (*OrderFragmentReceiptResponse, error) {
+	// Check for empty or invalid request fields.
+	if request == nil || len(request.OrderId) != 32 {
+		return nil, ErrOrderFragmentReceiptRequestIsNil
+	}
+	if service.receipter == nil {
+		return nil, ErrReceiptsUnavailable
+	}
+
+	orderID := order.ID{}
+	copy(orderID[:], request.OrderId)
+	receipt, err := service.receipter.Receipt(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderFragmentReceiptResponse{
+		OrderId:    receipt.OrderID[:],
+		FragmentId: receipt.FragmentID[:],
+		EpochDepth: int32(receipt.EpochDepth),
+		Decrypted:  receipt.Decrypted,
+		Darknode:   receipt.Darknode.String(),
+		Signature:  receipt.Signature,
+	}, nil
+}
+
+// OrderLifecycle implements the gRPC service for requesting the lifecycle of
+// an order, as seen by the Darknode.
+func (service *OrderbookService) OrderLifecycle(ctx context.Context, request *OrderLifecycleRequest) (*OrderLifecycleResponse, error) {
+	// Check for empty or invalid request fields.
+	if request == nil || len(request.OrderId) != 32 {
+		return nil, ErrOrderLifecycleRequestIsNil
+	}
+	if service.tracker == nil {
+		return nil, ErrReceiptsUnavailable
+	}
+
+	orderID := order.ID{}
+	copy(orderID[:], request.OrderId)
+	lifecycle, err := service.tracker.OrderLifecycle(orderID)
+	if err != nil {
+		return nil, err
+	}
+	response := &OrderLifecycleResponse{
+		OrderId: lifecycle.OrderID[:],
+		Stage:   marshalOrderStage(lifecycle.Stage),
+	}
+	if lifecycle.ComputationID != (ome.ComputationID{}) {
+		response.ComputationId = lifecycle.ComputationID[:]
+	}
+	return response, nil
+}
+
+// OpenOrders implements the gRPC service for receiving a stream of
+// EncryptedOrderFragments defined in protobuf. An acknowledgement is returned
+// for each EncryptedOrderFragment as soon as it has been opened.
+func (service *OrderbookService) OpenOrders(stream OrderbookService_OpenOrdersServer) error {
+	n := 0
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n >= MaxOpenOrdersPerStream {
+			return ErrTooManyOrderFragments
+		}
+		n++
+		if err := stream.Send(service.openOrder(stream.Context(), request)); err != nil {
+			return err
+		}
+	}
+}
+
+// openOrder delegates an OpenOrdersRequest to the orderbook.Server once it is
+// known to be signed by the trader of its order, and the trader is within its
+// quota. When the order has not been synced, the signatory is used as the
+// trader. Errors are returned in the OpenOrdersAck.
+func (service *OrderbookService) openOrder(ctx context.Context, request *OpenOrdersRequest) *OpenOrdersAck {
+	// Check for empty or invalid request fields.
+	if request == nil || request.OrderFragment == nil {
+		return &OpenOrdersAck{
+			Error: ErrOpenOrdersRequestIsNil.Error(),
+		}
+	}
+	ack := &OpenOrdersAck{
+		OrderId:    request.OrderFragment.OrderId,
+		FragmentId: request.OrderFragment.Id,
+	}
+
+	fragment, err := unmarshalEncryptedOrderFragment(request.OrderFragment)
+	if err != nil {
+		ack.Error = err.Error()
+		return ack
+	}
+	signatory, err := crypto.RecoverAddress(orderbook.OpenOrderHash(fragment), request.Signature)
+	if err != nil {
+		ack.Error = fmt.Sprintf("cannot recover trader: %v", err)
+		return ack
+	}
+	trader := signatory
+	if service.orderStore != nil {
+		// The signatory must be the trader that opened the order, otherwise
+		// clients could use new keys to evade their quota. Order fragments
+		// can arrive before their order has been synced, in which case the
+		// signatory cannot be checked yet and its own quota is used.
+		signatoryAddr := common.BytesToAddress(identity.Address(signatory).ID())
+		_, orderTrader, _, err := service.orderStore.Order(fragment.OrderID)
+		if err != nil && err != orderbook.ErrOrderNotFound {
+			ack.Error = fmt.Sprintf("cannot load trader: %v", err)
+			return ack
+		}
+		if err == nil && signatoryAddr != common.HexToAddress(orderTrader) {
+			ack.Error = ErrUnexpectedTrader.Error()
+			return ack
+		}
+		trader = signatoryAddr.Hex()
+	}
+	if service.traderLimiter != nil && !service.traderLimiter.Allow(trader) {
+		ack.Error = ErrTraderQuotaExceeded.Error()
+		return ack
+	}
+	if err := service.server.OpenOrder(ctx, fragment); err != nil {
+		ack.Error = err.Error()
+	}
+	return ack
+}
+
 func marshalEncryptedOrderFragment(orderFragmentIn order.EncryptedFragment) *EncryptedOrderFragment {
 	return &EncryptedOrderFragment{
 		OrderId:         orderFragmentIn.OrderID[:],
@@ -119,6 +427,9 @@ func marshalEncryptedOrderFragment(orderFragmentIn order.EncryptedFragment) *Enc
 
 		Blinding:    []byte(orderFragmentIn.Blinding),
 		Commitments: marshalCommitments(orderFragmentIn.Commitments),
+
+		CoefficientCommitments: marshalCoefficientCommitments(orderFragmentIn.CoefficientCommitments),
+		Openings:               marshalEncryptedOpenings(orderFragmentIn.Openings),
 	}
 }
 
@@ -155,6 +466,9 @@ func unmarshalEncryptedOrderFragment(orderFragmentIn *EncryptedOrderFragment) (o
 
 		Blinding:    orderFragmentIn.Blinding,
 		Commitments: unmarshalCommitments(orderFragmentIn.Commitments),
+
+		CoefficientCommitments: unmarshalCoefficientCommitments(orderFragmentIn.CoefficientCommitments),
+		Openings:               unmarshalEncryptedOpenings(orderFragmentIn.Openings),
 	}
 	copy(orderFragment.OrderID[:], orderFragmentIn.OrderId)
 	copy(orderFragment.ID[:], orderFragmentIn.Id)
@@ -162,6 +476,23 @@ func unmarshalEncryptedOrderFragment(orderFragmentIn *EncryptedOrderFragment) (o
 	return orderFragment, nil
 }
 
+func marshalOrderStage(stage ome.OrderStage) OrderStage {
+	switch stage {
+	case ome.OrderStageReceived:
+		return OrderStage_Received
+	case ome.OrderStageInMatrix:
+		return OrderStage_InMatrix
+	case ome.OrderStageMatched:
+		return OrderStage_Matched
+	case ome.OrderStageConfirmed:
+		return OrderStage_Confirmed
+	case ome.OrderStageSettled:
+		return OrderStage_Settled
+	default:
+		return OrderStage_Unknown
+	}
+}
+
 func marshalEncryptedCoExpShare(value order.EncryptedCoExpShare) *EncryptedCoExpShare {
 	return &EncryptedCoExpShare{
 		Co:  value.Co,
@@ -183,12 +514,14 @@ func marshalCommitments(values order.FragmentCommitments) map[uint64]*OrderFragm
 	commitments := map[uint64]*OrderFragmentCommitment{}
 	for i, value := range values {
 		commitments[i] = &OrderFragmentCommitment{
-			PriceCo:          value.PriceCo.Bytes(),
-			PriceExp:         value.PriceExp.Bytes(),
-			VolumeCo:         value.VolumeCo.Bytes(),
-			VolumeExp:        value.VolumeExp.Bytes(),
-			MinimumVolumeCo:  value.MinimumVolumeCo.Bytes(),
-			MinimumVolumeExp: value.MinimumVolumeExp.Bytes(),
+			Tokens:           marshalCommitment(value.Tokens),
+			PriceCo:          marshalCommitment(value.PriceCo),
+			PriceExp:         marshalCommitment(value.PriceExp),
+			VolumeCo:         marshalCommitment(value.VolumeCo),
+			VolumeExp:        marshalCommitment(value.VolumeExp),
+			MinimumVolumeCo:  marshalCommitment(value.MinimumVolumeCo),
+			MinimumVolumeExp: marshalCommitment(value.MinimumVolumeExp),
+			Nonce:            marshalCommitment(value.Nonce),
 		}
 	}
 	return commitments
@@ -201,13 +534,108 @@ func unmarshalCommitments(values map[uint64]*OrderFragmentCommitment) order.Frag
 			continue
 		}
 		commitments[i] = order.FragmentCommitment{
-			PriceCo:          shamir.Commitment{Int: big.NewInt(0).SetBytes(value.PriceCo)},
-			PriceExp:         shamir.Commitment{Int: big.NewInt(0).SetBytes(value.PriceExp)},
-			VolumeCo:         shamir.Commitment{Int: big.NewInt(0).SetBytes(value.VolumeCo)},
-			VolumeExp:        shamir.Commitment{Int: big.NewInt(0).SetBytes(value.VolumeExp)},
-			MinimumVolumeCo:  shamir.Commitment{Int: big.NewInt(0).SetBytes(value.MinimumVolumeCo)},
-			MinimumVolumeExp: shamir.Commitment{Int: big.NewInt(0).SetBytes(value.MinimumVolumeExp)},
+			Tokens:           unmarshalCommitment(value.Tokens),
+			PriceCo:          unmarshalCommitment(value.PriceCo),
+			PriceExp:         unmarshalCommitment(value.PriceExp),
+			VolumeCo:         unmarshalCommitment(value.VolumeCo),
+			VolumeExp:        unmarshalCommitment(value.VolumeExp),
+			MinimumVolumeCo:  unmarshalCommitment(value.MinimumVolumeCo),
+			MinimumVolumeExp: unmarshalCommitment(value.MinimumVolumeExp),
+			Nonce:            unmarshalCommitment(value.Nonce),
 		}
 	}
 	return commitments
 }
+
+func marshalCoefficientCommitments(value order.CoefficientCommitments) *OrderCoefficientCommitments {
+	return &OrderCoefficientCommitments{
+		Tokens:           marshalCommitmentSlice(value.Tokens),
+		PriceCo:          marshalCommitmentSlice(value.PriceCo),
+		PriceExp:         marshalCommitmentSlice(value.PriceExp),
+		VolumeCo:         marshalCommitmentSlice(value.VolumeCo),
+		VolumeExp:        marshalCommitmentSlice(value.VolumeExp),
+		MinimumVolumeCo:  marshalCommitmentSlice(value.MinimumVolumeCo),
+		MinimumVolumeExp: marshalCommitmentSlice(value.MinimumVolumeExp),
+		Nonce:            marshalCommitmentSlice(value.Nonce),
+	}
+}
+
+func unmarshalCoefficientCommitments(value *OrderCoefficientCommitments) order.CoefficientCommitments {
+	if value == nil {
+		return order.CoefficientCommitments{}
+	}
+	return order.CoefficientCommitments{
+		Tokens:           unmarshalCommitmentSlice(value.Tokens),
+		PriceCo:          unmarshalCommitmentSlice(value.PriceCo),
+		PriceExp:         unmarshalCommitmentSlice(value.PriceExp),
+		VolumeCo:         unmarshalCommitmentSlice(value.VolumeCo),
+		VolumeExp:        unmarshalCommitmentSlice(value.VolumeExp),
+		MinimumVolumeCo:  unmarshalCommitmentSlice(value.MinimumVolumeCo),
+		MinimumVolumeExp: unmarshalCommitmentSlice(value.MinimumVolumeExp),
+		Nonce:            unmarshalCommitmentSlice(value.Nonce),
+	}
+}
+
+func marshalEncryptedOpenings(value order.EncryptedFragmentOpenings) *EncryptedOrderFragmentOpenings {
+	return &EncryptedOrderFragmentOpenings{
+		Tokens:           value.Tokens,
+		PriceCo:          value.PriceCo,
+		PriceExp:         value.PriceExp,
+		VolumeCo:         value.VolumeCo,
+		VolumeExp:        value.VolumeExp,
+		MinimumVolumeCo:  value.MinimumVolumeCo,
+		MinimumVolumeExp: value.MinimumVolumeExp,
+		Nonce:            value.Nonce,
+	}
+}
+
+func unmarshalEncryptedOpenings(value *EncryptedOrderFragmentOpenings) order.EncryptedFragmentOpenings {
+	if value == nil {
+		return order.EncryptedFragmentOpenings{}
+	}
+	return order.EncryptedFragmentOpenings{
+		Tokens:           value.Tokens,
+		PriceCo:          value.PriceCo,
+		PriceExp:         value.PriceExp,
+		VolumeCo:         value.VolumeCo,
+		VolumeExp:        value.VolumeExp,
+		MinimumVolumeCo:  value.MinimumVolumeCo,
+		MinimumVolumeExp: value.MinimumVolumeExp,
+		Nonce:            value.Nonce,
+	}
+}
+
+func marshalCommitmentSlice(values shamir.Commitments) [][]byte {
+	commitments := make([][]byte, len(values))
+	for i, value := range values {
+		commitments[i] = marshalCommitment(value)
+	}
+	return commitments
+}
+
+func unmarshalCommitmentSlice(values [][]byte) shamir.Commitments {
+	if len(values) == 0 {
+		return nil
+	}
+	commitments := make(shamir.Commitments, len(values))
+	for i, value := range values {
+		commitments[i] = unmarshalCommitment(value)
+	}
+	return commitments
+}
+
+func marshalCommitment(value shamir.Commitment) []byte {
+	if value.Int == nil {
+		return nil
+	}
+	return value.Bytes()
+}
+
+func unmarshalCommitment(value []byte) shamir.Commitment {
+	if len(value) == 0 {
+		// Fragments created before a value was committed to will not have a
+		// commitment for it
+		return shamir.Commitment{}
+	}
+	return shamir.Commitment{Int: big.NewInt(0).SetBytes(value)}
+}
@@ -2,12 +2,22 @@ package grpc_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"sync/atomic"
 	"time"
 
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/ome"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/testutils"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -61,6 +71,318 @@ var _ = Describe("Orderbook", func() {
 
 })
 
+var _ = Describe("Orderbook receipts", func() {
+
+	var receipterMock *mockReceipter
+	var trackerMock *mockTracker
+	var server *Server
+	var client OrderbookServiceClient
+	var conn *grpc.ClientConn
+
+	BeforeEach(func() {
+		serviceEcdsaKey, err := crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		serviceMultiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18514/republic/%v", serviceEcdsaKey.Address()))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		receipterMock = &mockReceipter{receipts: map[order.ID]orderbook.Receipt{}}
+		trackerMock = &mockTracker{lifecycles: map[order.ID]ome.OrderLifecycle{}}
+		server = NewServer()
+		service := NewOrderbookServiceWithReceipts(receipterMock, trackerMock)
+		service.Register(server)
+		go server.Start("0.0.0.0:18514")
+		time.Sleep(time.Millisecond)
+
+		conn, err = Dial(context.Background(), serviceMultiAddr)
+		Expect(err).ShouldNot(HaveOccurred())
+		client = NewOrderbookServiceClient(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	Context("when requesting order fragment receipts", func() {
+
+		It("should return the receipt", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			receipterMock.receipts[orderID] = orderbook.Receipt{
+				OrderID:    orderID,
+				FragmentID: order.FragmentID(testutils.Random32Bytes()),
+				EpochDepth: 1,
+				Decrypted:  true,
+				Darknode:   identity.Address("darknode"),
+				Signature:  []byte("signature"),
+			}
+
+			response, err := client.OrderFragmentReceipt(context.Background(), &OrderFragmentReceiptRequest{OrderId: orderID[:]})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(response.OrderId).Should(Equal(orderID[:]))
+			Expect(response.FragmentId).Should(HaveLen(32))
+			Expect(response.EpochDepth).Should(Equal(int32(1)))
+			Expect(response.Decrypted).Should(BeTrue())
+			Expect(response.Darknode).Should(Equal("darknode"))
+			Expect(response.Signature).Should(Equal([]byte("signature")))
+		})
+
+		It("should return an error for an unknown order", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			_, err := client.OrderFragmentReceipt(context.Background(), &OrderFragmentReceiptRequest{OrderId: orderID[:]})
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should return an error for an invalid order ID", func() {
+			_, err := client.OrderFragmentReceipt(context.Background(), &OrderFragmentReceiptRequest{OrderId: []byte("invalid")})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when requesting order lifecycles", func() {
+
+		It("should return the lifecycle", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			comID := ome.ComputationID(testutils.Random32Bytes())
+			trackerMock.lifecycles[orderID] = ome.OrderLifecycle{
+				OrderID:       orderID,
+				Stage:         ome.OrderStageConfirmed,
+				ComputationID: comID,
+			}
+
+			response, err := client.OrderLifecycle(context.Background(), &OrderLifecycleRequest{OrderId: orderID[:]})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(response.OrderId).Should(Equal(orderID[:]))
+			Expect(response.Stage).Should(Equal(OrderStage_Confirmed))
+			Expect(response.ComputationId).Should(Equal(comID[:]))
+		})
+
+		It("should return an error for an unknown order", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			_, err := client.OrderLifecycle(context.Background(), &OrderLifecycleRequest{OrderId: orderID[:]})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Orderbook streams", func() {
+
+	var receipterMock *mockReceipter
+	var store *leveldb.Store
+	var service *interruptedOrderbookService
+	var server *Server
+	var serviceMultiAddr identity.MultiAddress
+	var client orderbook.Client
+
+	BeforeEach(func() {
+		serviceEcdsaKey, err := crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		serviceMultiAddr, err = identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18514/republic/%v", serviceEcdsaKey.Address()))
+		Expect(err).ShouldNot(HaveOccurred())
+		store, err = leveldb.NewStore("./tmp/orderbook.1.out", 10*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// Each trader can open two order fragments
+		traderLimiter := NewRateLimiter(rate.NewLimiter(1000, 1000), 0.001, 2)
+
+		client = NewOrderbookClient()
+		receipterMock = &mockReceipter{receipts: map[order.ID]orderbook.Receipt{}}
+		server = NewServer()
+		service = &interruptedOrderbookService{
+			OrderbookService: NewOrderbookServiceWithTraderLimiter(receipterMock, &mockTracker{}, store.OrderbookOrderStore(), traderLimiter),
+		}
+		RegisterOrderbookServiceServer(server.Server, service)
+		go server.Start("0.0.0.0:18514")
+		time.Sleep(time.Millisecond)
+	})
+
+	AfterEach(func() {
+		server.Stop()
+		store.Release()
+		os.RemoveAll("./tmp")
+	})
+
+	// createEncryptedFragments for orders that have been opened by the trader
+	createEncryptedFragments := func(n int, trader crypto.EcdsaKey) []order.EncryptedFragment {
+		orderFragments := make([]order.EncryptedFragment, n)
+		for i := range orderFragments {
+			orderFragment, err := createEncryptedFragment()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderFragments[i] = orderFragment
+			Expect(store.OrderbookOrderStore().PutOrder(orderFragment.OrderID, order.Open, ethCrypto.PubkeyToAddress(trader.PublicKey).Hex(), uint(i))).ShouldNot(HaveOccurred())
+		}
+		return orderFragments
+	}
+
+	Context("when opening many order fragments", func() {
+
+		It("should accept order fragments within the quota of the trader", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			errs, err := client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, createEncryptedFragments(2, traderKey))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(2))
+			for _, err := range errs {
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(2)))
+		})
+
+		It("should reject order fragments beyond the quota of the trader", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			otherTraderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			errs, err := client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, createEncryptedFragments(4, traderKey))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(4))
+			Expect(errs[0]).ShouldNot(HaveOccurred())
+			Expect(errs[1]).ShouldNot(HaveOccurred())
+			Expect(errs[2]).Should(MatchError(ErrTraderQuotaExceeded.Error()))
+			Expect(errs[3]).Should(MatchError(ErrTraderQuotaExceeded.Error()))
+
+			// The quota of one trader does not affect other traders
+			errs, err = client.OpenOrders(context.Background(), serviceMultiAddr, &otherTraderKey, createEncryptedFragments(1, otherTraderKey))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs[0]).ShouldNot(HaveOccurred())
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(3)))
+		})
+
+		It("should reject order fragments that are not signed by the trader of their order", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			otherTraderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			orderFragments := createEncryptedFragments(2, traderKey)
+			errs, err := client.OpenOrders(context.Background(), serviceMultiAddr, &otherTraderKey, orderFragments)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(2))
+			Expect(errs[0]).Should(MatchError(ErrUnexpectedTrader.Error()))
+			Expect(errs[1]).Should(MatchError(ErrUnexpectedTrader.Error()))
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(0)))
+
+			// Rejected order fragments do not count against the quota of the
+			// trader
+			errs, err = client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, orderFragments[:2])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs[0]).ShouldNot(HaveOccurred())
+			Expect(errs[1]).ShouldNot(HaveOccurred())
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(2)))
+		})
+
+		It("should accept order fragments for orders that have not been synced within the quota of the signatory", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderFragments := createEncryptedFragments(1, traderKey)
+			for i := 0; i < 2; i++ {
+				unsyncedOrderFragment, err := createEncryptedFragment()
+				Expect(err).ShouldNot(HaveOccurred())
+				orderFragments = append(orderFragments, unsyncedOrderFragment)
+			}
+
+			// Order fragments for synced and unsynced orders share the quota
+			// of the trader
+			errs, err := client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, orderFragments)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(3))
+			Expect(errs[0]).ShouldNot(HaveOccurred())
+			Expect(errs[1]).ShouldNot(HaveOccurred())
+			Expect(errs[2]).Should(MatchError(ErrTraderQuotaExceeded.Error()))
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(2)))
+		})
+
+		It("should only resend order fragments that have not been acknowledged", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// The first stream is interrupted after one order fragment has
+			// been acknowledged, and resending it would exceed the quota
+			service.interruptAfter = 1
+			errs, err := client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, createEncryptedFragments(2, traderKey))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(2))
+			Expect(errs[0]).ShouldNot(HaveOccurred())
+			Expect(errs[1]).ShouldNot(HaveOccurred())
+			Expect(atomic.LoadInt64(&service.streams)).Should(Equal(int64(2)))
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(2)))
+		})
+
+		It("should return an error for each invalid order fragment", func() {
+			conn, err := Dial(context.Background(), serviceMultiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			orderFragment, err := createEncryptedFragment()
+			Expect(err).ShouldNot(HaveOccurred())
+			stream, err := NewOrderbookServiceClient(conn).OpenOrders(context.Background())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stream.Send(&OpenOrdersRequest{})).ShouldNot(HaveOccurred())
+			Expect(stream.Send(&OpenOrdersRequest{
+				OrderFragment: &EncryptedOrderFragment{
+					OrderId:       orderFragment.OrderID[:],
+					Id:            orderFragment.ID[:],
+					Price:         &EncryptedCoExpShare{},
+					Volume:        &EncryptedCoExpShare{},
+					MinimumVolume: &EncryptedCoExpShare{},
+				},
+				Signature: []byte("invalid"),
+			})).ShouldNot(HaveOccurred())
+			Expect(stream.CloseSend()).ShouldNot(HaveOccurred())
+
+			ack, err := stream.Recv()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ack.Error).Should(Equal(ErrOpenOrdersRequestIsNil.Error()))
+			ack, err = stream.Recv()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ack.OrderId).Should(Equal(orderFragment.OrderID[:]))
+			Expect(ack.Error).ShouldNot(BeEmpty())
+			_, err = stream.Recv()
+			Expect(err).Should(Equal(io.EOF))
+			Expect(atomic.LoadInt64(&receipterMock.n)).Should(Equal(int64(0)))
+		})
+
+		It("should return an error when too many order fragments are opened", func() {
+			traderKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = client.OpenOrders(context.Background(), serviceMultiAddr, &traderKey, make([]order.EncryptedFragment, MaxOpenOrdersPerStream+1))
+			Expect(err).Should(Equal(ErrTooManyOrderFragments))
+		})
+	})
+})
+
+// interruptedOrderbookService is an OrderbookService that interrupts the first
+// OpenOrders stream after it has acknowledged some of the order fragments.
+type interruptedOrderbookService struct {
+	OrderbookService
+
+	interruptAfter int
+	streams        int64
+}
+
+func (service *interruptedOrderbookService) OpenOrders(stream OrderbookService_OpenOrdersServer) error {
+	if atomic.AddInt64(&service.streams, 1) > 1 || service.interruptAfter == 0 {
+		return service.OrderbookService.OpenOrders(stream)
+	}
+	return service.OrderbookService.OpenOrders(&interruptedStream{stream, service.interruptAfter})
+}
+
+type interruptedStream struct {
+	OrderbookService_OpenOrdersServer
+
+	remaining int
+}
+
+func (stream *interruptedStream) Recv() (*OpenOrdersRequest, error) {
+	if stream.remaining == 0 {
+		return nil, errors.New("stream interrupted")
+	}
+	stream.remaining--
+	return stream.OrderbookService_OpenOrdersServer.Recv()
+}
+
 type mockOrderbookServer struct {
 	n int64
 }
@@ -82,3 +404,37 @@ func createEncryptedFragment() (order.EncryptedFragment, error) {
 	}
 	return ordFragments[0].Encrypt(rsaKey.PublicKey)
 }
+
+type mockReceipter struct {
+	mockOrderbookServer
+	receipts map[order.ID]orderbook.Receipt
+}
+
+func (receipter *mockReceipter) OnChangeEpoch(epoch registry.Epoch) {
+}
+
+func (receipter *mockReceipter) Run(done <-chan struct{}) {
+}
+
+func (receipter *mockReceipter) Receipt(orderID order.ID) (orderbook.Receipt, error) {
+	receipt, ok := receipter.receipts[orderID]
+	if !ok {
+		return orderbook.Receipt{}, orderbook.ErrReceiptNotFound
+	}
+	return receipt, nil
+}
+
+type mockTracker struct {
+	lifecycles map[order.ID]ome.OrderLifecycle
+}
+
+func (tracker *mockTracker) OnChangeEpoch(epoch registry.Epoch) {
+}
+
+func (tracker *mockTracker) OrderLifecycle(orderID order.ID) (ome.OrderLifecycle, error) {
+	lifecycle, ok := tracker.lifecycles[orderID]
+	if !ok {
+		return ome.OrderLifecycle{}, ome.ErrOrderLifecycleNotFound
+	}
+	return lifecycle, nil
+}
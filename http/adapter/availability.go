@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+// ErrInvalidOrderID is returned when an order ID is not the base64 encoding
+// of an order.ID.
+var ErrInvalidOrderID = errors.New("invalid order id")
+
+// FragmentAvailability defines a structure for JSON marshalling
+type FragmentAvailability struct {
+	OrderID      string                `json:"orderId"`
+	Epoch        string                `json:"epoch"`
+	Attestations []FragmentAttestation `json:"attestations"`
+	Missing      []string              `json:"missing"`
+	Timestamp    time.Time             `json:"timestamp"`
+}
+
+// FragmentAttestation defines a structure for JSON marshalling. The
+// FragmentID, and the CommitmentsHash, are empty when the darknode does not
+// hold an order fragment.
+type FragmentAttestation struct {
+	Darknode        string `json:"darknode"`
+	FragmentID      string `json:"fragmentId"`
+	CommitmentsHash string `json:"commitmentsHash"`
+	Signature       string `json:"signature"`
+}
+
+// AvailabilityAdapter defines a struct which has fragment availability
+// reading capability
+type AvailabilityAdapter struct {
+	orderbook.Availability
+}
+
+// NewAvailabilityAdapter returns an adapter which reads the availability of
+// order fragments across the pod of a darknode
+func NewAvailabilityAdapter(availability orderbook.Availability) AvailabilityAdapter {
+	return AvailabilityAdapter{
+		Availability: availability,
+	}
+}
+
+// FragmentAvailability returns the FragmentAvailability of the order with the
+// given base64 encoded order ID
+func (adapter *AvailabilityAdapter) FragmentAvailability(ctx context.Context, orderID string) (FragmentAvailability, error) {
+	id, err := unmarshalOrderID(orderID)
+	if err != nil {
+		return FragmentAvailability{}, err
+	}
+
+	report, err := adapter.Availability.FragmentAvailability(ctx, id)
+	if err != nil {
+		return FragmentAvailability{}, err
+	}
+	availability := FragmentAvailability{
+		OrderID:      orderID,
+		Epoch:        base64.StdEncoding.EncodeToString(report.Epoch[:]),
+		Attestations: make([]FragmentAttestation, len(report.Attestations)),
+		Missing:      make([]string, len(report.Missing)),
+		Timestamp:    report.Timestamp,
+	}
+	for i, attestation := range report.Attestations {
+		availability.Attestations[i] = FragmentAttestation{
+			Darknode:  attestation.Darknode.String(),
+			Signature: base64.StdEncoding.EncodeToString(attestation.Signature),
+		}
+		if attestation.IsHeld() {
+			availability.Attestations[i].FragmentID = base64.StdEncoding.EncodeToString(attestation.FragmentID[:])
+			availability.Attestations[i].CommitmentsHash = base64.StdEncoding.EncodeToString(attestation.CommitmentsHash[:])
+		}
+	}
+	for i, addr := range report.Missing {
+		availability.Missing[i] = addr.String()
+	}
+	return availability, nil
+}
+
+func unmarshalOrderID(orderID string) (order.ID, error) {
+	orderIDBytes, err := base64.StdEncoding.DecodeString(orderID)
+	if err != nil || len(orderIDBytes) != 32 {
+		return order.ID{}, ErrInvalidOrderID
+	}
+	id := order.ID{}
+	copy(id[:], orderIDBytes)
+	return id, nil
+}
@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// JournalEntry defines a structure for JSON marshalling
+type JournalEntry struct {
+	ID        string        `json:"id"`
+	Kind      string        `json:"kind"`
+	Buy       string        `json:"buy"`
+	Sell      string        `json:"sell"`
+	Orders    []order.Order `json:"orders"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// JournalAdapter defines a struct which has journal reading capability
+type JournalAdapter struct {
+	ome.JournalStorer
+}
+
+// NewJournalAdapter returns an adapter which reads the journal of a shadow
+// ome.ContractBinder
+func NewJournalAdapter(journalStore ome.JournalStorer) JournalAdapter {
+	return JournalAdapter{
+		JournalStorer: journalStore,
+	}
+}
+
+// Journal returns all JournalEntries in the order that they were recorded
+func (adapter *JournalAdapter) Journal() ([]JournalEntry, error) {
+	entriesIter, err := adapter.JournalEntries()
+	if err != nil {
+		return nil, err
+	}
+	defer entriesIter.Release()
+
+	entries, err := entriesIter.Collect()
+	if err != nil {
+		return nil, err
+	}
+	journal := make([]JournalEntry, len(entries))
+	for i, entry := range entries {
+		journal[i] = JournalEntry{
+			ID:        entry.ID.String(),
+			Kind:      entry.Kind.String(),
+			Buy:       base64.StdEncoding.EncodeToString(entry.Buy[:]),
+			Sell:      base64.StdEncoding.EncodeToString(entry.Sell[:]),
+			Orders:    entry.Orders,
+			Timestamp: entry.Timestamp,
+		}
+	}
+	return journal, nil
+}
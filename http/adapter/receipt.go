@@ -0,0 +1,85 @@
+package adapter
+
+import (
+	"encoding/base64"
+
+	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+// Receipt defines a structure for JSON marshalling
+type Receipt struct {
+	OrderID    string `json:"orderId"`
+	FragmentID string `json:"fragmentId"`
+	EpochDepth uint32 `json:"epochDepth"`
+	Decrypted  bool   `json:"decrypted"`
+	Darknode   string `json:"darknode"`
+	Signature  string `json:"signature"`
+}
+
+// OrderLifecycle defines a structure for JSON marshalling. The ComputationID
+// is empty when the order has not been matched.
+type OrderLifecycle struct {
+	OrderID       string `json:"orderId"`
+	Stage         string `json:"stage"`
+	ComputationID string `json:"computationId"`
+}
+
+// ReceiptAdapter defines a struct which has order fragment receipt, and order
+// lifecycle, reading capability
+type ReceiptAdapter struct {
+	receipter orderbook.Receipter
+	tracker   ome.Tracker
+}
+
+// NewReceiptAdapter returns an adapter which reads the receipts of order
+// fragments received by a darknode, and the lifecycle of orders as seen by
+// the darknode
+func NewReceiptAdapter(receipter orderbook.Receipter, tracker ome.Tracker) ReceiptAdapter {
+	return ReceiptAdapter{
+		receipter: receipter,
+		tracker:   tracker,
+	}
+}
+
+// Receipt returns the signed Receipt for the order fragment received for the
+// order with the given base64 encoded order ID
+func (adapter *ReceiptAdapter) Receipt(orderID string) (Receipt, error) {
+	id, err := unmarshalOrderID(orderID)
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt, err := adapter.receipter.Receipt(id)
+	if err != nil {
+		return Receipt{}, err
+	}
+	return Receipt{
+		OrderID:    orderID,
+		FragmentID: base64.StdEncoding.EncodeToString(receipt.FragmentID[:]),
+		EpochDepth: uint32(receipt.EpochDepth),
+		Decrypted:  receipt.Decrypted,
+		Darknode:   receipt.Darknode.String(),
+		Signature:  base64.StdEncoding.EncodeToString(receipt.Signature),
+	}, nil
+}
+
+// OrderLifecycle returns the OrderLifecycle of the order with the given
+// base64 encoded order ID
+func (adapter *ReceiptAdapter) OrderLifecycle(orderID string) (OrderLifecycle, error) {
+	id, err := unmarshalOrderID(orderID)
+	if err != nil {
+		return OrderLifecycle{}, err
+	}
+	lifecycle, err := adapter.tracker.OrderLifecycle(id)
+	if err != nil {
+		return OrderLifecycle{}, err
+	}
+	orderLifecycle := OrderLifecycle{
+		OrderID: orderID,
+		Stage:   lifecycle.Stage.String(),
+	}
+	if lifecycle.ComputationID != (ome.ComputationID{}) {
+		orderLifecycle.ComputationID = base64.StdEncoding.EncodeToString(lifecycle.ComputationID[:])
+	}
+	return orderLifecycle, nil
+}
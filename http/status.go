@@ -1,12 +1,16 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	netHttp "net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/republicprotocol/republic-go/http/adapter"
+	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/rs/cors"
 )
 
@@ -14,6 +18,51 @@ import (
 func NewStatusServer(statusAdapter adapter.StatusAdapter) netHttp.Handler {
 	r := mux.NewRouter().StrictSlash(true)
 	r.HandleFunc("/status", statusHandler(statusAdapter)).Methods("GET")
+	return newStatusServer(r)
+}
+
+// NewStatusServerWithJournal returns a new http.Handler for serving darknode
+// status, and the journal of transactions recorded by a darknode running in
+// shadow mode
+func NewStatusServerWithJournal(statusAdapter adapter.StatusAdapter, journalAdapter adapter.JournalAdapter) netHttp.Handler {
+	r := mux.NewRouter().StrictSlash(true)
+	r.HandleFunc("/status", statusHandler(statusAdapter)).Methods("GET")
+	r.HandleFunc("/journal", journalHandler(journalAdapter)).Methods("GET")
+	return newStatusServer(r)
+}
+
+// NewStatusServerWithAvailability returns a new http.Handler for serving
+// darknode status, and the availability of order fragments across the pod of
+// the darknode so that traders can find the darknodes that need an order
+// fragment to be resent. The journal is also served if a JournalAdapter is
+// given
+func NewStatusServerWithAvailability(statusAdapter adapter.StatusAdapter, availabilityAdapter adapter.AvailabilityAdapter, journalAdapter *adapter.JournalAdapter) netHttp.Handler {
+	r := mux.NewRouter().StrictSlash(true)
+	r.HandleFunc("/status", statusHandler(statusAdapter)).Methods("GET")
+	r.HandleFunc("/availability", availabilityHandler(availabilityAdapter)).Methods("GET")
+	if journalAdapter != nil {
+		r.HandleFunc("/journal", journalHandler(*journalAdapter)).Methods("GET")
+	}
+	return newStatusServer(r)
+}
+
+// NewStatusServerWithReceipts returns a new http.Handler that serves the same
+// endpoints as NewStatusServerWithAvailability, and also serves signed
+// receipts for the order fragments received by the darknode, and the
+// lifecycle of orders as seen by the darknode
+func NewStatusServerWithReceipts(statusAdapter adapter.StatusAdapter, availabilityAdapter adapter.AvailabilityAdapter, receiptAdapter adapter.ReceiptAdapter, journalAdapter *adapter.JournalAdapter) netHttp.Handler {
+	r := mux.NewRouter().StrictSlash(true)
+	r.HandleFunc("/status", statusHandler(statusAdapter)).Methods("GET")
+	r.HandleFunc("/availability", availabilityHandler(availabilityAdapter)).Methods("GET")
+	r.HandleFunc("/receipt", receiptHandler(receiptAdapter)).Methods("GET")
+	r.HandleFunc("/lifecycle", lifecycleHandler(receiptAdapter)).Methods("GET")
+	if journalAdapter != nil {
+		r.HandleFunc("/journal", journalHandler(*journalAdapter)).Methods("GET")
+	}
+	return newStatusServer(r)
+}
+
+func newStatusServer(r *mux.Router) netHttp.Handler {
 	r.Use(RecoveryHandler)
 
 	handler := cors.New(cors.Options{
@@ -44,3 +93,109 @@ func statusHandler(statusAdapter adapter.StatusAdapter) netHttp.HandlerFunc {
 		w.Write(str)
 	}
 }
+
+// journalHandler
+func journalHandler(journalAdapter adapter.JournalAdapter) netHttp.HandlerFunc {
+	return func(w netHttp.ResponseWriter, r *netHttp.Request) {
+		journal, err := journalAdapter.Journal()
+		if err != nil {
+			WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot retrieve journal: %v", err))
+			return
+		}
+		str, err := json.Marshal(journal)
+		if err != nil {
+			WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot convert journal into json: %v", err))
+			return
+		}
+		// Set content type to JSON before StatusOK or it will be ignored
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(netHttp.StatusOK)
+		w.Write(str)
+	}
+}
+
+// availabilityHandler returns the availability of the order fragments of an
+// order across the Pod of the Darknode.
+func availabilityHandler(availabilityAdapter adapter.AvailabilityAdapter) netHttp.HandlerFunc {
+	return func(w netHttp.ResponseWriter, r *netHttp.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		availability, err := availabilityAdapter.FragmentAvailability(ctx, r.URL.Query().Get("id"))
+		if err != nil {
+			switch err {
+			case adapter.ErrInvalidOrderID:
+				WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot retrieve fragment availability: %v", err))
+			case orderbook.ErrFragmentAvailabilityNotFound:
+				WriteError(w, netHttp.StatusNotFound, fmt.Sprintf("cannot retrieve fragment availability: %v", err))
+			default:
+				WriteError(w, netHttp.StatusInternalServerError, fmt.Sprintf("cannot retrieve fragment availability: %v", err))
+			}
+			return
+		}
+		str, err := json.Marshal(availability)
+		if err != nil {
+			WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot convert fragment availability into json: %v", err))
+			return
+		}
+		// Set content type to JSON before StatusOK or it will be ignored
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(netHttp.StatusOK)
+		w.Write(str)
+	}
+}
+
+// receiptHandler returns the signed receipt for the order fragment of an
+// order that was received by the Darknode.
+func receiptHandler(receiptAdapter adapter.ReceiptAdapter) netHttp.HandlerFunc {
+	return func(w netHttp.ResponseWriter, r *netHttp.Request) {
+		receipt, err := receiptAdapter.Receipt(r.URL.Query().Get("id"))
+		if err != nil {
+			switch err {
+			case adapter.ErrInvalidOrderID:
+				WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot retrieve receipt: %v", err))
+			case orderbook.ErrReceiptNotFound:
+				WriteError(w, netHttp.StatusNotFound, fmt.Sprintf("cannot retrieve receipt: %v", err))
+			default:
+				WriteError(w, netHttp.StatusInternalServerError, fmt.Sprintf("cannot retrieve receipt: %v", err))
+			}
+			return
+		}
+		str, err := json.Marshal(receipt)
+		if err != nil {
+			WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot convert receipt into json: %v", err))
+			return
+		}
+		// Set content type to JSON before StatusOK or it will be ignored
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(netHttp.StatusOK)
+		w.Write(str)
+	}
+}
+
+// lifecycleHandler returns the lifecycle of an order as seen by the Darknode.
+func lifecycleHandler(receiptAdapter adapter.ReceiptAdapter) netHttp.HandlerFunc {
+	return func(w netHttp.ResponseWriter, r *netHttp.Request) {
+		lifecycle, err := receiptAdapter.OrderLifecycle(r.URL.Query().Get("id"))
+		if err != nil {
+			switch err {
+			case adapter.ErrInvalidOrderID:
+				WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot retrieve order lifecycle: %v", err))
+			case ome.ErrOrderLifecycleNotFound:
+				WriteError(w, netHttp.StatusNotFound, fmt.Sprintf("cannot retrieve order lifecycle: %v", err))
+			default:
+				WriteError(w, netHttp.StatusInternalServerError, fmt.Sprintf("cannot retrieve order lifecycle: %v", err))
+			}
+			return
+		}
+		str, err := json.Marshal(lifecycle)
+		if err != nil {
+			WriteError(w, netHttp.StatusBadRequest, fmt.Sprintf("cannot convert order lifecycle into json: %v", err))
+			return
+		}
+		// Set content type to JSON before StatusOK or it will be ignored
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(netHttp.StatusOK)
+		w.Write(str)
+	}
+}
@@ -2,14 +2,26 @@ package http_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	netHttp "net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/republicprotocol/republic-go/http"
 
 	"github.com/republicprotocol/republic-go/http/adapter"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/registry"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -46,3 +58,242 @@ var _ = Describe("Status handler", func() {
 		})
 	})
 })
+
+var _ = Describe("Journal handler", func() {
+
+	var store *leveldb.Store
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.journal.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.journal.out")
+	})
+
+	It("should return the journal in the order that it was recorded", func() {
+		buy, sell := testutils.RandomOrder(), testutils.RandomOrder()
+		Expect(store.SomerJournalStore().PutJournalEntry(ome.NewJournalEntry(ome.JournalEntryKindConfirmOrder, buy.ID, sell.ID))).ShouldNot(HaveOccurred())
+		Expect(store.SomerJournalStore().PutJournalEntry(ome.NewJournalEntry(ome.JournalEntryKindSettle, buy.ID, sell.ID, buy, sell))).ShouldNot(HaveOccurred())
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/journal", bytes.NewBuffer([]byte{}))
+
+		reader := testutils.NewMockReader(false)
+		server := NewStatusServerWithJournal(adapter.NewStatusAdapter(&reader), adapter.NewJournalAdapter(store.SomerJournalStore()))
+		server.ServeHTTP(w, r)
+		Expect(w.Code).To(Equal(netHttp.StatusOK))
+
+		journal := []adapter.JournalEntry{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &journal)).ShouldNot(HaveOccurred())
+		Expect(journal).Should(HaveLen(2))
+		Expect(journal[0].Kind).Should(Equal("confirmOrder"))
+		Expect(journal[1].Kind).Should(Equal("settle"))
+		Expect(journal[1].Orders).Should(HaveLen(2))
+	})
+
+	It("should not serve the journal from a status server without one", func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/journal", bytes.NewBuffer([]byte{}))
+
+		reader := testutils.NewMockReader(false)
+		NewStatusServer(adapter.NewStatusAdapter(&reader)).ServeHTTP(w, r)
+		Expect(w.Code).To(Equal(netHttp.StatusNotFound))
+	})
+})
+
+var _ = Describe("Availability handler", func() {
+
+	sendRequest := func(availability orderbook.Availability, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/availability?id="+id, bytes.NewBuffer([]byte{}))
+
+		reader := testutils.NewMockReader(false)
+		server := NewStatusServerWithAvailability(adapter.NewStatusAdapter(&reader), adapter.NewAvailabilityAdapter(availability), nil)
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	It("should return the fragment availability of an order", func() {
+		ord := testutils.RandomOrder()
+		held, err := testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		missing, err := testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		availability := &mockAvailability{
+			orderID: ord.ID,
+			held:    held,
+			missing: missing,
+		}
+
+		w := sendRequest(availability, url.QueryEscape(base64.StdEncoding.EncodeToString(ord.ID[:])))
+		Expect(w.Code).To(Equal(netHttp.StatusOK))
+
+		report := adapter.FragmentAvailability{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &report)).ShouldNot(HaveOccurred())
+		Expect(report.Attestations).Should(HaveLen(2))
+		Expect(report.Attestations[0].Darknode).Should(Equal(availability.held.String()))
+		Expect(report.Attestations[0].FragmentID).ShouldNot(BeEmpty())
+		Expect(report.Attestations[1].FragmentID).Should(BeEmpty())
+		Expect(report.Missing).Should(Equal([]string{availability.missing.String()}))
+	})
+
+	It("should return a 400 (StatusBadRequest) status code for an invalid order ID", func() {
+		w := sendRequest(&mockAvailability{}, "invalid")
+		Expect(w.Code).To(Equal(netHttp.StatusBadRequest))
+	})
+
+	It("should return a 404 (StatusNotFound) status code for an unknown order", func() {
+		ord := testutils.RandomOrder()
+		w := sendRequest(&mockAvailability{}, url.QueryEscape(base64.StdEncoding.EncodeToString(ord.ID[:])))
+		Expect(w.Code).To(Equal(netHttp.StatusNotFound))
+	})
+})
+
+var _ = Describe("Receipt handlers", func() {
+
+	var orderID order.ID
+	var receiptAdapter adapter.ReceiptAdapter
+
+	BeforeEach(func() {
+		orderID = testutils.RandomOrder().ID
+		receiptAdapter = adapter.NewReceiptAdapter(
+			&mockReceipter{
+				receipt: orderbook.Receipt{
+					OrderID:    orderID,
+					FragmentID: testutils.Random32Bytes(),
+					EpochDepth: 1,
+					Decrypted:  true,
+					Darknode:   identity.Address("darknode"),
+					Signature:  []byte("signature"),
+				},
+			},
+			&mockTracker{
+				lifecycle: ome.OrderLifecycle{
+					OrderID: orderID,
+					Stage:   ome.OrderStageInMatrix,
+				},
+			})
+	})
+
+	sendRequest := func(path, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://localhost/"+path+"?id="+url.QueryEscape(id), bytes.NewBuffer([]byte{}))
+
+		reader := testutils.NewMockReader(false)
+		server := NewStatusServerWithReceipts(adapter.NewStatusAdapter(&reader), adapter.NewAvailabilityAdapter(&mockAvailability{}), receiptAdapter, nil)
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	It("should return the receipt for an order fragment", func() {
+		w := sendRequest("receipt", base64.StdEncoding.EncodeToString(orderID[:]))
+		Expect(w.Code).To(Equal(netHttp.StatusOK))
+
+		receipt := adapter.Receipt{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &receipt)).ShouldNot(HaveOccurred())
+		Expect(receipt.OrderID).Should(Equal(base64.StdEncoding.EncodeToString(orderID[:])))
+		Expect(receipt.EpochDepth).Should(Equal(uint32(1)))
+		Expect(receipt.Decrypted).Should(BeTrue())
+		Expect(receipt.Darknode).Should(Equal("darknode"))
+		Expect(receipt.Signature).Should(Equal(base64.StdEncoding.EncodeToString([]byte("signature"))))
+	})
+
+	It("should return the lifecycle of an order", func() {
+		w := sendRequest("lifecycle", base64.StdEncoding.EncodeToString(orderID[:]))
+		Expect(w.Code).To(Equal(netHttp.StatusOK))
+
+		lifecycle := adapter.OrderLifecycle{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &lifecycle)).ShouldNot(HaveOccurred())
+		Expect(lifecycle.Stage).Should(Equal("inMatrix"))
+		Expect(lifecycle.ComputationID).Should(BeEmpty())
+	})
+
+	It("should return a 400 (StatusBadRequest) status code for an invalid order ID", func() {
+		Expect(sendRequest("receipt", "invalid").Code).To(Equal(netHttp.StatusBadRequest))
+		Expect(sendRequest("lifecycle", "invalid").Code).To(Equal(netHttp.StatusBadRequest))
+	})
+
+	It("should return a 404 (StatusNotFound) status code for an unknown order", func() {
+		unknown := testutils.RandomOrder().ID
+		Expect(sendRequest("receipt", base64.StdEncoding.EncodeToString(unknown[:])).Code).To(Equal(netHttp.StatusNotFound))
+		Expect(sendRequest("lifecycle", base64.StdEncoding.EncodeToString(unknown[:])).Code).To(Equal(netHttp.StatusNotFound))
+	})
+})
+
+// mockAvailability reports that one Darknode holds an order fragment, and
+// that another Darknode is missing its order fragment.
+type mockAvailability struct {
+	orderID order.ID
+	held    identity.Address
+	missing identity.Address
+}
+
+func (availability *mockAvailability) AttestOrderFragment(ctx context.Context, orderID order.ID) (orderbook.FragmentAttestation, error) {
+	return orderbook.FragmentAttestation{}, orderbook.ErrFragmentAvailabilityNotFound
+}
+
+func (availability *mockAvailability) Run(done <-chan struct{}) {
+}
+
+func (availability *mockAvailability) OnChangeEpoch(epoch registry.Epoch) {
+}
+
+func (availability *mockAvailability) FragmentAvailability(ctx context.Context, orderID order.ID) (orderbook.FragmentAvailability, error) {
+	if !orderID.Equal(availability.orderID) {
+		return orderbook.FragmentAvailability{}, orderbook.ErrFragmentAvailabilityNotFound
+	}
+	return orderbook.FragmentAvailability{
+		OrderID: orderID,
+		Attestations: []orderbook.FragmentAttestation{
+			{OrderID: orderID, FragmentID: testutils.Random32Bytes(), Darknode: availability.held},
+			{OrderID: orderID, Darknode: availability.missing},
+		},
+		Missing:   []identity.Address{availability.missing},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (availability *mockAvailability) VerifyCommitments(ctx context.Context, orderFragment order.Fragment) error {
+	return orderbook.ErrFragmentAvailabilityNotFound
+}
+
+// mockReceipter returns a Receipt for one order.
+type mockReceipter struct {
+	receipt orderbook.Receipt
+}
+
+func (receipter *mockReceipter) OpenOrder(ctx context.Context, orderFragment order.EncryptedFragment) error {
+	return nil
+}
+
+func (receipter *mockReceipter) OnChangeEpoch(epoch registry.Epoch) {
+}
+
+func (receipter *mockReceipter) Run(done <-chan struct{}) {
+}
+
+func (receipter *mockReceipter) Receipt(orderID order.ID) (orderbook.Receipt, error) {
+	if !orderID.Equal(receipter.receipt.OrderID) {
+		return orderbook.Receipt{}, orderbook.ErrReceiptNotFound
+	}
+	return receipter.receipt, nil
+}
+
+// mockTracker returns an OrderLifecycle for one order.
+type mockTracker struct {
+	lifecycle ome.OrderLifecycle
+}
+
+func (tracker *mockTracker) OnChangeEpoch(epoch registry.Epoch) {
+}
+
+func (tracker *mockTracker) OrderLifecycle(orderID order.ID) (ome.OrderLifecycle, error) {
+	if !orderID.Equal(tracker.lifecycle.OrderID) {
+		return ome.OrderLifecycle{}, ome.ErrOrderLifecycleNotFound
+	}
+	return tracker.lifecycle, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/oracle"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/swarm"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -36,8 +37,9 @@ var (
 // OrderbookPointerTable have a length of 0 bytes, and so 64 bytes of padding
 // is needed to ensure that keys are 64 bytes.
 var (
-	OrderbookPointerTableBegin   = []byte{0x03, 0x00}
-	OrderbookPointerTablePadding = paddingBytes(0x00, 64)
+	OrderbookPointerTableBegin      = []byte{0x03, 0x00}
+	OrderbookPointerTablePadding    = paddingBytes(0x00, 64)
+	OrderbookBlockPointerTableBegin = []byte{0x03, 0x01}
 )
 
 // Constants for use in the SomerComputationTable. Keys in the
@@ -72,6 +74,35 @@ var (
 	SomerSellOrderFragmentIterEnd      = paddingBytes(0xFF, 32)
 )
 
+// Constants for use in the SomerSettlementTable. Keys in the
+// SomerSettlementTable have a length of 32 bytes, and so 32 bytes of padding
+// is needed to ensure that keys are 64 bytes.
+var (
+	SomerSettlementTableBegin   = []byte{0x13, 0x00}
+	SomerSettlementTablePadding = paddingBytes(0x00, 32)
+	SomerSettlementIterBegin    = paddingBytes(0x00, 32)
+	SomerSettlementIterEnd      = paddingBytes(0xFF, 32)
+)
+
+// Constants for use in the SomerJournalTable. Keys in the SomerJournalTable
+// have a length of 32 bytes, 8 bytes for the timestamp and 24 bytes for the
+// JournalEntryID, and so 32 bytes of padding is needed to ensure that keys are
+// 64 bytes.
+var (
+	SomerJournalTableBegin   = []byte{0x14, 0x00}
+	SomerJournalTablePadding = paddingBytes(0x00, 32)
+	SomerJournalIterBegin    = paddingBytes(0x00, 32)
+	SomerJournalIterEnd      = paddingBytes(0xFF, 32)
+)
+
+// Constants for use in the OracleMidpointPriceTable. Keys in the
+// OracleMidpointPriceTable have a length of 0 bytes, and so 64 bytes of
+// padding is needed to ensure that keys are 64 bytes.
+var (
+	OracleMidpointPriceTableBegin   = []byte{0x30, 0x00}
+	OracleMidpointPriceTablePadding = paddingBytes(0x00, 64)
+)
+
 // Constants for use in the SwarmMultiAddress. Keys in the
 // SwarmMultiAddressTable have a length of 32 bytes, and so 32 bytes of padding is
 // needed to ensure that keys are 64 bytes.
@@ -96,6 +127,10 @@ type Store struct {
 
 	somerComputationTable   *SomerComputationTable
 	somerOrderFragmentTable *SomerOrderFragmentTable
+	somerSettlementTable    *SomerSettlementTable
+	somerJournalTable       *SomerJournalTable
+
+	oracleMidpointPriceTable *OracleMidpointPriceTable
 
 	swarmMultiAddressTable *SwarmMultiAddressTable
 }
@@ -123,6 +158,10 @@ func NewStore(dir string, expiry time.Duration, multiAddressStorerExpiry time.Du
 
 		somerComputationTable:   NewSomerComputationTable(db),
 		somerOrderFragmentTable: NewSomerOrderFragmentTable(db, expiry),
+		somerSettlementTable:    NewSomerSettlementTable(db),
+		somerJournalTable:       NewSomerJournalTable(db),
+
+		oracleMidpointPriceTable: NewOracleMidpointPriceTable(db),
 
 		swarmMultiAddressTable: NewSwarmMultiAddressTable(db, multiAddressStorerExpiry),
 	}, nil
@@ -133,7 +172,9 @@ func (store *Store) Release() error {
 	return store.db.Close()
 }
 
-// Prune the Store by deleting expired data.
+// Prune the Store by deleting expired data. Settlements are never pruned, so
+// that failed settlements can be inspected and replayed, and neither is the
+// journal of a shadow ContractBinder.
 func (store *Store) Prune() (err error) {
 	if localErr := store.orderbookOrderTable.Prune(); localErr != nil {
 		err = localErr
@@ -183,6 +224,24 @@ func (store *Store) SomerOrderFragmentStore() ome.OrderFragmentStorer {
 	return store.somerOrderFragmentTable
 }
 
+// SomerSettlementStore returns the SomerSettlementTable used by the Store. It
+// implements the ome.SettlementStorer interface.
+func (store *Store) SomerSettlementStore() ome.SettlementStorer {
+	return store.somerSettlementTable
+}
+
+// SomerJournalStore returns the SomerJournalTable used by the Store. It
+// implements the ome.JournalStorer interface.
+func (store *Store) SomerJournalStore() ome.JournalStorer {
+	return store.somerJournalTable
+}
+
+// OracleMidpointPriceStore returns the OracleMidpointPriceTable used by the
+// Store. It implements the oracle.MidpointPriceStorer interface.
+func (store *Store) OracleMidpointPriceStore() oracle.MidpointPriceStorer {
+	return store.oracleMidpointPriceTable
+}
+
 // SwarmMultiAddressStore returns the SwarmMultiAddressTable used by the Store.
 // It implements the swarm.MultiAddressStorer interface.
 func (store *Store) SwarmMultiAddressStore() swarm.MultiAddressStorer {
@@ -178,6 +178,14 @@ var _ = Describe("LevelDB storage", func() {
 			pointer, err := db.OrderbookPointerStore().Pointer()
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(pointer).Should(Equal(orderbook.Pointer(42)))
+			err = db.OrderbookPointerStore().PutBlockPointer(orderbook.BlockPointer{Number: 1337, Hash: [32]byte{13, 37}})
+			Expect(err).ShouldNot(HaveOccurred())
+			blockPointer, err := db.OrderbookPointerStore().BlockPointer()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(blockPointer).Should(Equal(orderbook.BlockPointer{Number: 1337, Hash: [32]byte{13, 37}}))
+			pointer, err = db.OrderbookPointerStore().Pointer()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pointer).Should(Equal(orderbook.Pointer(42)))
 
 			err = db.Release()
 			Expect(err).ShouldNot(HaveOccurred())
@@ -1,51 +1,67 @@
 package leveldb
 
 import (
-	"sync"
+	"encoding/json"
 
 	"github.com/republicprotocol/republic-go/oracle"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
-// midpointPriceStorer implements MidpointPriceStorer interface with an
-// in-memory map implementation. Data will be lost every time it restarts.
-type midpointPriceStorer struct {
-	mu     *sync.Mutex
-	prices oracle.MidpointPrice
+// OracleMidpointPriceTable implements the oracle.MidpointPriceStorer interface
+// using LevelDB. Only the latest oracle.MidpointPrice is stored, and it is
+// persistent across reboots.
+type OracleMidpointPriceTable struct {
+	db *leveldb.DB
 }
 
-// NewMidpointPriceStorer returns a new MidpointPriceStorer.
-func NewMidpointPriceStorer() oracle.MidpointPriceStorer {
-	return &midpointPriceStorer{
-		mu: new(sync.Mutex),
+// NewOracleMidpointPriceTable returns a new OracleMidpointPriceTable that uses
+// the given LevelDB instance to store and load values from the disk.
+func NewOracleMidpointPriceTable(db *leveldb.DB) *OracleMidpointPriceTable {
+	return &OracleMidpointPriceTable{
+		db: db,
 	}
 }
 
-// PutMidpointPrice implements the MidpointPriceStorer interface.
-func (storer *midpointPriceStorer) PutMidpointPrice(midPointPrice oracle.MidpointPrice) error {
-	storer.mu.Lock()
-	defer storer.mu.Unlock()
-
-	storer.prices = midPointPrice
-
-	return nil
+// PutMidpointPrice implements the oracle.MidpointPriceStorer interface.
+func (table *OracleMidpointPriceTable) PutMidpointPrice(midpointPrice oracle.MidpointPrice) error {
+	data, err := json.Marshal(midpointPrice)
+	if err != nil {
+		return err
+	}
+	return table.db.Put(table.key(), data, nil)
 }
 
-// MidpointPrices implements the MidpointPriceStorer interface.
-func (storer *midpointPriceStorer) MidpointPrices() (oracle.MidpointPrice, error) {
-	storer.mu.Lock()
-	defer storer.mu.Unlock()
+// MidpointPrices implements the oracle.MidpointPriceStorer interface. An empty
+// oracle.MidpointPrice is returned if no oracle.MidpointPrice has been stored.
+func (table *OracleMidpointPriceTable) MidpointPrices() (oracle.MidpointPrice, error) {
+	data, err := table.db.Get(table.key(), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return oracle.MidpointPrice{}, nil
+		}
+		return oracle.MidpointPrice{}, err
+	}
 
-	return storer.prices, nil
+	midpointPrice := oracle.MidpointPrice{}
+	if err := json.Unmarshal(data, &midpointPrice); err != nil {
+		return oracle.MidpointPrice{}, err
+	}
+	return midpointPrice, nil
 }
 
-// MidpointPrice implements the MidpointPriceStorer interface.
-func (storer *midpointPriceStorer) MidpointPrice(token order.Tokens) (uint64, error) {
-	storer.mu.Lock()
-	defer storer.mu.Unlock()
-
-	if price, ok := storer.prices.Prices[uint64(token)]; ok {
+// MidpointPrice implements the oracle.MidpointPriceStorer interface.
+func (table *OracleMidpointPriceTable) MidpointPrice(tokens order.Tokens) (uint64, error) {
+	midpointPrice, err := table.MidpointPrices()
+	if err != nil {
+		return 0, err
+	}
+	if price, ok := midpointPrice.Prices[uint64(tokens)]; ok {
 		return price, nil
 	}
 	return 0, oracle.ErrMidpointPriceNotFound
 }
+
+func (table *OracleMidpointPriceTable) key() []byte {
+	return append(OracleMidpointPriceTableBegin, OracleMidpointPriceTablePadding...)
+}
@@ -2,6 +2,7 @@ package leveldb_test
 
 import (
 	"math/rand"
+	"os"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -22,7 +23,10 @@ var _ = Describe("MidpointPrice storage", func() {
 	Context("when storing and retrieving data", func() {
 
 		It("should be able to get the right data we store", func() {
-			storer := NewMidpointPriceStorer()
+			db := newDB(dbFile)
+			defer os.RemoveAll(dbFolder)
+			defer db.Close()
+			storer := NewOracleMidpointPriceTable(db)
 			emptyPrice, err := storer.MidpointPrices()
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(emptyPrice.Equals(oracle.MidpointPrice{})).Should(BeTrue())
@@ -45,5 +49,21 @@ var _ = Describe("MidpointPrice storage", func() {
 			_, err = storer.MidpointPrice(order.Tokens(len(prices.Prices) + 1))
 			Expect(err).Should(HaveOccurred())
 		})
+
+		It("should load the data we stored after reopening the database", func() {
+			prices := testutils.RandMidpointPrice()
+			prices.Prices[uint64(order.TokensETHREN)] = 100
+
+			db := newDB(dbFile)
+			defer os.RemoveAll(dbFolder)
+			Expect(NewOracleMidpointPriceTable(db).PutMidpointPrice(prices)).ShouldNot(HaveOccurred())
+			Expect(db.Close()).ShouldNot(HaveOccurred())
+
+			db = newDB(dbFile)
+			defer db.Close()
+			storedPrice, err := NewOracleMidpointPriceTable(db).MidpointPrice(order.TokensETHREN)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(storedPrice).Should(Equal(uint64(100)))
+		})
 	})
 })
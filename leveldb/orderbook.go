@@ -308,6 +308,11 @@ type OrderbookPointerValue struct {
 	Pointer int `json:"pointer"`
 }
 
+type OrderbookBlockPointerValue struct {
+	BlockPointer uint64   `json:"blockPointer"`
+	BlockHash    [32]byte `json:"blockHash"`
+}
+
 // OrderbookPointerTable implements the orderbook.PointerStorer using in-memory
 // storage. Data stored is not persistent across reboots.
 type OrderbookPointerTable struct {
@@ -351,6 +356,43 @@ func (table *OrderbookPointerTable) Pointer() (orderbook.Pointer, error) {
 	return orderbook.Pointer(value.Pointer), nil
 }
 
+// PutBlockPointer implements the orderbook.PointerStorer interface.
+func (table *OrderbookPointerTable) PutBlockPointer(pointer orderbook.BlockPointer) error {
+	value := OrderbookBlockPointerValue{
+		BlockPointer: pointer.Number,
+		BlockHash:    pointer.Hash,
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return table.db.Put(table.blockKey(), data, nil)
+}
+
+// BlockPointer implements the orderbook.PointerStorer interface. The
+// orderbook.BlockPointer is zero if it has never been stored. An
+// orderbook.BlockPointer that was stored before block hashes were stored has
+// a zero hash.
+func (table *OrderbookPointerTable) BlockPointer() (orderbook.BlockPointer, error) {
+	data, err := table.db.Get(table.blockKey(), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return orderbook.BlockPointer{}, nil
+		}
+		return orderbook.BlockPointer{}, err
+	}
+
+	value := OrderbookBlockPointerValue{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return orderbook.BlockPointer{}, err
+	}
+	return orderbook.BlockPointer{Number: value.BlockPointer, Hash: value.BlockHash}, nil
+}
+
 func (table *OrderbookPointerTable) key() []byte {
 	return append(OrderbookPointerTableBegin, OrderbookPointerTablePadding...)
 }
+
+func (table *OrderbookPointerTable) blockKey() []byte {
+	return append(OrderbookBlockPointerTableBegin, OrderbookPointerTablePadding...)
+}
@@ -1,6 +1,7 @@
 package leveldb
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"time"
 
@@ -147,6 +148,190 @@ func (table *SomerComputationTable) key(k []byte) []byte {
 	return append(append(SomerComputationTableBegin, k...), SomerComputationTablePadding...)
 }
 
+// SomerSettlementIterator implements the ome.SettlementIterator using a
+// LevelDB iterator.
+type SomerSettlementIterator struct {
+	inner iterator.Iterator
+}
+
+func newSomerSettlementIterator(iter iterator.Iterator) *SomerSettlementIterator {
+	return &SomerSettlementIterator{
+		inner: iter,
+	}
+}
+
+// Next implements the ome.SettlementIterator interface.
+func (iter *SomerSettlementIterator) Next() bool {
+	return iter.inner.Next()
+}
+
+// Cursor implements the ome.SettlementIterator interface.
+func (iter *SomerSettlementIterator) Cursor() (ome.Settlement, error) {
+	if !iter.inner.Valid() {
+		return ome.Settlement{}, ome.ErrCursorOutOfRange
+	}
+	settlement := ome.Settlement{}
+	data := iter.inner.Value()
+	if err := json.Unmarshal(data, &settlement); err != nil {
+		return ome.Settlement{}, err
+	}
+	return settlement, nil
+}
+
+// Collect implements the ome.SettlementIterator interface.
+func (iter *SomerSettlementIterator) Collect() ([]ome.Settlement, error) {
+	settlements := []ome.Settlement{}
+	for iter.Next() {
+		settlement, err := iter.Cursor()
+		if err != nil {
+			return settlements, err
+		}
+		settlements = append(settlements, settlement)
+	}
+	return settlements, iter.inner.Error()
+}
+
+// Release implements the ome.SettlementIterator interface.
+func (iter *SomerSettlementIterator) Release() {
+	iter.inner.Release()
+}
+
+// SomerSettlementTable implements the ome.SettlementStorer interface using
+// LevelDB. Settlements do not expire.
+type SomerSettlementTable struct {
+	db *leveldb.DB
+}
+
+// NewSomerSettlementTable returns a new SomerSettlementTable that uses the
+// given LevelDB instance to store and load values from the disk.
+func NewSomerSettlementTable(db *leveldb.DB) *SomerSettlementTable {
+	return &SomerSettlementTable{db: db}
+}
+
+// PutSettlement implements the ome.SettlementStorer interface.
+func (table *SomerSettlementTable) PutSettlement(settlement ome.Settlement) error {
+	data, err := json.Marshal(settlement)
+	if err != nil {
+		return err
+	}
+	return table.db.Put(table.key(settlement.Computation.ID[:]), data, nil)
+}
+
+// DeleteSettlement implements the ome.SettlementStorer interface.
+func (table *SomerSettlementTable) DeleteSettlement(id ome.ComputationID) error {
+	return table.db.Delete(table.key(id[:]), nil)
+}
+
+// Settlement implements the ome.SettlementStorer interface.
+func (table *SomerSettlementTable) Settlement(id ome.ComputationID) (ome.Settlement, error) {
+	data, err := table.db.Get(table.key(id[:]), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			err = ome.ErrSettlementNotFound
+		}
+		return ome.Settlement{}, err
+	}
+
+	settlement := ome.Settlement{}
+	if err := json.Unmarshal(data, &settlement); err != nil {
+		return ome.Settlement{}, err
+	}
+	return settlement, nil
+}
+
+// Settlements implements the ome.SettlementStorer interface.
+func (table *SomerSettlementTable) Settlements() (ome.SettlementIterator, error) {
+	iter := table.db.NewIterator(&util.Range{Start: table.key(SomerSettlementIterBegin), Limit: table.key(SomerSettlementIterEnd)}, nil)
+	return newSomerSettlementIterator(iter), nil
+}
+
+func (table *SomerSettlementTable) key(k []byte) []byte {
+	return append(append(SomerSettlementTableBegin, k...), SomerSettlementTablePadding...)
+}
+
+// SomerJournalIterator implements the ome.JournalEntryIterator using a LevelDB
+// iterator.
+type SomerJournalIterator struct {
+	inner iterator.Iterator
+}
+
+func newSomerJournalIterator(iter iterator.Iterator) *SomerJournalIterator {
+	return &SomerJournalIterator{
+		inner: iter,
+	}
+}
+
+// Next implements the ome.JournalEntryIterator interface.
+func (iter *SomerJournalIterator) Next() bool {
+	return iter.inner.Next()
+}
+
+// Cursor implements the ome.JournalEntryIterator interface.
+func (iter *SomerJournalIterator) Cursor() (ome.JournalEntry, error) {
+	if !iter.inner.Valid() {
+		return ome.JournalEntry{}, ome.ErrCursorOutOfRange
+	}
+	entry := ome.JournalEntry{}
+	data := iter.inner.Value()
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return ome.JournalEntry{}, err
+	}
+	return entry, nil
+}
+
+// Collect implements the ome.JournalEntryIterator interface.
+func (iter *SomerJournalIterator) Collect() ([]ome.JournalEntry, error) {
+	entries := []ome.JournalEntry{}
+	for iter.Next() {
+		entry, err := iter.Cursor()
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, iter.inner.Error()
+}
+
+// Release implements the ome.JournalEntryIterator interface.
+func (iter *SomerJournalIterator) Release() {
+	iter.inner.Release()
+}
+
+// SomerJournalTable implements the ome.JournalStorer interface using LevelDB.
+// JournalEntries are keyed by their timestamp, so that they are iterated in
+// the order that they were recorded, and they do not expire.
+type SomerJournalTable struct {
+	db *leveldb.DB
+}
+
+// NewSomerJournalTable returns a new SomerJournalTable that uses the given
+// LevelDB instance to store and load values from the disk.
+func NewSomerJournalTable(db *leveldb.DB) *SomerJournalTable {
+	return &SomerJournalTable{db: db}
+}
+
+// PutJournalEntry implements the ome.JournalStorer interface.
+func (table *SomerJournalTable) PutJournalEntry(entry ome.JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	k := make([]byte, 32)
+	binary.BigEndian.PutUint64(k[:8], uint64(entry.Timestamp.UnixNano()))
+	copy(k[8:], entry.ID[:24])
+	return table.db.Put(table.key(k), data, nil)
+}
+
+// JournalEntries implements the ome.JournalStorer interface.
+func (table *SomerJournalTable) JournalEntries() (ome.JournalEntryIterator, error) {
+	iter := table.db.NewIterator(&util.Range{Start: table.key(SomerJournalIterBegin), Limit: table.key(SomerJournalIterEnd)}, nil)
+	return newSomerJournalIterator(iter), nil
+}
+
+func (table *SomerJournalTable) key(k []byte) []byte {
+	return append(append(SomerJournalTableBegin, k...), SomerJournalTablePadding...)
+}
+
 // SomerOrderFragmentValue is the storage format for computations being stored in
 // LevelDB. It contains additional timestamping information so that LevelDB can
 // provide pruning.
@@ -218,4 +218,35 @@ var _ = Describe("Somer storage", func() {
 			}
 		})
 	})
+
+	Context("when storing settlements", func() {
+		It("should load, iterate and delete stored settlements", func() {
+			db := newDB(dbFile)
+			defer db.Close()
+			somerSettlementTable := NewSomerSettlementTable(db)
+
+			for i := 0; i < len(computations); i++ {
+				settlement := ome.Settlement{Computation: computations[i], State: ome.SettlementStatePending, Attempts: i}
+				Expect(somerSettlementTable.PutSettlement(settlement)).ShouldNot(HaveOccurred())
+			}
+			for i := 0; i < len(computations); i++ {
+				settlement, err := somerSettlementTable.Settlement(computations[i].ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(settlement.Attempts).Should(Equal(i))
+			}
+
+			settlementsIter, err := somerSettlementTable.Settlements()
+			Expect(err).ShouldNot(HaveOccurred())
+			settlements, err := settlementsIter.Collect()
+			settlementsIter.Release()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(settlements).Should(HaveLen(len(computations)))
+
+			for i := 0; i < len(computations); i++ {
+				Expect(somerSettlementTable.DeleteSettlement(computations[i].ID)).ShouldNot(HaveOccurred())
+				_, err := somerSettlementTable.Settlement(computations[i].ID)
+				Expect(err).Should(Equal(ome.ErrSettlementNotFound))
+			}
+		})
+	})
 })
@@ -0,0 +1,61 @@
+package ome
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrSettlementBackendNotFound is returned when there is no SettlementBackend
+// registered for the order.Settlement of an order.
+var ErrSettlementBackendNotFound = errors.New("settlement backend not found")
+
+// A SettlementBackend settles a matched buy and sell order, at an execution
+// price, using the settlement layer of one order.Settlement. Settling the same
+// orders more than once must not return an error, so that a Settlement can be
+// retried after a failure, or after a restart. A SettlementBackend that cannot
+// settle the orders at the execution price must return an error, instead of
+// settling them at a different price.
+type SettlementBackend interface {
+	Settle(buy order.Order, sell order.Order, price uint64) error
+}
+
+// A SettlementBackendRegistry selects the SettlementBackend that is used to
+// settle orders, by the order.Settlement of the orders. It is safe for
+// concurrent use.
+type SettlementBackendRegistry struct {
+	mu       *sync.RWMutex
+	backends map[order.Settlement]SettlementBackend
+}
+
+// NewSettlementBackendRegistry returns an empty SettlementBackendRegistry.
+func NewSettlementBackendRegistry() *SettlementBackendRegistry {
+	return &SettlementBackendRegistry{
+		mu:       new(sync.RWMutex),
+		backends: map[order.Settlement]SettlementBackend{},
+	}
+}
+
+// Register the SettlementBackend that settles orders with the given
+// order.Settlement. A SettlementBackend that is already registered for the
+// order.Settlement is replaced.
+func (registry *SettlementBackendRegistry) Register(settlement order.Settlement, backend SettlementBackend) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.backends[settlement] = backend
+}
+
+// Backend returns the SettlementBackend that settles orders with the given
+// order.Settlement, or ErrSettlementBackendNotFound.
+func (registry *SettlementBackendRegistry) Backend(settlement order.Settlement) (SettlementBackend, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	backend, ok := registry.backends[settlement]
+	if !ok {
+		return nil, ErrSettlementBackendNotFound
+	}
+	return backend, nil
+}
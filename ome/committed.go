@@ -0,0 +1,116 @@
+package ome
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+// VerifyCommitmentsTimeout is the maximum time that a committed Matcher waits
+// for the commitments of an order.Fragment to be verified by its
+// CommitmentsVerifier before retrying.
+const VerifyCommitmentsTimeout = 30 * time.Second
+
+// A CommitmentsVerifier checks that the order.CoefficientCommitments of an
+// order.Fragment are the same as those held by the other Darknodes in its
+// Pod. It returns orderbook.ErrInconsistentCommitments when they are not.
+// Other errors mean that the order.CoefficientCommitments could not be
+// verified yet. An orderbook.Availability is a CommitmentsVerifier.
+type CommitmentsVerifier interface {
+	VerifyCommitments(ctx context.Context, orderFragment order.Fragment) error
+}
+
+type committedMatcher struct {
+	matcher          Matcher
+	computationStore ComputationStorer
+	verifier         CommitmentsVerifier
+	retries          int
+	retryDelay       time.Duration
+}
+
+// NewCommittedMatcher returns a Matcher that only resolves Computations when
+// both order.Fragments are committed. Order.Fragments that were split without
+// commitments, or that were reshared from a previous registry.Epoch, cannot
+// be verified, so the shares that they open during matching and settlement
+// cannot be checked. Computations with an uncommitted order.Fragment are
+// rejected. All other Computations are resolved by the Matcher.
+func NewCommittedMatcher(matcher Matcher, computationStore ComputationStorer) Matcher {
+	return &committedMatcher{
+		matcher:          matcher,
+		computationStore: computationStore,
+	}
+}
+
+// NewCommittedMatcherWithVerifier returns a Matcher that is the same as the
+// Matcher returned by NewCommittedMatcher, but that also uses a
+// CommitmentsVerifier to check that the Pod holds the same commitments for
+// both order.Fragments before resolving a Computation. Without this check, a
+// trader could give each Darknode commitments that only agree with its own
+// order.Fragment. Computations with inconsistent commitments are rejected,
+// and verification is retried using ResolveRetries and ResolveRetryDelay
+// before the Computation is failed.
+func NewCommittedMatcherWithVerifier(matcher Matcher, computationStore ComputationStorer, verifier CommitmentsVerifier) Matcher {
+	committedMatcher := NewCommittedMatcher(matcher, computationStore).(*committedMatcher)
+	committedMatcher.verifier = verifier
+	committedMatcher.retries = ResolveRetries
+	committedMatcher.retryDelay = ResolveRetryDelay
+	return committedMatcher
+}
+
+// Resolve implements the Matcher interface.
+func (matcher *committedMatcher) Resolve(com Computation, callback MatchCallback) {
+	if !com.Buy.IsCommitted() || !com.Sell.IsCommitted() {
+		matcher.reject(com, callback, ComputationStateRejected)
+		return
+	}
+	if matcher.verifier == nil {
+		matcher.matcher.Resolve(com, callback)
+		return
+	}
+
+	go func() {
+		for attempt := 0; ; attempt++ {
+			err := matcher.verifyCommitments(com)
+			if err == nil {
+				matcher.matcher.Resolve(com, callback)
+				return
+			}
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot verify commitments for computation = %v: %v", com.ID, err))
+			if err == orderbook.ErrInconsistentCommitments {
+				matcher.reject(com, callback, ComputationStateRejected)
+				return
+			}
+			if attempt >= matcher.retries {
+				matcher.reject(com, callback, ComputationStateFailed)
+				return
+			}
+			time.Sleep(matcher.retryDelay)
+		}
+	}()
+}
+
+func (matcher *committedMatcher) verifyCommitments(com Computation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), VerifyCommitmentsTimeout)
+	defer cancel()
+
+	if err := matcher.verifier.VerifyCommitments(ctx, com.Buy); err != nil {
+		return err
+	}
+	return matcher.verifier.VerifyCommitments(ctx, com.Sell)
+}
+
+func (matcher *committedMatcher) reject(com Computation, callback MatchCallback, state ComputationState) {
+	// Store the computation as rejected, or as a failure
+	com.State = state
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store %v computation buy = %v, sell = %v", com.State, com.Buy.OrderID, com.Sell.OrderID))
+	}
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ commitments => buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+	callback(com)
+}
@@ -0,0 +1,132 @@
+package ome_test
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Committed matcher", func() {
+
+	var store *leveldb.Store
+	var matcher Matcher
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.committed.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		matcher = NewCommittedMatcher(NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), testutils.NewAlwaysMatchSmpc()), store.SomerComputationStore())
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.committed.out")
+	})
+
+	It("should reject computations with an order fragment that has no commitments", func() {
+		buy := testutils.RandomBuyOrder()
+		buyFragments, err := buy.SplitWithCommitments(rand.Reader, 6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateNil, true)
+
+		matched := true
+		matcher.Resolve(com, func(com Computation) {
+			matched = com.Match
+		})
+		Expect(matched).Should(BeFalse())
+		stored, err := store.SomerComputationStore().Computation(com.ID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stored.State).Should(Equal(ComputationStateRejected))
+	})
+
+	It("should resolve computations when both order fragments have commitments", func() {
+		buy := testutils.RandomBuyOrder()
+		sell := testutils.RandomSellOrder()
+		buyFragments, err := buy.SplitWithCommitments(rand.Reader, 6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragments, err := sell.SplitWithCommitments(rand.Reader, 6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(store.SomerOrderFragmentStore().PutBuyOrderFragment([32]byte{}, buyFragments[0], "buyer", 1, order.Open)).ShouldNot(HaveOccurred())
+		Expect(store.SomerOrderFragmentStore().PutSellOrderFragment([32]byte{}, sellFragments[0], "seller", 2, order.Open)).ShouldNot(HaveOccurred())
+		com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateNil, true)
+
+		matched := false
+		matcher.Resolve(com, func(com Computation) {
+			matched = com.Match
+		})
+		Eventually(func() bool { return matched }).Should(BeTrue())
+	})
+
+	Context("when commitments are verified by the pod", func() {
+
+		It("should reject computations with commitments that are inconsistent with the pod", func() {
+			com, err := committedComputation(store)
+			Expect(err).ShouldNot(HaveOccurred())
+			matcher = NewCommittedMatcherWithVerifier(NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), testutils.NewAlwaysMatchSmpc()), store.SomerComputationStore(), &mockCommitmentsVerifier{err: orderbook.ErrInconsistentCommitments})
+
+			done := make(chan bool, 1)
+			matcher.Resolve(com, func(com Computation) {
+				done <- com.Match
+			})
+			Eventually(done).Should(Receive(BeFalse()))
+			stored, err := store.SomerComputationStore().Computation(com.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.State).Should(Equal(ComputationStateRejected))
+		})
+
+		It("should resolve computations with commitments that are consistent with the pod", func() {
+			com, err := committedComputation(store)
+			Expect(err).ShouldNot(HaveOccurred())
+			matcher = NewCommittedMatcherWithVerifier(NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), testutils.NewAlwaysMatchSmpc()), store.SomerComputationStore(), &mockCommitmentsVerifier{})
+
+			done := make(chan bool, 1)
+			matcher.Resolve(com, func(com Computation) {
+				done <- com.Match
+			})
+			Eventually(done).Should(Receive(BeTrue()))
+		})
+	})
+})
+
+// committedComputation returns a Computation between order fragments that
+// were split with commitments, and stores both order fragments.
+func committedComputation(store *leveldb.Store) (Computation, error) {
+	buy := testutils.RandomBuyOrder()
+	sell := testutils.RandomSellOrder()
+	buyFragments, err := buy.SplitWithCommitments(rand.Reader, 6, 4)
+	if err != nil {
+		return Computation{}, err
+	}
+	sellFragments, err := sell.SplitWithCommitments(rand.Reader, 6, 4)
+	if err != nil {
+		return Computation{}, err
+	}
+	if err := store.SomerOrderFragmentStore().PutBuyOrderFragment([32]byte{}, buyFragments[0], "buyer", 1, order.Open); err != nil {
+		return Computation{}, err
+	}
+	if err := store.SomerOrderFragmentStore().PutSellOrderFragment([32]byte{}, sellFragments[0], "seller", 2, order.Open); err != nil {
+		return Computation{}, err
+	}
+	return NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateNil, true), nil
+}
+
+// mockCommitmentsVerifier returns the same error for all order fragments.
+type mockCommitmentsVerifier struct {
+	err error
+}
+
+func (verifier *mockCommitmentsVerifier) VerifyCommitments(ctx context.Context, orderFragment order.Fragment) error {
+	return verifier.err
+}
@@ -45,6 +45,8 @@ const (
 	ComputationStateAccepted
 	ComputationStateRejected
 	ComputationStateSettled
+	ComputationStateFailed
+	ComputationStateUnverified
 )
 
 // String returns a human-readable representation of the ComputationState.
@@ -62,6 +64,10 @@ func (state ComputationState) String() string {
 		return "rejected"
 	case ComputationStateSettled:
 		return "settled"
+	case ComputationStateFailed:
+		return "failed"
+	case ComputationStateUnverified:
+		return "unverified"
 	default:
 		return "unsupported state"
 	}
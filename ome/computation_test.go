@@ -63,6 +63,8 @@ var _ = Describe("Computations", func() {
 			Expect(fmt.Sprintf("%v", ComputationStateAccepted)).Should(Equal("accepted"))
 			Expect(fmt.Sprintf("%v", ComputationStateRejected)).Should(Equal("rejected"))
 			Expect(fmt.Sprintf("%v", ComputationStateSettled)).Should(Equal("settled"))
+			Expect(fmt.Sprintf("%v", ComputationStateFailed)).Should(Equal("failed"))
+			Expect(fmt.Sprintf("%v", ComputationStateUnverified)).Should(Equal("unverified"))
 			Expect(fmt.Sprintf("%v", ComputationState(100))).Should(Equal("unsupported state"))
 		})
 	})
@@ -39,13 +39,15 @@ type confirmer struct {
 	confirmingBuyOrders  map[order.ID]time.Time
 	confirmingSellOrders map[order.ID]time.Time
 	confirmed            map[order.ID]time.Time
+	accepted             map[ComputationID]Computation
 }
 
 // NewConfirmer returns a Confirmer that submits Computations to the
 // Orderbook for confirmation. It polls the Orderbook on an interval
 // and checks for consensus on confirmations by waiting until a submitted
 // Computation has been confirmed has the confirmation has passed the block
-// depth limit.
+// depth limit. Confirmations that are removed from the Orderbook by a
+// reorganisation of the blockchain are rolled back, and confirmed again.
 func NewConfirmer(computationStore ComputationStorer, fragmentStore OrderFragmentStorer, contract ContractBinder, orderbookPollInterval time.Duration, orderbookBlockDepth uint) Confirmer {
 	return &confirmer{
 		computationStore: computationStore,
@@ -59,6 +61,7 @@ func NewConfirmer(computationStore ComputationStorer, fragmentStore OrderFragmen
 		confirmingBuyOrders:  map[order.ID]time.Time{},
 		confirmingSellOrders: map[order.ID]time.Time{},
 		confirmed:            map[order.ID]time.Time{},
+		accepted:             map[ComputationID]Computation{},
 	}
 }
 
@@ -136,6 +139,7 @@ func (confirmer *confirmer) Confirm(done <-chan struct{}, coms <-chan Computatio
 				confirmer.confirmingMu.Lock()
 				confirmer.checkOrdersForConfirmationFinality(order.ParityBuy, done, confirmations, errs)
 				confirmer.checkOrdersForConfirmationFinality(order.ParitySell, done, confirmations, errs)
+				confirmer.checkComputationsForRollback(done, errs)
 
 				// Clean up confirmed orders that are old enough to forget about
 				for key, t := range confirmer.confirmed {
@@ -143,6 +147,11 @@ func (confirmer *confirmer) Confirm(done <-chan struct{}, coms <-chan Computatio
 						delete(confirmer.confirmed, key)
 					}
 				}
+				for key, com := range confirmer.accepted {
+					if time.Since(com.Timestamp) > time.Hour {
+						delete(confirmer.accepted, key)
+					}
+				}
 
 				for key, t := range confirmer.confirmingBuyOrders {
 					if time.Since(t) > 24*time.Hour {
@@ -235,8 +244,84 @@ func (confirmer *confirmer) checkOrdersForConfirmationFinality(orderParity order
 			delete(confirmer.confirmingSellOrders, com.Sell.OrderID)
 			confirmer.confirmed[com.Buy.OrderID] = time.Now()
 			confirmer.confirmed[com.Sell.OrderID] = time.Now()
+			confirmer.accepted[com.ID] = com
+		}
+	}
+}
+
+// checkComputationsForRollback checks that the orders of recently accepted
+// Computations are still confirmed against each other. A reorganisation of
+// the blockchain can remove a confirmation after it has passed the block depth
+// limit, in which case the Computation is rolled back to the matched state.
+// Orders that are open again are confirmed again.
+func (confirmer *confirmer) checkComputationsForRollback(done <-chan struct{}, errs chan<- error) {
+	for id, com := range confirmer.accepted {
+		buyStatus, err := confirmer.contract.Status(com.Buy.OrderID)
+		if err != nil {
+			writeError(done, errs, err)
+			continue
+		}
+		sellStatus, err := confirmer.contract.Status(com.Sell.OrderID)
+		if err != nil {
+			writeError(done, errs, err)
+			continue
+		}
+		if buyStatus == order.Confirmed && sellStatus == order.Confirmed {
+			buyMatch, err := confirmer.contract.OrderMatch(com.Buy.OrderID)
+			if err != nil {
+				writeError(done, errs, err)
+				continue
+			}
+			if buyMatch.Equal(com.Sell.OrderID) {
+				continue
+			}
+		}
+
+		logger.Warn(fmt.Sprintf("confirmation of buy = %v, sell = %v removed by reorganisation", com.Buy.OrderID, com.Sell.OrderID))
+		delete(confirmer.accepted, id)
+		delete(confirmer.confirmed, com.Buy.OrderID)
+		delete(confirmer.confirmed, com.Sell.OrderID)
+		if err := confirmer.rollbackComputation(com, buyStatus, sellStatus); err != nil {
+			writeError(done, errs, err)
+			continue
 		}
+
+		if buyStatus == order.Open && sellStatus == order.Open {
+			confirmer.confirmingBuyOrders[com.Buy.OrderID] = time.Now()
+			confirmer.confirmingSellOrders[com.Sell.OrderID] = time.Now()
+			go func(com Computation) {
+				if err := confirmer.beginConfirmation(com); err != nil {
+					logger.Error(err.Error())
+				}
+			}(com)
+		}
+	}
+}
+
+// rollbackComputation restores an accepted Computation to the matched state,
+// and restores the status of its order fragments to the status of their
+// orders. Computations that are no longer accepted are not changed.
+func (confirmer *confirmer) rollbackComputation(com Computation, buyStatus, sellStatus order.Status) error {
+	stored, err := confirmer.computationStore.Computation(com.ID)
+	if err != nil {
+		return fmt.Errorf("cannot load computation buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err)
+	}
+	if stored.State != ComputationStateAccepted {
+		return nil
+	}
+	stored.State = ComputationStateMatched
+	stored.Timestamp = time.Now()
+	if err := confirmer.computationStore.PutComputation(stored); err != nil {
+		return fmt.Errorf("cannot store computation buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err)
 	}
+
+	if err := confirmer.fragmentStore.UpdateBuyOrderFragmentStatus(com.Epoch, com.Buy.OrderID, buyStatus); err != nil && err != ErrOrderFragmentNotFound {
+		return err
+	}
+	if err := confirmer.fragmentStore.UpdateSellOrderFragmentStatus(com.Epoch, com.Sell.OrderID, sellStatus); err != nil && err != ErrOrderFragmentNotFound {
+		return err
+	}
+	return nil
 }
 
 func (confirmer *confirmer) checkOrderForConfirmationFinality(ord order.ID, orderParity order.Parity) (order.ID, error) {
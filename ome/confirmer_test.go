@@ -9,6 +9,7 @@ import (
 	. "github.com/republicprotocol/republic-go/ome"
 
 	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -130,4 +131,53 @@ var _ = Describe("Confirmer", func() {
 
 		Expect(len(confirmedMatches)).Should(BeZero())
 	})
+
+	Context("when a confirmation is removed by a reorganisation", func() {
+
+		var done chan struct{}
+		var coms chan Computation
+		var confirmations <-chan Computation
+		var com Computation
+
+		BeforeEach(func() {
+			random, err := testutils.RandomComputation()
+			Expect(err).ShouldNot(HaveOccurred())
+			com = NewComputation([32]byte{}, random.Buy, random.Sell, ComputationStateMatched, true)
+			Expect(comStorer.PutComputation(com)).ShouldNot(HaveOccurred())
+			Expect(contract.OpenBuyOrder([65]byte{}, com.Buy.OrderID)).ShouldNot(HaveOccurred())
+			Expect(contract.OpenSellOrder([65]byte{}, com.Sell.OrderID)).ShouldNot(HaveOccurred())
+
+			done = make(chan struct{})
+			coms = make(chan Computation, 1)
+			confirmations, _ = confirmer.Confirm(done, coms)
+			coms <- com
+			Eventually(confirmations, 3*time.Second).Should(Receive())
+		})
+
+		AfterEach(func() {
+			close(done)
+		})
+
+		It("should confirm the computation again when the orders are open", func() {
+			Expect(contract.setOrderStatus(com.Buy.OrderID, order.Open)).ShouldNot(HaveOccurred())
+			Expect(contract.setOrderStatus(com.Sell.OrderID, order.Open)).ShouldNot(HaveOccurred())
+
+			var confirmation Computation
+			Eventually(confirmations, 3*time.Second).Should(Receive(&confirmation))
+			Expect(confirmation.ID).Should(Equal(com.ID))
+			Expect(contract.Status(com.Buy.OrderID)).Should(Equal(order.Confirmed))
+		})
+
+		It("should roll back the computation when the orders are not open", func() {
+			Expect(contract.setOrderStatus(com.Buy.OrderID, order.Open)).ShouldNot(HaveOccurred())
+			Expect(contract.setOrderStatus(com.Buy.OrderID, order.Canceled)).ShouldNot(HaveOccurred())
+
+			Eventually(func() ComputationState {
+				stored, err := comStorer.Computation(com.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				return stored.State
+			}, 3*time.Second).Should(Equal(ComputationStateMatched))
+			Consistently(confirmations, 2*time.Second).ShouldNot(Receive())
+		})
+	})
 })
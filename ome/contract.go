@@ -22,7 +22,7 @@ type ContractBinder interface {
 
 	OrderMatch(order order.ID) (order.ID, error)
 
-	Settle(buy order.Order, sell order.Order) error
+	Settle(buy order.Order, sell order.Order, price uint64) error
 
 	SubmitChallengeOrder(ord order.Order) error
 
@@ -4,6 +4,7 @@ import (
 	"log"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
@@ -12,10 +13,10 @@ import (
 	"github.com/republicprotocol/republic-go/registry"
 )
 
-type computationWeight struct {
-	computation Computation
-	weight      uint64
-}
+// OrderFragmentPruneInterval is the interval at which expired order.Fragments
+// are removed from storage, and expired Computations are removed before they
+// are sent to the Matcher.
+const OrderFragmentPruneInterval = 30 * time.Second
 
 type ComputationGenerator interface {
 	Generate(done <-chan struct{}, notifications <-chan orderbook.Notification) (<-chan Computation, <-chan error)
@@ -38,9 +39,19 @@ type computationGenerator struct {
 	broadcastErrs         chan (<-chan error)
 
 	fragmentStore OrderFragmentStorer
+	policy        MatchingPolicy
 }
 
+// NewComputationGenerator returns a ComputationGenerator that sends
+// Computations to the Matcher using the weighted MatchingPolicy.
 func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFragmentStorer) ComputationGenerator {
+	return NewComputationGeneratorWithMatchingPolicy(addr, orderFragmentStore, NewWeightedMatchingPolicy())
+}
+
+// NewComputationGeneratorWithMatchingPolicy returns a ComputationGenerator
+// that uses a MatchingPolicy to decide which orders are compatible, and the
+// order in which Computations are sent to the Matcher.
+func NewComputationGeneratorWithMatchingPolicy(addr identity.Address, orderFragmentStore OrderFragmentStorer, policy MatchingPolicy) ComputationGenerator {
 	return &computationGenerator{
 		doneMu: new(sync.Mutex),
 		done:   nil,
@@ -57,6 +68,7 @@ func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFrag
 		broadcastErrs:         make(chan (<-chan error)),
 
 		fragmentStore: orderFragmentStore,
+		policy:        policy,
 	}
 }
 
@@ -115,7 +127,7 @@ func (gen *computationGenerator) OnChangeEpoch(epoch registry.Epoch) {
 	gen.matCurrDone = make(chan struct{})
 	gen.matCurrNotifications = make(chan orderbook.Notification)
 
-	mat := newComputationMatrix(gen.addr, epoch, gen.fragmentStore)
+	mat := newComputationMatrix(gen.addr, epoch, gen.fragmentStore, gen.policy)
 	computations, errs := mat.generate(gen.matCurrDone, gen.matCurrNotifications)
 
 	go func() {
@@ -189,19 +201,21 @@ type computationMatrix struct {
 	pod           *registry.Pod
 	epoch         registry.Epoch
 	fragmentStore OrderFragmentStorer
+	policy        MatchingPolicy
 
 	sortedComputationsMu     *sync.Mutex
-	sortedComputations       []computationWeight
+	sortedComputations       []MatchingCandidate
 	sortedComputationsSignal chan struct{}
 }
 
-func newComputationMatrix(addr identity.Address, epoch registry.Epoch, orderFragmentStore OrderFragmentStorer) *computationMatrix {
+func newComputationMatrix(addr identity.Address, epoch registry.Epoch, orderFragmentStore OrderFragmentStorer, policy MatchingPolicy) *computationMatrix {
 	mat := &computationMatrix{
 		epoch:         epoch,
 		fragmentStore: orderFragmentStore,
+		policy:        policy,
 
 		sortedComputationsMu:     new(sync.Mutex),
-		sortedComputations:       []computationWeight{},
+		sortedComputations:       []MatchingCandidate{},
 		sortedComputationsSignal: make(chan struct{}),
 	}
 	pod, err := epoch.Pod(addr)
@@ -254,18 +268,36 @@ func (mat *computationMatrix) generate(done <-chan struct{}, notifications <-cha
 							default:
 							}
 
-							computationWeight := mat.sortedComputations[0]
+							candidate := mat.sortedComputations[0]
 							mat.sortedComputations = mat.sortedComputations[1:]
 							mat.sortedComputationsMu.Unlock()
 
+							// Do not use smpc rounds for expired orders
+							if isComputationExpired(candidate.Computation, time.Now()) {
+								continue
+							}
+
 							select {
 							case <-done:
 								break
-							case computations <- computationWeight.computation:
+							case computations <- candidate.Computation:
 							}
 						}
 					}
 				}
+			},
+			func() {
+				ticker := time.NewTicker(OrderFragmentPruneInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						mat.pruneExpired(time.Now())
+					}
+				}
 			})
 	}()
 
@@ -284,6 +316,13 @@ func (mat *computationMatrix) handleNotification(notification orderbook.Notifica
 		mat.removeOrderFragment(notification.OrderID)
 	case orderbook.NotificationCancelOrder:
 		mat.removeOrderFragment(notification.OrderID)
+	// Notifications that roll back the opening of an order result in the
+	// removal of that order from storage, other rollbacks are handled by the
+	// Confirmer
+	case orderbook.NotificationRollbackOrder:
+		if notification.Status == order.Nil {
+			mat.removeOrderFragment(notification.OrderID)
+		}
 	default:
 		select {
 		case <-done:
@@ -299,6 +338,13 @@ func (mat *computationMatrix) insertOrderFragment(notification orderbook.Notific
 		return
 	}
 
+	// Expired orders are dropped before they are stored
+	now := time.Now()
+	if isOrderFragmentExpired(notification.OrderFragment, now) {
+		log.Printf("[debug] (generator) dropping expired order fragment = %v", notification.OrderID)
+		return
+	}
+
 	// Store the order.Fragment and get the opposing list so that computations
 	// can be generated
 	var oppositeOrderFragmentIter OrderFragmentIterator
@@ -338,12 +384,15 @@ func (mat *computationMatrix) insertOrderFragment(notification orderbook.Notific
 			continue
 		}
 
-		if !isCompatible(notification, orderFragment, trader, priority) {
+		if !mat.policy.IsCompatible(notification, orderFragment, trader, priority) {
 			continue
 		}
 		if status != order.Open {
 			continue
 		}
+		if isOrderFragmentExpired(orderFragment, now) {
+			continue
+		}
 
 		var computation Computation
 		if notification.OrderFragment.OrderParity == order.ParityBuy {
@@ -363,18 +412,23 @@ func (mat *computationMatrix) insertOrderFragment(notification orderbook.Notific
 			continue
 		}
 		adjustment := uint64(len(commonPath) - (index + 1))
-		comWeight := computationWeight{weight: uint64(notification.Priority) + priority + adjustment, computation: computation}
+		candidate := MatchingCandidate{
+			Computation:     computation,
+			OpenPriority:    uint64(notification.Priority),
+			RestingPriority: priority,
+			Adjustment:      adjustment,
+		}
 
 		// Insert sort into the list of sorted computations
 		didGenerateNewComputation = true
 		if len(mat.sortedComputations) == 0 {
-			mat.sortedComputations = append(mat.sortedComputations, comWeight)
+			mat.sortedComputations = append(mat.sortedComputations, candidate)
 			continue
 		}
 		n := sort.Search(len(mat.sortedComputations), func(i int) bool {
-			return comWeight.weight >= mat.sortedComputations[i].weight
+			return mat.policy.Before(candidate, mat.sortedComputations[i])
 		})
-		mat.sortedComputations = append(mat.sortedComputations[:n], append([]computationWeight{comWeight}, mat.sortedComputations[n:]...)...)
+		mat.sortedComputations = append(mat.sortedComputations[:n], append([]MatchingCandidate{candidate}, mat.sortedComputations[n:]...)...)
 	}
 	mat.sortedComputationsMu.Unlock()
 
@@ -395,57 +449,56 @@ func (mat *computationMatrix) removeOrderFragment(orderID order.ID) {
 	}
 }
 
-// isCompatible checks if the notification's order is compatible with another
-// order based on the following conditions:
-// 1. If the trader is the same as the notification's trader, the 2 orders are
-//    incompatible.
-// 2. Orders from the same epoch must not be matched twice (i.e. if both orders
-//    are at depth 1, they are incompatible).
-// 3. If both orders are Fill-or-Kill (FOK), they are incompatible.
-// 4. If one of the orders is a FOK, then both orders are incompatible if the
-//    other order is of a higher priority.
-func isCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool {
-
-	// Traders should not match against themselves
-	if trader == notification.Trader {
-		return false
-	}
-
-	// Order fragments with depth 1 should have been found compatible at epoch
-	// depth of 0.
-	if orderFragment.EpochDepth == 1 && notification.OrderFragment.EpochDepth == orderFragment.EpochDepth {
-		return false
+// pruneExpired removes expired order.Fragments from storage, and expired
+// Computations that have not been sent to the Matcher.
+func (mat *computationMatrix) pruneExpired(now time.Time) {
+	mat.sortedComputationsMu.Lock()
+	sortedComputations := mat.sortedComputations[:0]
+	for _, candidate := range mat.sortedComputations {
+		if isComputationExpired(candidate.Computation, now) {
+			continue
+		}
+		sortedComputations = append(sortedComputations, candidate)
 	}
+	mat.sortedComputations = sortedComputations
+	mat.sortedComputationsMu.Unlock()
 
-	switch orderFragment.OrderType {
-
-	// The orderFragment is a Fill-or-Kill order
-	case order.TypeMidpointFOK, order.TypeLimitFOK:
-		switch notification.OrderFragment.OrderType {
-		case order.TypeMidpointFOK, order.TypeLimitFOK:
-			// Both orders are FOK, thus, incompatible.
-			return false
-		default:
-			// Does notification.OrderFragment, which is not an FOK order, have
-			// a higher priority than the FOK order ?
-			if uint64(notification.Priority) > priority {
-				return false
-			}
-			return true
+	collectExpired := func(iter OrderFragmentIterator, err error) []order.ID {
+		if err != nil {
+			log.Printf("[error] (generator) cannot load order fragment iterator: %v", err)
+			return nil
 		}
-
-	// The orderFragment is not a Fill-or-Kill order
-	default:
-		switch notification.OrderFragment.OrderType {
-		case order.TypeMidpointFOK, order.TypeLimitFOK:
-			// Does notification.OrderFragment, which is an FOK order, have a
-			// lower priority than the other order ?
-			if priority > uint64(notification.Priority) {
-				return false
+		defer iter.Release()
+
+		expired := []order.ID{}
+		for iter.Next() {
+			orderFragment, _, _, _, err := iter.Cursor()
+			if err != nil {
+				log.Printf("[error] (generator) cannot load cursor: %v", err)
+				continue
+			}
+			if isOrderFragmentExpired(orderFragment, now) {
+				expired = append(expired, orderFragment.OrderID)
 			}
-			return true
-		default:
-			return true
+		}
+		return expired
+	}
+	for _, orderID := range collectExpired(mat.fragmentStore.BuyOrderFragments(mat.epoch.Hash)) {
+		if err := mat.fragmentStore.DeleteBuyOrderFragment(mat.epoch.Hash, orderID); err != nil {
+			log.Printf("[error] (generator) cannot delete expired order fragment = %v; %v", orderID, err)
+		}
+	}
+	for _, orderID := range collectExpired(mat.fragmentStore.SellOrderFragments(mat.epoch.Hash)) {
+		if err := mat.fragmentStore.DeleteSellOrderFragment(mat.epoch.Hash, orderID); err != nil {
+			log.Printf("[error] (generator) cannot delete expired order fragment = %v; %v", orderID, err)
 		}
 	}
 }
+
+func isOrderFragmentExpired(orderFragment order.Fragment, now time.Time) bool {
+	return now.After(orderFragment.OrderExpiry)
+}
+
+func isComputationExpired(com Computation, now time.Time) bool {
+	return isOrderFragmentExpired(com.Buy, now) || isOrderFragmentExpired(com.Sell, now)
+}
@@ -1,11 +1,14 @@
 package ome
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"math/big"
 	"time"
 
+	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
@@ -16,6 +19,18 @@ import (
 // explicitly enumerated values.
 var ErrUnexpectedResolveStage = errors.New("unexpected resolve stage")
 
+// ResolveTimeout is the default maximum time that a Matcher will wait for a
+// stage of a Computation to be joined before retrying the stage.
+const ResolveTimeout = 10 * time.Minute
+
+// ResolveRetries is the default number of times that a Matcher will retry a
+// stage of a Computation that has timed out before the Computation is failed.
+const ResolveRetries = 3
+
+// ResolveRetryDelay is the time that a Matcher waits before retrying a stage
+// of a Computation that has timed out.
+const ResolveRetryDelay = 10 * time.Second
+
 // ResolveStage defines the various stages that resolving can be in for any
 // given Computation.
 type ResolveStage byte
@@ -31,6 +46,22 @@ const (
 	ResolveStageSellVolumeCo
 	ResolveStageTokens
 	ResolveStageSettlement
+
+	// ResolveStageComparePrice, ResolveStageCompareBuyVolume, and
+	// ResolveStageCompareSellVolume compare prices and volumes using an
+	// smpc.Comparer, which reveals only whether or not one value is greater
+	// than, or equal to, the other.
+	ResolveStageComparePrice
+	ResolveStageCompareBuyVolume
+	ResolveStageCompareSellVolume
+
+	// ResolveStageMidpointTokens, ResolveStageMidpointBuyPrice, and
+	// ResolveStageMidpointSellPrice are used by the midpoint Matcher to open
+	// the tokens of matched orders, and to check that the mid-point price of
+	// the tokens is within the prices of both orders.
+	ResolveStageMidpointTokens
+	ResolveStageMidpointBuyPrice
+	ResolveStageMidpointSellPrice
 )
 
 // String returns the human-readable representation of a ResolveStage.
@@ -52,6 +83,18 @@ func (stage ResolveStage) String() string {
 		return "sellVolumeCo"
 	case ResolveStageTokens:
 		return "tokens"
+	case ResolveStageComparePrice:
+		return "comparePrice"
+	case ResolveStageCompareBuyVolume:
+		return "compareBuyVolume"
+	case ResolveStageCompareSellVolume:
+		return "compareSellVolume"
+	case ResolveStageMidpointTokens:
+		return "midpointTokens"
+	case ResolveStageMidpointBuyPrice:
+		return "midpointBuyPrice"
+	case ResolveStageMidpointSellPrice:
+		return "midpointSellPrice"
 	}
 	return ""
 }
@@ -74,20 +117,51 @@ type matcher struct {
 	computationStore ComputationStorer
 	fragmentStore    OrderFragmentStorer
 	smpcer           smpc.Smpcer
+	comparer         smpc.Comparer
+	timeout          time.Duration
+	retries          int
+	retryDelay       time.Duration
 }
 
 // NewMatcher returns a Matcher that will resolve Computations by resolving
 // each component in a pipeline. If a mismatch is encountered at any stage of
 // the pipeline, the Computation is short circuited and the MatchCallback will
-// be called immediately.
+// be called immediately. Stages are retried using ResolveTimeout and
+// ResolveRetries. The differences between the prices, and volumes, of two
+// orders are revealed, so NewSecureMatcher should be used instead.
 func NewMatcher(computationStore ComputationStorer, fragmentStore OrderFragmentStorer, smpcer smpc.Smpcer) Matcher {
+	return NewMatcherWithTimeout(computationStore, fragmentStore, smpcer, ResolveTimeout, ResolveRetries, ResolveRetryDelay)
+}
+
+// NewMatcherWithTimeout returns a Matcher that will wait for the timeout for
+// each stage of a Computation to be joined. A stage that times out, or that is
+// joined on a network that is not connected, is retried, after the retry
+// delay, at most the given number of retries. When a stage cannot be joined,
+// the Computation is stored with ComputationStateFailed, or with
+// ComputationStateUnverified when the Joins of other nodes cannot be verified,
+// and the MatchCallback is called with a mismatch.
+func NewMatcherWithTimeout(computationStore ComputationStorer, fragmentStore OrderFragmentStorer, smpcer smpc.Smpcer, timeout time.Duration, retries int, retryDelay time.Duration) Matcher {
 	return &matcher{
 		computationStore: computationStore,
 		fragmentStore:    fragmentStore,
 		smpcer:           smpcer,
+		timeout:          timeout,
+		retries:          retries,
+		retryDelay:       retryDelay,
 	}
 }
 
+// NewSecureMatcher returns a Matcher that is the same as a Matcher returned by
+// NewMatcher, but that compares prices and volumes using an smpc.Comparer. The
+// difference between the prices, and volumes, of two orders is never
+// revealed. Only the tokens of two orders are compared by revealing their
+// difference.
+func NewSecureMatcher(computationStore ComputationStorer, fragmentStore OrderFragmentStorer, smpcer smpc.Smpcer, comparer smpc.Comparer) Matcher {
+	matcher := NewMatcherWithTimeout(computationStore, fragmentStore, smpcer, ResolveTimeout, ResolveRetries, ResolveRetryDelay).(*matcher)
+	matcher.comparer = comparer
+	return matcher
+}
+
 // Resolve implements the Matcher interface.
 func (matcher *matcher) Resolve(com Computation, callback MatchCallback) {
 	if com.Buy.OrderSettlement != com.Sell.OrderSettlement {
@@ -102,10 +176,16 @@ func (matcher *matcher) Resolve(com Computation, callback MatchCallback) {
 		callback(com)
 		return
 	}
-	matcher.resolve(smpc.NetworkID(com.Epoch), com, callback, ResolveStagePriceExp)
+
+	networkID := smpc.NetworkID(com.Epoch)
+	if matcher.comparer != nil {
+		matcher.resolve(networkID, com, callback, ResolveStageComparePrice, 0)
+		return
+	}
+	matcher.resolve(networkID, com, callback, ResolveStagePriceExp, 0)
 }
 
-func (matcher *matcher) resolve(networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
+func (matcher *matcher) resolve(networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, attempt int) {
 	if isExpired(com) {
 		com.State = ComputationStateRejected
 		if err := matcher.computationStore.PutComputation(com); err != nil {
@@ -114,6 +194,11 @@ func (matcher *matcher) resolve(networkID smpc.NetworkID, com Computation, callb
 		return
 	}
 
+	if stage == ResolveStageComparePrice || stage == ResolveStageCompareBuyVolume || stage == ResolveStageCompareSellVolume {
+		matcher.resolveCompare(networkID, com, callback, stage, attempt)
+		return
+	}
+
 	join, joinCommitments, err := buildJoin(com, stage)
 	if err != nil {
 		logger.Compute(logger.LevelError, fmt.Sprintf("cannot build %v join: %v", stage, err))
@@ -121,12 +206,102 @@ func (matcher *matcher) resolve(networkID smpc.NetworkID, com Computation, callb
 	}
 	matcher.smpcer.InsertCommitments(networkID, join.ID, joinCommitments)
 
-	err = matcher.smpcer.Join(networkID, join, func(joinID smpc.JoinID, values []uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), matcher.timeout)
+	err = matcher.smpcer.Join(ctx, networkID, join, func(joinID smpc.JoinID, values []uint64) {
+		cancel()
 		matcher.resolveValues(values, networkID, com, callback, stage)
+	}, func(joinID smpc.JoinID, err error) {
+		cancel()
+		matcher.resolveError(err, networkID, com, callback, stage, attempt)
 	}, stage == ResolveStageTokens /* delay messaging for the last check so that the dedicated confirmer has a head start */)
 	if err != nil {
-		logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: cannot join computation = %v: %v", stage, com.ID, err))
+		cancel()
+		matcher.resolveError(err, networkID, com, callback, stage, attempt)
+	}
+}
+
+// resolveCompare compares the prices, or volumes, of a Computation for a stage
+// using the smpc.Comparer.
+func (matcher *matcher) resolveCompare(networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, attempt int) {
+	lhs, rhs, err := buildComparison(com, stage)
+	if err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot build %v comparison: %v", stage, err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), matcher.timeout)
+	err = matcher.comparer.Compare(ctx, networkID, buildCompareID(com, stage), lhs, rhs, func(id smpc.CompareID, result bool) {
+		cancel()
+		matcher.resolveComparison(result, networkID, com, callback, stage)
+	}, func(id smpc.CompareID, err error) {
+		cancel()
+		matcher.resolveError(err, networkID, com, callback, stage, attempt)
+	})
+	if err != nil {
+		cancel()
+		matcher.resolveError(err, networkID, com, callback, stage, attempt)
+	}
+}
+
+func (matcher *matcher) resolveComparison(result bool, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
+	if matcher.orderConfirmed(com) {
+		logger.Compute(logger.LevelDebug, fmt.Sprintf("stop resolving buy=%v, sell=%v as at lease one of them gets confirmed", com.Buy.OrderID, com.Sell.OrderID))
+		return
+	}
+
+	if result {
+		switch stage {
+		case ResolveStageComparePrice, ResolveStageCompareBuyVolume:
+			matcher.resolve(networkID, com, callback, stage+1, 0)
+			return
+		case ResolveStageCompareSellVolume:
+			matcher.resolve(networkID, com, callback, ResolveStageTokens, 0)
+			return
+		}
+	}
+
+	// Store the computation as a mismatch
+	com.State = ComputationStateMismatched
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store mismatched computation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+	}
+
+	// Trigger the callback with a mismatch
+	log.Printf("[debug] (%v) ✗ buy = %v, sell = %v", stage, com.Buy.OrderID, com.Sell.OrderID)
+	callback(com)
+}
+
+// resolveError retries a stage that has timed out, or that was joined on a
+// network that is not connected, until the maximum number of retries has
+// been reached. A stage with Joins that cannot be verified is not retried,
+// and the Computation is stored as unverified. Otherwise, the Computation is
+// stored as failed. In both cases, the MatchCallback is called with a
+// mismatch.
+func (matcher *matcher) resolveError(err error, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, attempt int) {
+	logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: cannot join computation = %v: %v", stage, com.ID, err))
+	if (err == smpc.ErrJoinTimeout || err == smpc.ErrJoinOnDisconnectedNetwork) && attempt < matcher.retries {
+		logger.Compute(logger.LevelInfo, fmt.Sprintf("retrying %v for computation = %v: attempt %v of %v", stage, com.ID, attempt+1, matcher.retries))
+		go func() {
+			time.Sleep(matcher.retryDelay)
+			matcher.resolve(networkID, com, callback, stage, attempt+1)
+		}()
+		return
+	}
+
+	// Store the computation as unverified, or as a failure
+	com.State = ComputationStateFailed
+	if err == smpc.ErrUnverifiedJoin {
+		com.State = ComputationStateUnverified
 	}
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store %v computation buy = %v, sell = %v", com.State, com.Buy.OrderID, com.Sell.OrderID))
+	}
+
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ %v %v => buy = %v, sell = %v", com.State, stage, com.Buy.OrderID, com.Sell.OrderID))
+	callback(com)
 }
 
 func (matcher *matcher) resolveValues(values []uint64, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
@@ -142,17 +317,17 @@ func (matcher *matcher) resolveValues(values []uint64, networkID smpc.NetworkID,
 	switch stage {
 	case ResolveStagePriceExp, ResolveStageBuyVolumeExp, ResolveStageSellVolumeExp:
 		if isGreaterThanZero(values[0]) {
-			matcher.resolve(networkID, com, callback, stage+2)
+			matcher.resolve(networkID, com, callback, stage+2, 0)
 			return
 		}
 		if isEqualToZero(values[0]) {
-			matcher.resolve(networkID, com, callback, stage+1)
+			matcher.resolve(networkID, com, callback, stage+1, 0)
 			return
 		}
 
 	case ResolveStagePriceCo, ResolveStageBuyVolumeCo, ResolveStageSellVolumeCo:
 		if isGreaterThanOrEqualToZero(values[0]) {
-			matcher.resolve(networkID, com, callback, stage+1)
+			matcher.resolve(networkID, com, callback, stage+1, 0)
 			return
 		}
 
@@ -201,69 +376,172 @@ func (matcher *matcher) orderConfirmed(com Computation) bool {
 }
 
 func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitments, error) {
-	joinCommitments := smpc.JoinCommitments{
-		LHS: map[uint64]shamir.Commitment{},
-		RHS: map[uint64]shamir.Commitment{},
-	}
-
 	var share shamir.Share
+	var openings shamir.Openings
+	var joinCommitments smpc.JoinCommitments
+
 	switch stage {
 	case ResolveStagePriceExp:
 		share = com.Buy.Price.Exp.Sub(&com.Sell.Price.Exp)
-		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].PriceExp
-		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].PriceExp
+		openings = buildJoinOpenings(com.Buy.Openings.PriceExp, com.Sell.Openings.PriceExp)
+		joinCommitments = buildJoinCommitments(com.Buy.Commitments, com.Sell.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.PriceExp
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.PriceExp
+		})
 
 	case ResolveStagePriceCo:
 		share = com.Buy.Price.Co.Sub(&com.Sell.Price.Co)
-		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].PriceCo
-		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].PriceCo
+		openings = buildJoinOpenings(com.Buy.Openings.PriceCo, com.Sell.Openings.PriceCo)
+		joinCommitments = buildJoinCommitments(com.Buy.Commitments, com.Sell.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.PriceCo
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.PriceCo
+		})
 
 	case ResolveStageBuyVolumeExp:
 		share = com.Buy.Volume.Exp.Sub(&com.Sell.MinimumVolume.Exp)
-		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].VolumeExp
-		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].MinimumVolumeExp
+		openings = buildJoinOpenings(com.Buy.Openings.VolumeExp, com.Sell.Openings.MinimumVolumeExp)
+		joinCommitments = buildJoinCommitments(com.Buy.Commitments, com.Sell.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.VolumeExp
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.MinimumVolumeExp
+		})
 
 	case ResolveStageBuyVolumeCo:
 		share = com.Buy.Volume.Co.Sub(&com.Sell.MinimumVolume.Co)
-		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].VolumeCo
-		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].MinimumVolumeCo
+		openings = buildJoinOpenings(com.Buy.Openings.VolumeCo, com.Sell.Openings.MinimumVolumeCo)
+		joinCommitments = buildJoinCommitments(com.Buy.Commitments, com.Sell.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.VolumeCo
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.MinimumVolumeCo
+		})
 
 	case ResolveStageSellVolumeExp:
 		share = com.Sell.Volume.Exp.Sub(&com.Buy.MinimumVolume.Exp)
-		joinCommitments.LHS[share.Index] = com.Sell.Commitments[share.Index].VolumeExp
-		joinCommitments.RHS[share.Index] = com.Buy.Commitments[share.Index].MinimumVolumeExp
+		openings = buildJoinOpenings(com.Sell.Openings.VolumeExp, com.Buy.Openings.MinimumVolumeExp)
+		joinCommitments = buildJoinCommitments(com.Sell.Commitments, com.Buy.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.VolumeExp
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.MinimumVolumeExp
+		})
 
 	case ResolveStageSellVolumeCo:
 		share = com.Sell.Volume.Co.Sub(&com.Buy.MinimumVolume.Co)
-		joinCommitments.LHS[share.Index] = com.Sell.Commitments[share.Index].VolumeCo
-		joinCommitments.RHS[share.Index] = com.Buy.Commitments[share.Index].MinimumVolumeCo
+		openings = buildJoinOpenings(com.Sell.Openings.VolumeCo, com.Buy.Openings.MinimumVolumeCo)
+		joinCommitments = buildJoinCommitments(com.Sell.Commitments, com.Buy.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.VolumeCo
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.MinimumVolumeCo
+		})
 
 	case ResolveStageTokens:
 		share = com.Buy.Tokens.Sub(&com.Sell.Tokens)
-		// FIXME: Tokens are not verified.
+		openings = buildJoinOpenings(com.Buy.Openings.Tokens, com.Sell.Openings.Tokens)
+		joinCommitments = buildJoinCommitments(com.Buy.Commitments, com.Sell.Commitments, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.Tokens
+		}, func(commitment order.FragmentCommitment) shamir.Commitment {
+			return commitment.Tokens
+		})
 
 	default:
 		return smpc.Join{}, smpc.JoinCommitments{}, ErrUnexpectedResolveStage
 	}
 
-	// FIXME: Re-enable blindings
-	// Create the blinding to verify the computation
-	// blinding := shamir.Blinding{
-	// 	Int: big.NewInt(0).Add(com.Buy.Blinding.Int, big.NewInt(0).Sub(shamir.CommitP, com.Sell.Blinding.Int)),
-	// }
-	// blinding.Mod(blinding.Int, shamir.CommitP)
-
 	// Create the join
 	join := smpc.Join{
-		Index:     smpc.JoinIndex(share.Index),
-		Shares:    shamir.Shares{share},
-		Blindings: shamir.Blindings{},
+		Index:    smpc.JoinIndex(share.Index),
+		Shares:   shamir.Shares{share},
+		Openings: openings,
 	}
 	copy(join.ID[:], com.ID[:])
 	join.ID[32] = byte(stage)
 	return join, joinCommitments, nil
 }
 
+// buildComparison returns the lhs and rhs shares for a stage that compares
+// the prices, or volumes, of a Computation using an smpc.Comparer.
+func buildComparison(com Computation, stage ResolveStage) (shamir.Share, shamir.Share, error) {
+	switch stage {
+	case ResolveStageComparePrice:
+		return coExpKey(com.Buy.Price), coExpKey(com.Sell.Price), nil
+	case ResolveStageCompareBuyVolume:
+		return coExpKey(com.Buy.Volume), coExpKey(com.Sell.MinimumVolume), nil
+	case ResolveStageCompareSellVolume:
+		return coExpKey(com.Sell.Volume), coExpKey(com.Buy.MinimumVolume), nil
+	}
+	return shamir.Share{}, shamir.Share{}, ErrUnexpectedResolveStage
+}
+
+// buildCompareID returns the smpc.CompareID for a stage of a Computation.
+func buildCompareID(com Computation, stage ResolveStage) smpc.CompareID {
+	id := smpc.CompareID{}
+	copy(id[:], crypto.Keccak256(com.ID[:], []byte{byte(stage)}))
+	return id
+}
+
+// coExpKey returns a share of exp * 2^11 + co. Coefficients are always less
+// than 2^11, so ordering the keys is the same as ordering the values that the
+// order.CoExpShares represent.
+func coExpKey(value order.CoExpShare) shamir.Share {
+	key := value.Exp.Mul(1 << 11)
+	return key.Add(&value.Co)
+}
+
+// coExpKeyOpening returns the shamir.Opening for the share returned by
+// coExpKey, using the shamir.Openings of the exponent and the coefficient. It
+// returns false if either shamir.Opening is missing.
+func coExpKeyOpening(exp, co shamir.Opening) (shamir.Opening, bool) {
+	if exp.Value == nil || exp.Blinding.Int == nil || co.Value == nil || co.Blinding.Int == nil {
+		return shamir.Opening{}, false
+	}
+	value := big.NewInt(0).Lsh(exp.Value, 11)
+	value.Add(value, co.Value)
+	blinding := big.NewInt(0).Lsh(exp.Blinding.Int, 11)
+	blinding.Add(blinding, co.Blinding.Int)
+	return shamir.Opening{Value: value, Blinding: shamir.Blinding{Int: blinding}}, true
+}
+
+// coExpKeyCommitment returns the shamir.Commitment to the share returned by
+// coExpKey, using the shamir.Commitments to the exponent and the coefficient.
+// A nil Commitment is returned if either shamir.Commitment is nil.
+func coExpKeyCommitment(exp, co shamir.Commitment) shamir.Commitment {
+	if exp.Int == nil || co.Int == nil {
+		return shamir.Commitment{}
+	}
+	key := big.NewInt(0).Exp(exp.Int, big.NewInt(1<<11), shamir.CommitP)
+	key.Mul(key, co.Int)
+	return shamir.Commitment{Int: key.Mod(key, shamir.CommitP)}
+}
+
+// buildJoinCommitments returns the smpc.JoinCommitments for a Join that
+// subtracts one value from another. For every fragment index that has
+// commitments for both values, the expected commitment is the LHS commitment
+// divided by the RHS commitment.
+func buildJoinCommitments(lhs, rhs order.FragmentCommitments, lhsSelector, rhsSelector func(order.FragmentCommitment) shamir.Commitment) smpc.JoinCommitments {
+	joinCommitments := smpc.JoinCommitments{}
+	for index, lhsCommitment := range lhs {
+		rhsCommitment, ok := rhs[index]
+		if !ok {
+			continue
+		}
+		joinCommitments[smpc.JoinIndex(index)] = shamir.Commitments{
+			lhsSelector(lhsCommitment).Sub(rhsSelector(rhsCommitment)),
+		}
+	}
+	return joinCommitments
+}
+
+// buildJoinOpenings returns the shamir.Openings for a Join that subtracts one
+// value from another. No shamir.Openings are returned unless both values have
+// an Opening.
+func buildJoinOpenings(lhs, rhs shamir.Opening) shamir.Openings {
+	if lhs.Value == nil || lhs.Blinding.Int == nil || rhs.Value == nil || rhs.Blinding.Int == nil {
+		return nil
+	}
+	return shamir.Openings{lhs.Sub(&rhs)}
+}
+
 func isGreaterThanOrEqualToZero(value uint64) bool {
 	return value >= 0 && value < shamir.Prime/2
 }
@@ -1,8 +1,10 @@
 package ome_test
 
 import (
+	"context"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -98,4 +102,162 @@ var _ = Describe("Matcher", func() {
 			Expect(numMatches).Should(BeNumerically("<", numTrials))
 		})
 	})
+
+	Context("when using an smpc that fails joins", func() {
+		It("should retry joins that time out", func() {
+			smpcer := newFailingSmpc(testutils.NewAlwaysMatchSmpc(), smpc.ErrJoinTimeout, 2)
+			matcher := NewMatcherWithTimeout(compStore, fragmentStore, smpcer, time.Second, 2, time.Millisecond)
+
+			results := make(chan Computation, 1)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				results <- com
+			})
+
+			var result Computation
+			Eventually(results).Should(Receive(&result))
+			Expect(result.Match).Should(BeTrue())
+			Expect(result.State).Should(Equal(ComputationStateMatched))
+		})
+
+		It("should fail computations when the retries are exhausted", func() {
+			smpcer := newFailingSmpc(testutils.NewAlwaysMatchSmpc(), smpc.ErrJoinTimeout, 3)
+			matcher := NewMatcherWithTimeout(compStore, fragmentStore, smpcer, time.Second, 2, time.Millisecond)
+
+			results := make(chan Computation, 1)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				results <- com
+			})
+
+			var result Computation
+			Eventually(results).Should(Receive(&result))
+			Expect(result.Match).Should(BeFalse())
+			Expect(result.State).Should(Equal(ComputationStateFailed))
+
+			stored, err := compStore.Computation(com.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.State).Should(Equal(ComputationStateFailed))
+		})
+
+		It("should retry joins on networks that are not connected", func() {
+			smpcer := newFailingSmpc(testutils.NewAlwaysMatchSmpc(), smpc.ErrJoinOnDisconnectedNetwork, 2)
+			matcher := NewMatcherWithTimeout(compStore, fragmentStore, smpcer, time.Second, 2, time.Millisecond)
+
+			results := make(chan Computation, 1)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				results <- com
+			})
+
+			var result Computation
+			Eventually(results).Should(Receive(&result))
+			Expect(result.Match).Should(BeTrue())
+			Expect(result.State).Should(Equal(ComputationStateMatched))
+		})
+
+		It("should store computations as unverified without retrying joins that cannot be verified", func() {
+			smpcer := newFailingSmpc(testutils.NewAlwaysMatchSmpc(), smpc.ErrUnverifiedJoin, 1)
+			matcher := NewMatcherWithTimeout(compStore, fragmentStore, smpcer, time.Second, 2, time.Millisecond)
+
+			results := make(chan Computation, 1)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				results <- com
+			})
+
+			var result Computation
+			Eventually(results).Should(Receive(&result))
+			Expect(result.Match).Should(BeFalse())
+			Expect(result.State).Should(Equal(ComputationStateUnverified))
+
+			stored, err := compStore.Computation(com.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stored.State).Should(Equal(ComputationStateUnverified))
+		})
+	})
+
+	Context("when using a comparer", func() {
+		It("should trigger the callback with matched results when all comparisons succeed", func() {
+			comparer := &resultComparer{result: true}
+			matcher := NewSecureMatcher(compStore, fragmentStore, testutils.NewAlwaysMatchSmpc(), comparer)
+
+			numMatches := 0
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				if com.Match {
+					numMatches++
+				}
+			})
+
+			Expect(numMatches).Should(Equal(1))
+			Expect(comparer.numComparisons).Should(Equal(3))
+		})
+
+		It("should never trigger the callback with matched results when a comparison fails", func() {
+			comparer := &resultComparer{result: false}
+			matcher := NewSecureMatcher(compStore, fragmentStore, testutils.NewAlwaysMatchSmpc(), comparer)
+
+			numMatches := 0
+			numMismatches := 0
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {
+				if com.Match {
+					numMatches++
+					return
+				}
+				numMismatches++
+			})
+
+			Expect(numMatches).Should(Equal(0))
+			Expect(numMismatches).Should(Equal(1))
+			Expect(comparer.numComparisons).Should(Equal(1))
+		})
+	})
 })
+
+// resultComparer is an smpc.Comparer that always returns the same result.
+type resultComparer struct {
+	result         bool
+	numComparisons int
+}
+
+func (comparer *resultComparer) Compare(ctx context.Context, networkID smpc.NetworkID, id smpc.CompareID, lhs, rhs shamir.Share, callback smpc.CompareCallback, errCallback smpc.CompareErrorCallback) error {
+	comparer.numComparisons++
+	callback(id, comparer.result)
+	return nil
+}
+
+// failingSmpc is an smpc.Smpcer that fails the first n Joins with an error,
+// and delegates all other Joins to a testutils.Smpc.
+type failingSmpc struct {
+	*testutils.Smpc
+
+	mu  *sync.Mutex
+	err error
+	n   int
+}
+
+func newFailingSmpc(smpcer *testutils.Smpc, err error, n int) *failingSmpc {
+	return &failingSmpc{
+		Smpc: smpcer,
+		mu:   new(sync.Mutex),
+		err:  err,
+		n:    n,
+	}
+}
+
+func (smpcer *failingSmpc) Join(ctx context.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, errCallback smpc.ErrorCallback, useDelay bool) error {
+	smpcer.mu.Lock()
+	fail := smpcer.n > 0
+	if fail {
+		smpcer.n--
+	}
+	smpcer.mu.Unlock()
+
+	if fail {
+		go errCallback(join.ID, smpcer.err)
+		return nil
+	}
+	return smpcer.Smpc.Join(ctx, networkID, join, callback, errCallback, useDelay)
+}
@@ -0,0 +1,286 @@
+package ome
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
+)
+
+type midpointMatcher struct {
+	matcher          Matcher
+	computationStore ComputationStorer
+	smpcer           smpc.Smpcer
+	comparer         smpc.Comparer
+	midpointPrices   oracle.MidpointPriceFeed
+	timeout          time.Duration
+	retries          int
+	retryDelay       time.Duration
+}
+
+// NewMidpointMatcher returns a Matcher that resolves Computations with a
+// midpoint order using the mid-point price from the oracle.MidpointPriceFeed.
+// Computations with a midpoint order are rejected when the mid-point price is
+// stale, or missing. Otherwise, they are resolved by the Matcher and, when the
+// orders match, the tokens of the orders are opened so that the mid-point
+// price of the tokens can be found. The orders only match when the mid-point
+// price is within the prices of both orders, which is checked by joining the
+// difference between each price and the mid-point price. The tokens of orders
+// are only opened after they have matched, and are opened during settlement
+// anyway. All other Computations are resolved by the Matcher. The difference
+// between each price and the mid-point price is revealed, so
+// NewSecureMidpointMatcher should be used instead.
+func NewMidpointMatcher(matcher Matcher, computationStore ComputationStorer, smpcer smpc.Smpcer, midpointPrices oracle.MidpointPriceFeed) Matcher {
+	return &midpointMatcher{
+		matcher:          matcher,
+		computationStore: computationStore,
+		smpcer:           smpcer,
+		midpointPrices:   midpointPrices,
+		timeout:          ResolveTimeout,
+		retries:          ResolveRetries,
+		retryDelay:       ResolveRetryDelay,
+	}
+}
+
+// NewSecureMidpointMatcher returns a Matcher that is the same as a Matcher
+// returned by NewMidpointMatcher, but that compares the prices of orders with
+// the mid-point price using an smpc.Comparer. The difference between the
+// prices and the mid-point price is never revealed.
+func NewSecureMidpointMatcher(matcher Matcher, computationStore ComputationStorer, smpcer smpc.Smpcer, comparer smpc.Comparer, midpointPrices oracle.MidpointPriceFeed) Matcher {
+	midpointMatcher := NewMidpointMatcher(matcher, computationStore, smpcer, midpointPrices).(*midpointMatcher)
+	midpointMatcher.comparer = comparer
+	return midpointMatcher
+}
+
+// Resolve implements the Matcher interface.
+func (matcher *midpointMatcher) Resolve(com Computation, callback MatchCallback) {
+	if !com.Buy.OrderType.IsMidpoint() && !com.Sell.OrderType.IsMidpoint() {
+		matcher.matcher.Resolve(com, callback)
+		return
+	}
+	if _, err := matcher.midpointPrices.Latest(); err != nil {
+		matcher.reject(com, callback, err)
+		return
+	}
+	matcher.matcher.Resolve(com, func(com Computation) {
+		if !com.Match {
+			callback(com)
+			return
+		}
+		matcher.resolve(smpc.NetworkID(com.Epoch), com, callback, ResolveStageMidpointTokens, 0, 0)
+	})
+}
+
+// resolve a stage of checking the mid-point price of a Computation. The price
+// is ignored when opening the tokens.
+func (matcher *midpointMatcher) resolve(networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, price uint64, attempt int) {
+	if isExpired(com) {
+		com.State = ComputationStateRejected
+		com.Match = false
+		if err := matcher.computationStore.PutComputation(com); err != nil {
+			logger.Error(fmt.Sprintf("cannot store expired computation buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err))
+		}
+		return
+	}
+
+	onError := func(err error) {
+		matcher.resolveError(err, networkID, com, callback, stage, price, attempt)
+	}
+
+	if stage != ResolveStageMidpointTokens && matcher.comparer != nil {
+		lhs, rhs := buildMidpointComparison(com, stage, price)
+		ctx, cancel := context.WithTimeout(context.Background(), matcher.timeout)
+		err := matcher.comparer.Compare(ctx, networkID, buildCompareID(com, stage), lhs, rhs, func(id smpc.CompareID, result bool) {
+			cancel()
+			matcher.resolveResult(result, networkID, com, callback, stage, price)
+		}, func(id smpc.CompareID, err error) {
+			cancel()
+			onError(err)
+		})
+		if err != nil {
+			cancel()
+			onError(err)
+		}
+		return
+	}
+
+	join, joinCommitments := buildMidpointJoin(com, stage, price)
+	matcher.smpcer.InsertCommitments(networkID, join.ID, joinCommitments)
+
+	ctx, cancel := context.WithTimeout(context.Background(), matcher.timeout)
+	err := matcher.smpcer.Join(ctx, networkID, join, func(joinID smpc.JoinID, values []uint64) {
+		cancel()
+		if len(values) != 1 {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: unexpected number of values: %v", stage, len(values)))
+			return
+		}
+		if stage == ResolveStageMidpointTokens {
+			price, err := matcher.midpointPrices.MidpointPrice(order.Tokens(values[0]))
+			if err != nil {
+				matcher.reject(com, callback, err)
+				return
+			}
+			matcher.resolve(networkID, com, callback, ResolveStageMidpointBuyPrice, price, 0)
+			return
+		}
+		matcher.resolveResult(isGreaterThanOrEqualToZero(values[0]), networkID, com, callback, stage, price)
+	}, func(joinID smpc.JoinID, err error) {
+		cancel()
+		onError(err)
+	}, false)
+	if err != nil {
+		cancel()
+		onError(err)
+	}
+}
+
+// resolveResult continues to the next stage when the mid-point price is within
+// the price of the order checked by a stage. The MatchCallback is called with
+// the match once the prices of both orders have been checked.
+func (matcher *midpointMatcher) resolveResult(result bool, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, price uint64) {
+	if result {
+		if stage == ResolveStageMidpointBuyPrice {
+			matcher.resolve(networkID, com, callback, ResolveStageMidpointSellPrice, price, 0)
+			return
+		}
+		callback(com)
+		return
+	}
+
+	// Store the computation as a mismatch
+	com.State = ComputationStateMismatched
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store mismatched computation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+	}
+
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ %v %v => buy = %v, sell = %v", stage, price, com.Buy.OrderID, com.Sell.OrderID))
+	callback(com)
+}
+
+// resolveError retries a stage in the same way as the Matcher returned by
+// NewMatcherWithTimeout.
+func (matcher *midpointMatcher) resolveError(err error, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage, price uint64, attempt int) {
+	logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: cannot join computation = %v: %v", stage, com.ID, err))
+	if (err == smpc.ErrJoinTimeout || err == smpc.ErrJoinOnDisconnectedNetwork) && attempt < matcher.retries {
+		logger.Compute(logger.LevelInfo, fmt.Sprintf("retrying %v for computation = %v: attempt %v of %v", stage, com.ID, attempt+1, matcher.retries))
+		go func() {
+			time.Sleep(matcher.retryDelay)
+			matcher.resolve(networkID, com, callback, stage, price, attempt+1)
+		}()
+		return
+	}
+
+	// Store the computation as unverified, or as a failure
+	com.State = ComputationStateFailed
+	if err == smpc.ErrUnverifiedJoin {
+		com.State = ComputationStateUnverified
+	}
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store %v computation buy = %v, sell = %v", com.State, com.Buy.OrderID, com.Sell.OrderID))
+	}
+
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ %v %v => buy = %v, sell = %v", com.State, stage, com.Buy.OrderID, com.Sell.OrderID))
+	callback(com)
+}
+
+func (matcher *midpointMatcher) reject(com Computation, callback MatchCallback, err error) {
+	// Store the computation as rejected
+	com.State = ComputationStateRejected
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store rejected computation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+	}
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ midpoint price => buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err))
+	callback(com)
+}
+
+// buildMidpointJoin returns the smpc.Join for a stage of checking the
+// mid-point price of a Computation. The tokens of the buy order are opened,
+// which are equal to the tokens of the sell order once the orders have
+// matched. Otherwise, the difference between the buy price and the mid-point
+// price, or between the mid-point price and the sell price, is opened.
+func buildMidpointJoin(com Computation, stage ResolveStage, price uint64) (smpc.Join, smpc.JoinCommitments) {
+	var share shamir.Share
+	var openings shamir.Openings
+	joinCommitments := smpc.JoinCommitments{}
+
+	key := midpointPriceKey(price)
+	keyOpening := shamir.Opening{Value: big.NewInt(0).SetUint64(key), Blinding: shamir.Blinding{Int: big.NewInt(0)}}
+	keyCommitment := shamir.NewCommitment(shamir.Share{Value: key}, shamir.Blinding{})
+
+	switch stage {
+	case ResolveStageMidpointTokens:
+		share = com.Buy.Tokens
+		if com.Buy.Openings.Tokens.Value != nil && com.Buy.Openings.Tokens.Blinding.Int != nil {
+			openings = shamir.Openings{com.Buy.Openings.Tokens}
+		}
+		for index, commitment := range com.Buy.Commitments {
+			joinCommitments[smpc.JoinIndex(index)] = shamir.Commitments{commitment.Tokens}
+		}
+
+	case ResolveStageMidpointBuyPrice:
+		buyKey := coExpKey(com.Buy.Price)
+		share = buyKey.AddConstant(shamir.Prime - key)
+		if opening, ok := coExpKeyOpening(com.Buy.Openings.PriceExp, com.Buy.Openings.PriceCo); ok {
+			openings = shamir.Openings{opening.Sub(&keyOpening)}
+		}
+		for index, commitment := range com.Buy.Commitments {
+			joinCommitments[smpc.JoinIndex(index)] = shamir.Commitments{
+				coExpKeyCommitment(commitment.PriceExp, commitment.PriceCo).Sub(keyCommitment),
+			}
+		}
+
+	case ResolveStageMidpointSellPrice:
+		sellKey := coExpKey(com.Sell.Price)
+		share = shamir.Share{Index: sellKey.Index, Value: key}
+		share = share.Sub(&sellKey)
+		if opening, ok := coExpKeyOpening(com.Sell.Openings.PriceExp, com.Sell.Openings.PriceCo); ok {
+			openings = shamir.Openings{keyOpening.Sub(&opening)}
+		}
+		for index, commitment := range com.Sell.Commitments {
+			joinCommitments[smpc.JoinIndex(index)] = shamir.Commitments{
+				keyCommitment.Sub(coExpKeyCommitment(commitment.PriceExp, commitment.PriceCo)),
+			}
+		}
+	}
+
+	// Create the join
+	join := smpc.Join{
+		Index:    smpc.JoinIndex(share.Index),
+		Shares:   shamir.Shares{share},
+		Openings: openings,
+	}
+	copy(join.ID[:], com.ID[:])
+	join.ID[32] = byte(stage)
+	return join, joinCommitments
+}
+
+// buildMidpointComparison returns the lhs and rhs shares for a stage that
+// compares the price of an order with the mid-point price using an
+// smpc.Comparer. The mid-point price is public, so it is shared by a constant
+// polynomial.
+func buildMidpointComparison(com Computation, stage ResolveStage, price uint64) (shamir.Share, shamir.Share) {
+	if stage == ResolveStageMidpointBuyPrice {
+		buyKey := coExpKey(com.Buy.Price)
+		return buyKey, shamir.Share{Index: buyKey.Index, Value: midpointPriceKey(price)}
+	}
+	sellKey := coExpKey(com.Sell.Price)
+	return shamir.Share{Index: sellKey.Index, Value: midpointPriceKey(price)}, sellKey
+}
+
+// midpointPriceKey returns the value shared by coExpKey for a price.
+func midpointPriceKey(price uint64) uint64 {
+	coExp := order.PriceToCoExp(price)
+	return coExp.Exp<<11 + coExp.Co
+}
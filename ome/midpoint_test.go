@@ -0,0 +1,220 @@
+package ome_test
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Midpoint matcher", func() {
+
+	var store *leveldb.Store
+	var midpointPriceStore *testutils.MockMidpointPriceStorer
+	var smpcer *midpointSmpc
+	var matcher Matcher
+	var com Computation
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.midpoint.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		midpointPriceStore = testutils.NewMockMidpointPriceStorer()
+		midpointPrices := oracle.NewMidpointPriceFeed(midpointPriceStore, time.Minute)
+		smpcer = newMidpointSmpc()
+		matcher = NewMidpointMatcher(NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), smpcer), store.SomerComputationStore(), smpcer, midpointPrices)
+
+		buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		buyFragments[0].OrderType = order.TypeMidpoint
+		sellFragments[0].OrderType = order.TypeLimit
+		Expect(store.SomerOrderFragmentStore().PutBuyOrderFragment([32]byte{}, buyFragments[0], "buyer", 1, order.Open)).ShouldNot(HaveOccurred())
+		Expect(store.SomerOrderFragmentStore().PutSellOrderFragment([32]byte{}, sellFragments[0], "seller", 2, order.Open)).ShouldNot(HaveOccurred())
+		com = NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateNil, true)
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.midpoint.out")
+	})
+
+	putFreshMidpointPrice := func() {
+		Expect(midpointPriceStore.PutMidpointPrice(oracle.MidpointPrice{
+			Prices: map[uint64]uint64{uint64(order.TokensETHREN): 100},
+			Nonce:  uint64(time.Now().Unix()),
+		})).ShouldNot(HaveOccurred())
+	}
+
+	resolve := func(com Computation) Computation {
+		results := make(chan Computation, 1)
+		matcher.Resolve(com, func(com Computation) {
+			results <- com
+		})
+		var result Computation
+		Eventually(results).Should(Receive(&result))
+		return result
+	}
+
+	It("should reject midpoint orders when the mid-point price is missing", func() {
+		Expect(resolve(com).Match).Should(BeFalse())
+		stored, err := store.SomerComputationStore().Computation(com.ID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stored.State).Should(Equal(ComputationStateRejected))
+	})
+
+	It("should reject midpoint orders when the mid-point price is stale", func() {
+		Expect(midpointPriceStore.PutMidpointPrice(oracle.MidpointPrice{
+			Prices: map[uint64]uint64{uint64(order.TokensETHREN): 100},
+			Nonce:  uint64(time.Now().Add(-time.Hour).Unix()),
+		})).ShouldNot(HaveOccurred())
+
+		Expect(resolve(com).Match).Should(BeFalse())
+	})
+
+	It("should reject midpoint orders when there is no mid-point price for their tokens", func() {
+		putFreshMidpointPrice()
+		smpcer.values[ResolveStageMidpointTokens] = uint64(order.TokensBTCETH)
+
+		Expect(resolve(com).Match).Should(BeFalse())
+		stored, err := store.SomerComputationStore().Computation(com.ID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stored.State).Should(Equal(ComputationStateRejected))
+	})
+
+	It("should match midpoint orders when the mid-point price is within the prices of both orders", func() {
+		putFreshMidpointPrice()
+
+		Expect(resolve(com).Match).Should(BeTrue())
+		Expect(smpcer.joins[ResolveStageMidpointBuyPrice]).Should(HaveLen(1))
+		Expect(smpcer.joins[ResolveStageMidpointSellPrice]).Should(HaveLen(1))
+	})
+
+	It("should mismatch midpoint orders when the mid-point price is above the buy price", func() {
+		putFreshMidpointPrice()
+		smpcer.values[ResolveStageMidpointBuyPrice] = shamir.Prime - 1
+
+		Expect(resolve(com).Match).Should(BeFalse())
+		stored, err := store.SomerComputationStore().Computation(com.ID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stored.State).Should(Equal(ComputationStateMismatched))
+		Expect(smpcer.joins[ResolveStageMidpointSellPrice]).Should(BeEmpty())
+	})
+
+	It("should mismatch midpoint orders when the mid-point price is below the sell price", func() {
+		putFreshMidpointPrice()
+		smpcer.values[ResolveStageMidpointSellPrice] = shamir.Prime - 1
+
+		Expect(resolve(com).Match).Should(BeFalse())
+		stored, err := store.SomerComputationStore().Computation(com.ID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stored.State).Should(Equal(ComputationStateMismatched))
+	})
+
+	It("should join committed differences between the prices of the orders and the mid-point price", func() {
+		putFreshMidpointPrice()
+		expiry := time.Now().Add(time.Hour)
+		buy := order.NewOrder(order.ParityBuy, order.TypeMidpoint, expiry, order.SettlementRenEx, order.TokensETHREN, 110, 1000, 1000, 1)
+		sell := order.NewOrder(order.ParitySell, order.TypeLimit, expiry, order.SettlementRenEx, order.TokensETHREN, 90, 1000, 1000, 2)
+		buyFragments, err := buy.SplitWithCommitments(rand.Reader, 6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragments, err := sell.SplitWithCommitments(rand.Reader, 6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := range buyFragments {
+			Expect(resolve(NewComputation([32]byte{}, buyFragments[i], sellFragments[i], ComputationStateNil, true)).Match).Should(BeTrue())
+		}
+
+		// The mid-point price of 100 is 20 keys below the buy price of 110,
+		// and 448 keys above the sell price of 90
+		expected := map[ResolveStage]uint64{
+			ResolveStageMidpointTokens:    uint64(order.TokensETHREN),
+			ResolveStageMidpointBuyPrice:  20,
+			ResolveStageMidpointSellPrice: 448,
+		}
+		for stage, value := range expected {
+			joins := smpcer.joins[stage]
+			Expect(joins).Should(HaveLen(6))
+			shares := make(shamir.Shares, 0, len(joins))
+			for _, join := range joins {
+				commitments := smpcer.commitments[stage][join.Index]
+				Expect(commitments).Should(HaveLen(1))
+				Expect(join.Openings).Should(HaveLen(1))
+				Expect(commitments[0].VerifyOpening(join.Shares[0], join.Openings[0])).Should(BeTrue())
+				shares = append(shares, join.Shares[0])
+			}
+			Expect(shamir.Join(shares)).Should(Equal(value))
+		}
+	})
+})
+
+// midpointSmpc is an smpc.Smpcer that records the Joins, and commitments, of
+// the midpoint stages. Joins of the midpoint stages are opened to the values
+// set for their stage, and all other Joins are matched.
+type midpointSmpc struct {
+	*testutils.Smpc
+
+	mu          *sync.Mutex
+	values      map[ResolveStage]uint64
+	joins       map[ResolveStage][]smpc.Join
+	commitments map[ResolveStage]smpc.JoinCommitments
+}
+
+func newMidpointSmpc() *midpointSmpc {
+	return &midpointSmpc{
+		Smpc: testutils.NewAlwaysMatchSmpc(),
+
+		mu: new(sync.Mutex),
+		values: map[ResolveStage]uint64{
+			ResolveStageMidpointTokens:    uint64(order.TokensETHREN),
+			ResolveStageMidpointBuyPrice:  0,
+			ResolveStageMidpointSellPrice: 0,
+		},
+		joins:       map[ResolveStage][]smpc.Join{},
+		commitments: map[ResolveStage]smpc.JoinCommitments{},
+	}
+}
+
+func (smpcer *midpointSmpc) InsertCommitments(networkID smpc.NetworkID, joinID smpc.JoinID, joinCommitments smpc.JoinCommitments) {
+	smpcer.mu.Lock()
+	defer smpcer.mu.Unlock()
+
+	stage := ResolveStage(joinID[32])
+	if _, ok := smpcer.values[stage]; !ok {
+		return
+	}
+	if smpcer.commitments[stage] == nil {
+		smpcer.commitments[stage] = smpc.JoinCommitments{}
+	}
+	for index, commitments := range joinCommitments {
+		smpcer.commitments[stage][index] = commitments
+	}
+}
+
+func (smpcer *midpointSmpc) Join(ctx context.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, errCallback smpc.ErrorCallback, useDelay bool) error {
+	smpcer.mu.Lock()
+	stage := ResolveStage(join.ID[32])
+	value, ok := smpcer.values[stage]
+	if ok {
+		smpcer.joins[stage] = append(smpcer.joins[stage], join)
+	}
+	smpcer.mu.Unlock()
+
+	if !ok {
+		return smpcer.Smpc.Join(ctx, networkID, join, callback, errCallback, useDelay)
+	}
+	callback(join.ID, []uint64{value})
+	return nil
+}
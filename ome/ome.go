@@ -36,8 +36,11 @@ type ome struct {
 	matcher   Matcher
 	confirmer Confirmer
 	settler   Settler
+	resharer  Resharer
 	smpcer    smpc.Smpcer
 
+	computationStore ComputationStorer
+
 	epochMu   *sync.RWMutex
 	epochCurr *registry.Epoch
 	epochPrev *registry.Epoch
@@ -46,7 +49,10 @@ type ome struct {
 // NewOme returns an Ome that uses an order.Orderbook to synchronize changes
 // from the Ethereum blockchain, and an smpc.Smpcer to run the secure
 // multi-party computations necessary for the secure order matching engine.
-func NewOme(addr identity.Address, gen ComputationGenerator, matcher Matcher, confirmer Confirmer, settler Settler, orderbook orderbook.Orderbook, smpcer smpc.Smpcer, epochPrev registry.Epoch) Ome {
+// The Resharer is used to carry open orders across changes to the Epoch. The
+// ComputationStorer is used to resume Computations that were in-flight when
+// the Ome was last stopped.
+func NewOme(addr identity.Address, gen ComputationGenerator, matcher Matcher, confirmer Confirmer, settler Settler, resharer Resharer, orderbook orderbook.Orderbook, smpcer smpc.Smpcer, computationStore ComputationStorer, epochPrev registry.Epoch) Ome {
 	ome := &ome{
 		addr:      addr,
 		orderbook: orderbook,
@@ -54,8 +60,11 @@ func NewOme(addr identity.Address, gen ComputationGenerator, matcher Matcher, co
 		matcher:   matcher,
 		confirmer: confirmer,
 		settler:   settler,
+		resharer:  resharer,
 		smpcer:    smpcer,
 
+		computationStore: computationStore,
+
 		epochMu:   new(sync.RWMutex),
 		epochCurr: nil,
 		epochPrev: nil,
@@ -94,6 +103,14 @@ func (ome *ome) Run(done <-chan struct{}) <-chan error {
 		dispatch.Forward(done, matchErr, errs)
 	}()
 
+	// Run the settlement queue
+	settlementErrs := ome.settler.Run(done)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dispatch.Forward(done, settlementErrs, errs)
+	}()
+
 	// Sync the Confirmer to the Settler
 	wg.Add(1)
 	go func() {
@@ -101,6 +118,13 @@ func (ome *ome) Run(done <-chan struct{}) <-chan error {
 		ome.syncConfirmerToSettler(done, matches, errs)
 	}()
 
+	// Resume the Computations that were in-flight when the Ome was stopped
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ome.resumeComputations(done, matches)
+	}()
+
 	// Cleanup
 	go func() {
 		defer close(errs)
@@ -112,7 +136,8 @@ func (ome *ome) Run(done <-chan struct{}) <-chan error {
 
 // OnChangeEpoch updates the Ome to the next Epoch. This will cause
 // cascading changes throughout the Ome, most notably it will connect to a new
-// Smpc network that will handle future Computations.
+// Smpc network that will handle future Computations, and reshare the open
+// orders from the current Epoch to the Pods of the next Epoch.
 func (ome *ome) OnChangeEpoch(epoch registry.Epoch) {
 	ome.epochMu.RLock()
 	defer ome.epochMu.RUnlock()
@@ -136,6 +161,9 @@ func (ome *ome) OnChangeEpoch(epoch registry.Epoch) {
 
 		ome.orderbook.OnChangeEpoch(epoch)
 		ome.gen.OnChangeEpoch(epoch)
+		if ome.epochCurr != nil {
+			ome.resharer.Reshare(*ome.epochCurr, epoch)
+		}
 
 		// Wait for some time to allow for the connections to begin
 		time.Sleep(time.Second)
@@ -199,7 +227,7 @@ func (ome *ome) syncConfirmerToSettler(done <-chan struct{}, matches <-chan Comp
 			if !ok {
 				return
 			}
-			go ome.sendComputationToSettler(confirmation)
+			ome.sendComputationToSettler(confirmation)
 		}
 	}
 }
@@ -210,3 +238,62 @@ func (ome *ome) sendComputationToSettler(com Computation) {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot settle: %v", err))
 	}
 }
+
+// resumeComputations loads the Computations that were matched, or accepted,
+// but not settled when the Ome was last stopped. Matched Computations are sent
+// to the Confirmer, which resumes polling for their confirmation, and accepted
+// Computations are sent to the Settler once the Ome has connected to its
+// Epoch. Computations with expired orders are not resumed.
+func (ome *ome) resumeComputations(done <-chan struct{}, matches chan<- Computation) {
+	comsIter, err := ome.computationStore.Computations()
+	if err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot load computations for resuming: %v", err))
+		return
+	}
+	coms, err := comsIter.Collect()
+	comsIter.Release()
+	if err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot collect computations for resuming: %v", err))
+	}
+
+	now := time.Now()
+	accepted := Computations{}
+	for _, com := range coms {
+		if isComputationExpired(com, now) {
+			continue
+		}
+		switch com.State {
+		case ComputationStateMatched:
+			logger.Compute(logger.LevelDebug, fmt.Sprintf("resuming confirmation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+			select {
+			case <-done:
+				return
+			case matches <- com:
+			}
+		case ComputationStateAccepted:
+			accepted = append(accepted, com)
+		}
+	}
+	if len(accepted) == 0 {
+		return
+	}
+
+	// Settlement needs a connection to the smpc network of the Epoch
+	for {
+		ome.epochMu.RLock()
+		connected := ome.epochCurr != nil
+		ome.epochMu.RUnlock()
+		if connected {
+			break
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+	for _, com := range accepted {
+		logger.Compute(logger.LevelDebug, fmt.Sprintf("resuming settlement buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+		ome.sendComputationToSettler(com)
+	}
+}
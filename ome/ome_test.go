@@ -42,6 +42,7 @@ var _ = Describe("Ome", func() {
 		matcher               Matcher
 		confirmer             Confirmer
 		settler               Settler
+		resharer              Resharer
 	)
 
 	Context("ome should manage everything about order matching ", func() {
@@ -66,7 +67,10 @@ var _ = Describe("Ome", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 			matcher = NewMatcher(comStorer, fragmentStorer, smpcer)
 			confirmer = NewConfirmer(comStorer, fragmentStorer, contract, PollInterval, Depth)
-			settler = NewSettler(comStorer, smpcer, contract, 0)
+			backends := NewSettlementBackendRegistry()
+			backends.Register(order.SettlementRenEx, contract)
+			settler = NewSettler(comStorer, store.SomerSettlementStore(), smpcer, contract, backends, nil, 0)
+			resharer = NewResharer(addr, book, smpcer, fragmentStorer, time.Second)
 		})
 
 		AfterEach(func() {
@@ -76,7 +80,7 @@ var _ = Describe("Ome", func() {
 		It("should be able to sync with the order book ", func() {
 			done := make(chan struct{})
 
-			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, smpcer, epoch)
+			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, resharer, book, smpcer, comStorer, epoch)
 			errs := ome.Run(done)
 			go func() {
 				defer GinkgoRecover()
@@ -91,7 +95,7 @@ var _ = Describe("Ome", func() {
 
 		It("should be able to listen for epoch change event", func() {
 			done := make(chan struct{})
-			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, smpcer, epoch)
+			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, resharer, book, smpcer, comStorer, epoch)
 			errs := ome.Run(done)
 
 			go func() {
@@ -109,6 +113,30 @@ var _ = Describe("Ome", func() {
 			time.Sleep(2 * time.Second)
 
 		})
+
+		It("should resume confirming matched computations after restarting", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			com := NewComputation(epoch.Hash, buyFragments[0], sellFragments[0], ComputationStateMatched, true)
+			Expect(comStorer.PutComputation(com)).ShouldNot(HaveOccurred())
+			Expect(contract.(*omeBinder).OpenBuyOrder([65]byte{}, com.Buy.OrderID)).ShouldNot(HaveOccurred())
+			Expect(contract.(*omeBinder).OpenSellOrder([65]byte{}, com.Sell.OrderID)).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, resharer, book, smpcer, comStorer, epoch)
+			ome.Run(done)
+
+			Eventually(func() ComputationState {
+				com, err := comStorer.Computation(com.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				return com.State
+			}, 5*time.Second).ShouldNot(Equal(ComputationStateMatched))
+			Expect(contract.Status(com.Buy.OrderID)).Should(Equal(order.Confirmed))
+			Expect(contract.Status(com.Sell.OrderID)).Should(Equal(order.Confirmed))
+		})
 	})
 })
 
@@ -127,10 +155,11 @@ type omeBinder struct {
 	orders      map[order.ID]int
 	orderStatus map[order.ID]order.Status
 
-	mu    *sync.Mutex
-	comps int
-	buys  map[order.ID]struct{}
-	sells map[order.ID]struct{}
+	mu     *sync.Mutex
+	comps  int
+	buys   map[order.ID]struct{}
+	sells  map[order.ID]struct{}
+	prices map[order.ID]uint64
 }
 
 // newOmeBinder returns a mock omeBinder.
@@ -146,10 +175,11 @@ func newOmeBinder() *omeBinder {
 		orders:      map[order.ID]int{},
 		orderStatus: map[order.ID]order.Status{},
 
-		mu:    new(sync.Mutex),
-		comps: 0,
-		buys:  map[order.ID]struct{}{},
-		sells: map[order.ID]struct{}{},
+		mu:     new(sync.Mutex),
+		comps:  0,
+		buys:   map[order.ID]struct{}{},
+		sells:  map[order.ID]struct{}{},
+		prices: map[order.ID]uint64{},
 	}
 }
 
@@ -236,13 +266,14 @@ func (binder *omeBinder) OpenSellOrder(signature [65]byte, orderID order.ID) err
 }
 
 // Settle implements the Settle function of the mock omeBinder
-func (binder *omeBinder) Settle(buy order.Order, sell order.Order) error {
+func (binder *omeBinder) Settle(buy order.Order, sell order.Order, price uint64) error {
 	binder.mu.Lock()
 	defer binder.mu.Unlock()
 
 	binder.comps++
 	binder.buys[buy.ID] = struct{}{}
 	binder.sells[buy.ID] = struct{}{}
+	binder.prices[buy.ID] = price
 
 	return nil
 }
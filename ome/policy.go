@@ -0,0 +1,213 @@
+package ome
+
+import (
+	"errors"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+// ErrUnknownMatchingPolicy is returned when a MatchingPolicy is requested by a
+// name that is not recognised.
+var ErrUnknownMatchingPolicy = errors.New("unknown matching policy")
+
+// Names of the MatchingPolicies that can be selected by name.
+const (
+	MatchingPolicyWeighted     = "weighted"
+	MatchingPolicyTimePriority = "timePriority"
+	MatchingPolicyOldestFirst  = "oldestFirst"
+)
+
+// A MatchingCandidate is a Computation that has been generated by the
+// ComputationGenerator, and is waiting to be sent to the Matcher.
+type MatchingCandidate struct {
+	Computation Computation
+
+	// OpenPriority is the priority of the order that was opened, causing the
+	// Computation to be generated. RestingPriority is the priority of the
+	// order that was already stored when the other order was opened.
+	OpenPriority    uint64
+	RestingPriority uint64
+
+	// Adjustment is the distance of this Pod from the first Pod that could
+	// possibly do the Computation.
+	Adjustment uint64
+}
+
+// A MatchingPolicy decides which orders can be matched with each other, and
+// the order in which MatchingCandidates are sent to the Matcher.
+type MatchingPolicy interface {
+
+	// IsCompatible returns true if the order opened by the notification can
+	// be matched with an order.Fragment that is already stored, and opened by
+	// the trader at the priority.
+	IsCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool
+
+	// Before returns true if the lhs MatchingCandidate must be sent to the
+	// Matcher before the rhs MatchingCandidate. MatchingCandidates are insert
+	// sorted, and Before must be consistent with the existing ordering.
+	Before(lhs, rhs MatchingCandidate) bool
+}
+
+// NewMatchingPolicy returns the MatchingPolicy with the given name. An empty
+// name returns the weighted MatchingPolicy.
+func NewMatchingPolicy(name string) (MatchingPolicy, error) {
+	switch name {
+	case "", MatchingPolicyWeighted:
+		return NewWeightedMatchingPolicy(), nil
+	case MatchingPolicyTimePriority:
+		return NewTimePriorityMatchingPolicy(), nil
+	case MatchingPolicyOldestFirst:
+		return NewOldestFirstMatchingPolicy(), nil
+	default:
+		return nil, ErrUnknownMatchingPolicy
+	}
+}
+
+type weightedMatchingPolicy struct {
+}
+
+// NewWeightedMatchingPolicy returns a MatchingPolicy that weights a
+// MatchingCandidate by the sum of the priorities of its orders, and the
+// adjustment of this Pod. MatchingCandidates with higher weights are sent to
+// the Matcher first, and candidates with equal weights are sent newest first.
+func NewWeightedMatchingPolicy() MatchingPolicy {
+	return &weightedMatchingPolicy{}
+}
+
+// IsCompatible implements the MatchingPolicy interface.
+func (policy *weightedMatchingPolicy) IsCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool {
+	return isCompatible(notification, orderFragment, trader, priority)
+}
+
+// Before implements the MatchingPolicy interface.
+func (policy *weightedMatchingPolicy) Before(lhs, rhs MatchingCandidate) bool {
+	return lhs.OpenPriority+lhs.RestingPriority+lhs.Adjustment >= rhs.OpenPriority+rhs.RestingPriority+rhs.Adjustment
+}
+
+type timePriorityMatchingPolicy struct {
+}
+
+// NewTimePriorityMatchingPolicy returns a MatchingPolicy that gives priority
+// to orders by the time at which they were opened. Orders are handled in the
+// order that they are opened, and each order is matched against the stored
+// orders starting with the oldest stored order. Prices are secret shares, so
+// orders cannot be prioritised by price.
+func NewTimePriorityMatchingPolicy() MatchingPolicy {
+	return &timePriorityMatchingPolicy{}
+}
+
+// IsCompatible implements the MatchingPolicy interface.
+func (policy *timePriorityMatchingPolicy) IsCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool {
+	return isCompatible(notification, orderFragment, trader, priority)
+}
+
+// Before implements the MatchingPolicy interface.
+func (policy *timePriorityMatchingPolicy) Before(lhs, rhs MatchingCandidate) bool {
+	if lhs.OpenPriority != rhs.OpenPriority {
+		return lhs.OpenPriority < rhs.OpenPriority
+	}
+	if lhs.RestingPriority != rhs.RestingPriority {
+		return lhs.RestingPriority < rhs.RestingPriority
+	}
+	return lhs.Adjustment > rhs.Adjustment
+}
+
+type oldestFirstMatchingPolicy struct {
+}
+
+// NewOldestFirstMatchingPolicy returns a MatchingPolicy that sends the
+// MatchingCandidate with the oldest order to the Matcher first, regardless of
+// whether the oldest order was opened or stored. Candidates with the same
+// oldest order are sent in the order of their other order.
+func NewOldestFirstMatchingPolicy() MatchingPolicy {
+	return &oldestFirstMatchingPolicy{}
+}
+
+// IsCompatible implements the MatchingPolicy interface.
+func (policy *oldestFirstMatchingPolicy) IsCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool {
+	return isCompatible(notification, orderFragment, trader, priority)
+}
+
+// Before implements the MatchingPolicy interface.
+func (policy *oldestFirstMatchingPolicy) Before(lhs, rhs MatchingCandidate) bool {
+	lhsOldest, lhsNewest := oldestAndNewest(lhs)
+	rhsOldest, rhsNewest := oldestAndNewest(rhs)
+	if lhsOldest != rhsOldest {
+		return lhsOldest < rhsOldest
+	}
+	if lhsNewest != rhsNewest {
+		return lhsNewest < rhsNewest
+	}
+	return lhs.Adjustment > rhs.Adjustment
+}
+
+func oldestAndNewest(candidate MatchingCandidate) (uint64, uint64) {
+	if candidate.OpenPriority < candidate.RestingPriority {
+		return candidate.OpenPriority, candidate.RestingPriority
+	}
+	return candidate.RestingPriority, candidate.OpenPriority
+}
+
+// isCompatible checks if the notification's order is compatible with another
+// order based on the following conditions:
+//  1. If the trader is the same as the notification's trader, the 2 orders are
+//     incompatible.
+//  2. Orders from the same epoch must not be matched twice (i.e. if both orders
+//     are at depth 1, they are incompatible).
+//  3. If both orders are Fill-or-Kill (FOK), they are incompatible.
+//  4. If one of the orders is a FOK, then both orders are incompatible if the
+//     other order is of a higher priority.
+//  5. If the stored order is Immediate-or-Cancel (IOC), the orders are
+//     incompatible, because IOC orders are only matched against orders that
+//     were stored before them.
+func isCompatible(notification orderbook.NotificationOpenOrder, orderFragment order.Fragment, trader string, priority uint64) bool {
+
+	// Traders should not match against themselves
+	if trader == notification.Trader {
+		return false
+	}
+
+	// Immediate-or-Cancel orders do not rest in the order book
+	if orderFragment.OrderType.IsImmediateOrCancel() {
+		return false
+	}
+
+	// Order fragments with depth 1 should have been found compatible at epoch
+	// depth of 0.
+	if orderFragment.EpochDepth == 1 && notification.OrderFragment.EpochDepth == orderFragment.EpochDepth {
+		return false
+	}
+
+	switch orderFragment.OrderType {
+
+	// The orderFragment is a Fill-or-Kill order
+	case order.TypeMidpointFOK, order.TypeLimitFOK:
+		switch notification.OrderFragment.OrderType {
+		case order.TypeMidpointFOK, order.TypeLimitFOK:
+			// Both orders are FOK, thus, incompatible.
+			return false
+		default:
+			// Does notification.OrderFragment, which is not an FOK order, have
+			// a higher priority than the FOK order ?
+			if uint64(notification.Priority) > priority {
+				return false
+			}
+			return true
+		}
+
+	// The orderFragment is not a Fill-or-Kill order
+	default:
+		switch notification.OrderFragment.OrderType {
+		case order.TypeMidpointFOK, order.TypeLimitFOK:
+			// Does notification.OrderFragment, which is an FOK order, have a
+			// lower priority than the other order ?
+			if priority > uint64(notification.Priority) {
+				return false
+			}
+			return true
+		default:
+			return true
+		}
+	}
+}
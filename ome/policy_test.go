@@ -0,0 +1,97 @@
+package ome_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+var _ = Describe("Matching policies", func() {
+
+	candidate := func(openPriority, restingPriority uint64) MatchingCandidate {
+		return MatchingCandidate{
+			OpenPriority:    openPriority,
+			RestingPriority: restingPriority,
+		}
+	}
+
+	Context("when selecting a policy by name", func() {
+
+		It("should return the weighted policy by default", func() {
+			policy, err := NewMatchingPolicy("")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(policy).Should(Equal(NewWeightedMatchingPolicy()))
+		})
+
+		It("should return the policies with known names", func() {
+			for _, name := range []string{MatchingPolicyWeighted, MatchingPolicyTimePriority, MatchingPolicyOldestFirst} {
+				_, err := NewMatchingPolicy(name)
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should error for unknown names", func() {
+			_, err := NewMatchingPolicy("proRata")
+			Expect(err).Should(Equal(ErrUnknownMatchingPolicy))
+		})
+	})
+
+	Context("when ordering candidates", func() {
+
+		It("should send the heaviest candidates first using the weighted policy", func() {
+			policy := NewWeightedMatchingPolicy()
+			Expect(policy.Before(candidate(10, 1), candidate(5, 5))).Should(BeTrue())
+			Expect(policy.Before(candidate(5, 5), candidate(10, 1))).Should(BeFalse())
+		})
+
+		It("should send candidates in the order that orders are opened using the time priority policy", func() {
+			policy := NewTimePriorityMatchingPolicy()
+			Expect(policy.Before(candidate(5, 5), candidate(10, 1))).Should(BeTrue())
+			Expect(policy.Before(candidate(10, 1), candidate(10, 2))).Should(BeTrue())
+			Expect(policy.Before(candidate(10, 2), candidate(10, 1))).Should(BeFalse())
+		})
+
+		It("should send candidates with the oldest order first using the oldest-first policy", func() {
+			policy := NewOldestFirstMatchingPolicy()
+			Expect(policy.Before(candidate(10, 1), candidate(5, 5))).Should(BeTrue())
+			Expect(policy.Before(candidate(2, 10), candidate(10, 1))).Should(BeFalse())
+			Expect(policy.Before(candidate(1, 3), candidate(4, 1))).Should(BeTrue())
+		})
+	})
+
+	Context("when checking compatibility", func() {
+
+		policies := []MatchingPolicy{NewWeightedMatchingPolicy(), NewTimePriorityMatchingPolicy(), NewOldestFirstMatchingPolicy()}
+
+		It("should not match orders from the same trader", func() {
+			notification := orderbook.NotificationOpenOrder{Trader: "trader", Priority: 2, OrderFragment: order.Fragment{OrderType: order.TypeLimit}}
+			for _, policy := range policies {
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimit}, "trader", 1)).Should(BeFalse())
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimit}, "other", 1)).Should(BeTrue())
+			}
+		})
+
+		It("should not match fill-or-kill orders with older orders", func() {
+			notification := orderbook.NotificationOpenOrder{Trader: "trader", Priority: 2, OrderFragment: order.Fragment{OrderType: order.TypeLimitFOK}}
+			for _, policy := range policies {
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimit}, "other", 3)).Should(BeFalse())
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimit}, "other", 1)).Should(BeTrue())
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimitFOK}, "other", 1)).Should(BeFalse())
+			}
+		})
+
+		It("should only match immediate-or-cancel orders against orders stored before them", func() {
+			notification := orderbook.NotificationOpenOrder{Trader: "trader", Priority: 2, OrderFragment: order.Fragment{OrderType: order.TypeLimitIOC}}
+			for _, policy := range policies {
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimit}, "other", 1)).Should(BeTrue())
+			}
+			notification = orderbook.NotificationOpenOrder{Trader: "trader", Priority: 2, OrderFragment: order.Fragment{OrderType: order.TypeLimit}}
+			for _, policy := range policies {
+				Expect(policy.IsCompatible(notification, order.Fragment{OrderType: order.TypeLimitIOC}, "other", 1)).Should(BeFalse())
+			}
+		})
+	})
+})
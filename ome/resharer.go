@@ -0,0 +1,280 @@
+package ome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
+)
+
+// ErrUnexpectedReshare is returned when new shamir.Shares produced by
+// resharing cannot be used to build an order.Fragment.
+var ErrUnexpectedReshare = errors.New("unexpected reshare")
+
+// A Resharer carries open order.Fragments across registry.Epoch transitions.
+// The Pod that held an order.Fragment in the previous registry.Epoch reshares
+// it to the Pods that are responsible for the order in the current
+// registry.Epoch, without ever reconstructing the order. Old and new
+// order.Fragments use independent polynomials, so order.Fragments leaked
+// from the previous registry.Epoch cannot be combined with order.Fragments
+// from the current registry.Epoch.
+type Resharer interface {
+
+	// Reshare the open order.Fragments held for the previous registry.Epoch
+	// to the Pods of the current registry.Epoch, and insert order.Fragments
+	// that are reshared to this node into the orderbook.Orderbook.
+	Reshare(epochPrev, epochCurr registry.Epoch)
+}
+
+type resharer struct {
+	addr          identity.Address
+	orderbook     orderbook.Orderbook
+	smpcer        smpc.Smpcer
+	fragmentStore OrderFragmentStorer
+	delay         time.Duration
+
+	networkMu *sync.Mutex
+	networkID *smpc.NetworkID
+}
+
+// NewResharer returns a Resharer that reads open order.Fragments from an
+// OrderFragmentStorer, and uses an smpc.Smpcer to reshare them. The delay
+// gives other nodes time to connect to the resharing network before any
+// order.Fragments are reshared.
+func NewResharer(addr identity.Address, orderbook orderbook.Orderbook, smpcer smpc.Smpcer, fragmentStore OrderFragmentStorer, delay time.Duration) Resharer {
+	return &resharer{
+		addr:          addr,
+		orderbook:     orderbook,
+		smpcer:        smpcer,
+		fragmentStore: fragmentStore,
+		delay:         delay,
+
+		networkMu: new(sync.Mutex),
+		networkID: nil,
+	}
+}
+
+// Reshare implements the Resharer interface. All Darknodes from both
+// registry.Epochs are connected to a single resharing network, which replaces
+// the resharing network from the previous call.
+func (resharer *resharer) Reshare(epochPrev, epochCurr registry.Epoch) {
+	if epochPrev.IsNil() || epochCurr.IsNil() {
+		return
+	}
+
+	var networkID smpc.NetworkID
+	copy(networkID[:], crypto.Keccak256(epochPrev.Hash[:], epochCurr.Hash[:]))
+
+	func() {
+		resharer.networkMu.Lock()
+		defer resharer.networkMu.Unlock()
+
+		if resharer.networkID != nil {
+			resharer.smpcer.Disconnect(*resharer.networkID)
+		}
+		resharer.networkID = &networkID
+
+		resharer.smpcer.Connect(networkID, resharingAddresses(epochPrev, epochCurr))
+		resharer.smpcer.OnReshare(networkID, func(id smpc.ReshareID) identity.Addresses {
+			return resharingSenders(epochPrev, order.ID(id))
+		}, func(id smpc.ReshareID, shares shamir.Shares, data []byte) {
+			go func() {
+				if err := resharer.insertReshare(id, shares, data); err != nil {
+					logger.Compute(logger.LevelError, fmt.Sprintf("cannot insert reshared order fragment: %v", err))
+				}
+			}()
+		})
+	}()
+
+	go func() {
+		time.Sleep(resharer.delay)
+		resharer.reshareOrderFragments(networkID, epochPrev, epochCurr)
+	}()
+}
+
+// reshareOrderFragments held by this node for the previous registry.Epoch.
+// Only the leaf Pod in the path of an order reshares its order.Fragments, so
+// that all receivers combine order.Fragments that were split from the same
+// polynomials. The order.Fragment held by the Darknode at position i of the
+// Pod must have index i+1, which is checked by the receivers of its Reshares.
+func (resharer *resharer) reshareOrderFragments(networkID smpc.NetworkID, epochPrev, epochCurr registry.Epoch) {
+	podPrev, err := epochPrev.Pod(resharer.addr)
+	if err != nil {
+		// This node held no order.Fragments in the previous registry.Epoch
+		return
+	}
+
+	orderFragments := []order.Fragment{}
+	traders := []string{}
+	priorities := []uint64{}
+	collect := func(iter OrderFragmentIterator, err error) {
+		if err != nil {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot load order fragments for resharing: %v", err))
+			return
+		}
+		defer iter.Release()
+
+		for iter.Next() {
+			orderFragment, trader, priority, status, err := iter.Cursor()
+			if err != nil {
+				logger.Compute(logger.LevelError, fmt.Sprintf("cannot load order fragment for resharing: %v", err))
+				continue
+			}
+			if status != order.Open {
+				continue
+			}
+			// Immediate-or-cancel orders can only match orders that were
+			// open before them, so they are not carried into the next epoch
+			if orderFragment.OrderType.IsImmediateOrCancel() {
+				continue
+			}
+			orderFragments = append(orderFragments, orderFragment)
+			traders = append(traders, trader)
+			priorities = append(priorities, priority)
+		}
+	}
+	collect(resharer.fragmentStore.BuyOrderFragments(epochPrev.Hash))
+	collect(resharer.fragmentStore.SellOrderFragments(epochPrev.Hash))
+
+	for i, orderFragment := range orderFragments {
+		pathPrev := epochPrev.Pods.PathOfOrder(orderFragment.OrderID)
+		if len(pathPrev) == 0 || pathPrev[len(pathPrev)-1].Hash != podPrev.Hash {
+			continue
+		}
+		if err := resharer.reshareOrderFragment(networkID, podPrev, epochCurr, orderFragment, traders[i], priorities[i]); err != nil {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot reshare order fragment for order = %v: %v", orderFragment.OrderID, err))
+		}
+	}
+}
+
+func (resharer *resharer) reshareOrderFragment(networkID smpc.NetworkID, podPrev registry.Pod, epochCurr registry.Epoch, orderFragment order.Fragment, trader string, priority uint64) error {
+	data, err := json.Marshal(reshareData{
+		OrderID:         orderFragment.OrderID,
+		OrderType:       orderFragment.OrderType,
+		OrderParity:     orderFragment.OrderParity,
+		OrderSettlement: orderFragment.OrderSettlement,
+		OrderExpiry:     orderFragment.OrderExpiry,
+		Trader:          trader,
+		Priority:        priority,
+	})
+	if err != nil {
+		return err
+	}
+	shares := shamir.Shares{
+		orderFragment.Tokens,
+		orderFragment.Price.Co,
+		orderFragment.Price.Exp,
+		orderFragment.Volume.Co,
+		orderFragment.Volume.Exp,
+		orderFragment.MinimumVolume.Co,
+		orderFragment.MinimumVolume.Exp,
+		orderFragment.Nonce,
+	}
+
+	index := smpc.JoinIndex(orderFragment.Tokens.Index)
+	if index == 0 || int(index) > len(podPrev.Darknodes) || podPrev.Darknodes[index-1] != resharer.addr {
+		return fmt.Errorf("%v: order fragment index %v is not held by %v", ErrUnexpectedReshare, index, resharer.addr)
+	}
+	n := int64(len(podPrev.Darknodes))
+	k := int64(podPrev.Threshold())
+	for _, pod := range epochCurr.Pods.PathOfOrder(orderFragment.OrderID) {
+		reshares, err := smpc.ReshareShares(smpc.ReshareID(orderFragment.OrderID), index, n, k, shares, int64(len(pod.Darknodes)), int64(pod.Threshold()), data)
+		if err != nil {
+			return err
+		}
+		for j, addr := range pod.Darknodes {
+			if err := resharer.smpcer.Reshare(networkID, addr, reshares[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// insertReshare builds an order.Fragment from the new shamir.Shares produced
+// by resharing, and inserts it into the orderbook.Orderbook.
+func (resharer *resharer) insertReshare(id smpc.ReshareID, shares shamir.Shares, data []byte) error {
+	reshareData := reshareData{}
+	if err := json.Unmarshal(data, &reshareData); err != nil {
+		return err
+	}
+	if smpc.ReshareID(reshareData.OrderID) != id {
+		return fmt.Errorf("%v: unexpected order = %v", ErrUnexpectedReshare, reshareData.OrderID)
+	}
+	if len(shares) != 8 {
+		return fmt.Errorf("%v: expected 8 shares, got %v", ErrUnexpectedReshare, len(shares))
+	}
+
+	orderFragment, err := order.NewFragment(
+		reshareData.OrderID,
+		reshareData.OrderType,
+		reshareData.OrderParity,
+		reshareData.OrderSettlement,
+		reshareData.OrderExpiry,
+		shares[0],
+		order.CoExpShare{Co: shares[1], Exp: shares[2]},
+		order.CoExpShare{Co: shares[3], Exp: shares[4]},
+		order.CoExpShare{Co: shares[5], Exp: shares[6]},
+		shares[7],
+	)
+	if err != nil {
+		return err
+	}
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("reshared order = %v", orderFragment.OrderID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), smpc.ReshareTimeout)
+	defer cancel()
+	return resharer.orderbook.InsertOrderFragment(ctx, orderFragment, reshareData.Trader, uint(reshareData.Priority))
+}
+
+// reshareData is the public data about an order.Fragment that is sent
+// alongside its reshared shamir.Shares.
+type reshareData struct {
+	OrderID         order.ID         `json:"orderID"`
+	OrderType       order.Type       `json:"orderType"`
+	OrderParity     order.Parity     `json:"orderParity"`
+	OrderSettlement order.Settlement `json:"orderSettlement"`
+	OrderExpiry     time.Time        `json:"orderExpiry"`
+	Trader          string           `json:"trader"`
+	Priority        uint64           `json:"priority"`
+}
+
+// resharingSenders returns the identity.Addresses of the Darknodes that reshare
+// the order.Fragments of an order, ordered by the index of the order.Fragment
+// that they hold. These are the Darknodes in the leaf Pod of the path of the
+// order in the previous registry.Epoch.
+func resharingSenders(epochPrev registry.Epoch, orderID order.ID) identity.Addresses {
+	pathPrev := epochPrev.Pods.PathOfOrder(orderID)
+	if len(pathPrev) == 0 {
+		return nil
+	}
+	return pathPrev[len(pathPrev)-1].Darknodes
+}
+
+// resharingAddresses returns the identity.Addresses of all Darknodes in
+// either registry.Epoch, without duplicates.
+func resharingAddresses(epochPrev, epochCurr registry.Epoch) identity.Addresses {
+	seen := map[identity.Address]struct{}{}
+	addrs := identity.Addresses{}
+	for _, darknodes := range []identity.Addresses{epochPrev.Darknodes, epochCurr.Darknodes} {
+		for _, addr := range darknodes {
+			if _, ok := seen[addr]; ok {
+				continue
+			}
+			seen[addr] = struct{}{}
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
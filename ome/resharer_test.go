@@ -0,0 +1,196 @@
+package ome_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Resharer", func() {
+
+	var numPrev = 6
+	var numCurr = 5
+
+	AfterEach(func() {
+		os.RemoveAll("./data.out")
+	})
+
+	Context("when the epoch changes", func() {
+
+		It("should reshare open order fragments to the pod of the next epoch", func() {
+			epochPrev := randomEpochWithDarknodes(numPrev)
+			epochCurr := randomEpochWithDarknodes(numCurr)
+			router := newResharerRouter()
+
+			// Give the order fragments of an open order to the previous pod
+			ord := testutils.RandomOrder()
+			podPrev := epochPrev.Pods[0]
+			fragments, err := ord.Split(int64(numPrev), int64(podPrev.Threshold()))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			resharers := []Resharer{}
+			for i, addr := range podPrev.Darknodes {
+				store, err := leveldb.NewStore(fmt.Sprintf("./data.out/%v", i), time.Hour, time.Hour)
+				Expect(err).ShouldNot(HaveOccurred())
+				fragmentStore := store.SomerOrderFragmentStore()
+				if ord.Parity == order.ParityBuy {
+					Expect(fragmentStore.PutBuyOrderFragment(epochPrev.Hash, fragments[i], "trader", 1, order.Open)).ShouldNot(HaveOccurred())
+				} else {
+					Expect(fragmentStore.PutSellOrderFragment(epochPrev.Hash, fragments[i], "trader", 1, order.Open)).ShouldNot(HaveOccurred())
+				}
+				resharers = append(resharers, NewResharer(addr, router.orderbook(addr), router.smpcer(addr), fragmentStore, 100*time.Millisecond))
+			}
+			for i, addr := range epochCurr.Pods[0].Darknodes {
+				store, err := leveldb.NewStore(fmt.Sprintf("./data.out/%v", numPrev+i), time.Hour, time.Hour)
+				Expect(err).ShouldNot(HaveOccurred())
+				resharers = append(resharers, NewResharer(addr, router.orderbook(addr), router.smpcer(addr), store.SomerOrderFragmentStore(), 100*time.Millisecond))
+			}
+			for _, resharer := range resharers {
+				resharer.Reshare(epochPrev, epochCurr)
+			}
+
+			// Every node in the next pod must receive a new order fragment
+			Eventually(func() int {
+				return len(router.insertedOrderFragments(epochCurr.Pods[0].Darknodes))
+			}, 5*time.Second).Should(Equal(numCurr))
+
+			orderFragments := router.insertedOrderFragments(epochCurr.Pods[0].Darknodes)
+			shares := shamir.Shares{}
+			for _, orderFragment := range orderFragments {
+				Expect(orderFragment.OrderID).Should(Equal(ord.ID))
+				Expect(orderFragment.OrderParity).Should(Equal(ord.Parity))
+				shares = append(shares, orderFragment.Price.Co)
+			}
+			Expect(shamir.Join(shares[:epochCurr.Pods[0].Threshold()])).Should(Equal(order.PriceToCoExp(ord.Price).Co))
+		})
+	})
+})
+
+func randomEpochWithDarknodes(n int) registry.Epoch {
+	darknodes := identity.Addresses{}
+	for i := 0; i < n; i++ {
+		addr, err := testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		darknodes = append(darknodes, addr)
+	}
+	return registry.Epoch{
+		Hash: testutils.Random32Bytes(),
+		Pods: []registry.Pod{
+			{
+				Position:  0,
+				Hash:      testutils.Random32Bytes(),
+				Darknodes: darknodes,
+			},
+		},
+		Darknodes: darknodes,
+	}
+}
+
+// resharerRouter routes smpc.Reshares between in-memory smpc.Resharers, and
+// collects the order.Fragments that are inserted into the orderbook of each
+// node.
+type resharerRouter struct {
+	mu             *sync.Mutex
+	resharers      map[identity.Address]*smpc.Resharer
+	senders        map[identity.Address]smpc.ReshareSenders
+	callbacks      map[identity.Address]smpc.ReshareCallback
+	orderFragments map[identity.Address]order.Fragment
+}
+
+func newResharerRouter() *resharerRouter {
+	return &resharerRouter{
+		mu:             new(sync.Mutex),
+		resharers:      map[identity.Address]*smpc.Resharer{},
+		senders:        map[identity.Address]smpc.ReshareSenders{},
+		callbacks:      map[identity.Address]smpc.ReshareCallback{},
+		orderFragments: map[identity.Address]order.Fragment{},
+	}
+}
+
+func (router *resharerRouter) smpcer(addr identity.Address) smpc.Smpcer {
+	return &routerSmpcer{Smpc: testutils.NewAlwaysMatchSmpc(), addr: addr, router: router}
+}
+
+func (router *resharerRouter) orderbook(addr identity.Address) orderbook.Orderbook {
+	return &routerOrderbook{EmptyOrderbook: testutils.NewEmptyOrderbook(), addr: addr, router: router}
+}
+
+func (router *resharerRouter) insertedOrderFragments(addrs identity.Addresses) []order.Fragment {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+
+	orderFragments := []order.Fragment{}
+	for _, addr := range addrs {
+		if orderFragment, ok := router.orderFragments[addr]; ok {
+			orderFragments = append(orderFragments, orderFragment)
+		}
+	}
+	return orderFragments
+}
+
+type routerSmpcer struct {
+	*testutils.Smpc
+	addr   identity.Address
+	router *resharerRouter
+}
+
+func (smpcer *routerSmpcer) Connect(networkID smpc.NetworkID, addrs identity.Addresses) {
+	smpcer.router.mu.Lock()
+	defer smpcer.router.mu.Unlock()
+
+	smpcer.router.resharers[smpcer.addr] = smpc.NewResharer(time.Minute, func(id smpc.ReshareID) identity.Addresses {
+		smpcer.router.mu.Lock()
+		senders := smpcer.router.senders[smpcer.addr]
+		smpcer.router.mu.Unlock()
+		return senders(id)
+	}, func(id smpc.ReshareID, shares shamir.Shares, data []byte) {
+		smpcer.router.mu.Lock()
+		callback := smpcer.router.callbacks[smpcer.addr]
+		smpcer.router.mu.Unlock()
+		callback(id, shares, data)
+	})
+}
+
+func (smpcer *routerSmpcer) Reshare(networkID smpc.NetworkID, to identity.Address, reshare smpc.Reshare) error {
+	smpcer.router.mu.Lock()
+	resharer := smpcer.router.resharers[to]
+	smpcer.router.mu.Unlock()
+	return resharer.InsertReshare(smpcer.addr, reshare)
+}
+
+func (smpcer *routerSmpcer) OnReshare(networkID smpc.NetworkID, senders smpc.ReshareSenders, callback smpc.ReshareCallback) {
+	smpcer.router.mu.Lock()
+	defer smpcer.router.mu.Unlock()
+
+	smpcer.router.senders[smpcer.addr] = senders
+	smpcer.router.callbacks[smpcer.addr] = callback
+}
+
+type routerOrderbook struct {
+	*testutils.EmptyOrderbook
+	addr   identity.Address
+	router *resharerRouter
+}
+
+func (book *routerOrderbook) InsertOrderFragment(ctx context.Context, orderFragment order.Fragment, trader string, priority uint) error {
+	book.router.mu.Lock()
+	defer book.router.mu.Unlock()
+
+	book.router.orderFragments[book.addr] = orderFragment
+	return nil
+}
@@ -0,0 +1,123 @@
+package ome
+
+import (
+	"errors"
+	"time"
+
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrSettlementNotFailed is returned when replaying a Settlement that has not
+// failed.
+var ErrSettlementNotFailed = errors.New("settlement not failed")
+
+// SettlementWorkers is the number of Settlements that are submitted to the
+// Ethereum contract at the same time.
+const SettlementWorkers = 4
+
+// SettlementMaxAttempts is the number of times that the submission of a
+// Settlement is attempted before it is marked as failed.
+const SettlementMaxAttempts = 8
+
+// SettlementBackoff is the time waited after the first failed attempt to
+// submit a Settlement. The time waited doubles after each failed attempt, up to
+// SettlementMaxBackoff.
+const SettlementBackoff = 5 * time.Second
+
+// SettlementMaxBackoff is the maximum time waited between attempts to submit
+// a Settlement.
+const SettlementMaxBackoff = 10 * time.Minute
+
+// SettlementPollInterval is the interval at which the SettlementStorer is
+// polled for Settlements that are ready to be attempted.
+const SettlementPollInterval = time.Second
+
+// SettlementState is used to track the state of a Settlement in the settlement
+// queue.
+type SettlementState int
+
+// Values for a SettlementState
+const (
+	SettlementStatePending SettlementState = iota
+	SettlementStateFailed
+)
+
+// String returns a human-readable representation of the SettlementState.
+func (state SettlementState) String() string {
+	switch state {
+	case SettlementStatePending:
+		return "pending"
+	case SettlementStateFailed:
+		return "failed"
+	default:
+		return "unsupported state"
+	}
+}
+
+// A Settlement is a Computation that has been opened, and is waiting for its
+// orders to be submitted to the Ethereum contract for settlement. Settlements
+// are stored so that they survive restarts, and are attempted until they
+// succeed or reach SettlementMaxAttempts.
+type Settlement struct {
+	Computation Computation `json:"computation"`
+	Buy         order.Order `json:"buy"`
+	Sell        order.Order `json:"sell"`
+
+	// Price is the price at which the orders are executed
+	Price uint64 `json:"price"`
+
+	State       SettlementState `json:"state"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	Err         string          `json:"err"`
+}
+
+// FailedSettlements returns all Settlements in a SettlementStorer that have
+// failed after SettlementMaxAttempts.
+func FailedSettlements(settlementStore SettlementStorer) ([]Settlement, error) {
+	settlementsIter, err := settlementStore.Settlements()
+	if err != nil {
+		return nil, err
+	}
+	defer settlementsIter.Release()
+
+	settlements, err := settlementsIter.Collect()
+	failed := []Settlement{}
+	for _, settlement := range settlements {
+		if settlement.State == SettlementStateFailed {
+			failed = append(failed, settlement)
+		}
+	}
+	return failed, err
+}
+
+// ReplaySettlement moves a failed Settlement back into the settlement queue.
+// Its attempts are reset, and it will be attempted by the next Settler that
+// polls the SettlementStorer.
+func ReplaySettlement(settlementStore SettlementStorer, id ComputationID) error {
+	settlement, err := settlementStore.Settlement(id)
+	if err != nil {
+		return err
+	}
+	if settlement.State != SettlementStateFailed {
+		return ErrSettlementNotFailed
+	}
+	settlement.State = SettlementStatePending
+	settlement.Attempts = 0
+	settlement.NextAttempt = time.Now()
+	settlement.Err = ""
+	return settlementStore.PutSettlement(settlement)
+}
+
+// settlementBackoff returns the time to wait before the next attempt of a
+// Settlement that has failed a number of attempts.
+func settlementBackoff(attempts int) time.Duration {
+	backoff := SettlementBackoff
+	for i := 1; i < attempts && backoff < SettlementMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > SettlementMaxBackoff {
+		return SettlementMaxBackoff
+	}
+	return backoff
+}
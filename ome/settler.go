@@ -1,11 +1,15 @@
 package ome
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/oracle"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/smpc"
@@ -19,42 +23,114 @@ type Settler interface {
 	// confirmed. Computations are usually settled after they have been through
 	// the Matcher and Confirmer interfaces.
 	Settle(com Computation) error
+
+	// Run the settlement queue until the done channel is closed. Orders that
+	// have been opened by Settle are submitted to their SettlementBackend by a
+	// bounded number of workers, and failed submissions are retried with an
+	// exponential backoff. Settlements that were queued before a restart are
+	// resumed.
+	Run(done <-chan struct{}) <-chan error
 }
 
 type settler struct {
 	computationStore    ComputationStorer
+	settlementStore     SettlementStorer
 	smpcer              smpc.Smpcer
 	contract            ContractBinder
+	backends            *SettlementBackendRegistry
 	minimumSettleVolume uint64 // In units of 1e-12 ETH
+	midpointPrices      oracle.MidpointPriceFeed
+
+	settlementsSignal chan struct{}
+	inFlightMu        *sync.Mutex
+	inFlight          map[ComputationID]struct{}
 }
 
 // NewSettler returns a Settler that settles orders by first using an
-// smpc.Smpcer to join all of the composing order.Fragments, and then submits
-// them to an Ethereum contract.
-func NewSettler(computationStore ComputationStorer, smpcer smpc.Smpcer, contract ContractBinder, minimumSettleVolume uint64) Settler {
+// smpc.Smpcer to join all of the composing order.Fragments, and then queues
+// them in a SettlementStorer for submission to the SettlementBackend that is
+// registered for their order.Settlement. Challenges are submitted to the
+// ContractBinder. Midpoint orders are executed at the mid-point price from
+// the oracle.MidpointPriceFeed, and are never settled when the feed is nil, or
+// its price is stale or missing.
+func NewSettler(computationStore ComputationStorer, settlementStore SettlementStorer, smpcer smpc.Smpcer, contract ContractBinder, backends *SettlementBackendRegistry, midpointPrices oracle.MidpointPriceFeed, minimumSettleVolume uint64) Settler {
 	return &settler{
 		computationStore:    computationStore,
+		settlementStore:     settlementStore,
 		smpcer:              smpcer,
 		contract:            contract,
+		backends:            backends,
 		minimumSettleVolume: minimumSettleVolume,
+		midpointPrices:      midpointPrices,
+
+		settlementsSignal: make(chan struct{}, 1),
+		inFlightMu:        new(sync.Mutex),
+		inFlight:          map[ComputationID]struct{}{},
 	}
 }
 
 // Settle implements the Settler interface.
 func (settler *settler) Settle(com Computation) error {
+	// Computations that have already been opened are waiting in the queue
+	if _, err := settler.settlementStore.Settlement(com.ID); err == nil {
+		return nil
+	}
 	networkID := smpc.NetworkID(com.Epoch)
 	settler.joinOrderMatch(networkID, com)
 	return nil
 }
 
-func (settler *settler) joinOrderMatch(networkID smpc.NetworkID, com Computation) {
+// Run implements the Settler interface.
+func (settler *settler) Run(done <-chan struct{}) <-chan error {
+	errs := make(chan error, SettlementWorkers)
+	settlements := make(chan Settlement)
 
-	// Create the blinding to verify the computation
-	blinding := shamir.Blinding{
-		Int: big.NewInt(0).Add(com.Buy.Blinding.Int, big.NewInt(0).Sub(shamir.CommitP, com.Sell.Blinding.Int)),
+	var wg sync.WaitGroup
+	for i := 0; i < SettlementWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case settlement := <-settlements:
+					settler.attemptSettlement(settlement)
+					settler.inFlightMu.Lock()
+					delete(settler.inFlight, settlement.Computation.ID)
+					settler.inFlightMu.Unlock()
+				}
+			}
+		}()
 	}
-	blinding.Mod(blinding.Int, shamir.CommitP)
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(SettlementPollInterval)
+		defer ticker.Stop()
+
+		for {
+			settler.dispatchSettlements(done, settlements, errs)
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			case <-settler.settlementsSignal:
+			}
+		}
+	}()
+
+	go func() {
+		defer close(errs)
+		wg.Wait()
+	}()
+
+	return errs
+}
+
+func (settler *settler) joinOrderMatch(networkID smpc.NetworkID, com Computation) {
 	join := smpc.Join{
 		Index: smpc.JoinIndex(com.Buy.Tokens.Index),
 		Shares: shamir.Shares{
@@ -70,24 +146,15 @@ func (settler *settler) joinOrderMatch(networkID smpc.NetworkID, com Computation
 			com.Sell.MinimumVolume.Co, com.Sell.MinimumVolume.Exp,
 			com.Sell.Nonce,
 		},
-		Blindings: shamir.Blindings{
-			com.Buy.Blinding,
-			com.Buy.Blinding, com.Buy.Blinding,
-			com.Buy.Blinding, com.Buy.Blinding,
-			com.Buy.Blinding, com.Buy.Blinding,
-			com.Buy.Blinding,
-
-			com.Sell.Blinding,
-			com.Sell.Blinding, com.Sell.Blinding,
-			com.Sell.Blinding, com.Sell.Blinding,
-			com.Sell.Blinding, com.Sell.Blinding,
-			com.Sell.Blinding,
-		},
+		Openings: buildSettlementJoinOpenings(com),
 	}
 	copy(join.ID[:], com.ID[:])
 	join.ID[32] = byte(ResolveStageSettlement)
+	settler.smpcer.InsertCommitments(networkID, join.ID, buildSettlementJoinCommitments(com))
 
-	err := settler.smpcer.Join(networkID, join, func(joinID smpc.JoinID, values []uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), ResolveTimeout)
+	err := settler.smpcer.Join(ctx, networkID, join, func(joinID smpc.JoinID, values []uint64) {
+		cancel()
 		if len(values) != 16 {
 			logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: unexpected number of values: %v", com.Buy.OrderID, com.Sell.OrderID, len(values)))
 			return
@@ -99,12 +166,18 @@ func (settler *settler) joinOrderMatch(networkID smpc.NetworkID, com Computation
 		sell.MinimumVolume = order.VolumeFromCoExp(values[13], values[14])
 
 		settler.settleOrderMatch(com, buy, sell)
+	}, func(joinID smpc.JoinID, err error) {
+		cancel()
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err))
 	}, true /* delay message sending to ensure the round-robin */)
 	if err != nil {
+		cancel()
 		logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: %v", com.Buy.OrderID, com.Sell.OrderID, err))
 	}
 }
 
+// settleOrderMatch queues the orders for settlement, or submits a challenge if
+// the orders do not match.
 func (settler *settler) settleOrderMatch(com Computation, buy, sell order.Order) {
 	// Submit a challenge if the orders do not match.
 	if buy.Tokens != sell.Tokens ||
@@ -124,61 +197,272 @@ func (settler *settler) settleOrderMatch(com Computation, buy, sell order.Order)
 		return
 	}
 
+	// Good-till-time orders must not be settled after they have expired
+	if isGoodTillTimeExpired(buy, sell, time.Now()) {
+		settler.rejectSettlement(com, buy, sell, "good-till-time order expired")
+		return
+	}
+
+	// Only token pairs that are enabled in the TokenRegistry can be settled
+	registry := order.DefaultTokenRegistry()
+	if !registry.IsPairEnabled(buy.Tokens) {
+		settler.rejectSettlement(com, buy, sell, fmt.Sprintf("token pair %v not enabled", buy.Tokens))
+		return
+	}
+
+	// Only orders with a SettlementBackend can be settled
+	if _, err := settler.backends.Backend(com.Buy.OrderSettlement); err != nil {
+		settler.rejectSettlement(com, buy, sell, fmt.Sprintf("%v: %v", com.Buy.OrderSettlement, err))
+		return
+	}
+
+	price, err := settler.executionPrice(buy, sell)
+	if err != nil {
+		settler.rejectSettlement(com, buy, sell, err.Error())
+		return
+	}
+
 	// Leave the orders if volume is too low and there is no profit for
-	// submitting such orders. Note: minimum volume is set to 1 ETH.
-	settleVolume := volumeInEth(buy, sell)
-	if settleVolume < settler.minimumSettleVolume {
+	// submitting such orders. Note: minimum volume is set to 1 ETH. Pairs
+	// that do not include ETH cannot be valued, and are always settled.
+	settleVolume, ok := volumeInEth(registry, buy, sell, price)
+	if ok && settleVolume < settler.minimumSettleVolume {
 		log.Printf("[info] (settle) cannot execute settlement buy = %v, sell = %v: volume = %v ETH too low", buy.ID, sell.ID, settleVolume)
 		return
 	}
 
-	// Try settling the orders for at most 3 times.
-	err := settler.contract.Settle(buy, sell)
+	settlement := Settlement{
+		Computation: com,
+		Buy:         buy,
+		Sell:        sell,
+		Price:       price,
+		State:       SettlementStatePending,
+		Attempts:    0,
+		NextAttempt: time.Now(),
+	}
+	if err := settler.settlementStore.PutSettlement(settlement); err != nil {
+		log.Printf("[error] (settle) cannot queue settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		return
+	}
+	select {
+	case settler.settlementsSignal <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchSettlements sends the pending Settlements that are ready to be
+// attempted, and are not already being attempted, to the workers.
+func (settler *settler) dispatchSettlements(done <-chan struct{}, settlements chan<- Settlement, errs chan<- error) {
+	settlementsIter, err := settler.settlementStore.Settlements()
 	if err != nil {
-		log.Printf("[error] (settle) cannot store settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		writeError(done, errs, err)
+		return
+	}
+	pending, err := settlementsIter.Collect()
+	settlementsIter.Release()
+	if err != nil {
+		writeError(done, errs, err)
+	}
+
+	now := time.Now()
+	for _, settlement := range pending {
+		if settlement.State != SettlementStatePending || settlement.NextAttempt.After(now) {
+			continue
+		}
+		settler.inFlightMu.Lock()
+		if _, ok := settler.inFlight[settlement.Computation.ID]; ok {
+			settler.inFlightMu.Unlock()
+			continue
+		}
+		settler.inFlight[settlement.Computation.ID] = struct{}{}
+		settler.inFlightMu.Unlock()
+
+		select {
+		case <-done:
+			return
+		case settlements <- settlement:
+		}
+	}
+}
+
+// attemptSettlement submits a Settlement to its SettlementBackend. On success,
+// the Settlement is removed from the queue. On failure, the next attempt is
+// scheduled with an exponential backoff, and after SettlementMaxAttempts the
+// Settlement, and its Computation, are marked as failed.
+func (settler *settler) attemptSettlement(settlement Settlement) {
+	com, buy, sell := settlement.Computation, settlement.Buy, settlement.Sell
+
+	if isGoodTillTimeExpired(buy, sell, time.Now()) {
+		settler.rejectSettlement(com, buy, sell, "good-till-time order expired")
+		if err := settler.settlementStore.DeleteSettlement(com.ID); err != nil {
+			log.Printf("[error] (settle) cannot delete expired settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		}
+		return
+	}
+
+	backend, err := settler.backends.Backend(com.Buy.OrderSettlement)
+	if err != nil {
+		settler.rejectSettlement(com, buy, sell, fmt.Sprintf("%v: %v", com.Buy.OrderSettlement, err))
+		if err := settler.settlementStore.DeleteSettlement(com.ID); err != nil {
+			log.Printf("[error] (settle) cannot delete settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		}
+		return
+	}
+
+	if err := backend.Settle(buy, sell, settlement.Price); err != nil {
+		settlement.Attempts++
+		settlement.Err = err.Error()
+		if settlement.Attempts >= SettlementMaxAttempts {
+			log.Printf("[error] (settle) cannot execute settlement buy = %v, sell = %v after %v attempts: %v", buy.ID, sell.ID, settlement.Attempts, err)
+			settlement.State = SettlementStateFailed
+			com.State = ComputationStateFailed
+			if err := settler.computationStore.PutComputation(com); err != nil {
+				log.Printf("[error] (settle) cannot store failed settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+			}
+		} else {
+			log.Printf("[error] (settle) cannot execute settlement buy = %v, sell = %v (attempt %v): %v", buy.ID, sell.ID, settlement.Attempts, err)
+			settlement.NextAttempt = time.Now().Add(settlementBackoff(settlement.Attempts))
+		}
+		if err := settler.settlementStore.PutSettlement(settlement); err != nil {
+			log.Printf("[error] (settle) cannot store settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		}
 		return
 	}
 
 	com.State = ComputationStateSettled
 	if err := settler.computationStore.PutComputation(com); err != nil {
 		log.Printf("[error] (settle) cannot store settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
-		return
+	}
+	if err := settler.settlementStore.DeleteSettlement(com.ID); err != nil {
+		log.Printf("[error] (settle) cannot delete settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
 	}
 }
 
-func volumeInEth(buy, sell order.Order) uint64 {
-	if buy.Tokens.PriorityToken() == order.TokenETH {
-		// BTC-ETH
-		if buy.Volume >= sell.Volume {
-			return sell.Volume
-		} else {
-			return buy.Volume
+func (settler *settler) rejectSettlement(com Computation, buy, sell order.Order, reason string) {
+	log.Printf("[info] (settle) cannot execute settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, reason)
+	com.State = ComputationStateRejected
+	if err := settler.computationStore.PutComputation(com); err != nil {
+		log.Printf("[error] (settle) cannot store rejected settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+	}
+}
+
+func isGoodTillTimeExpired(buy, sell order.Order, now time.Time) bool {
+	return (buy.Type.IsGoodTillTime() && now.After(buy.Expiry)) || (sell.Type.IsGoodTillTime() && now.After(sell.Expiry))
+}
+
+// buildSettlementJoinOpenings returns the shamir.Openings for the shares of
+// both order.Fragments in a Computation, in the same order as the shares of
+// the settlement Join. No shamir.Openings are returned unless every share has
+// an Opening.
+func buildSettlementJoinOpenings(com Computation) shamir.Openings {
+	openings := make(shamir.Openings, 0, 16)
+	for _, fragmentOpenings := range []order.FragmentOpenings{com.Buy.Openings, com.Sell.Openings} {
+		openings = append(openings,
+			fragmentOpenings.Tokens,
+			fragmentOpenings.PriceCo, fragmentOpenings.PriceExp,
+			fragmentOpenings.VolumeCo, fragmentOpenings.VolumeExp,
+			fragmentOpenings.MinimumVolumeCo, fragmentOpenings.MinimumVolumeExp,
+			fragmentOpenings.Nonce,
+		)
+	}
+	for _, opening := range openings {
+		if opening.Value == nil || opening.Blinding.Int == nil {
+			return nil
 		}
-	} else {
-		// ETH-ERC20
-		var erc20Volume uint64
-		if buy.Volume >= sell.Volume {
-			erc20Volume = sell.Volume
-		} else {
-			erc20Volume = buy.Volume
+	}
+	return openings
+}
+
+// buildSettlementJoinCommitments returns the smpc.JoinCommitments for the
+// settlement Join. The settlement Join opens the buy and sell order.Fragments
+// directly, so the expected commitments are the commitments held in the
+// order.Fragments.
+func buildSettlementJoinCommitments(com Computation) smpc.JoinCommitments {
+	joinCommitments := smpc.JoinCommitments{}
+	for index, buy := range com.Buy.Commitments {
+		sell, ok := com.Sell.Commitments[index]
+		if !ok {
+			continue
+		}
+		joinCommitments[smpc.JoinIndex(index)] = shamir.Commitments{
+			buy.Tokens,
+			buy.PriceCo, buy.PriceExp,
+			buy.VolumeCo, buy.VolumeExp,
+			buy.MinimumVolumeCo, buy.MinimumVolumeExp,
+			buy.Nonce,
+
+			sell.Tokens,
+			sell.PriceCo, sell.PriceExp,
+			sell.VolumeCo, sell.VolumeExp,
+			sell.MinimumVolumeCo, sell.MinimumVolumeExp,
+			sell.Nonce,
 		}
+	}
+	return joinCommitments
+}
 
+// executionPrice returns the price at which a buy and sell order are executed.
+// Orders are executed at the midpoint of their prices, unless one of them is a
+// midpoint order, in which case they are executed at the mid-point price of
+// their token pair. The mid-point price must be within the prices of both
+// orders.
+func (settler *settler) executionPrice(buy, sell order.Order) (uint64, error) {
+	if !buy.Type.IsMidpoint() && !sell.Type.IsMidpoint() {
+		x := big.NewInt(0).SetUint64(buy.Price)
+		x.Add(x, big.NewInt(0).SetUint64(sell.Price))
+		x.Div(x, big.NewInt(2))
+		return x.Uint64(), nil
+	}
+	if settler.midpointPrices == nil {
+		return 0, fmt.Errorf("cannot get mid-point price of %v: %v", buy.Tokens, oracle.ErrMidpointPriceNotFound)
+	}
+	price, err := settler.midpointPrices.MidpointPrice(buy.Tokens)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get mid-point price of %v: %v", buy.Tokens, err)
+	}
+	if price > buy.Price || price < sell.Price {
+		return 0, fmt.Errorf("mid-point price %v of %v outside of order prices", price, buy.Tokens)
+	}
+	return price, nil
+}
+
+// volumeInEth returns the settled volume of the orders in ETH, at the
+// execution price, using the TokenRegistry to find which Token of the pair is
+// ETH. It returns false if the pair does not include ETH, and so the volume
+// cannot be valued in ETH.
+func volumeInEth(registry *order.TokenRegistry, buy, sell order.Order, price uint64) (uint64, bool) {
+	eth, err := registry.TokenByName("ETH")
+	if err != nil {
+		return 0, false
+	}
+
+	var volume uint64
+	if buy.Volume >= sell.Volume {
+		volume = sell.Volume
+	} else {
+		volume = buy.Volume
+	}
+
+	switch eth.Token {
+	case buy.Tokens.PriorityToken():
+		// The volume is in ETH (e.g. BTC-ETH)
+		return volume, true
+	case buy.Tokens.NonPriorityToken():
+		// The volume is in the priority token (e.g. ETH-ERC20) and is valued
+		// at the execution price
 		x := big.NewInt(0)
 		y := big.NewInt(0)
 
-		x.SetUint64(buy.Price)
-		y.SetUint64(sell.Price)
-		x.Add(x, y)
-
-		y.SetUint64(2)
-		x.Div(x, y)
+		x.SetUint64(price)
 
-		y.SetUint64(erc20Volume)
+		y.SetUint64(volume)
 		x.Mul(x, y)
 
 		y.SetUint64(1e12)
 		x.Div(x, y)
 
-		return x.Uint64()
+		return x.Uint64(), true
+	default:
+		return 0, false
 	}
 }
@@ -1,6 +1,7 @@
 package ome_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -10,6 +11,9 @@ import (
 	. "github.com/republicprotocol/republic-go/ome"
 
 	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/smpc"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -17,10 +21,11 @@ const NumberOfNodes = 24
 
 var _ = Describe("Settler", func() {
 	var (
-		storers   [NumberOfNodes]ComputationStorer
-		smpcers   [NumberOfNodes]*testutils.Smpc
-		contracts [NumberOfNodes]*omeBinder
-		settles   [NumberOfNodes]Settler
+		storers           [NumberOfNodes]ComputationStorer
+		settlementStorers [NumberOfNodes]SettlementStorer
+		smpcers           [NumberOfNodes]*testutils.Smpc
+		contracts         [NumberOfNodes]*omeBinder
+		settles           [NumberOfNodes]Settler
 	)
 
 	BeforeEach(func() {
@@ -28,9 +33,12 @@ var _ = Describe("Settler", func() {
 			storer, err := leveldb.NewStore(fmt.Sprintf("./data-%v.out", i), 24*time.Hour, time.Hour)
 			Expect(err).ShouldNot(HaveOccurred())
 			storers[i] = storer.SomerComputationStore()
+			settlementStorers[i] = storer.SomerSettlementStore()
 			smpcers[i] = testutils.NewAlwaysMatchSmpc()
 			contracts[i] = newOmeBinder()
-			settles[i] = NewSettler(storers[i], smpcers[i], contracts[i], 0)
+			backends := NewSettlementBackendRegistry()
+			backends.Register(order.SettlementRenEx, contracts[i])
+			settles[i] = NewSettler(storers[i], settlementStorers[i], smpcers[i], contracts[i], backends, nil, 0)
 		}
 	})
 
@@ -60,4 +68,169 @@ var _ = Describe("Settler", func() {
 			}
 		})
 	})
+
+	Context("when settlements have been queued", func() {
+		It("should submit the queued settlements when the queue is run", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateAccepted, true)
+			Expect(storers[0].PutComputation(com)).ShouldNot(HaveOccurred())
+			Expect(settlementStorers[0].PutSettlement(Settlement{Computation: com, NextAttempt: time.Now()})).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			settles[0].Run(done)
+
+			Eventually(contracts[0].SettleCounts, 5*time.Second).Should(Equal(1))
+			Eventually(func() error {
+				_, err := settlementStorers[0].Settlement(com.ID)
+				return err
+			}, 5*time.Second).Should(Equal(ErrSettlementNotFound))
+			com, err = storers[0].Computation(com.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(com.State).Should(Equal(ComputationStateSettled))
+		})
+
+		It("should only submit failed settlements after they have been replayed", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateFailed, true)
+			Expect(storers[0].PutComputation(com)).ShouldNot(HaveOccurred())
+			settlement := Settlement{Computation: com, State: SettlementStateFailed, Attempts: SettlementMaxAttempts, Err: "reverted"}
+			Expect(settlementStorers[0].PutSettlement(settlement)).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			settles[0].Run(done)
+
+			failed, err := FailedSettlements(settlementStorers[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(failed).Should(HaveLen(1))
+			Expect(failed[0].Err).Should(Equal("reverted"))
+			Consistently(contracts[0].SettleCounts, 2*SettlementPollInterval).Should(Equal(0))
+
+			Expect(ReplaySettlement(settlementStorers[0], com.ID)).ShouldNot(HaveOccurred())
+			Expect(ReplaySettlement(settlementStorers[0], com.ID)).Should(Equal(ErrSettlementNotFailed))
+			Eventually(contracts[0].SettleCounts, 5*time.Second).Should(Equal(1))
+		})
+	})
+
+	Context("when settlements use different settlement layers", func() {
+		It("should submit settlements to the backend of their settlement layer", func() {
+			atomic := newOmeBinder()
+			backends := NewSettlementBackendRegistry()
+			backends.Register(order.SettlementRenEx, contracts[0])
+			backends.Register(order.SettlementRenExAtomic, atomic)
+			settler := NewSettler(storers[0], settlementStorers[0], smpcers[0], contracts[0], backends, nil, 0)
+
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			buyFragments[0].OrderSettlement = order.SettlementRenExAtomic
+			sellFragments[0].OrderSettlement = order.SettlementRenExAtomic
+			com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateAccepted, true)
+			Expect(storers[0].PutComputation(com)).ShouldNot(HaveOccurred())
+			Expect(settlementStorers[0].PutSettlement(Settlement{Computation: com, NextAttempt: time.Now()})).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			settler.Run(done)
+
+			Eventually(atomic.SettleCounts, 5*time.Second).Should(Equal(1))
+			Expect(contracts[0].SettleCounts()).Should(Equal(0))
+		})
+
+		It("should reject settlements that do not have a backend", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			buyFragments[0].OrderSettlement = order.SettlementRenExAtomic
+			sellFragments[0].OrderSettlement = order.SettlementRenExAtomic
+			com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateAccepted, true)
+			Expect(storers[0].PutComputation(com)).ShouldNot(HaveOccurred())
+			Expect(settlementStorers[0].PutSettlement(Settlement{Computation: com, NextAttempt: time.Now()})).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			settles[0].Run(done)
+
+			Eventually(func() ComputationState {
+				com, err := storers[0].Computation(com.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				return com.State
+			}, 5*time.Second).Should(Equal(ComputationStateRejected))
+			Expect(contracts[0].SettleCounts()).Should(Equal(0))
+			_, err = settlementStorers[0].Settlement(com.ID)
+			Expect(err).Should(Equal(ErrSettlementNotFound))
+		})
+	})
+
+	Context("when a midpoint order has been matched", func() {
+		It("should settle the orders at the mid-point price", func() {
+			expiry := time.Now().Add(time.Hour)
+			buy := order.NewOrder(order.ParityBuy, order.TypeMidpoint, expiry, order.SettlementRenEx, order.TokensETHREN, 1200000000000, 1000000000000, 1000000000000, 1)
+			sell := order.NewOrder(order.ParitySell, order.TypeLimit, expiry, order.SettlementRenEx, order.TokensETHREN, 800000000000, 1000000000000, 1000000000000, 2)
+			buyFragments, err := buy.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := sell.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			midpointPriceStore := testutils.NewMockMidpointPriceStorer()
+			Expect(midpointPriceStore.PutMidpointPrice(oracle.MidpointPrice{
+				Prices: map[uint64]uint64{uint64(order.TokensETHREN): 1100000000000},
+				Nonce:  uint64(time.Now().Unix()),
+			})).ShouldNot(HaveOccurred())
+			midpointPrices := oracle.NewMidpointPriceFeed(midpointPriceStore, time.Minute)
+
+			contract := newOmeBinder()
+			com := NewComputation([32]byte{}, buyFragments[0], sellFragments[0], ComputationStateAccepted, true)
+			Expect(storers[0].PutComputation(com)).ShouldNot(HaveOccurred())
+
+			smpcer := &openingSmpc{Smpc: testutils.NewAlwaysMatchSmpc(), values: settlementValues(buy, sell)}
+			backends := NewSettlementBackendRegistry()
+			backends.Register(order.SettlementRenEx, contract)
+			settler := NewSettler(storers[0], settlementStorers[0], smpcer, contract, backends, midpointPrices, 0)
+
+			done := make(chan struct{})
+			defer close(done)
+			settler.Run(done)
+			Expect(settler.Settle(com)).ShouldNot(HaveOccurred())
+
+			Eventually(contract.SettleCounts, 5*time.Second).Should(Equal(1))
+			contract.mu.Lock()
+			price := contract.prices[buy.ID]
+			contract.mu.Unlock()
+			Expect(price).Should(Equal(uint64(1100000000000)))
+		})
+	})
 })
+
+// openingSmpc is an smpc.Smpcer that opens every Join to the same values.
+type openingSmpc struct {
+	*testutils.Smpc
+	values []uint64
+}
+
+func (smpcer *openingSmpc) Join(ctx context.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, errCallback smpc.ErrorCallback, useDelay bool) error {
+	callback(join.ID, smpcer.values)
+	return nil
+}
+
+// settlementValues returns the values opened by the settlement Join of two
+// orders.
+func settlementValues(buy, sell order.Order) []uint64 {
+	values := make([]uint64, 0, 16)
+	for _, ord := range []order.Order{buy, sell} {
+		price := order.PriceToCoExp(ord.Price)
+		volume := order.VolumeToCoExp(ord.Volume)
+		minimumVolume := order.VolumeToCoExp(ord.MinimumVolume)
+		values = append(values, uint64(ord.Tokens), price.Co, price.Exp, volume.Co, volume.Exp, minimumVolume.Co, minimumVolume.Exp, ord.Nonce)
+	}
+	return values
+}
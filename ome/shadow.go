@@ -0,0 +1,210 @@
+package ome
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// JournalEntryKind is used to distinguish between the transactions that can be
+// recorded in a JournalEntry.
+type JournalEntryKind int
+
+// Values for a JournalEntryKind
+const (
+	JournalEntryKindConfirmOrder JournalEntryKind = iota
+	JournalEntryKindSettle
+	JournalEntryKindSubmitChallengeOrder
+	JournalEntryKindSubmitChallenge
+)
+
+// String returns a human-readable representation of the JournalEntryKind.
+func (kind JournalEntryKind) String() string {
+	switch kind {
+	case JournalEntryKindConfirmOrder:
+		return "confirmOrder"
+	case JournalEntryKindSettle:
+		return "settle"
+	case JournalEntryKindSubmitChallengeOrder:
+		return "submitChallengeOrder"
+	case JournalEntryKindSubmitChallenge:
+		return "submitChallenge"
+	default:
+		return "unsupported kind"
+	}
+}
+
+// A JournalEntryID is the Keccak256 hash of a JournalEntry.
+type JournalEntryID [32]byte
+
+// String returns a human-readable representation of the JournalEntryID.
+func (id JournalEntryID) String() string {
+	return fmt.Sprintf("%x", id[:])
+}
+
+// A JournalEntry is a transaction that would have been submitted to the
+// Ethereum contract if the Ome was not running in shadow mode. The Buy and
+// Sell order IDs are set for all kinds of transaction, except for a challenged
+// order, which only sets the order ID with the parity of the order. Orders are
+// only set for transactions that submit the opened orders, and the Price is
+// only set for settlements.
+type JournalEntry struct {
+	ID        JournalEntryID   `json:"id"`
+	Kind      JournalEntryKind `json:"kind"`
+	Buy       order.ID         `json:"buy"`
+	Sell      order.ID         `json:"sell"`
+	Orders    []order.Order    `json:"orders"`
+	Price     uint64           `json:"price"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// NewJournalEntry returns a new JournalEntry recorded at the current time. Its
+// JournalEntryID is the Keccak256 hash of the kind, the order IDs, and the
+// time.
+func NewJournalEntry(kind JournalEntryKind, buy, sell order.ID, orders ...order.Order) JournalEntry {
+	entry := JournalEntry{
+		Kind:      kind,
+		Buy:       buy,
+		Sell:      sell,
+		Orders:    orders,
+		Timestamp: time.Now(),
+	}
+	kindBytes := [8]byte{}
+	binary.BigEndian.PutUint64(kindBytes[:], uint64(kind))
+	timestampBytes := [8]byte{}
+	binary.BigEndian.PutUint64(timestampBytes[:], uint64(entry.Timestamp.UnixNano()))
+	copy(entry.ID[:], crypto.Keccak256(kindBytes[:], buy[:], sell[:], timestampBytes[:]))
+	return entry
+}
+
+// newSettleJournalEntry returns a JournalEntry that records the settlement of
+// a buy and sell order at an execution price.
+func newSettleJournalEntry(buy, sell order.Order, price uint64) JournalEntry {
+	entry := NewJournalEntry(JournalEntryKindSettle, buy.ID, sell.ID, buy, sell)
+	entry.Price = price
+	return entry
+}
+
+type shadowContractBinder struct {
+	contract     ContractBinder
+	journalStore JournalStorer
+
+	matchesMu *sync.RWMutex
+	matches   map[order.ID]order.ID
+}
+
+// NewShadowContractBinder returns a ContractBinder that never submits
+// transactions to the Ethereum contract. Transactions are recorded as
+// JournalEntries in the JournalStorer instead, and reads are delegated to the
+// ContractBinder. Orders that are confirmed by the shadow ContractBinder are
+// reported as confirmed, at a final depth, so that matches can progress to
+// settlement without a confirmation being observed on the Ethereum contract.
+func NewShadowContractBinder(contract ContractBinder, journalStore JournalStorer) ContractBinder {
+	return &shadowContractBinder{
+		contract:     contract,
+		journalStore: journalStore,
+
+		matchesMu: new(sync.RWMutex),
+		matches:   map[order.ID]order.ID{},
+	}
+}
+
+// ConfirmOrder implements the ContractBinder interface.
+func (binder *shadowContractBinder) ConfirmOrder(buy order.ID, sell order.ID) error {
+	if err := binder.record(NewJournalEntry(JournalEntryKindConfirmOrder, buy, sell)); err != nil {
+		return err
+	}
+
+	binder.matchesMu.Lock()
+	defer binder.matchesMu.Unlock()
+
+	binder.matches[buy] = sell
+	binder.matches[sell] = buy
+	return nil
+}
+
+// Depth implements the ContractBinder interface.
+func (binder *shadowContractBinder) Depth(orderID order.ID) (uint, error) {
+	if _, ok := binder.match(orderID); ok {
+		// Confirmations that were never submitted cannot be reorganised
+		return ^uint(0), nil
+	}
+	return binder.contract.Depth(orderID)
+}
+
+// Status implements the ContractBinder interface.
+func (binder *shadowContractBinder) Status(orderID order.ID) (order.Status, error) {
+	if _, ok := binder.match(orderID); ok {
+		return order.Confirmed, nil
+	}
+	return binder.contract.Status(orderID)
+}
+
+// OrderMatch implements the ContractBinder interface.
+func (binder *shadowContractBinder) OrderMatch(orderID order.ID) (order.ID, error) {
+	if match, ok := binder.match(orderID); ok {
+		return match, nil
+	}
+	return binder.contract.OrderMatch(orderID)
+}
+
+// Settle implements the ContractBinder interface.
+func (binder *shadowContractBinder) Settle(buy order.Order, sell order.Order, price uint64) error {
+	return binder.record(newSettleJournalEntry(buy, sell, price))
+}
+
+// SubmitChallengeOrder implements the ContractBinder interface.
+func (binder *shadowContractBinder) SubmitChallengeOrder(ord order.Order) error {
+	if ord.Parity == order.ParityBuy {
+		return binder.record(NewJournalEntry(JournalEntryKindSubmitChallengeOrder, ord.ID, order.ID{}, ord))
+	}
+	return binder.record(NewJournalEntry(JournalEntryKindSubmitChallengeOrder, order.ID{}, ord.ID, ord))
+}
+
+// SubmitChallenge implements the ContractBinder interface.
+func (binder *shadowContractBinder) SubmitChallenge(buyID, sellID order.ID) error {
+	return binder.record(NewJournalEntry(JournalEntryKindSubmitChallenge, buyID, sellID))
+}
+
+func (binder *shadowContractBinder) record(entry JournalEntry) error {
+	return recordJournalEntry(binder.journalStore, entry)
+}
+
+func (binder *shadowContractBinder) match(orderID order.ID) (order.ID, bool) {
+	binder.matchesMu.RLock()
+	defer binder.matchesMu.RUnlock()
+
+	match, ok := binder.matches[orderID]
+	return match, ok
+}
+
+type shadowSettlementBackend struct {
+	journalStore JournalStorer
+}
+
+// NewShadowSettlementBackend returns a SettlementBackend that never settles
+// orders. Settlements are recorded as JournalEntries in the JournalStorer
+// instead.
+func NewShadowSettlementBackend(journalStore JournalStorer) SettlementBackend {
+	return &shadowSettlementBackend{
+		journalStore: journalStore,
+	}
+}
+
+// Settle implements the SettlementBackend interface.
+func (backend *shadowSettlementBackend) Settle(buy order.Order, sell order.Order, price uint64) error {
+	return recordJournalEntry(backend.journalStore, newSettleJournalEntry(buy, sell, price))
+}
+
+func recordJournalEntry(journalStore JournalStorer, entry JournalEntry) error {
+	if err := journalStore.PutJournalEntry(entry); err != nil {
+		return fmt.Errorf("cannot record %v buy = %v, sell = %v: %v", entry.Kind, entry.Buy, entry.Sell, err)
+	}
+	log.Printf("[info] (shadow) recorded %v buy = %v, sell = %v", entry.Kind, entry.Buy, entry.Sell)
+	return nil
+}
@@ -0,0 +1,95 @@
+package ome_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Shadow contract binder", func() {
+
+	var store *leveldb.Store
+	var contract *omeBinder
+	var shadow ContractBinder
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.shadow.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		contract = newOmeBinder()
+		shadow = NewShadowContractBinder(contract, store.SomerJournalStore())
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.shadow.out")
+	})
+
+	journal := func() []JournalEntry {
+		entriesIter, err := store.SomerJournalStore().JournalEntries()
+		Expect(err).ShouldNot(HaveOccurred())
+		defer entriesIter.Release()
+		entries, err := entriesIter.Collect()
+		Expect(err).ShouldNot(HaveOccurred())
+		return entries
+	}
+
+	It("should record confirmations instead of submitting them", func() {
+		buy, sell := testutils.RandomOrder(), testutils.RandomOrder()
+		Expect(contract.OpenBuyOrder([65]byte{}, buy.ID)).ShouldNot(HaveOccurred())
+		Expect(contract.OpenSellOrder([65]byte{}, sell.ID)).ShouldNot(HaveOccurred())
+
+		Expect(shadow.ConfirmOrder(buy.ID, sell.ID)).ShouldNot(HaveOccurred())
+		Expect(contract.Status(buy.ID)).Should(Equal(order.Open))
+		Expect(contract.Status(sell.ID)).Should(Equal(order.Open))
+
+		Expect(shadow.Status(buy.ID)).Should(Equal(order.Confirmed))
+		Expect(shadow.OrderMatch(buy.ID)).Should(Equal(sell.ID))
+		Expect(shadow.OrderMatch(sell.ID)).Should(Equal(buy.ID))
+
+		entries := journal()
+		Expect(entries).Should(HaveLen(1))
+		Expect(entries[0].Kind).Should(Equal(JournalEntryKindConfirmOrder))
+		Expect(entries[0].Buy).Should(Equal(buy.ID))
+		Expect(entries[0].Sell).Should(Equal(sell.ID))
+	})
+
+	It("should record settlements and challenges in the order that they happen", func() {
+		buy, sell := testutils.RandomOrder(), testutils.RandomOrder()
+		Expect(shadow.Settle(buy, sell, 100)).ShouldNot(HaveOccurred())
+		Expect(shadow.SubmitChallenge(buy.ID, sell.ID)).ShouldNot(HaveOccurred())
+		Expect(contract.SettleCounts()).Should(Equal(0))
+
+		entries := journal()
+		Expect(entries).Should(HaveLen(2))
+		Expect(entries[0].Kind).Should(Equal(JournalEntryKindSettle))
+		Expect(entries[0].Orders).Should(HaveLen(2))
+		Expect(entries[1].Kind).Should(Equal(JournalEntryKindSubmitChallenge))
+	})
+
+	It("should record settlements of any settlement layer", func() {
+		backend := NewShadowSettlementBackend(store.SomerJournalStore())
+		buy, sell := testutils.RandomOrder(), testutils.RandomOrder()
+		Expect(backend.Settle(buy, sell, 100)).ShouldNot(HaveOccurred())
+
+		entries := journal()
+		Expect(entries).Should(HaveLen(1))
+		Expect(entries[0].Kind).Should(Equal(JournalEntryKindSettle))
+		Expect(entries[0].Buy).Should(Equal(buy.ID))
+		Expect(entries[0].Price).Should(Equal(uint64(100)))
+	})
+
+	It("should delegate reads for orders that it has not confirmed", func() {
+		ord := testutils.RandomOrder()
+		Expect(contract.OpenBuyOrder([65]byte{}, ord.ID)).ShouldNot(HaveOccurred())
+		Expect(shadow.Status(ord.ID)).Should(Equal(order.Open))
+		Expect(shadow.Depth(ord.ID)).Should(Equal(uint(100)))
+	})
+})
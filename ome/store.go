@@ -14,6 +14,10 @@ var ErrComputationNotFound = errors.New("computation not found")
 // cannot be found.
 var ErrOrderFragmentNotFound = errors.New("order fragment not found")
 
+// ErrSettlementNotFound is returned when the Storer cannot find a Settlement
+// associated with a ComputationID.
+var ErrSettlementNotFound = errors.New("settlement not found")
+
 // ErrCursorOutOfRange is returned when an iterator cursor is used to read a
 // value outside the range of the iterator.
 var ErrCursorOutOfRange = errors.New("cursor out of range")
@@ -76,3 +80,57 @@ type OrderFragmentIterator interface {
 	// Release the resources allocated by the iterator.
 	Release()
 }
+
+// SettlementStorer for the Settlements that are queued for submission to the
+// Ethereum contract. Settlements are identified by the ComputationID of their
+// Computation.
+type SettlementStorer interface {
+	PutSettlement(settlement Settlement) error
+	DeleteSettlement(id ComputationID) error
+	Settlement(id ComputationID) (Settlement, error)
+	Settlements() (SettlementIterator, error)
+}
+
+// SettlementIterator is used to iterate over a Settlement collection.
+type SettlementIterator interface {
+
+	// Next progresses the cursor. Returns true if the new cursor is still in
+	// the range of the Settlement collection, otherwise false.
+	Next() bool
+
+	// Cursor returns the Settlement at the current cursor location. Returns
+	// an error if the cursor is out of range.
+	Cursor() (Settlement, error)
+
+	// Collect all Settlements in the iterator into a slice.
+	Collect() ([]Settlement, error)
+
+	// Release the resources allocated by the iterator.
+	Release()
+}
+
+// JournalStorer for the JournalEntries that are recorded by a shadow
+// ContractBinder instead of being submitted to the Ethereum contract.
+type JournalStorer interface {
+	PutJournalEntry(entry JournalEntry) error
+	JournalEntries() (JournalEntryIterator, error)
+}
+
+// JournalEntryIterator is used to iterate over a JournalEntry collection.
+// JournalEntries are iterated in the order that they were recorded.
+type JournalEntryIterator interface {
+
+	// Next progresses the cursor. Returns true if the new cursor is still in
+	// the range of the JournalEntry collection, otherwise false.
+	Next() bool
+
+	// Cursor returns the JournalEntry at the current cursor location. Returns
+	// an error if the cursor is out of range.
+	Cursor() (JournalEntry, error)
+
+	// Collect all JournalEntries in the iterator into a slice.
+	Collect() ([]JournalEntry, error)
+
+	// Release the resources allocated by the iterator.
+	Release()
+}
@@ -0,0 +1,179 @@
+package ome
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/registry"
+)
+
+// ErrOrderLifecycleNotFound is returned when the Darknode has not seen an
+// order.
+var ErrOrderLifecycleNotFound = errors.New("order lifecycle not found")
+
+// OrderStage is the furthest stage that an order has reached in its
+// lifecycle, as seen by a Darknode.
+type OrderStage int
+
+// Values for an OrderStage
+const (
+	OrderStageNil OrderStage = iota
+	OrderStageReceived
+	OrderStageInMatrix
+	OrderStageMatched
+	OrderStageConfirmed
+	OrderStageSettled
+)
+
+// String returns a human-readable representation of the OrderStage.
+func (stage OrderStage) String() string {
+	switch stage {
+	case OrderStageNil:
+		return "nil"
+	case OrderStageReceived:
+		return "received"
+	case OrderStageInMatrix:
+		return "inMatrix"
+	case OrderStageMatched:
+		return "matched"
+	case OrderStageConfirmed:
+		return "confirmed"
+	case OrderStageSettled:
+		return "settled"
+	default:
+		return "unsupported stage"
+	}
+}
+
+// OrderLifecycle is the lifecycle of an order as seen by a Darknode. The
+// ComputationID is the Computation that moved the order into its Stage, and
+// is zero for orders that have not been matched.
+type OrderLifecycle struct {
+	OrderID       order.ID
+	Stage         OrderStage
+	ComputationID ComputationID
+}
+
+// A Tracker reads the lifecycle of orders from the order.Fragments received
+// by the orderbook.Orderbook, the order.Fragments in the matrix of the
+// Generator, and the Computations that have been stored.
+type Tracker interface {
+
+	// OnChangeEpoch should be called whenever a change to the registry.Epoch
+	// is detected.
+	OnChangeEpoch(epoch registry.Epoch)
+
+	// OrderLifecycle returns the OrderLifecycle of an order.ID.
+	OrderLifecycle(orderID order.ID) (OrderLifecycle, error)
+}
+
+type tracker struct {
+	orderbookFragmentStore orderbook.OrderFragmentStorer
+	fragmentStore          OrderFragmentStorer
+	computationStore       ComputationStorer
+
+	epochMu   *sync.RWMutex
+	epochCurr *registry.Epoch
+	epochPrev *registry.Epoch
+}
+
+// NewTracker returns a Tracker that reads from the stores used by the
+// orderbook.Orderbook and the Ome.
+func NewTracker(orderbookFragmentStore orderbook.OrderFragmentStorer, fragmentStore OrderFragmentStorer, computationStore ComputationStorer) Tracker {
+	return &tracker{
+		orderbookFragmentStore: orderbookFragmentStore,
+		fragmentStore:          fragmentStore,
+		computationStore:       computationStore,
+
+		epochMu:   new(sync.RWMutex),
+		epochCurr: nil,
+		epochPrev: nil,
+	}
+}
+
+// OnChangeEpoch implements the Tracker interface.
+func (tracker *tracker) OnChangeEpoch(epoch registry.Epoch) {
+	if epoch.IsNil() {
+		return
+	}
+
+	tracker.epochMu.Lock()
+	defer tracker.epochMu.Unlock()
+
+	tracker.epochPrev = tracker.epochCurr
+	tracker.epochCurr = &epoch
+}
+
+// OrderLifecycle implements the Tracker interface.
+func (tracker *tracker) OrderLifecycle(orderID order.ID) (OrderLifecycle, error) {
+	lifecycle := OrderLifecycle{
+		OrderID: orderID,
+		Stage:   tracker.orderStage(orderID),
+	}
+
+	// Computations can advance the order beyond the matrix
+	comsIter, err := tracker.computationStore.Computations()
+	if err != nil {
+		return OrderLifecycle{}, err
+	}
+	defer comsIter.Release()
+
+	for comsIter.Next() {
+		com, err := comsIter.Cursor()
+		if err != nil {
+			return OrderLifecycle{}, err
+		}
+		if !com.Buy.OrderID.Equal(orderID) && !com.Sell.OrderID.Equal(orderID) {
+			continue
+		}
+
+		stage := OrderStageInMatrix
+		switch com.State {
+		case ComputationStateMatched:
+			stage = OrderStageMatched
+		case ComputationStateAccepted:
+			stage = OrderStageConfirmed
+		case ComputationStateSettled:
+			stage = OrderStageSettled
+		}
+		if stage > lifecycle.Stage {
+			lifecycle.Stage = stage
+			if stage >= OrderStageMatched {
+				lifecycle.ComputationID = com.ID
+			}
+		}
+	}
+
+	if lifecycle.Stage == OrderStageNil {
+		return OrderLifecycle{}, ErrOrderLifecycleNotFound
+	}
+	return lifecycle, nil
+}
+
+// orderStage returns OrderStageInMatrix if the order.Fragment is in the
+// matrix, OrderStageReceived if the order.Fragment has been received by the
+// orderbook.Orderbook, and OrderStageNil otherwise. The current, and
+// previous, registry.Epochs are checked.
+func (tracker *tracker) orderStage(orderID order.ID) OrderStage {
+	tracker.epochMu.RLock()
+	defer tracker.epochMu.RUnlock()
+
+	stage := OrderStageNil
+	for _, epoch := range []*registry.Epoch{tracker.epochCurr, tracker.epochPrev} {
+		if epoch == nil {
+			continue
+		}
+		if _, _, _, _, err := tracker.fragmentStore.BuyOrderFragment(epoch.Hash, orderID); err == nil {
+			return OrderStageInMatrix
+		}
+		if _, _, _, _, err := tracker.fragmentStore.SellOrderFragment(epoch.Hash, orderID); err == nil {
+			return OrderStageInMatrix
+		}
+		if _, err := tracker.orderbookFragmentStore.OrderFragment(*epoch, orderID); err == nil {
+			stage = OrderStageReceived
+		}
+	}
+	return stage
+}
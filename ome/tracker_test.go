@@ -0,0 +1,110 @@
+package ome_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Tracker", func() {
+
+	var store *leveldb.Store
+	var epoch registry.Epoch
+	var tracker Tracker
+	var buy, sell order.Fragment
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.tracker.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, epoch, err = testutils.RandomEpoch(0)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		tracker = NewTracker(store.OrderbookOrderFragmentStore(), store.SomerOrderFragmentStore(), store.SomerComputationStore())
+		tracker.OnChangeEpoch(epoch)
+
+		buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		buy, sell = buyFragments[0], sellFragments[0]
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.tracker.out")
+	})
+
+	It("should return an error for orders that have not been seen", func() {
+		_, err := tracker.OrderLifecycle(buy.OrderID)
+		Expect(err).Should(Equal(ErrOrderLifecycleNotFound))
+	})
+
+	It("should return received for order fragments that have been received", func() {
+		Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(epoch, buy)).ShouldNot(HaveOccurred())
+
+		lifecycle, err := tracker.OrderLifecycle(buy.OrderID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lifecycle.Stage).Should(Equal(OrderStageReceived))
+	})
+
+	It("should return in matrix for order fragments that are in the matrix", func() {
+		Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(epoch, buy)).ShouldNot(HaveOccurred())
+		Expect(store.SomerOrderFragmentStore().PutBuyOrderFragment(epoch.Hash, buy, "buyer", 1, order.Open)).ShouldNot(HaveOccurred())
+
+		lifecycle, err := tracker.OrderLifecycle(buy.OrderID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lifecycle.Stage).Should(Equal(OrderStageInMatrix))
+	})
+
+	It("should return the furthest stage reached by the computations of an order", func() {
+		Expect(store.SomerOrderFragmentStore().PutBuyOrderFragment(epoch.Hash, buy, "buyer", 1, order.Open)).ShouldNot(HaveOccurred())
+		Expect(store.SomerComputationStore().PutComputation(NewComputation(epoch.Hash, buy, sell, ComputationStateMismatched, false))).ShouldNot(HaveOccurred())
+
+		lifecycle, err := tracker.OrderLifecycle(buy.OrderID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lifecycle.Stage).Should(Equal(OrderStageInMatrix))
+		Expect(lifecycle.ComputationID).Should(Equal(ComputationID{}))
+
+		stages := map[ComputationState]OrderStage{
+			ComputationStateMatched:  OrderStageMatched,
+			ComputationStateAccepted: OrderStageConfirmed,
+			ComputationStateSettled:  OrderStageSettled,
+		}
+		for _, state := range []ComputationState{ComputationStateMatched, ComputationStateAccepted, ComputationStateSettled} {
+			otherSellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			com := NewComputation(epoch.Hash, buy, otherSellFragments[0], state, true)
+			Expect(store.SomerComputationStore().PutComputation(com)).ShouldNot(HaveOccurred())
+
+			lifecycle, err := tracker.OrderLifecycle(buy.OrderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(lifecycle.Stage).Should(Equal(stages[state]))
+			Expect(lifecycle.ComputationID).Should(Equal(com.ID))
+
+			// The counterparty advances through the same stages
+			lifecycle, err = tracker.OrderLifecycle(otherSellFragments[0].OrderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(lifecycle.Stage).Should(Equal(stages[state]))
+		}
+	})
+
+	It("should return the lifecycle of orders from the previous epoch", func() {
+		Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(epoch, buy)).ShouldNot(HaveOccurred())
+		_, nextEpoch, err := testutils.RandomEpoch(1)
+		Expect(err).ShouldNot(HaveOccurred())
+		tracker.OnChangeEpoch(nextEpoch)
+
+		lifecycle, err := tracker.OrderLifecycle(buy.OrderID)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lifecycle.Stage).Should(Equal(OrderStageReceived))
+	})
+})
@@ -0,0 +1,75 @@
+package oracle
+
+import (
+	"errors"
+	"time"
+
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrMidpointPriceStale is returned when the latest MidpointPrice was signed
+// by the oracle longer ago than the staleness window.
+var ErrMidpointPriceStale = errors.New("mid-point price is stale")
+
+// MidpointPriceStalenessWindow is the default time after which a
+// MidpointPrice is considered to be stale.
+const MidpointPriceStalenessWindow = 5 * time.Minute
+
+// A MidpointPriceFeed returns the mid-point prices that can be used as the
+// execution price of midpoint orders. Stale, and missing, prices are never
+// returned.
+type MidpointPriceFeed interface {
+
+	// Latest returns the latest MidpointPrice. It returns
+	// ErrMidpointPriceNotFound if no MidpointPrice has been received, and
+	// ErrMidpointPriceStale if the latest MidpointPrice is stale.
+	Latest() (MidpointPrice, error)
+
+	// MidpointPrice returns the mid-point price of a token pair from the
+	// latest MidpointPrice. It returns an error if the latest MidpointPrice is
+	// missing or stale, or if it does not have a price for the token pair.
+	MidpointPrice(tokens order.Tokens) (uint64, error)
+}
+
+type midpointPriceFeed struct {
+	midpointPriceStorer MidpointPriceStorer
+	stalenessWindow     time.Duration
+}
+
+// NewMidpointPriceFeed returns a MidpointPriceFeed that reads the mid-point
+// prices stored in a MidpointPriceStorer, and rejects mid-point prices that
+// were signed longer ago than the staleness window.
+func NewMidpointPriceFeed(midpointPriceStorer MidpointPriceStorer, stalenessWindow time.Duration) MidpointPriceFeed {
+	return &midpointPriceFeed{
+		midpointPriceStorer: midpointPriceStorer,
+		stalenessWindow:     stalenessWindow,
+	}
+}
+
+// Latest implements the MidpointPriceFeed interface.
+func (feed *midpointPriceFeed) Latest() (MidpointPrice, error) {
+	midpointPrice, err := feed.midpointPriceStorer.MidpointPrices()
+	if err != nil {
+		return MidpointPrice{}, err
+	}
+	if midpointPrice.IsNil() || midpointPrice.Nonce == 0 {
+		return MidpointPrice{}, ErrMidpointPriceNotFound
+	}
+	if time.Since(midpointPrice.Timestamp()) > feed.stalenessWindow {
+		return MidpointPrice{}, ErrMidpointPriceStale
+	}
+	return midpointPrice, nil
+}
+
+// MidpointPrice implements the MidpointPriceFeed interface.
+func (feed *midpointPriceFeed) MidpointPrice(tokens order.Tokens) (uint64, error) {
+	midpointPrice, err := feed.Latest()
+	if err != nil {
+		return 0, err
+	}
+	price, ok := midpointPrice.Prices[uint64(tokens)]
+	if !ok {
+		return 0, ErrMidpointPriceNotFound
+	}
+	return price, nil
+}
@@ -0,0 +1,49 @@
+package oracle_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/oracle"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Midpoint price feed", func() {
+
+	var storer MidpointPriceStorer
+	var feed MidpointPriceFeed
+
+	BeforeEach(func() {
+		storer = testutils.NewMockMidpointPriceStorer()
+		feed = NewMidpointPriceFeed(storer, time.Minute)
+	})
+
+	It("should return an error when no price has been received", func() {
+		_, err := feed.Latest()
+		Expect(err).Should(Equal(ErrMidpointPriceNotFound))
+		_, err = feed.MidpointPrice(order.TokensETHREN)
+		Expect(err).Should(Equal(ErrMidpointPriceNotFound))
+	})
+
+	It("should return an error when the price is stale", func() {
+		Expect(storer.PutMidpointPrice(MidpointPrice{
+			Prices: map[uint64]uint64{uint64(order.TokensETHREN): 100},
+			Nonce:  uint64(time.Now().Add(-2 * time.Minute).Unix()),
+		})).ShouldNot(HaveOccurred())
+		_, err := feed.MidpointPrice(order.TokensETHREN)
+		Expect(err).Should(Equal(ErrMidpointPriceStale))
+	})
+
+	It("should return fresh prices of known token pairs", func() {
+		Expect(storer.PutMidpointPrice(MidpointPrice{
+			Prices: map[uint64]uint64{uint64(order.TokensETHREN): 100},
+			Nonce:  uint64(time.Now().Unix()),
+		})).ShouldNot(HaveOccurred())
+		Expect(feed.MidpointPrice(order.TokensETHREN)).Should(Equal(uint64(100)))
+		_, err := feed.MidpointPrice(order.TokensETHDGX)
+		Expect(err).Should(Equal(ErrMidpointPriceNotFound))
+	})
+})
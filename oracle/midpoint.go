@@ -5,12 +5,14 @@ import (
 	"encoding/binary"
 	"reflect"
 	"sort"
+	"time"
 
 	"github.com/republicprotocol/republic-go/crypto"
 )
 
 // A MidpointPrice is a signed message contains the the mid-prices of
-// token pairs.
+// token pairs. The Nonce is the Unix time, in seconds, at which the oracle
+// signed the mid-prices.
 type MidpointPrice struct {
 	Signature []byte
 	Prices    map[uint64]uint64
@@ -59,3 +61,9 @@ func (midpointPrice MidpointPrice) Hash() []byte {
 func (midpointPrice *MidpointPrice) IsNil() bool {
 	return midpointPrice == nil || len(midpointPrice.Prices) == 0
 }
+
+// Timestamp returns the time at which the MidpointPrice was signed by the
+// oracle.
+func (midpointPrice MidpointPrice) Timestamp() time.Time {
+	return time.Unix(int64(midpointPrice.Nonce), 0)
+}
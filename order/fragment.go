@@ -5,6 +5,8 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -12,6 +14,10 @@ import (
 	"github.com/republicprotocol/republic-go/shamir"
 )
 
+// ErrUnverifiedFragment is returned when the shares in a Fragment cannot be
+// verified against its commitments.
+var ErrUnverifiedFragment = errors.New("unverified fragment")
+
 // An FragmentID is the Keccak256 hash of a Fragment.
 type FragmentID [32]byte
 
@@ -52,6 +58,12 @@ type Fragment struct {
 	// CommitmentSet for different fragment indices, other than the index of
 	// this fragment
 	Commitments FragmentCommitments `json:"commitments"`
+
+	// Commitments to the coefficients of the polynomials used to create the
+	// shares in this fragment, and the openings needed to verify the shares
+	// against them
+	CoefficientCommitments CoefficientCommitments `json:"coefficientCommitments"`
+	Openings               FragmentOpenings       `json:"openings"`
 }
 
 // NewFragment returns a new Fragment and computes the FragmentID.
@@ -181,6 +193,11 @@ func (fragment *Fragment) Encrypt(pubKey rsa.PublicKey) (EncryptedFragment, erro
 		return encryptedFragment, err
 	}
 	encryptedFragment.Commitments = fragment.Commitments
+	encryptedFragment.CoefficientCommitments = fragment.CoefficientCommitments
+	encryptedFragment.Openings, err = fragment.Openings.Encrypt(pubKey)
+	if err != nil {
+		return encryptedFragment, err
+	}
 	return encryptedFragment, nil
 }
 
@@ -202,6 +219,9 @@ type EncryptedFragment struct {
 
 	Blinding    []byte              `json:"blinding"`
 	Commitments FragmentCommitments `json:"commitments"`
+
+	CoefficientCommitments CoefficientCommitments    `json:"coefficientCommitments"`
+	Openings               EncryptedFragmentOpenings `json:"openings"`
 }
 
 // Decrypt an EncryptedFragment using an rsa.PrivateKey.
@@ -242,6 +262,11 @@ func (fragment *EncryptedFragment) Decrypt(privKey *rsa.PrivateKey) (Fragment, e
 		return decryptedFragment, err
 	}
 	decryptedFragment.Commitments = fragment.Commitments
+	decryptedFragment.CoefficientCommitments = fragment.CoefficientCommitments
+	decryptedFragment.Openings, err = fragment.Openings.Decrypt(privKey)
+	if err != nil {
+		return decryptedFragment, err
+	}
 	return decryptedFragment, nil
 }
 
@@ -250,13 +275,244 @@ func (fragment *EncryptedFragment) IsNil() bool {
 	return fragment == nil || fragment.ID == (FragmentID{}) || fragment.ID == [32]byte{} || fragment.OrderID == [32]byte{}
 }
 
+// A FragmentCommitment stores the shamir.Commitments to the shares held by one
+// Fragment index.
 type FragmentCommitment struct {
+	Tokens           shamir.Commitment `json:"tokens"`
 	PriceCo          shamir.Commitment `json:"priceCo"`
 	PriceExp         shamir.Commitment `json:"priceExp"`
 	VolumeCo         shamir.Commitment `json:"volumeCo"`
 	VolumeExp        shamir.Commitment `json:"volumeExp"`
 	MinimumVolumeCo  shamir.Commitment `json:"minimumVolumeCo"`
 	MinimumVolumeExp shamir.Commitment `json:"minimumVolumeExp"`
+	Nonce            shamir.Commitment `json:"nonce"`
 }
 
+// Equal returns an equality check between two FragmentCommitments.
+func (commitment *FragmentCommitment) Equal(other *FragmentCommitment) bool {
+	pairs := [][2]shamir.Commitment{
+		{commitment.Tokens, other.Tokens},
+		{commitment.PriceCo, other.PriceCo},
+		{commitment.PriceExp, other.PriceExp},
+		{commitment.VolumeCo, other.VolumeCo},
+		{commitment.VolumeExp, other.VolumeExp},
+		{commitment.MinimumVolumeCo, other.MinimumVolumeCo},
+		{commitment.MinimumVolumeExp, other.MinimumVolumeExp},
+		{commitment.Nonce, other.Nonce},
+	}
+	for _, pair := range pairs {
+		if pair[0].Int == nil || pair[1].Int == nil || pair[0].Cmp(pair[1].Int) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// FragmentCommitments maps Fragment indices to their FragmentCommitment.
 type FragmentCommitments map[uint64]FragmentCommitment
+
+// CoefficientCommitments store the shamir.Commitments to the coefficients of
+// the polynomials used to split each secure field of an Order. They are the
+// same for all Fragments of the Order.
+type CoefficientCommitments struct {
+	Tokens           shamir.Commitments `json:"tokens"`
+	PriceCo          shamir.Commitments `json:"priceCo"`
+	PriceExp         shamir.Commitments `json:"priceExp"`
+	VolumeCo         shamir.Commitments `json:"volumeCo"`
+	VolumeExp        shamir.Commitments `json:"volumeExp"`
+	MinimumVolumeCo  shamir.Commitments `json:"minimumVolumeCo"`
+	MinimumVolumeExp shamir.Commitments `json:"minimumVolumeExp"`
+	Nonce            shamir.Commitments `json:"nonce"`
+}
+
+// Evaluate the CoefficientCommitments at a Fragment index, returning the
+// FragmentCommitment to the shares held by that index.
+func (commitments *CoefficientCommitments) Evaluate(index uint64) FragmentCommitment {
+	return FragmentCommitment{
+		Tokens:           commitments.Tokens.Evaluate(index),
+		PriceCo:          commitments.PriceCo.Evaluate(index),
+		PriceExp:         commitments.PriceExp.Evaluate(index),
+		VolumeCo:         commitments.VolumeCo.Evaluate(index),
+		VolumeExp:        commitments.VolumeExp.Evaluate(index),
+		MinimumVolumeCo:  commitments.MinimumVolumeCo.Evaluate(index),
+		MinimumVolumeExp: commitments.MinimumVolumeExp.Evaluate(index),
+		Nonce:            commitments.Nonce.Evaluate(index),
+	}
+}
+
+// Hash returns the Keccak256 hash of the CoefficientCommitments. All
+// Fragments of an Order must have CoefficientCommitments with the same Hash,
+// so Darknodes can compare the Hash to check that they were given consistent
+// Fragments.
+func (commitments *CoefficientCommitments) Hash() [32]byte {
+	buf := new(bytes.Buffer)
+	for _, fieldCommitments := range []shamir.Commitments{
+		commitments.Tokens,
+		commitments.PriceCo,
+		commitments.PriceExp,
+		commitments.VolumeCo,
+		commitments.VolumeExp,
+		commitments.MinimumVolumeCo,
+		commitments.MinimumVolumeExp,
+		commitments.Nonce,
+	} {
+		binary.Write(buf, binary.BigEndian, uint32(len(fieldCommitments)))
+		for _, commitment := range fieldCommitments {
+			var commitmentData []byte
+			if commitment.Int != nil {
+				commitmentData = commitment.Bytes()
+			}
+			binary.Write(buf, binary.BigEndian, uint32(len(commitmentData)))
+			buf.Write(commitmentData)
+		}
+	}
+	hash32 := [32]byte{}
+	copy(hash32[:], crypto.Keccak256(buf.Bytes()))
+	return hash32
+}
+
+// IsNil returns true if none of the CoefficientCommitments have been set.
+func (commitments *CoefficientCommitments) IsNil() bool {
+	return len(commitments.Tokens) == 0 &&
+		len(commitments.PriceCo) == 0 &&
+		len(commitments.PriceExp) == 0 &&
+		len(commitments.VolumeCo) == 0 &&
+		len(commitments.VolumeExp) == 0 &&
+		len(commitments.MinimumVolumeCo) == 0 &&
+		len(commitments.MinimumVolumeExp) == 0 &&
+		len(commitments.Nonce) == 0
+}
+
+// FragmentOpenings store the shamir.Openings for the shares in a Fragment.
+// They must only be revealed to the holder of the Fragment.
+type FragmentOpenings struct {
+	Tokens           shamir.Opening `json:"tokens"`
+	PriceCo          shamir.Opening `json:"priceCo"`
+	PriceExp         shamir.Opening `json:"priceExp"`
+	VolumeCo         shamir.Opening `json:"volumeCo"`
+	VolumeExp        shamir.Opening `json:"volumeExp"`
+	MinimumVolumeCo  shamir.Opening `json:"minimumVolumeCo"`
+	MinimumVolumeExp shamir.Opening `json:"minimumVolumeExp"`
+	Nonce            shamir.Opening `json:"nonce"`
+}
+
+// Encrypt FragmentOpenings using an rsa.PublicKey. Openings that have not been
+// set are left empty.
+func (openings *FragmentOpenings) Encrypt(pubKey rsa.PublicKey) (EncryptedFragmentOpenings, error) {
+	encryptedOpenings := EncryptedFragmentOpenings{}
+	pairs := []struct {
+		opening          *shamir.Opening
+		encryptedOpening *[]byte
+	}{
+		{&openings.Tokens, &encryptedOpenings.Tokens},
+		{&openings.PriceCo, &encryptedOpenings.PriceCo},
+		{&openings.PriceExp, &encryptedOpenings.PriceExp},
+		{&openings.VolumeCo, &encryptedOpenings.VolumeCo},
+		{&openings.VolumeExp, &encryptedOpenings.VolumeExp},
+		{&openings.MinimumVolumeCo, &encryptedOpenings.MinimumVolumeCo},
+		{&openings.MinimumVolumeExp, &encryptedOpenings.MinimumVolumeExp},
+		{&openings.Nonce, &encryptedOpenings.Nonce},
+	}
+	for _, pair := range pairs {
+		if pair.opening.Value == nil {
+			continue
+		}
+		cipherText, err := pair.opening.Encrypt(pubKey)
+		if err != nil {
+			return encryptedOpenings, err
+		}
+		*pair.encryptedOpening = cipherText
+	}
+	return encryptedOpenings, nil
+}
+
+// EncryptedFragmentOpenings are FragmentOpenings that have been encrypted by
+// an RSA public key.
+type EncryptedFragmentOpenings struct {
+	Tokens           []byte `json:"tokens"`
+	PriceCo          []byte `json:"priceCo"`
+	PriceExp         []byte `json:"priceExp"`
+	VolumeCo         []byte `json:"volumeCo"`
+	VolumeExp        []byte `json:"volumeExp"`
+	MinimumVolumeCo  []byte `json:"minimumVolumeCo"`
+	MinimumVolumeExp []byte `json:"minimumVolumeExp"`
+	Nonce            []byte `json:"nonce"`
+}
+
+// Decrypt EncryptedFragmentOpenings using an rsa.PrivateKey. Openings that are
+// empty are left unset.
+func (encryptedOpenings *EncryptedFragmentOpenings) Decrypt(privKey *rsa.PrivateKey) (FragmentOpenings, error) {
+	openings := FragmentOpenings{}
+	pairs := []struct {
+		encryptedOpening []byte
+		opening          *shamir.Opening
+	}{
+		{encryptedOpenings.Tokens, &openings.Tokens},
+		{encryptedOpenings.PriceCo, &openings.PriceCo},
+		{encryptedOpenings.PriceExp, &openings.PriceExp},
+		{encryptedOpenings.VolumeCo, &openings.VolumeCo},
+		{encryptedOpenings.VolumeExp, &openings.VolumeExp},
+		{encryptedOpenings.MinimumVolumeCo, &openings.MinimumVolumeCo},
+		{encryptedOpenings.MinimumVolumeExp, &openings.MinimumVolumeExp},
+		{encryptedOpenings.Nonce, &openings.Nonce},
+	}
+	for _, pair := range pairs {
+		if len(pair.encryptedOpening) == 0 {
+			continue
+		}
+		if err := pair.opening.Decrypt(privKey, pair.encryptedOpening); err != nil {
+			return openings, err
+		}
+	}
+	return openings, nil
+}
+
+// IsCommitted returns true if the Fragment was created with commitments,
+// either to its shares or to the coefficients of its polynomials. Fragments
+// created by Split are not committed, and cannot be verified.
+func (fragment *Fragment) IsCommitted() bool {
+	return len(fragment.Commitments) > 0 || !fragment.CoefficientCommitments.IsNil()
+}
+
+// VerifyCommitments returns an error if the shares in the Fragment are not
+// consistent with the CoefficientCommitments, or if any of the
+// FragmentCommitments are not the CoefficientCommitments evaluated at their
+// index. The FragmentCommitment for the index of the Fragment must exist.
+func (fragment *Fragment) VerifyCommitments() error {
+	coefficientCommitments := fragment.CoefficientCommitments
+	openings := fragment.Openings
+	checks := []struct {
+		name        string
+		share       shamir.Share
+		commitments shamir.Commitments
+		opening     shamir.Opening
+	}{
+		{"tokens", fragment.Tokens, coefficientCommitments.Tokens, openings.Tokens},
+		{"price co", fragment.Price.Co, coefficientCommitments.PriceCo, openings.PriceCo},
+		{"price exp", fragment.Price.Exp, coefficientCommitments.PriceExp, openings.PriceExp},
+		{"volume co", fragment.Volume.Co, coefficientCommitments.VolumeCo, openings.VolumeCo},
+		{"volume exp", fragment.Volume.Exp, coefficientCommitments.VolumeExp, openings.VolumeExp},
+		{"minimum volume co", fragment.MinimumVolume.Co, coefficientCommitments.MinimumVolumeCo, openings.MinimumVolumeCo},
+		{"minimum volume exp", fragment.MinimumVolume.Exp, coefficientCommitments.MinimumVolumeExp, openings.MinimumVolumeExp},
+		{"nonce", fragment.Nonce, coefficientCommitments.Nonce, openings.Nonce},
+	}
+	for _, check := range checks {
+		if check.share.Index != fragment.Tokens.Index {
+			return fmt.Errorf("%v: %v has index %v", ErrUnverifiedFragment, check.name, check.share.Index)
+		}
+		if !check.commitments.VerifyShare(check.share, check.opening) {
+			return fmt.Errorf("%v: %v is inconsistent with its coefficient commitments", ErrUnverifiedFragment, check.name)
+		}
+	}
+
+	if _, ok := fragment.Commitments[fragment.Tokens.Index]; !ok {
+		return fmt.Errorf("%v: no commitment for index %v", ErrUnverifiedFragment, fragment.Tokens.Index)
+	}
+	for index, commitment := range fragment.Commitments {
+		expected := coefficientCommitments.Evaluate(index)
+		if !commitment.Equal(&expected) {
+			return fmt.Errorf("%v: commitment for index %v is inconsistent with the coefficient commitments", ErrUnverifiedFragment, index)
+		}
+	}
+	return nil
+}
@@ -143,4 +143,81 @@ var _ = Describe("Order fragments", func() {
 			Expect(decryptedFragment).ToNot(Equal(fragment))
 		})
 	})
+
+	Context("when verifying commitments", func() {
+
+		It("should verify fragments split with commitments after decrypting their encrypted form", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 100000000000, 42)
+			fragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				encryptedFragment, err := fragment.Encrypt(rsaKey.PublicKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				decryptedFragment, err := encryptedFragment.Decrypt(rsaKey.PrivateKey)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(decryptedFragment.VerifyCommitments()).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should not verify fragments with tampered shares", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 100000000000, 42)
+			fragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fragments[0].Price.Co.Value = (fragments[0].Price.Co.Value + 1) % shamir.Prime
+			Expect(fragments[0].VerifyCommitments()).Should(HaveOccurred())
+
+			// Shares from a different split are inconsistent with the
+			// commitments
+			otherFragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			fragments[1].Volume = otherFragments[1].Volume
+			Expect(fragments[1].VerifyCommitments()).Should(HaveOccurred())
+		})
+
+		It("should not verify fragments with commitments that are not bound to the coefficient commitments", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 100000000000, 42)
+			fragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			otherFragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Replace the commitment for another index, which cannot be
+			// checked using the shares of this fragment
+			index := fragments[1].Tokens.Index
+			commitments := FragmentCommitments{}
+			for i, commitment := range fragments[0].Commitments {
+				commitments[i] = commitment
+			}
+			commitments[index] = otherFragments[0].Commitments[index]
+			fragments[0].Commitments = commitments
+			Expect(fragments[0].IsCommitted()).Should(BeTrue())
+			Expect(fragments[0].VerifyCommitments()).Should(HaveOccurred())
+		})
+
+		It("should hash the coefficient commitments of all fragments of an order to the same value", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 100000000000, 42)
+			fragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Expect(fragment.CoefficientCommitments.Hash()).Should(Equal(fragments[0].CoefficientCommitments.Hash()))
+			}
+
+			// A different split of the same order has different commitments
+			otherFragments, err := ord.SplitWithCommitments(rand.Reader, 6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(otherFragments[0].CoefficientCommitments.Hash()).ShouldNot(Equal(fragments[0].CoefficientCommitments.Hash()))
+		})
+
+		It("should not verify fragments without commitments", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 100000000000, 42)
+			fragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fragments[0].IsCommitted()).Should(BeFalse())
+			Expect(fragments[0].VerifyCommitments()).Should(HaveOccurred())
+		})
+	})
 })
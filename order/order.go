@@ -2,9 +2,11 @@ package order
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"io"
 	"math/big"
 	"os"
 	"time"
@@ -45,26 +47,14 @@ const (
 	TokenOMG  Token = 65538
 )
 
-// String returns a human-readable representation of a Token.
+// String returns a human-readable representation of a Token, using the name
+// of the Token in the DefaultTokenRegistry.
 func (token Token) String() string {
-	switch token {
-	case TokenBTC:
-		return "BTC"
-	case TokenETH:
-		return "ETH"
-	case TokenDGX:
-		return "DGX"
-	case TokenTUSD:
-		return "TUSD"
-	case TokenREN:
-		return "REN"
-	case TokenZRX:
-		return "ZRX"
-	case TokenOMG:
-		return "OMG"
-	default:
+	details, err := DefaultTokenRegistry().Token(token)
+	if err != nil {
 		return "unexpected token"
 	}
+	return details.Name
 }
 
 // Tokens are a numerical representation of the token pairings supported by
@@ -91,24 +81,14 @@ func (tokens Tokens) NonPriorityToken() Token {
 	return Token(tokens >> 32)
 }
 
-// String returns a human-readable representation of Tokens.
+// String returns a human-readable representation of Tokens, using the names
+// of the Tokens in the DefaultTokenRegistry.
 func (tokens Tokens) String() string {
-	switch tokens {
-	case TokensBTCETH:
-		return "BTC-ETH"
-	case TokensETHDGX:
-		return "ETH-DGX"
-	case TokensETHTUSD:
-		return "ETH-TUSD"
-	case TokensETHREN:
-		return "ETH-REN"
-	case TokensETHZRX:
-		return "ETH-ZRX"
-	case TokensETHOMG:
-		return "ETH-OMG"
-	default:
+	pair, err := DefaultTokenRegistry().PairString(tokens)
+	if err != nil {
 		return "unexpected tokens"
 	}
+	return pair
 }
 
 // A Type is a publicly bit of information that determines the type of
@@ -121,8 +101,39 @@ const (
 	TypeLimit       Type = 1
 	TypeMidpointFOK Type = 2
 	TypeLimitFOK    Type = 3
+	TypeMidpointIOC Type = 4
+	TypeLimitIOC    Type = 5
+	TypeMidpointGTT Type = 6
+	TypeLimitGTT    Type = 7
 )
 
+// IsMidpoint returns true if the Type is a midpoint type. Midpoint orders are
+// executed at the mid-point price of their token pair, and their price is the
+// limit at which they can be executed.
+func (ty Type) IsMidpoint() bool {
+	return ty == TypeMidpoint || ty == TypeMidpointFOK || ty == TypeMidpointIOC || ty == TypeMidpointGTT
+}
+
+// IsFillOrKill returns true if the Type is a Fill-or-Kill (FOK) type. FOK
+// orders must be filled completely by a single match.
+func (ty Type) IsFillOrKill() bool {
+	return ty == TypeMidpointFOK || ty == TypeLimitFOK
+}
+
+// IsImmediateOrCancel returns true if the Type is an Immediate-or-Cancel (IOC)
+// type. IOC orders are only matched against orders that were open before them,
+// and any volume that is not filled is cancelled.
+func (ty Type) IsImmediateOrCancel() bool {
+	return ty == TypeMidpointIOC || ty == TypeLimitIOC
+}
+
+// IsGoodTillTime returns true if the Type is a Good-till-Time (GTT) type. GTT
+// orders are never settled after their expiry, even if they were matched
+// before it.
+func (ty Type) IsGoodTillTime() bool {
+	return ty == TypeMidpointGTT || ty == TypeLimitGTT
+}
+
 // The Parity of an Order determines whether it is buy or a sell.
 type Parity int8
 
@@ -272,39 +283,47 @@ func WriteOrdersToJSONFile(fileName string, orders []*Order) error {
 // Split the Order into n OrderFragments, where k OrderFragments are needed to
 // reconstruct the Order. Returns a slice of all n OrderFragments, or an error.
 func (order *Order) Split(n, k int64) ([]Fragment, error) {
+	return order.SplitWithReader(rand.Reader, n, k)
+}
+
+// SplitWithReader splits the Order into n OrderFragments, the same as Split,
+// but draws the randomness used to create shamir.Shares from the io.Reader.
+// The io.Reader must be a cryptographically secure source of randomness,
+// except during testing.
+func (order *Order) SplitWithReader(reader io.Reader, n, k int64) ([]Fragment, error) {
 	priceCoExp := PriceToCoExp(order.Price)
 	volumeCoExp := VolumeToCoExp(order.Volume)
 	minimumVolumeCoExp := VolumeToCoExp(order.MinimumVolume)
 
-	tokens, err := shamir.Split(n, k, uint64(order.Tokens))
+	tokens, err := shamir.SplitWithReader(reader, n, k, uint64(order.Tokens))
 	if err != nil {
 		return nil, err
 	}
-	priceCos, err := shamir.Split(n, k, priceCoExp.Co)
+	priceCos, err := shamir.SplitWithReader(reader, n, k, priceCoExp.Co)
 	if err != nil {
 		return nil, err
 	}
-	priceExps, err := shamir.Split(n, k, priceCoExp.Exp)
+	priceExps, err := shamir.SplitWithReader(reader, n, k, priceCoExp.Exp)
 	if err != nil {
 		return nil, err
 	}
-	volumeCos, err := shamir.Split(n, k, volumeCoExp.Co)
+	volumeCos, err := shamir.SplitWithReader(reader, n, k, volumeCoExp.Co)
 	if err != nil {
 		return nil, err
 	}
-	volumeExps, err := shamir.Split(n, k, volumeCoExp.Exp)
+	volumeExps, err := shamir.SplitWithReader(reader, n, k, volumeCoExp.Exp)
 	if err != nil {
 		return nil, err
 	}
-	minimumVolumeCos, err := shamir.Split(n, k, minimumVolumeCoExp.Co)
+	minimumVolumeCos, err := shamir.SplitWithReader(reader, n, k, minimumVolumeCoExp.Co)
 	if err != nil {
 		return nil, err
 	}
-	minimumVolumeExps, err := shamir.Split(n, k, minimumVolumeCoExp.Exp)
+	minimumVolumeExps, err := shamir.SplitWithReader(reader, n, k, minimumVolumeCoExp.Exp)
 	if err != nil {
 		return nil, err
 	}
-	nonces, err := shamir.Split(n, k, order.Nonce)
+	nonces, err := shamir.SplitWithReader(reader, n, k, order.Nonce)
 	if err != nil {
 		return nil, err
 	}
@@ -329,6 +348,88 @@ func (order *Order) Split(n, k int64) ([]Fragment, error) {
 	return fragments, nil
 }
 
+// SplitWithCommitments splits the Order into n OrderFragments, the same as
+// SplitWithReader, and attaches commitments that allow each OrderFragment to
+// be verified. Each OrderFragment holds the shamir.Commitments to the
+// coefficients of every polynomial, and the shamir.Openings for its own
+// shares. The FragmentCommitments to the shares of all OrderFragments are
+// derived from the coefficient commitments, so that they are bound to them.
+func (order *Order) SplitWithCommitments(reader io.Reader, n, k int64) ([]Fragment, error) {
+	priceCoExp := PriceToCoExp(order.Price)
+	volumeCoExp := VolumeToCoExp(order.Volume)
+	minimumVolumeCoExp := VolumeToCoExp(order.MinimumVolume)
+
+	secrets := []uint64{
+		uint64(order.Tokens),
+		priceCoExp.Co,
+		priceCoExp.Exp,
+		volumeCoExp.Co,
+		volumeCoExp.Exp,
+		minimumVolumeCoExp.Co,
+		minimumVolumeCoExp.Exp,
+		order.Nonce,
+	}
+	shares := make([]shamir.Shares, len(secrets))
+	openings := make([]shamir.Openings, len(secrets))
+	coefficientCommitments := make([]shamir.Commitments, len(secrets))
+	for i, secret := range secrets {
+		var err error
+		shares[i], openings[i], coefficientCommitments[i], err = shamir.SplitWithCommitments(reader, n, k, secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	coefficients := CoefficientCommitments{
+		Tokens:           coefficientCommitments[0],
+		PriceCo:          coefficientCommitments[1],
+		PriceExp:         coefficientCommitments[2],
+		VolumeCo:         coefficientCommitments[3],
+		VolumeExp:        coefficientCommitments[4],
+		MinimumVolumeCo:  coefficientCommitments[5],
+		MinimumVolumeExp: coefficientCommitments[6],
+		Nonce:            coefficientCommitments[7],
+	}
+	commitments := FragmentCommitments{}
+	for i := int64(0); i < n; i++ {
+		index := shares[0][i].Index
+		commitments[index] = coefficients.Evaluate(index)
+	}
+
+	fragments := make([]Fragment, n)
+	for i := range fragments {
+		var err error
+		fragments[i], err = NewFragment(
+			order.ID,
+			order.Type,
+			order.Parity,
+			order.Settlement,
+			order.Expiry,
+			shares[0][i],
+			CoExpShare{Co: shares[1][i], Exp: shares[2][i]},
+			CoExpShare{Co: shares[3][i], Exp: shares[4][i]},
+			CoExpShare{Co: shares[5][i], Exp: shares[6][i]},
+			shares[7][i],
+		)
+		if err != nil {
+			return nil, err
+		}
+		fragments[i].Commitments = commitments
+		fragments[i].CoefficientCommitments = coefficients
+		fragments[i].Openings = FragmentOpenings{
+			Tokens:           openings[0][i],
+			PriceCo:          openings[1][i],
+			PriceExp:         openings[2][i],
+			VolumeCo:         openings[3][i],
+			VolumeExp:        openings[4][i],
+			MinimumVolumeCo:  openings[5][i],
+			MinimumVolumeExp: openings[6][i],
+			Nonce:            openings[7][i],
+		}
+	}
+	return fragments, nil
+}
+
 // Hash returns the Keccak256 hash of an Order. This hash is used to create the
 // ID and signature for an Order. Returns a zero-d hash if the order cannot be
 // marshaled into bytes.
@@ -152,6 +152,27 @@ var _ = Describe("Orders", func() {
 		})
 	})
 
+	Context("when handling types", func() {
+		It("should classify fill-or-kill, immediate-or-cancel and good-till-time types", func() {
+			Expect(TypeLimitFOK.IsFillOrKill()).Should(BeTrue())
+			Expect(TypeMidpointFOK.IsFillOrKill()).Should(BeTrue())
+			Expect(TypeLimitIOC.IsImmediateOrCancel()).Should(BeTrue())
+			Expect(TypeMidpointIOC.IsImmediateOrCancel()).Should(BeTrue())
+			Expect(TypeLimitGTT.IsGoodTillTime()).Should(BeTrue())
+			Expect(TypeMidpointGTT.IsGoodTillTime()).Should(BeTrue())
+
+			Expect(TypeLimit.IsFillOrKill() || TypeLimit.IsImmediateOrCancel() || TypeLimit.IsGoodTillTime()).Should(BeFalse())
+			Expect(TypeLimitIOC.IsFillOrKill() || TypeLimitIOC.IsGoodTillTime()).Should(BeFalse())
+
+			for _, ty := range []Type{TypeMidpoint, TypeMidpointFOK, TypeMidpointIOC, TypeMidpointGTT} {
+				Expect(ty.IsMidpoint()).Should(BeTrue())
+			}
+			for _, ty := range []Type{TypeLimit, TypeLimitFOK, TypeLimitIOC, TypeLimitGTT} {
+				Expect(ty.IsMidpoint()).Should(BeFalse())
+			}
+		})
+	})
+
 	Context("when handling status", func() {
 		It("should return status as a string", func() {
 			Expect(Open.String()).Should(Equal("open"))
@@ -0,0 +1,265 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownToken is returned when a Token, or the name of a Token, is not
+// registered in a TokenRegistry.
+var ErrUnknownToken = errors.New("unknown token")
+
+// ErrInvalidTokenPair is returned when a token pair does not follow the
+// priority token rules. The non-priority token of a pair must have a lower
+// Token value than the priority token of the pair.
+var ErrInvalidTokenPair = errors.New("invalid token pair")
+
+// ErrDuplicateTokenName is returned when registering a Token with a name that
+// is already used by a different Token.
+var ErrDuplicateTokenName = errors.New("duplicate token name")
+
+// TokenDetails are the details of a Token that are needed to trade it. The
+// Address of the Token is empty for tokens that are not ERC20 tokens.
+type TokenDetails struct {
+	Token    Token  `json:"token"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// NewTokens returns the Tokens that pair a non-priority Token with a priority
+// Token. An error is returned if the pair does not follow the priority token
+// rules.
+func NewTokens(nonPriority, priority Token) (Tokens, error) {
+	if nonPriority >= priority {
+		return 0, ErrInvalidTokenPair
+	}
+	return Tokens((uint64(nonPriority) << 32) | uint64(priority)), nil
+}
+
+// A TokenRegistry stores the Tokens that are supported by a darknode, and the
+// token pairs that are enabled for trading. It is safe for concurrent use.
+type TokenRegistry struct {
+	mu     *sync.RWMutex
+	tokens map[Token]TokenDetails
+	pairs  map[Tokens]struct{}
+}
+
+// NewTokenRegistry returns an empty TokenRegistry.
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		mu:     new(sync.RWMutex),
+		tokens: map[Token]TokenDetails{},
+		pairs:  map[Tokens]struct{}{},
+	}
+}
+
+// NewDefaultTokenRegistry returns a TokenRegistry with the Tokens, and token
+// pairs, that were supported before Tokens could be registered. The addresses
+// of the Tokens are not set, because they depend on the network.
+func NewDefaultTokenRegistry() *TokenRegistry {
+	registry := NewTokenRegistry()
+	for _, details := range []TokenDetails{
+		{Token: TokenBTC, Name: "BTC", Decimals: 8},
+		{Token: TokenETH, Name: "ETH", Decimals: 18},
+		{Token: TokenDGX, Name: "DGX", Decimals: 9},
+		{Token: TokenTUSD, Name: "TUSD", Decimals: 18},
+		{Token: TokenREN, Name: "REN", Decimals: 18},
+		{Token: TokenZRX, Name: "ZRX", Decimals: 18},
+		{Token: TokenOMG, Name: "OMG", Decimals: 18},
+	} {
+		registry.tokens[details.Token] = details
+	}
+	for _, tokens := range []Tokens{TokensBTCETH, TokensETHDGX, TokensETHTUSD, TokensETHREN, TokensETHZRX, TokensETHOMG} {
+		registry.pairs[tokens] = struct{}{}
+	}
+	return registry
+}
+
+// RegisterToken adds a Token to the TokenRegistry, or updates the details of
+// a Token that is already registered.
+func (registry *TokenRegistry) RegisterToken(details TokenDetails) error {
+	if details.Name == "" {
+		return fmt.Errorf("cannot register token %d: empty name", details.Token)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for token, other := range registry.tokens {
+		if token != details.Token && strings.EqualFold(other.Name, details.Name) {
+			return ErrDuplicateTokenName
+		}
+	}
+	registry.tokens[details.Token] = details
+	return nil
+}
+
+// DeregisterToken removes a Token from the TokenRegistry, and disables all
+// token pairs that include the Token.
+func (registry *TokenRegistry) DeregisterToken(token Token) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	delete(registry.tokens, token)
+	for tokens := range registry.pairs {
+		if tokens.PriorityToken() == token || tokens.NonPriorityToken() == token {
+			delete(registry.pairs, tokens)
+		}
+	}
+}
+
+// EnablePair enables trading of a token pair. Both Tokens of the pair must be
+// registered, and the pair must follow the priority token rules.
+func (registry *TokenRegistry) EnablePair(tokens Tokens) error {
+	if _, err := NewTokens(tokens.NonPriorityToken(), tokens.PriorityToken()); err != nil {
+		return err
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.tokens[tokens.NonPriorityToken()]; !ok {
+		return ErrUnknownToken
+	}
+	if _, ok := registry.tokens[tokens.PriorityToken()]; !ok {
+		return ErrUnknownToken
+	}
+	registry.pairs[tokens] = struct{}{}
+	return nil
+}
+
+// DisablePair disables trading of a token pair.
+func (registry *TokenRegistry) DisablePair(tokens Tokens) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	delete(registry.pairs, tokens)
+}
+
+// IsPairEnabled returns true if the token pair is enabled for trading.
+func (registry *TokenRegistry) IsPairEnabled(tokens Tokens) bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	_, ok := registry.pairs[tokens]
+	return ok
+}
+
+// Token returns the TokenDetails of a registered Token.
+func (registry *TokenRegistry) Token(token Token) (TokenDetails, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	details, ok := registry.tokens[token]
+	if !ok {
+		return TokenDetails{}, ErrUnknownToken
+	}
+	return details, nil
+}
+
+// TokenByName returns the TokenDetails of a registered Token using the name of
+// the Token. Names are not case sensitive.
+func (registry *TokenRegistry) TokenByName(name string) (TokenDetails, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	for _, details := range registry.tokens {
+		if strings.EqualFold(details.Name, name) {
+			return details, nil
+		}
+	}
+	return TokenDetails{}, ErrUnknownToken
+}
+
+// Tokens returns the TokenDetails of all registered Tokens, sorted by Token.
+func (registry *TokenRegistry) Tokens() []TokenDetails {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	tokens := make([]TokenDetails, 0, len(registry.tokens))
+	for _, details := range registry.tokens {
+		tokens = append(tokens, details)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Token < tokens[j].Token
+	})
+	return tokens
+}
+
+// Pairs returns all token pairs that are enabled for trading, sorted by
+// Tokens.
+func (registry *TokenRegistry) Pairs() []Tokens {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	pairs := make([]Tokens, 0, len(registry.pairs))
+	for tokens := range registry.pairs {
+		pairs = append(pairs, tokens)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i] < pairs[j]
+	})
+	return pairs
+}
+
+// ParsePair returns the Tokens for a human-readable token pair, such as
+// "ETH-REN", where the non-priority token is written first.
+func (registry *TokenRegistry) ParsePair(pair string) (Tokens, error) {
+	names := strings.Split(pair, "-")
+	if len(names) != 2 {
+		return 0, ErrInvalidTokenPair
+	}
+	nonPriority, err := registry.TokenByName(names[0])
+	if err != nil {
+		return 0, err
+	}
+	priority, err := registry.TokenByName(names[1])
+	if err != nil {
+		return 0, err
+	}
+	return NewTokens(nonPriority.Token, priority.Token)
+}
+
+// PairString returns a human-readable representation of a token pair, or an
+// error if either Token is not registered.
+func (registry *TokenRegistry) PairString(tokens Tokens) (string, error) {
+	if _, err := NewTokens(tokens.NonPriorityToken(), tokens.PriorityToken()); err != nil {
+		return "", err
+	}
+	nonPriority, err := registry.Token(tokens.NonPriorityToken())
+	if err != nil {
+		return "", err
+	}
+	priority, err := registry.Token(tokens.PriorityToken())
+	if err != nil {
+		return "", err
+	}
+	return nonPriority.Name + "-" + priority.Name, nil
+}
+
+var defaultTokenRegistryMu = new(sync.RWMutex)
+var defaultTokenRegistry = NewDefaultTokenRegistry()
+
+// DefaultTokenRegistry returns the TokenRegistry that is used to give Tokens
+// a human-readable representation, and to decide which token pairs can be
+// settled.
+func DefaultTokenRegistry() *TokenRegistry {
+	defaultTokenRegistryMu.RLock()
+	defer defaultTokenRegistryMu.RUnlock()
+
+	return defaultTokenRegistry
+}
+
+// SetDefaultTokenRegistry replaces the TokenRegistry returned by
+// DefaultTokenRegistry. It should be called once, when a darknode has loaded
+// its TokenRegistry from its config.
+func SetDefaultTokenRegistry(registry *TokenRegistry) {
+	defaultTokenRegistryMu.Lock()
+	defer defaultTokenRegistryMu.Unlock()
+
+	defaultTokenRegistry = registry
+}
@@ -0,0 +1,85 @@
+package order_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Token registry", func() {
+
+	const TokenABC = Token(65539)
+
+	Context("when registering tokens", func() {
+
+		It("should return the details of registered tokens", func() {
+			registry := NewTokenRegistry()
+			Expect(registry.RegisterToken(TokenDetails{Token: TokenABC, Name: "ABC", Address: "0xabc", Decimals: 12})).ShouldNot(HaveOccurred())
+
+			details, err := registry.Token(TokenABC)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details.Decimals).Should(Equal(uint8(12)))
+			details, err = registry.TokenByName("abc")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details.Token).Should(Equal(TokenABC))
+
+			_, err = registry.Token(TokenREN)
+			Expect(err).Should(Equal(ErrUnknownToken))
+		})
+
+		It("should not register two tokens with the same name", func() {
+			registry := NewDefaultTokenRegistry()
+			Expect(registry.RegisterToken(TokenDetails{Token: TokenABC, Name: "REN"})).Should(Equal(ErrDuplicateTokenName))
+		})
+
+		It("should disable the pairs of a deregistered token", func() {
+			registry := NewDefaultTokenRegistry()
+			Expect(registry.IsPairEnabled(TokensETHREN)).Should(BeTrue())
+			registry.DeregisterToken(TokenREN)
+			Expect(registry.IsPairEnabled(TokensETHREN)).Should(BeFalse())
+			Expect(registry.Pairs()).Should(HaveLen(5))
+		})
+	})
+
+	Context("when enabling token pairs", func() {
+
+		It("should enable pairs of registered tokens", func() {
+			registry := NewDefaultTokenRegistry()
+			Expect(registry.RegisterToken(TokenDetails{Token: TokenABC, Name: "ABC"})).ShouldNot(HaveOccurred())
+			tokens, err := registry.ParsePair("ETH-ABC")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(registry.EnablePair(tokens)).ShouldNot(HaveOccurred())
+			Expect(registry.IsPairEnabled(tokens)).Should(BeTrue())
+			Expect(registry.PairString(tokens)).Should(Equal("ETH-ABC"))
+		})
+
+		It("should not enable pairs that break the priority token rules", func() {
+			registry := NewDefaultTokenRegistry()
+			_, err := registry.ParsePair("REN-ETH")
+			Expect(err).Should(Equal(ErrInvalidTokenPair))
+			Expect(registry.EnablePair(Tokens((uint64(TokenREN) << 32) | uint64(TokenETH)))).Should(Equal(ErrInvalidTokenPair))
+		})
+
+		It("should not enable pairs of unknown tokens", func() {
+			registry := NewDefaultTokenRegistry()
+			tokens, err := NewTokens(TokenETH, TokenABC)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(registry.EnablePair(tokens)).Should(Equal(ErrUnknownToken))
+		})
+	})
+
+	Context("when replacing the default registry", func() {
+
+		It("should use the names of the new registry", func() {
+			registry := NewDefaultTokenRegistry()
+			Expect(registry.RegisterToken(TokenDetails{Token: TokenABC, Name: "ABC"})).ShouldNot(HaveOccurred())
+			SetDefaultTokenRegistry(registry)
+			defer SetDefaultTokenRegistry(NewDefaultTokenRegistry())
+
+			Expect(TokenABC.String()).Should(Equal("ABC"))
+			tokens, err := NewTokens(TokenETH, TokenABC)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tokens.String()).Should(Equal("ETH-ABC"))
+		})
+	})
+})
@@ -0,0 +1,443 @@
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/dispatch"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+)
+
+// ErrFragmentAvailabilityNotFound is returned when there is no
+// FragmentAvailability for an order, because the order is not the
+// responsibility of the Pod of this Darknode.
+var ErrFragmentAvailabilityNotFound = errors.New("fragment availability not found")
+
+// ErrInvalidFragmentAttestation is returned when a FragmentAttestation is not
+// for the requested order, or is not signed by the requested Darknode.
+var ErrInvalidFragmentAttestation = errors.New("invalid fragment attestation")
+
+// ErrInconsistentCommitments is returned when another Darknode in the Pod
+// attests to holding an order.Fragment with different
+// order.CoefficientCommitments for the same order.
+var ErrInconsistentCommitments = errors.New("inconsistent commitments")
+
+// ErrUnattestedCommitments is returned when fewer than the threshold of the
+// Pod attest to holding an order.Fragment with the same
+// order.CoefficientCommitments for an order.
+var ErrUnattestedCommitments = errors.New("unattested commitments")
+
+// A FragmentAttestation is signed by a Darknode to attest to the
+// order.Fragment that it holds for an order.ID, and to the Hash of its
+// order.CoefficientCommitments. The FragmentID, and the CommitmentsHash, are
+// zero when the Darknode does not hold an order.Fragment for the order.ID.
+type FragmentAttestation struct {
+	OrderID         order.ID
+	FragmentID      order.FragmentID
+	CommitmentsHash [32]byte
+	Darknode        identity.Address
+	Signature       []byte
+}
+
+// Hash returns the Keccak256 hash of the FragmentAttestation, excluding its
+// Signature. This is the hash that is signed by the Darknode.
+func (attestation FragmentAttestation) Hash() []byte {
+	return crypto.Keccak256([]byte("Republic Protocol: fragment attestation: "), attestation.OrderID[:], attestation.FragmentID[:], attestation.CommitmentsHash[:], []byte(attestation.Darknode))
+}
+
+// Verify that the FragmentAttestation was signed by its Darknode.
+func (attestation FragmentAttestation) Verify() error {
+	return crypto.NewEcdsaVerifier(attestation.Darknode.String()).Verify(attestation.Hash(), attestation.Signature)
+}
+
+// IsHeld returns true if the FragmentAttestation attests that the Darknode
+// holds an order.Fragment.
+func (attestation FragmentAttestation) IsHeld() bool {
+	return !attestation.FragmentID.Equal(order.FragmentID{})
+}
+
+// FragmentAvailability reports which Darknodes, in the Pod responsible for an
+// order.ID, hold an order.Fragment for the order.ID. The Darknodes that are
+// Missing an order.Fragment, or that could not attest to holding one, need
+// the trader to resend their order.Fragment.
+type FragmentAvailability struct {
+	OrderID      order.ID
+	Epoch        [32]byte
+	Attestations []FragmentAttestation
+	Missing      []identity.Address
+	Timestamp    time.Time
+}
+
+// AvailabilityClient for invoking the AvailabilityServer.AttestOrderFragment
+// RPC on the other Darknodes in a Pod.
+type AvailabilityClient interface {
+
+	// AttestOrderFragment requests a FragmentAttestation for an order.ID from
+	// a Darknode. The FragmentAttestation must be verified, and must be
+	// signed by the requested Darknode.
+	AttestOrderFragment(ctx context.Context, to identity.Address, orderID order.ID) (FragmentAttestation, error)
+}
+
+// AvailabilityServer for attesting to the order.Fragments held by a Darknode.
+type AvailabilityServer interface {
+	AttestOrderFragment(ctx context.Context, orderID order.ID) (FragmentAttestation, error)
+}
+
+// Availability runs the fragment availability protocol between the Darknodes
+// of a Pod. When an order has been open for longer than a timeout, but its
+// order.Fragment has not been received, the other Darknodes in the Pod are
+// asked to attest to the order.Fragments that they hold. The resulting
+// FragmentAvailability tells the trader which Darknodes need their
+// order.Fragment to be resent.
+type Availability interface {
+	AvailabilityServer
+
+	// Run the fragment availability protocol for orders that are missing an
+	// order.Fragment, checking all open orders on an interval. Stop once the
+	// done channel is closed.
+	Run(done <-chan struct{})
+
+	// OnChangeEpoch should be called whenever a change to the registry.Epoch
+	// is detected.
+	OnChangeEpoch(epoch registry.Epoch)
+
+	// FragmentAvailability of an order.ID. A recent FragmentAvailability is
+	// returned if there is one, otherwise the fragment availability protocol
+	// is run for the order.ID.
+	FragmentAvailability(ctx context.Context, orderID order.ID) (FragmentAvailability, error)
+
+	// VerifyCommitments of an order.Fragment held by this Darknode against the
+	// FragmentAttestations of the other Darknodes in its Pod. It returns
+	// ErrInconsistentCommitments when another Darknode holds an
+	// order.Fragment with different order.CoefficientCommitments, and
+	// ErrUnattestedCommitments when fewer than the threshold of the Pod hold
+	// an order.Fragment with the same order.CoefficientCommitments. Verified
+	// order.CoefficientCommitments are remembered until the registry.Epoch
+	// changes, or the order is closed.
+	VerifyCommitments(ctx context.Context, orderFragment order.Fragment) error
+}
+
+type availability struct {
+	addr               identity.Address
+	signer             crypto.Signer
+	orderStore         OrderStorer
+	orderFragmentStore OrderFragmentStorer
+	client             AvailabilityClient
+	timeout            time.Duration
+
+	epochMu *sync.RWMutex
+	epoch   *registry.Epoch
+
+	reportsMu *sync.Mutex
+	missing   map[order.ID]time.Time
+	reports   map[order.ID]FragmentAvailability
+	verified  map[order.ID][32]byte
+}
+
+// NewAvailability returns an Availability that signs FragmentAttestations
+// using a crypto.Signer, and requests FragmentAttestations from other
+// Darknodes using an AvailabilityClient. Orders are checked for missing
+// order.Fragments once their order.Fragments have not been received within
+// the timeout, and FragmentAvailabilities are kept for the timeout.
+func NewAvailability(addr identity.Address, signer crypto.Signer, orderStore OrderStorer, orderFragmentStore OrderFragmentStorer, client AvailabilityClient, timeout time.Duration) Availability {
+	return &availability{
+		addr:               addr,
+		signer:             signer,
+		orderStore:         orderStore,
+		orderFragmentStore: orderFragmentStore,
+		client:             client,
+		timeout:            timeout,
+
+		epochMu: new(sync.RWMutex),
+		epoch:   nil,
+
+		reportsMu: new(sync.Mutex),
+		missing:   map[order.ID]time.Time{},
+		reports:   map[order.ID]FragmentAvailability{},
+		verified:  map[order.ID][32]byte{},
+	}
+}
+
+// AttestOrderFragment implements the AvailabilityServer interface.
+func (availability *availability) AttestOrderFragment(ctx context.Context, orderID order.ID) (FragmentAttestation, error) {
+	attestation := FragmentAttestation{
+		OrderID:  orderID,
+		Darknode: availability.addr,
+	}
+	if epoch, ok := availability.currentEpoch(); ok {
+		orderFragment, err := availability.orderFragmentStore.OrderFragment(epoch, orderID)
+		if err != nil && err != ErrOrderFragmentNotFound {
+			return FragmentAttestation{}, err
+		}
+		if err == nil {
+			attestation.FragmentID = orderFragment.ID
+			attestation.CommitmentsHash = orderFragment.CoefficientCommitments.Hash()
+		}
+	}
+
+	signature, err := availability.signer.Sign(attestation.Hash())
+	if err != nil {
+		return FragmentAttestation{}, fmt.Errorf("cannot sign fragment attestation: %v", err)
+	}
+	attestation.Signature = signature
+	return attestation, nil
+}
+
+// Run implements the Availability interface.
+func (availability *availability) Run(done <-chan struct{}) {
+	interval := availability.timeout / 2
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			availability.checkOrders(done)
+		}
+	}
+}
+
+// OnChangeEpoch implements the Availability interface.
+func (availability *availability) OnChangeEpoch(epoch registry.Epoch) {
+	if epoch.IsNil() {
+		return
+	}
+
+	availability.epochMu.Lock()
+	availability.epoch = &epoch
+	availability.epochMu.Unlock()
+
+	// Order fragments are held per registry.Epoch, so reports from the
+	// previous registry.Epoch are no longer accurate
+	availability.reportsMu.Lock()
+	availability.missing = map[order.ID]time.Time{}
+	availability.reports = map[order.ID]FragmentAvailability{}
+	availability.verified = map[order.ID][32]byte{}
+	availability.reportsMu.Unlock()
+}
+
+// FragmentAvailability implements the Availability interface.
+func (availability *availability) FragmentAvailability(ctx context.Context, orderID order.ID) (FragmentAvailability, error) {
+	availability.reportsMu.Lock()
+	report, ok := availability.reports[orderID]
+	availability.reportsMu.Unlock()
+	if ok && time.Since(report.Timestamp) < availability.timeout {
+		return report, nil
+	}
+
+	report, err := availability.attest(ctx, orderID)
+	if err != nil {
+		return FragmentAvailability{}, err
+	}
+	availability.reportsMu.Lock()
+	availability.reports[orderID] = report
+	availability.reportsMu.Unlock()
+	return report, nil
+}
+
+// VerifyCommitments implements the Availability interface.
+func (availability *availability) VerifyCommitments(ctx context.Context, orderFragment order.Fragment) error {
+	commitmentsHash := orderFragment.CoefficientCommitments.Hash()
+
+	availability.reportsMu.Lock()
+	verifiedHash, ok := availability.verified[orderFragment.OrderID]
+	availability.reportsMu.Unlock()
+	if ok && verifiedHash == commitmentsHash {
+		return nil
+	}
+
+	_, pod, err := availability.pod(orderFragment.OrderID)
+	if err != nil {
+		return err
+	}
+	attested := 0
+	for _, attestation := range availability.attestations(ctx, orderFragment.OrderID, pod) {
+		if attestation == nil || !attestation.IsHeld() {
+			continue
+		}
+		if attestation.CommitmentsHash != commitmentsHash {
+			log.Printf("[error] (availability) %v attests to different commitments for order = %v", attestation.Darknode, orderFragment.OrderID)
+			return ErrInconsistentCommitments
+		}
+		attested++
+	}
+	if attested < pod.Threshold() {
+		return ErrUnattestedCommitments
+	}
+
+	availability.reportsMu.Lock()
+	availability.verified[orderFragment.OrderID] = commitmentsHash
+	availability.reportsMu.Unlock()
+	return nil
+}
+
+// checkOrders for open orders that have not received their order.Fragment
+// within the timeout, and run the fragment availability protocol for them.
+func (availability *availability) checkOrders(done <-chan struct{}) {
+	epoch, ok := availability.currentEpoch()
+	if !ok {
+		return
+	}
+
+	orderIter, err := availability.orderStore.Orders()
+	if err != nil {
+		log.Printf("[error] (availability) cannot load orders: %v", err)
+		return
+	}
+	defer orderIter.Release()
+	orderIDs, orderStatuses, _, _, err := orderIter.Collect()
+	if err != nil {
+		log.Printf("[error] (availability) cannot collect orders: %v", err)
+	}
+
+	now := time.Now()
+	missing := map[order.ID]time.Time{}
+	expired := []order.ID{}
+	func() {
+		availability.reportsMu.Lock()
+		defer availability.reportsMu.Unlock()
+
+		for i, orderID := range orderIDs {
+			if orderStatuses[i] != order.Open {
+				delete(availability.verified, orderID)
+				continue
+			}
+			if _, err := availability.orderFragmentStore.OrderFragment(epoch, orderID); err != ErrOrderFragmentNotFound {
+				continue
+			}
+			since, ok := availability.missing[orderID]
+			if !ok {
+				since = now
+			}
+			missing[orderID] = since
+			if now.Sub(since) < availability.timeout {
+				continue
+			}
+			if report, ok := availability.reports[orderID]; ok && now.Sub(report.Timestamp) < availability.timeout {
+				continue
+			}
+			expired = append(expired, orderID)
+		}
+
+		// Forget about orders that have been closed, or that have received
+		// their order.Fragment
+		availability.missing = missing
+		for orderID := range availability.reports {
+			if _, ok := missing[orderID]; !ok {
+				delete(availability.reports, orderID)
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), availability.timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for _, orderID := range expired {
+		report, err := availability.attest(ctx, orderID)
+		if err != nil {
+			log.Printf("[error] (availability) cannot attest to order fragments of order = %v: %v", orderID, err)
+			continue
+		}
+		log.Printf("[info] (availability) order = %v is missing %v order fragments", orderID, len(report.Missing))
+		availability.reportsMu.Lock()
+		availability.reports[orderID] = report
+		availability.reportsMu.Unlock()
+	}
+}
+
+// attest requests FragmentAttestations for an order.ID from all Darknodes in
+// the Pod that is responsible for the order.ID, including this Darknode.
+func (availability *availability) attest(ctx context.Context, orderID order.ID) (FragmentAvailability, error) {
+	epoch, pod, err := availability.pod(orderID)
+	if err != nil {
+		return FragmentAvailability{}, err
+	}
+	attestations := availability.attestations(ctx, orderID, pod)
+
+	report := FragmentAvailability{
+		OrderID:      orderID,
+		Epoch:        epoch.Hash,
+		Attestations: []FragmentAttestation{},
+		Missing:      []identity.Address{},
+		Timestamp:    time.Now(),
+	}
+	for i, attestation := range attestations {
+		if attestation == nil {
+			report.Missing = append(report.Missing, pod.Darknodes[i])
+			continue
+		}
+		report.Attestations = append(report.Attestations, *attestation)
+		if !attestation.IsHeld() {
+			report.Missing = append(report.Missing, pod.Darknodes[i])
+		}
+	}
+	return report, nil
+}
+
+// pod returns the current registry.Epoch, and the registry.Pod of this
+// Darknode, if the registry.Pod is responsible for the order.ID.
+func (availability *availability) pod(orderID order.ID) (registry.Epoch, registry.Pod, error) {
+	epoch, ok := availability.currentEpoch()
+	if !ok {
+		return registry.Epoch{}, registry.Pod{}, ErrFragmentAvailabilityNotFound
+	}
+	pod, err := epoch.Pod(availability.addr)
+	if err != nil {
+		return registry.Epoch{}, registry.Pod{}, ErrFragmentAvailabilityNotFound
+	}
+	if index, ok := epoch.Pods.PathOfOrder(orderID).IndexOfPod(&pod); !ok || index < 0 {
+		return registry.Epoch{}, registry.Pod{}, ErrFragmentAvailabilityNotFound
+	}
+	return epoch, pod, nil
+}
+
+// attestations requests a FragmentAttestation for an order.ID from each
+// Darknode in a registry.Pod, including this Darknode. The FragmentAttestation
+// is nil for Darknodes that could not attest.
+func (availability *availability) attestations(ctx context.Context, orderID order.ID, pod registry.Pod) []*FragmentAttestation {
+	attestations := make([]*FragmentAttestation, len(pod.Darknodes))
+	dispatch.CoForAll(pod.Darknodes, func(i int) {
+		var attestation FragmentAttestation
+		var err error
+		if pod.Darknodes[i] == availability.addr {
+			attestation, err = availability.AttestOrderFragment(ctx, orderID)
+		} else {
+			attestation, err = availability.client.AttestOrderFragment(ctx, pod.Darknodes[i], orderID)
+		}
+		if err != nil {
+			log.Printf("[error] (availability) cannot get fragment attestation from %v: %v", pod.Darknodes[i], err)
+			return
+		}
+		attestations[i] = &attestation
+	})
+	return attestations
+}
+
+func (availability *availability) currentEpoch() (registry.Epoch, bool) {
+	availability.epochMu.RLock()
+	defer availability.epochMu.RUnlock()
+
+	if availability.epoch == nil {
+		return registry.Epoch{}, false
+	}
+	return *availability.epoch, true
+}
@@ -0,0 +1,253 @@
+package orderbook_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/orderbook"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Availability", func() {
+
+	var (
+		numberOfDarknodes = 4
+		addrs             []identity.Address
+		stores            []*leveldb.Store
+		availabilities    []Availability
+		client            *availabilityClient
+		epoch             registry.Epoch
+	)
+
+	BeforeEach(func() {
+		addrs = make([]identity.Address, numberOfDarknodes)
+		keys := make([]crypto.EcdsaKey, numberOfDarknodes)
+		for i := range addrs {
+			var err error
+			keys[i], err = crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			addrs[i] = identity.Address(keys[i].Address())
+		}
+		epoch = registry.Epoch{
+			Hash: testutils.Random32Bytes(),
+			Pods: []registry.Pod{
+				{
+					Position:  0,
+					Hash:      testutils.Random32Bytes(),
+					Darknodes: addrs,
+				},
+			},
+			Darknodes:     addrs,
+			BlockNumber:   big.NewInt(1),
+			BlockInterval: big.NewInt(1),
+		}
+
+		client = newAvailabilityClient()
+		stores = make([]*leveldb.Store, numberOfDarknodes)
+		availabilities = make([]Availability, numberOfDarknodes)
+		for i := range availabilities {
+			var err error
+			stores[i], err = leveldb.NewStore(fmt.Sprintf("./tmp/availability.%v.out", i), 24*time.Hour, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			availabilities[i] = NewAvailability(addrs[i], &keys[i], stores[i].OrderbookOrderStore(), stores[i].OrderbookOrderFragmentStore(), client, 100*time.Millisecond)
+			availabilities[i].OnChangeEpoch(epoch)
+			client.servers[addrs[i]] = availabilities[i]
+		}
+	})
+
+	AfterEach(func() {
+		for _, store := range stores {
+			store.Release()
+		}
+		os.RemoveAll("./tmp")
+	})
+
+	// storeOrderFragments stores an order fragment of the order in the first n
+	// darknodes
+	storeOrderFragments := func(ord order.Order, n int) []order.Fragment {
+		fragments, err := ord.SplitWithCommitments(rand.Reader, int64(numberOfDarknodes), int64(numberOfDarknodes/2+1))
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := 0; i < n; i++ {
+			Expect(stores[i].OrderbookOrderFragmentStore().PutOrderFragment(epoch, fragments[i])).ShouldNot(HaveOccurred())
+		}
+		return fragments
+	}
+
+	Context("when attesting to order fragments", func() {
+
+		It("should sign an attestation to the order fragment that it holds", func() {
+			ord := testutils.RandomOrder()
+			fragments := storeOrderFragments(ord, 1)
+
+			attestation, err := availabilities[0].AttestOrderFragment(context.Background(), ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(attestation.IsHeld()).Should(BeTrue())
+			Expect(attestation.FragmentID).Should(Equal(fragments[0].ID))
+			Expect(attestation.Darknode).Should(Equal(addrs[0]))
+			Expect(attestation.Verify()).ShouldNot(HaveOccurred())
+		})
+
+		It("should sign an attestation to not holding an order fragment", func() {
+			attestation, err := availabilities[0].AttestOrderFragment(context.Background(), testutils.RandomOrder().ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(attestation.IsHeld()).Should(BeFalse())
+			Expect(attestation.Verify()).ShouldNot(HaveOccurred())
+
+			attestation.Darknode = addrs[1]
+			Expect(attestation.Verify()).Should(HaveOccurred())
+		})
+	})
+
+	Context("when reporting fragment availability", func() {
+
+		It("should report the darknodes that are missing an order fragment", func() {
+			ord := testutils.RandomOrder()
+			storeOrderFragments(ord, numberOfDarknodes-1)
+
+			availability, err := availabilities[0].FragmentAvailability(context.Background(), ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(availability.OrderID).Should(Equal(ord.ID))
+			Expect(availability.Epoch).Should(Equal(epoch.Hash))
+			Expect(availability.Attestations).Should(HaveLen(numberOfDarknodes))
+			Expect(availability.Missing).Should(Equal([]identity.Address{addrs[numberOfDarknodes-1]}))
+		})
+
+		It("should report darknodes that cannot attest as missing an order fragment", func() {
+			ord := testutils.RandomOrder()
+			storeOrderFragments(ord, numberOfDarknodes)
+			client.unreachable[addrs[1]] = true
+
+			availability, err := availabilities[0].FragmentAvailability(context.Background(), ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(availability.Attestations).Should(HaveLen(numberOfDarknodes - 1))
+			Expect(availability.Missing).Should(Equal([]identity.Address{addrs[1]}))
+		})
+
+		It("should not report orders before the epoch is known", func() {
+			availability := NewAvailability(addrs[0], nil, stores[0].OrderbookOrderStore(), stores[0].OrderbookOrderFragmentStore(), client, time.Second)
+			_, err := availability.FragmentAvailability(context.Background(), testutils.RandomOrder().ID)
+			Expect(err).Should(Equal(ErrFragmentAvailabilityNotFound))
+		})
+	})
+
+	Context("when verifying commitments", func() {
+
+		It("should verify commitments that are held by the threshold of the pod", func() {
+			ord := testutils.RandomOrder()
+			fragments := storeOrderFragments(ord, numberOfDarknodes-1)
+
+			Expect(availabilities[0].VerifyCommitments(context.Background(), fragments[0])).ShouldNot(HaveOccurred())
+		})
+
+		It("should not verify commitments that are different from the commitments held by another darknode", func() {
+			ord := testutils.RandomOrder()
+			fragments := storeOrderFragments(ord, numberOfDarknodes-1)
+
+			// The last darknode is given a fragment from a different split of
+			// the same order
+			otherFragments, err := ord.SplitWithCommitments(rand.Reader, int64(numberOfDarknodes), int64(numberOfDarknodes/2+1))
+			Expect(err).ShouldNot(HaveOccurred())
+			last := numberOfDarknodes - 1
+			Expect(stores[last].OrderbookOrderFragmentStore().PutOrderFragment(epoch, otherFragments[last])).ShouldNot(HaveOccurred())
+
+			Expect(availabilities[0].VerifyCommitments(context.Background(), fragments[0])).Should(Equal(ErrInconsistentCommitments))
+			Expect(availabilities[last].VerifyCommitments(context.Background(), otherFragments[last])).Should(Equal(ErrInconsistentCommitments))
+		})
+
+		It("should not verify commitments that are held by fewer than the threshold of the pod", func() {
+			ord := testutils.RandomOrder()
+			fragments := storeOrderFragments(ord, 1)
+
+			Expect(availabilities[0].VerifyCommitments(context.Background(), fragments[0])).Should(Equal(ErrUnattestedCommitments))
+		})
+	})
+
+	Context("when an open order is missing its order fragment", func() {
+
+		It("should ask the pod for attestations after the timeout", func() {
+			ord := testutils.RandomOrder()
+			storeOrderFragments(ord, numberOfDarknodes-1)
+			last := numberOfDarknodes - 1
+			Expect(stores[last].OrderbookOrderStore().PutOrder(ord.ID, order.Open, "trader", 1)).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			go availabilities[last].Run(done)
+
+			Eventually(func() int {
+				return client.requests(ord.ID)
+			}, time.Second).Should(Equal(numberOfDarknodes - 1))
+
+			// The report is reused until it expires
+			availability, err := availabilities[last].FragmentAvailability(context.Background(), ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(availability.Missing).Should(Equal([]identity.Address{addrs[last]}))
+		})
+
+		It("should not ask the pod for attestations when the order fragment is received", func() {
+			ord := testutils.RandomOrder()
+			storeOrderFragments(ord, numberOfDarknodes)
+			Expect(stores[0].OrderbookOrderStore().PutOrder(ord.ID, order.Open, "trader", 1)).ShouldNot(HaveOccurred())
+
+			done := make(chan struct{})
+			defer close(done)
+			go availabilities[0].Run(done)
+
+			Consistently(func() int {
+				return client.requests(ord.ID)
+			}, 500*time.Millisecond).Should(BeZero())
+		})
+	})
+})
+
+// availabilityClient is a mock AvailabilityClient that requests
+// FragmentAttestations directly from the Availability of each Darknode.
+type availabilityClient struct {
+	mu          *sync.Mutex
+	servers     map[identity.Address]AvailabilityServer
+	unreachable map[identity.Address]bool
+	numRequests map[order.ID]int
+}
+
+func newAvailabilityClient() *availabilityClient {
+	return &availabilityClient{
+		mu:          new(sync.Mutex),
+		servers:     map[identity.Address]AvailabilityServer{},
+		unreachable: map[identity.Address]bool{},
+		numRequests: map[order.ID]int{},
+	}
+}
+
+func (client *availabilityClient) AttestOrderFragment(ctx context.Context, to identity.Address, orderID order.ID) (FragmentAttestation, error) {
+	client.mu.Lock()
+	client.numRequests[orderID]++
+	server, ok := client.servers[to]
+	unreachable := client.unreachable[to]
+	client.mu.Unlock()
+
+	if !ok || unreachable {
+		return FragmentAttestation{}, errors.New("cannot dial darknode")
+	}
+	return server.AttestOrderFragment(ctx, orderID)
+}
+
+func (client *availabilityClient) requests(orderID order.ID) int {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	return client.numRequests[orderID]
+}
@@ -28,3 +28,43 @@ type ContractBinder interface {
 
 	SettlementStatus(orderID order.ID) (uint8, error)
 }
+
+// An OrderEvent is a change to the status of an order that has been logged by
+// the Orderbook contract. The Trader and Priority are only set for opened
+// orders.
+type OrderEvent struct {
+	OrderID     order.ID
+	Status      order.Status
+	Trader      string
+	Priority    uint
+	BlockNumber uint64
+	BlockHash   [32]byte
+}
+
+// An OrderEventSubscription delivers OrderEvents until it is unsubscribed, or
+// until an error is written to its error channel.
+type OrderEventSubscription interface {
+	Unsubscribe()
+	Err() <-chan error
+}
+
+// ContractEventBinder for reading the OrderEvents logged by the Orderbook
+// contract.
+type ContractEventBinder interface {
+
+	// LatestBlockNumber returns the number of the latest block.
+	LatestBlockNumber() (uint64, error)
+
+	// BlockHash returns the hash of the block with the given number in the
+	// canonical chain.
+	BlockHash(blockNumber uint64) ([32]byte, error)
+
+	// OrderEvents returns the OrderEvents that were logged between two
+	// blocks, inclusive, in the order that they were logged.
+	OrderEvents(fromBlock, toBlock uint64) ([]OrderEvent, error)
+
+	// SubscribeOrderEvents writes OrderEvents to the events channel as they
+	// are logged. An error is returned if the connection to Ethereum does not
+	// support subscriptions.
+	SubscribeOrderEvents(events chan<- OrderEvent) (OrderEventSubscription, error)
+}
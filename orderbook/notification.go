@@ -56,3 +56,16 @@ type NotificationCancelOrder struct {
 
 // IsNotification implements the Notification interface.
 func (notification NotificationCancelOrder) IsNotification() {}
+
+// NotificationRollbackOrder is used to signal that a change to an order.ID has
+// been removed from Ethereum by a reorganisation of the blockchain. The Status
+// is the order.Status that the order.ID has after the change is rolled back,
+// order.Nil if the opening of the order.ID was removed. The change is
+// notified again if it is included in the replacement blocks.
+type NotificationRollbackOrder struct {
+	OrderID order.ID
+	Status  order.Status
+}
+
+// IsNotification implements the Notification interface.
+func (notification NotificationRollbackOrder) IsNotification() {}
@@ -19,6 +19,10 @@ import (
 // fields.
 var ErrOrderFragmentIsNil = errors.New("order fragment is nil")
 
+// ErrOrderFragmentUncommitted is returned when an order fragment is opened
+// without the commitments needed to verify it.
+var ErrOrderFragmentUncommitted = errors.New("order fragment uncommitted")
+
 // Client for invoking the Server.OpenOrder RPC on a remote Server.
 type Client interface {
 
@@ -26,6 +30,19 @@ type Client interface {
 	// identity.MultiAddress. The order.EncryptedFragment will be stored by the
 	// Server.
 	OpenOrder(context.Context, identity.MultiAddress, order.EncryptedFragment) error
+
+	// OpenOrders by streaming many order.EncryptedFragments to an
+	// identity.MultiAddress. Each order.EncryptedFragment is signed by the
+	// crypto.Signer so that the Server can apply a quota to the trader. An
+	// error is returned for each order.EncryptedFragment, in the same order,
+	// and is nil when the order.EncryptedFragment was accepted.
+	OpenOrders(context.Context, identity.MultiAddress, crypto.Signer, []order.EncryptedFragment) ([]error, error)
+}
+
+// OpenOrderHash returns the Keccak256 hash of an order.EncryptedFragment that
+// is signed by a trader when opening many order.EncryptedFragments at once.
+func OpenOrderHash(orderFragment order.EncryptedFragment) []byte {
+	return crypto.Keccak256([]byte("Republic Protocol: open order: "), orderFragment.OrderID[:], orderFragment.ID[:])
 }
 
 // Server for opening order.EncryptedFragments. This RPC should only be called
@@ -52,6 +69,13 @@ type Orderbook interface {
 	// OnChangeEpoch should be called whenever a change to the registry.Epoch
 	// is detected.
 	OnChangeEpoch(epoch registry.Epoch)
+
+	// InsertOrderFragment that has been reshared from the previous
+	// registry.Epoch into the current registry.Epoch. The order.Fragment has
+	// no commitments that can be checked, so the status of its order is
+	// checked on Ethereum instead. A Notification is produced if the order is
+	// open.
+	InsertOrderFragment(ctx context.Context, orderFragment order.Fragment, trader string, priority uint) error
 }
 
 type orderbook struct {
@@ -96,7 +120,18 @@ func NewOrderbook(addr identity.Address, rsaKey crypto.RsaKey, pointerStore Poin
 	}
 }
 
-// OpenOrder implements the Server interface.
+// NewOrderbookWithSyncer returns an Orderbook that is the same as an Orderbook
+// returned by NewOrderbook, but that uses the given Syncer to synchronise
+// orders from Ethereum.
+func NewOrderbookWithSyncer(addr identity.Address, rsaKey crypto.RsaKey, pointerStore PointerStorer, orderStore OrderStorer, orderFragmentStore OrderFragmentStorer, contractBinder ContractBinder, syncer Syncer, interval time.Duration) Orderbook {
+	orderbook := NewOrderbook(addr, rsaKey, pointerStore, orderStore, orderFragmentStore, contractBinder, interval, 0).(*orderbook)
+	orderbook.syncer = syncer
+	return orderbook
+}
+
+// OpenOrder implements the Server interface. The decrypted order.Fragment must
+// have commitments, and must be consistent with them, otherwise it is
+// rejected.
 func (orderbook *orderbook) OpenOrder(ctx context.Context, encryptedOrderFragment order.EncryptedFragment) error {
 	if encryptedOrderFragment.IsNil() {
 		return ErrOrderFragmentIsNil
@@ -106,6 +141,17 @@ func (orderbook *orderbook) OpenOrder(ctx context.Context, encryptedOrderFragmen
 	if err != nil {
 		return err
 	}
+
+	// Reject fragments that are inconsistent with the commitments made by the
+	// trader, before they can be used during matching. Whether or not the
+	// commitments are the same for all fragments of the order is checked by
+	// the Pod before matching.
+	if !orderFragment.IsCommitted() {
+		return ErrOrderFragmentUncommitted
+	}
+	if err := orderFragment.VerifyCommitments(); err != nil {
+		return err
+	}
 	if encryptedOrderFragment.OrderParity == order.ParityBuy {
 		logger.BuyOrderReceived(logger.LevelDebugLow, encryptedOrderFragment.OrderID.String(), encryptedOrderFragment.ID.String())
 	} else {
@@ -151,6 +197,42 @@ func (orderbook *orderbook) OnChangeEpoch(epoch registry.Epoch) {
 	orderbook.aggCurr = NewAggregator(orderbook.addr, epoch, orderbook.orderStore, orderbook.orderFragmentStore)
 }
 
+// InsertOrderFragment implements the Orderbook interface.
+func (orderbook *orderbook) InsertOrderFragment(ctx context.Context, orderFragment order.Fragment, trader string, priority uint) error {
+	orderStatus, err := orderbook.contractBinder.Status(orderFragment.OrderID)
+	if err != nil {
+		return err
+	}
+	if orderStatus != order.Open {
+		return nil
+	}
+	orderFragment.EpochDepth = 0
+
+	n, err := func() (Notification, error) {
+		orderbook.aggMu.RLock()
+		defer orderbook.aggMu.RUnlock()
+
+		if orderbook.aggCurr == nil {
+			return nil, nil
+		}
+		// The order might not have been stored if this node was not in the
+		// path of the order during the previous registry.Epoch
+		if _, err := orderbook.aggCurr.InsertOrder(orderFragment.OrderID, orderStatus, trader, priority); err != nil {
+			return nil, err
+		}
+		return orderbook.aggCurr.InsertOrderFragment(orderFragment)
+	}()
+
+	if n != nil {
+		select {
+		case <-ctx.Done():
+		case orderbook.notifications <- n:
+		}
+	}
+
+	return err
+}
+
 func (orderbook *orderbook) sync(done <-chan struct{}) {
 	ticker := time.NewTicker(orderbook.interval)
 	defer ticker.Stop()
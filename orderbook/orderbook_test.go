@@ -2,6 +2,7 @@ package orderbook_test
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
@@ -19,6 +20,7 @@ import (
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -86,7 +88,7 @@ var _ = Describe("Orderbook", func() {
 				ord := testutils.RandomOrder()
 				err = storer.OrderbookOrderStore().PutOrder(ord.ID, order.Open, "", uint(i))
 				Expect(err).ShouldNot(HaveOccurred())
-				fragments, err := ord.Split(5, 4)
+				fragments, err := ord.SplitWithCommitments(rand.Reader, 5, 4)
 				encryptedOrderFragments[i], err = fragments[0].Encrypt(rsaKey.PublicKey)
 				Expect(err).ShouldNot(HaveOccurred())
 			}
@@ -106,6 +108,94 @@ var _ = Describe("Orderbook", func() {
 			countMu.Unlock()
 		})
 
+		It("should return an error for fragments that are inconsistent with their commitments", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", 24*time.Hour, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+
+			ord := testutils.RandomOrder()
+			fragments, err := ord.SplitWithCommitments(rand.Reader, 5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			fragments[0].Nonce.Value = (fragments[0].Nonce.Value + 1) % shamir.Prime
+			encryptedOrderFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = orderbook.OpenOrder(context.Background(), encryptedOrderFragment)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should reject fragments that were split without commitments", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", 24*time.Hour, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+
+			ord := testutils.RandomOrder()
+			fragments, err := ord.Split(5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			encryptedOrderFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = orderbook.OpenOrder(context.Background(), encryptedOrderFragment)
+			Expect(err).Should(Equal(ErrOrderFragmentUncommitted))
+		})
+
+		It("should produce notifications for reshared fragments of open orders", func() {
+			done := make(chan struct{})
+			defer close(done)
+
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", 24*time.Hour, time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+
+			addr, epoch, err := testutils.RandomEpoch(0)
+			Expect(err).ShouldNot(HaveOccurred())
+			binder := testutils.NewMockContractBinder()
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), binder, time.Hour, 100)
+			orderbook.OnChangeEpoch(epoch)
+
+			notifications, _ := orderbook.Sync(done)
+			countMu := new(sync.Mutex)
+			countNotifications := 0
+			go func() {
+				for _ = range notifications {
+					countMu.Lock()
+					countNotifications++
+					countMu.Unlock()
+				}
+			}()
+
+			openOrders := binder.OpenMatchingOrders(numberOfOrders/2, order.Open)
+			canceledOrders := binder.OpenMatchingOrders(numberOfOrders/2, order.Canceled)
+			for _, ord := range append(openOrders, canceledOrders...) {
+				fragments, err := ord.Split(5, 4)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(orderbook.InsertOrderFragment(context.Background(), fragments[0], "", 0)).ShouldNot(HaveOccurred())
+			}
+
+			time.Sleep(time.Second)
+			countMu.Lock()
+			Expect(countNotifications).Should(Equal(len(openOrders)))
+			countMu.Unlock()
+		})
+
 		It("should be able to sync with the ledger by the syncer", func() {
 			// Generate new RSA key
 			rsaKey, err := crypto.RandomRsaKey()
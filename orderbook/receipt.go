@@ -0,0 +1,293 @@
+package orderbook
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+)
+
+// ErrReceiptNotFound is returned when there is no Receipt for an order,
+// because the Darknode has not received an order.EncryptedFragment for it.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// MaxRejectedReceipts is the default maximum number of Receipts for rejected
+// order.EncryptedFragments that a Receipter keeps. Once it is reached, the
+// oldest Receipt is forgotten to make room for a new one.
+const MaxRejectedReceipts = 100000
+
+// MaxRejectedReceiptAge is the default maximum time that a Receipter keeps a
+// Receipt for a rejected order.EncryptedFragment. The expiry of the order is
+// chosen by the trader, so it cannot be trusted to bound the age of a Receipt.
+const MaxRejectedReceiptAge = time.Hour
+
+// RejectedReceiptPruneInterval is the interval at which a running Receipter
+// forgets the Receipts for rejected order.EncryptedFragments that have
+// expired.
+const RejectedReceiptPruneInterval = time.Minute
+
+// A Receipt is signed by a Darknode to confirm the order.EncryptedFragment
+// that it received for an order.ID. Decrypted is false when the
+// order.EncryptedFragment could not be decrypted, or was not consistent with
+// its commitments, and was rejected by the Darknode.
+type Receipt struct {
+	OrderID    order.ID
+	FragmentID order.FragmentID
+	EpochDepth order.FragmentEpochDepth
+	Decrypted  bool
+	Darknode   identity.Address
+	Signature  []byte
+}
+
+// Hash returns the Keccak256 hash of the Receipt, excluding its Signature.
+// This is the hash that is signed by the Darknode.
+func (receipt Receipt) Hash() []byte {
+	epochDepth := make([]byte, 4)
+	binary.BigEndian.PutUint32(epochDepth, uint32(receipt.EpochDepth))
+	decrypted := []byte{0}
+	if receipt.Decrypted {
+		decrypted[0] = 1
+	}
+	return crypto.Keccak256([]byte("Republic Protocol: receipt: "), receipt.OrderID[:], receipt.FragmentID[:], epochDepth, decrypted, []byte(receipt.Darknode))
+}
+
+// Verify that the Receipt was signed by its Darknode.
+func (receipt Receipt) Verify() error {
+	return crypto.NewEcdsaVerifier(receipt.Darknode.String()).Verify(receipt.Hash(), receipt.Signature)
+}
+
+// A Receipter is a Server that keeps track of the order.EncryptedFragments
+// that it receives, so that traders can confirm what has been stored by the
+// Darknode.
+type Receipter interface {
+	Server
+
+	// OnChangeEpoch should be called whenever a change to the registry.Epoch
+	// is detected.
+	OnChangeEpoch(epoch registry.Epoch)
+
+	// Run the Receipter, forgetting the Receipts for rejected
+	// order.EncryptedFragments that have expired on an interval. Stop once
+	// the done channel is closed.
+	Run(done <-chan struct{})
+
+	// Receipt returns a signed Receipt for the order.EncryptedFragment that
+	// was received for an order.ID. An order.Fragment that has been stored is
+	// preferred over an order.EncryptedFragment that was rejected.
+	Receipt(orderID order.ID) (Receipt, error)
+}
+
+type receipter struct {
+	addr               identity.Address
+	signer             crypto.Signer
+	server             Server
+	orderFragmentStore OrderFragmentStorer
+
+	epochMu   *sync.RWMutex
+	epochCurr *registry.Epoch
+	epochPrev *registry.Epoch
+
+	rejectedMu     *sync.Mutex
+	rejected       map[order.ID]*list.Element
+	rejectedQueue  *list.List
+	maxRejected    int
+	maxRejectedAge time.Duration
+}
+
+// rejectedOrderFragment is the Receipt for an order.EncryptedFragment that
+// was rejected, kept until the order expires, or until the maximum age of a
+// rejected Receipt has passed.
+type rejectedOrderFragment struct {
+	receipt Receipt
+	expiry  time.Time
+}
+
+// NewReceipter returns a Receipter that delegates the opening of
+// order.EncryptedFragments to a Server, and signs Receipts using a
+// crypto.Signer. Receipts for order.Fragments that were stored are loaded
+// from the OrderFragmentStorer used by the Server. Receipts for rejected
+// order.EncryptedFragments are kept in memory, limited by MaxRejectedReceipts
+// and MaxRejectedReceiptAge.
+func NewReceipter(addr identity.Address, signer crypto.Signer, server Server, orderFragmentStore OrderFragmentStorer) Receipter {
+	return NewReceipterWithLimits(addr, signer, server, orderFragmentStore, MaxRejectedReceipts, MaxRejectedReceiptAge)
+}
+
+// NewReceipterWithLimits returns a Receipter that keeps at most maxRejected
+// Receipts for rejected order.EncryptedFragments, each for no longer than
+// maxRejectedAge.
+func NewReceipterWithLimits(addr identity.Address, signer crypto.Signer, server Server, orderFragmentStore OrderFragmentStorer, maxRejected int, maxRejectedAge time.Duration) Receipter {
+	return &receipter{
+		addr:               addr,
+		signer:             signer,
+		server:             server,
+		orderFragmentStore: orderFragmentStore,
+
+		epochMu:   new(sync.RWMutex),
+		epochCurr: nil,
+		epochPrev: nil,
+
+		rejectedMu:     new(sync.Mutex),
+		rejected:       map[order.ID]*list.Element{},
+		rejectedQueue:  list.New(),
+		maxRejected:    maxRejected,
+		maxRejectedAge: maxRejectedAge,
+	}
+}
+
+// OpenOrder implements the Server interface. The order.EncryptedFragment is
+// recorded as rejected if the Server does not accept it.
+func (receipter *receipter) OpenOrder(ctx context.Context, encryptedOrderFragment order.EncryptedFragment) error {
+	err := receipter.server.OpenOrder(ctx, encryptedOrderFragment)
+	if err == ErrOrderFragmentIsNil {
+		return err
+	}
+
+	receipter.rejectedMu.Lock()
+	defer receipter.rejectedMu.Unlock()
+
+	if err == nil {
+		// The trader has resent an order.EncryptedFragment that was accepted
+		receipter.forgetRejected(encryptedOrderFragment.OrderID)
+		return nil
+	}
+	if _, stored := receipter.orderFragment(encryptedOrderFragment.OrderID); stored {
+		// The order.Fragment was stored before the error happened
+		return err
+	}
+
+	// Clamp the expiry chosen by the trader, and make room for the Receipt by
+	// forgetting the oldest Receipts
+	expiry := encryptedOrderFragment.OrderExpiry
+	if maxExpiry := time.Now().Add(receipter.maxRejectedAge); expiry.After(maxExpiry) {
+		expiry = maxExpiry
+	}
+	receipter.forgetRejected(encryptedOrderFragment.OrderID)
+	for receipter.rejectedQueue.Len() > 0 && receipter.rejectedQueue.Len() >= receipter.maxRejected {
+		oldest := receipter.rejectedQueue.Front().Value.(rejectedOrderFragment)
+		receipter.forgetRejected(oldest.receipt.OrderID)
+	}
+	if receipter.maxRejected <= 0 {
+		return err
+	}
+	receipter.rejected[encryptedOrderFragment.OrderID] = receipter.rejectedQueue.PushBack(rejectedOrderFragment{
+		receipt: Receipt{
+			OrderID:    encryptedOrderFragment.OrderID,
+			FragmentID: encryptedOrderFragment.ID,
+			EpochDepth: encryptedOrderFragment.EpochDepth,
+			Decrypted:  false,
+		},
+		expiry: expiry,
+	})
+	return err
+}
+
+// OnChangeEpoch implements the Receipter interface.
+func (receipter *receipter) OnChangeEpoch(epoch registry.Epoch) {
+	if epoch.IsNil() {
+		return
+	}
+
+	receipter.epochMu.Lock()
+	defer receipter.epochMu.Unlock()
+
+	receipter.epochPrev = receipter.epochCurr
+	receipter.epochCurr = &epoch
+}
+
+// Run implements the Receipter interface.
+func (receipter *receipter) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(RejectedReceiptPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			receipter.rejectedMu.Lock()
+			receipter.pruneRejected()
+			receipter.rejectedMu.Unlock()
+		}
+	}
+}
+
+// Receipt implements the Receipter interface.
+func (receipter *receipter) Receipt(orderID order.ID) (Receipt, error) {
+	receipt, ok := receipter.orderFragment(orderID)
+	if !ok {
+		receipter.rejectedMu.Lock()
+		elem, isRejected := receipter.rejected[orderID]
+		var rejected rejectedOrderFragment
+		if isRejected {
+			rejected = elem.Value.(rejectedOrderFragment)
+		}
+		receipter.rejectedMu.Unlock()
+
+		if !isRejected || rejected.expiry.Before(time.Now()) {
+			return Receipt{}, ErrReceiptNotFound
+		}
+		receipt = rejected.receipt
+	}
+
+	receipt.Darknode = receipter.addr
+	signature, err := receipter.signer.Sign(receipt.Hash())
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt.Signature = signature
+	return receipt, nil
+}
+
+// orderFragment returns an unsigned Receipt for the order.Fragment stored
+// for an order.ID in the current, or previous, registry.Epoch. Returns false
+// if no order.Fragment is stored.
+func (receipter *receipter) orderFragment(orderID order.ID) (Receipt, bool) {
+	receipter.epochMu.RLock()
+	defer receipter.epochMu.RUnlock()
+
+	for _, epoch := range []*registry.Epoch{receipter.epochCurr, receipter.epochPrev} {
+		if epoch == nil {
+			continue
+		}
+		orderFragment, err := receipter.orderFragmentStore.OrderFragment(*epoch, orderID)
+		if err != nil {
+			continue
+		}
+		return Receipt{
+			OrderID:    orderID,
+			FragmentID: orderFragment.ID,
+			EpochDepth: orderFragment.EpochDepth,
+			Decrypted:  true,
+		}, true
+	}
+	return Receipt{}, false
+}
+
+// pruneRejected removes rejected order.EncryptedFragments that have expired.
+// The rejectedMu must be locked by the caller.
+func (receipter *receipter) pruneRejected() {
+	now := time.Now()
+	for elem := receipter.rejectedQueue.Front(); elem != nil; {
+		next := elem.Next()
+		if rejected := elem.Value.(rejectedOrderFragment); rejected.expiry.Before(now) {
+			receipter.forgetRejected(rejected.receipt.OrderID)
+		}
+		elem = next
+	}
+}
+
+// forgetRejected removes the rejected order.EncryptedFragment for an order, if
+// there is one. The rejectedMu must be locked by the caller.
+func (receipter *receipter) forgetRejected(orderID order.ID) {
+	if elem, ok := receipter.rejected[orderID]; ok {
+		receipter.rejectedQueue.Remove(elem)
+		delete(receipter.rejected, orderID)
+	}
+}
@@ -0,0 +1,196 @@
+package orderbook_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/orderbook"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Receipter", func() {
+
+	var (
+		store     *leveldb.Store
+		rsaKey    crypto.RsaKey
+		ecdsaKey  crypto.EcdsaKey
+		epoch     registry.Epoch
+		receipter Receipter
+	)
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./tmp/receipt.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		rsaKey, err = crypto.RandomRsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		ecdsaKey, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, epoch, err = testutils.RandomEpoch(0)
+		Expect(err).ShouldNot(HaveOccurred())
+		server := &receiptServer{
+			rsaKey:             rsaKey,
+			epoch:              epoch,
+			orderFragmentStore: store.OrderbookOrderFragmentStore(),
+		}
+
+		receipter = NewReceipter(identity.Address(ecdsaKey.Address()), &ecdsaKey, server, store.OrderbookOrderFragmentStore())
+		receipter.OnChangeEpoch(epoch)
+	})
+
+	newReceipterWithLimits := func(maxRejected int, maxRejectedAge time.Duration) Receipter {
+		server := &receiptServer{
+			rsaKey:             rsaKey,
+			epoch:              epoch,
+			orderFragmentStore: store.OrderbookOrderFragmentStore(),
+		}
+		receipter := NewReceipterWithLimits(identity.Address(ecdsaKey.Address()), &ecdsaKey, server, store.OrderbookOrderFragmentStore(), maxRejected, maxRejectedAge)
+		receipter.OnChangeEpoch(epoch)
+		return receipter
+	}
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./tmp")
+	})
+
+	encryptOrderFragment := func(ord order.Order, key crypto.RsaKey) order.EncryptedFragment {
+		fragments, err := ord.Split(5, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		encryptedOrderFragment, err := fragments[0].Encrypt(key.PublicKey)
+		Expect(err).ShouldNot(HaveOccurred())
+		return encryptedOrderFragment
+	}
+
+	Context("when order fragments are opened", func() {
+
+		It("should sign a receipt for an order fragment that was stored", func() {
+			ord := testutils.RandomOrder()
+			encryptedOrderFragment := encryptOrderFragment(ord, rsaKey)
+			Expect(receipter.OpenOrder(context.Background(), encryptedOrderFragment)).ShouldNot(HaveOccurred())
+
+			receipt, err := receipter.Receipt(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(receipt.OrderID).Should(Equal(ord.ID))
+			Expect(receipt.FragmentID).Should(Equal(encryptedOrderFragment.ID))
+			Expect(receipt.EpochDepth).Should(Equal(encryptedOrderFragment.EpochDepth))
+			Expect(receipt.Decrypted).Should(BeTrue())
+			Expect(receipt.Darknode).Should(Equal(identity.Address(ecdsaKey.Address())))
+			Expect(receipt.Verify()).ShouldNot(HaveOccurred())
+		})
+
+		It("should sign a receipt for an order fragment that could not be decrypted", func() {
+			otherRsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			ord := testutils.RandomOrder()
+			encryptedOrderFragment := encryptOrderFragment(ord, otherRsaKey)
+			Expect(receipter.OpenOrder(context.Background(), encryptedOrderFragment)).Should(HaveOccurred())
+
+			receipt, err := receipter.Receipt(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(receipt.FragmentID).Should(Equal(encryptedOrderFragment.ID))
+			Expect(receipt.Decrypted).Should(BeFalse())
+			Expect(receipt.Verify()).ShouldNot(HaveOccurred())
+
+			receipt.Decrypted = true
+			Expect(receipt.Verify()).Should(HaveOccurred())
+		})
+
+		It("should prefer an order fragment that was resent and stored", func() {
+			otherRsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			ord := testutils.RandomOrder()
+			Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(ord, otherRsaKey))).Should(HaveOccurred())
+			Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(ord, rsaKey))).ShouldNot(HaveOccurred())
+
+			receipt, err := receipter.Receipt(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(receipt.Decrypted).Should(BeTrue())
+		})
+
+		It("should forget rejected order fragments once their order expires", func() {
+			otherRsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			expired := testutils.RandomOrder()
+			expired.Expiry = time.Now().Add(-time.Hour)
+			Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(expired, otherRsaKey))).Should(HaveOccurred())
+			ord := testutils.RandomOrder()
+			Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(ord, otherRsaKey))).Should(HaveOccurred())
+
+			_, err = receipter.Receipt(expired.ID)
+			Expect(err).Should(Equal(ErrReceiptNotFound))
+			_, err = receipter.Receipt(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("when many order fragments are rejected", func() {
+
+		It("should forget the oldest rejected order fragments once the limit is reached", func() {
+			receipter := newReceipterWithLimits(2, time.Hour)
+			otherRsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			ords := []order.Order{testutils.RandomOrder(), testutils.RandomOrder(), testutils.RandomOrder()}
+			for _, ord := range ords {
+				Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(ord, otherRsaKey))).Should(HaveOccurred())
+			}
+
+			_, err = receipter.Receipt(ords[0].ID)
+			Expect(err).Should(Equal(ErrReceiptNotFound))
+			for _, ord := range ords[1:] {
+				_, err = receipter.Receipt(ord.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should forget rejected order fragments after the maximum age, even if their order has not expired", func() {
+			receipter := newReceipterWithLimits(2, 100*time.Millisecond)
+			otherRsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			ord := testutils.RandomOrder()
+			ord.Expiry = time.Now().Add(24 * time.Hour)
+			Expect(receipter.OpenOrder(context.Background(), encryptOrderFragment(ord, otherRsaKey))).Should(HaveOccurred())
+
+			_, err = receipter.Receipt(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(func() error {
+				_, err := receipter.Receipt(ord.ID)
+				return err
+			}).Should(Equal(ErrReceiptNotFound))
+		})
+	})
+
+	Context("when no order fragment has been opened", func() {
+
+		It("should return an error", func() {
+			_, err := receipter.Receipt(testutils.RandomOrder().ID)
+			Expect(err).Should(Equal(ErrReceiptNotFound))
+		})
+	})
+})
+
+// receiptServer is a mock Server that stores the order.Fragments that it can
+// decrypt.
+type receiptServer struct {
+	rsaKey             crypto.RsaKey
+	epoch              registry.Epoch
+	orderFragmentStore OrderFragmentStorer
+}
+
+func (server *receiptServer) OpenOrder(ctx context.Context, encryptedOrderFragment order.EncryptedFragment) error {
+	orderFragment, err := encryptedOrderFragment.Decrypt(server.rsaKey.PrivateKey)
+	if err != nil {
+		return err
+	}
+	return server.orderFragmentStore.PutOrderFragment(server.epoch, orderFragment)
+}
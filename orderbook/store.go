@@ -81,7 +81,17 @@ type OrderFragmentIterator interface {
 type PointerStorer interface {
 	PutPointer(pointer Pointer) error
 	Pointer() (Pointer, error)
+	PutBlockPointer(pointer BlockPointer) error
+	BlockPointer() (BlockPointer, error)
 }
 
 // Pointer points to the last order.Order that was successfully synchronised.
 type Pointer int
+
+// BlockPointer points to the last block for which all OrderEvents were
+// successfully synchronised. It is anchored to the hash of the block, so that
+// a reorganisation of the blockchain that replaces the block can be detected.
+type BlockPointer struct {
+	Number uint64
+	Hash   [32]byte
+}
@@ -191,3 +191,460 @@ func (syncer *syncer) resync(notifications *Notifications) error {
 	}
 	return nil
 }
+
+// OrderEventBufferLimit is the number of OrderEvents that can be received from
+// a subscription between calls to Sync.
+const OrderEventBufferLimit = 1024
+
+// MaxReorganisationDepth is the number of blocks for which OrderEvents are
+// kept so that they can be rolled back after a reorganisation of the
+// blockchain. Reorganisations that are deeper than the kept blocks are assumed
+// to be no deeper than this number of blocks.
+const MaxReorganisationDepth = 128
+
+type eventSyncer struct {
+	pointerStore PointerStorer
+	orderStore   OrderStorer
+
+	contractBinder ContractEventBinder
+	blockLimit     uint64
+
+	subscription    OrderEventSubscription
+	subscribedBlock uint64
+	subscribeErr    error
+	events          chan OrderEvent
+
+	// Checkpoints of recent blocks, and the OrderEvents handled in those
+	// blocks, are kept in ascending block order so that the OrderEvents can
+	// be rolled back when the blocks are replaced by a reorganisation
+	checkpoints  []BlockPointer
+	recentEvents []OrderEvent
+}
+
+// NewEventSyncer returns a Syncer that synchronises orders using the
+// OrderEvents logged by the Orderbook contract, instead of loading all orders
+// and their statuses. It subscribes to new OrderEvents, and falls back to
+// polling for OrderEvents in at most blockLimit blocks per Sync when
+// subscriptions are unavailable. The last synchronised block is stored in the
+// PointerStorer so that synchronisation resumes from that block after a
+// reboot. Every Sync checks for a reorganisation of the synchronised blocks,
+// and produces a NotificationRollbackOrder for every OrderEvent that was
+// removed from the blockchain before synchronising the replacement blocks.
+func NewEventSyncer(pointerStore PointerStorer, orderStore OrderStorer, contractBinder ContractEventBinder, blockLimit uint64) Syncer {
+	if blockLimit == 0 {
+		blockLimit = 1
+	}
+	return &eventSyncer{
+		pointerStore: pointerStore,
+		orderStore:   orderStore,
+
+		contractBinder: contractBinder,
+		blockLimit:     blockLimit,
+
+		subscription:    nil,
+		subscribedBlock: 0,
+		subscribeErr:    nil,
+		events:          make(chan OrderEvent, OrderEventBufferLimit),
+
+		checkpoints:  []BlockPointer{},
+		recentEvents: []OrderEvent{},
+	}
+}
+
+// Sync implements the Syncer interface.
+func (syncer *eventSyncer) Sync() (Notifications, error) {
+	notifications := Notifications{}
+	if err := syncer.rollback(&notifications); err != nil {
+		return notifications, err
+	}
+
+	if syncer.subscription != nil {
+		select {
+		case err := <-syncer.subscription.Err():
+			log.Printf("[error] (sync) order event subscription closed: %v", err)
+			syncer.unsubscribe()
+		default:
+			syncer.receive(&notifications)
+			return notifications, nil
+		}
+	}
+
+	caughtUp, err := syncer.poll(&notifications)
+	if err != nil {
+		return notifications, err
+	}
+	if caughtUp {
+		if err := syncer.subscribe(&notifications); err != nil {
+			return notifications, err
+		}
+	}
+	return notifications, nil
+}
+
+// rollback the OrderEvents in blocks that have been replaced by a
+// reorganisation. The newest checkpoint is compared against the blockchain
+// and, if it has been replaced, the BlockPointer is moved back to the newest
+// checkpoint that has not been replaced so that the replacement blocks are
+// synchronised.
+func (syncer *eventSyncer) rollback(notifications *Notifications) error {
+	if len(syncer.checkpoints) == 0 {
+		pointer, err := syncer.pointerStore.BlockPointer()
+		if err != nil {
+			return fmt.Errorf("cannot load block pointer: %v", err)
+		}
+		if pointer.Number == 0 {
+			return nil
+		}
+		if pointer.Hash == [32]byte{} {
+			// The BlockPointer was stored without a hash so it is anchored to
+			// the block that is currently at its height
+			return syncer.storeBlockPointer(pointer.Number)
+		}
+		syncer.checkpoints = append(syncer.checkpoints, pointer)
+	}
+
+	newest := syncer.checkpoints[len(syncer.checkpoints)-1]
+	replaced, err := syncer.isReplaced(newest)
+	if err != nil || !replaced {
+		return err
+	}
+
+	// Find the newest checkpoint that has not been replaced
+	ancestor := BlockPointer{}
+	found := false
+	for i := len(syncer.checkpoints) - 2; i >= 0 && !found; i-- {
+		replaced, err := syncer.isReplaced(syncer.checkpoints[i])
+		if err != nil {
+			return err
+		}
+		if !replaced {
+			ancestor = syncer.checkpoints[i]
+			found = true
+		}
+	}
+	if !found {
+		// The reorganisation is deeper than the checkpoints, so assume that it
+		// is no deeper than the MaxReorganisationDepth
+		oldest := syncer.checkpoints[0]
+		if oldest.Number > MaxReorganisationDepth {
+			ancestor.Number = oldest.Number - MaxReorganisationDepth
+		}
+		if ancestor.Number > 0 {
+			hash, err := syncer.contractBinder.BlockHash(ancestor.Number)
+			if err != nil {
+				return fmt.Errorf("cannot load hash of block = %v: %v", ancestor.Number, err)
+			}
+			ancestor.Hash = hash
+		}
+	}
+	log.Printf("[info] (sync) reorganisation after block = %v", ancestor.Number)
+
+	// OrderEvents that have been delivered by the subscription might be from
+	// the replaced blocks
+	syncer.unsubscribe()
+
+	checkpoints := []BlockPointer{}
+	for _, checkpoint := range syncer.checkpoints {
+		if checkpoint.Number < ancestor.Number {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	if ancestor.Number > 0 {
+		checkpoints = append(checkpoints, ancestor)
+	}
+	syncer.checkpoints = checkpoints
+
+	numRecentEvents := len(syncer.recentEvents)
+	for numRecentEvents > 0 && syncer.recentEvents[numRecentEvents-1].BlockNumber > ancestor.Number {
+		numRecentEvents--
+	}
+	syncer.handleRollback(notifications, syncer.recentEvents[numRecentEvents:])
+	syncer.recentEvents = syncer.recentEvents[:numRecentEvents]
+
+	if err := syncer.pointerStore.PutBlockPointer(ancestor); err != nil {
+		return fmt.Errorf("cannot store block pointer: %v", err)
+	}
+	return nil
+}
+
+// isReplaced returns true if the block of a checkpoint has been replaced by a
+// different block.
+func (syncer *eventSyncer) isReplaced(checkpoint BlockPointer) (bool, error) {
+	hash, err := syncer.contractBinder.BlockHash(checkpoint.Number)
+	if err != nil {
+		return false, fmt.Errorf("cannot load hash of block = %v: %v", checkpoint.Number, err)
+	}
+	return hash != checkpoint.Hash, nil
+}
+
+// poll loads the OrderEvents from the blocks after the BlockPointer, up to the
+// latest block, and returns true if the latest block was reached.
+func (syncer *eventSyncer) poll(notifications *Notifications) (bool, error) {
+	pointer, err := syncer.pointerStore.BlockPointer()
+	if err != nil {
+		return false, fmt.Errorf("cannot load block pointer: %v", err)
+	}
+	latest, err := syncer.contractBinder.LatestBlockNumber()
+	if err != nil {
+		return false, fmt.Errorf("cannot load latest block number: %v", err)
+	}
+	from := pointer.Number + 1
+	if from > latest {
+		return true, nil
+	}
+	to := latest
+	if to-from >= syncer.blockLimit {
+		to = from + syncer.blockLimit - 1
+	}
+	if err := syncer.load(notifications, from, to); err != nil {
+		return false, err
+	}
+	return to == latest, nil
+}
+
+// subscribe to new OrderEvents. OrderEvents logged between the last poll and
+// the start of the subscription are loaded, so that no OrderEvents are missed.
+func (syncer *eventSyncer) subscribe(notifications *Notifications) error {
+	subscription, err := syncer.contractBinder.SubscribeOrderEvents(syncer.events)
+	if err != nil {
+		// Subscriptions are not supported, so keep polling
+		if syncer.subscribeErr == nil {
+			log.Printf("[info] (sync) cannot subscribe to order events, polling instead: %v", err)
+		}
+		syncer.subscribeErr = err
+		return nil
+	}
+	syncer.subscription = subscription
+	syncer.subscribeErr = nil
+
+	latest, err := syncer.contractBinder.LatestBlockNumber()
+	if err != nil {
+		syncer.unsubscribe()
+		return fmt.Errorf("cannot load latest block number: %v", err)
+	}
+	syncer.subscribedBlock = latest
+
+	pointer, err := syncer.pointerStore.BlockPointer()
+	if err != nil {
+		syncer.unsubscribe()
+		return fmt.Errorf("cannot load block pointer: %v", err)
+	}
+	if pointer.Number < latest {
+		if err := syncer.load(notifications, pointer.Number+1, latest); err != nil {
+			syncer.unsubscribe()
+			return err
+		}
+	}
+	log.Printf("[info] (sync) subscribed to order events from block = %v", latest+1)
+	return nil
+}
+
+func (syncer *eventSyncer) unsubscribe() {
+	if syncer.subscription != nil {
+		syncer.subscription.Unsubscribe()
+	}
+	syncer.subscription = nil
+
+	// Drop the OrderEvents that have not been received, they will be loaded
+	// again by polling
+	for {
+		select {
+		case <-syncer.events:
+		default:
+			return
+		}
+	}
+}
+
+// receive the OrderEvents that have been delivered by the subscription. The
+// BlockPointer is moved to the block before the last OrderEvent, because more
+// OrderEvents can still be delivered for the block of the last OrderEvent.
+// OrderEvents that are delivered out of block order have been logged by a
+// reorganisation, so the subscription is closed and the next Sync rolls back
+// the replaced blocks.
+func (syncer *eventSyncer) receive(notifications *Notifications) {
+	events := []OrderEvent{}
+	for receiving := true; receiving; {
+		select {
+		case event := <-syncer.events:
+			// OrderEvents before the subscription have already been loaded
+			if event.BlockNumber <= syncer.subscribedBlock {
+				continue
+			}
+			if syncer.isReorganised(event, events) {
+				log.Printf("[info] (sync) order event subscription reorganised at block = %v", event.BlockNumber)
+				syncer.unsubscribe()
+				receiving = false
+				continue
+			}
+			events = append(events, event)
+		default:
+			receiving = false
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+	syncer.handleEvents(notifications, events)
+
+	if err := syncer.storeBlockPointer(events[len(events)-1].BlockNumber - 1); err != nil {
+		log.Printf("[error] (sync) %v", err)
+	}
+}
+
+// isReorganised returns true if an OrderEvent is not from a block after the
+// blocks of the OrderEvents that have already been received.
+func (syncer *eventSyncer) isReorganised(event OrderEvent, events []OrderEvent) bool {
+	prev := BlockPointer{}
+	if len(events) > 0 {
+		prev = BlockPointer{Number: events[len(events)-1].BlockNumber, Hash: events[len(events)-1].BlockHash}
+	} else if len(syncer.checkpoints) > 0 {
+		prev = syncer.checkpoints[len(syncer.checkpoints)-1]
+	}
+	if event.BlockNumber == prev.Number {
+		return event.BlockHash != prev.Hash
+	}
+	return event.BlockNumber < prev.Number
+}
+
+// load the OrderEvents between two blocks, inclusive, and store the last block
+// as the BlockPointer. The hash of the last block is loaded before the
+// OrderEvents, so that a reorganisation while loading the OrderEvents is
+// detected by the next Sync.
+func (syncer *eventSyncer) load(notifications *Notifications, from, to uint64) error {
+	hash, err := syncer.contractBinder.BlockHash(to)
+	if err != nil {
+		return fmt.Errorf("cannot load hash of block = %v: %v", to, err)
+	}
+	events, err := syncer.contractBinder.OrderEvents(from, to)
+	if err != nil {
+		return fmt.Errorf("cannot load order events from block = %v to block = %v: %v", from, to, err)
+	}
+	syncer.handleEvents(notifications, events)
+
+	// Store the resulting pointer so that we do not re-sync blocks next time
+	pointer := BlockPointer{Number: to, Hash: hash}
+	if err := syncer.pointerStore.PutBlockPointer(pointer); err != nil {
+		log.Printf("[error] (sync) cannot store block pointer: %v", err)
+	}
+	syncer.checkpoint(pointer)
+	return nil
+}
+
+// storeBlockPointer stores the block with the given number as the
+// BlockPointer, anchored to the hash of the block.
+func (syncer *eventSyncer) storeBlockPointer(number uint64) error {
+	hash, err := syncer.contractBinder.BlockHash(number)
+	if err != nil {
+		return fmt.Errorf("cannot load hash of block = %v: %v", number, err)
+	}
+	pointer := BlockPointer{Number: number, Hash: hash}
+	if err := syncer.pointerStore.PutBlockPointer(pointer); err != nil {
+		return fmt.Errorf("cannot store block pointer: %v", err)
+	}
+	syncer.checkpoint(pointer)
+	return nil
+}
+
+// checkpoint a block so that a reorganisation that replaces the block can be
+// detected. Checkpoints, and OrderEvents, that are older than the
+// MaxReorganisationDepth are forgotten.
+func (syncer *eventSyncer) checkpoint(pointer BlockPointer) {
+	if pointer.Number == 0 {
+		return
+	}
+	i := len(syncer.checkpoints)
+	for i > 0 && syncer.checkpoints[i-1].Number >= pointer.Number {
+		i--
+	}
+	if i < len(syncer.checkpoints) && syncer.checkpoints[i].Number == pointer.Number {
+		// The block has already been checkpointed
+		return
+	}
+	syncer.checkpoints = append(syncer.checkpoints, BlockPointer{})
+	copy(syncer.checkpoints[i+1:], syncer.checkpoints[i:])
+	syncer.checkpoints[i] = pointer
+
+	newest := syncer.checkpoints[len(syncer.checkpoints)-1]
+	for len(syncer.checkpoints) > 1 && syncer.checkpoints[0].Number+MaxReorganisationDepth < newest.Number {
+		syncer.checkpoints = syncer.checkpoints[1:]
+	}
+	for len(syncer.recentEvents) > 0 && syncer.recentEvents[0].BlockNumber < syncer.checkpoints[0].Number {
+		syncer.recentEvents = syncer.recentEvents[1:]
+	}
+}
+
+func (syncer *eventSyncer) handleEvents(notifications *Notifications, events []OrderEvent) {
+	// Logging data
+	numOpenOrders := 0
+	numConfirmedOrders := 0
+	numCanceledOrders := 0
+	numUnknownOrders := 0
+	defer func() {
+		if numOpenOrders > 0 {
+			log.Printf("[info] (sync) opened = %v", numOpenOrders)
+		}
+		if numConfirmedOrders > 0 {
+			log.Printf("[info] (sync) confirmed = %v", numConfirmedOrders)
+		}
+		if numCanceledOrders > 0 {
+			log.Printf("[info] (sync) canceled = %v", numCanceledOrders)
+		}
+		if numUnknownOrders > 0 {
+			log.Printf("[info] (sync) unknown = %v", numUnknownOrders)
+		}
+	}()
+
+	for _, event := range events {
+		switch event.Status {
+		case order.Open:
+			numOpenOrders++
+			notification := NotificationOpenOrder{OrderID: event.OrderID, Trader: event.Trader, Priority: event.Priority}
+			*notifications = append(*notifications, notification)
+		case order.Confirmed:
+			numConfirmedOrders++
+			syncer.deleteOrder(event.OrderID)
+			notification := NotificationConfirmOrder{OrderID: event.OrderID}
+			*notifications = append(*notifications, notification)
+		case order.Canceled:
+			numCanceledOrders++
+			syncer.deleteOrder(event.OrderID)
+			notification := NotificationCancelOrder{OrderID: event.OrderID}
+			*notifications = append(*notifications, notification)
+		default:
+			numUnknownOrders++
+			continue
+		}
+		syncer.recentEvents = append(syncer.recentEvents, event)
+		syncer.checkpoint(BlockPointer{Number: event.BlockNumber, Hash: event.BlockHash})
+	}
+}
+
+// handleRollback of OrderEvents that have been removed from the blockchain.
+// The OrderEvents are rolled back in the reverse of the order in which they
+// were handled, so that each order is restored to the order.Status that it
+// had before its first removed OrderEvent.
+func (syncer *eventSyncer) handleRollback(notifications *Notifications, events []OrderEvent) {
+	numRolledBackOrders := 0
+	defer func() {
+		if numRolledBackOrders > 0 {
+			log.Printf("[info] (sync) rolled back = %v", numRolledBackOrders)
+		}
+	}()
+
+	for i := len(events) - 1; i >= 0; i-- {
+		numRolledBackOrders++
+		notification := NotificationRollbackOrder{OrderID: events[i].OrderID, Status: order.Open}
+		if events[i].Status == order.Open {
+			syncer.deleteOrder(events[i].OrderID)
+			notification.Status = order.Nil
+		}
+		*notifications = append(*notifications, notification)
+	}
+}
+
+func (syncer *eventSyncer) deleteOrder(orderID order.ID) {
+	if err := syncer.orderStore.DeleteOrder(orderID); err != nil {
+		log.Printf("[error] (sync) cannot delete order: %v", err)
+	}
+}
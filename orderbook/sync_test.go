@@ -2,6 +2,9 @@ package orderbook_test
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -248,11 +251,191 @@ var _ = Describe("Syncer", func() {
 	})
 })
 
+var _ = Describe("Event syncer", func() {
+
+	var (
+		contract *eventBinder
+		storer   *leveldb.Store
+		syncer   Syncer
+	)
+
+	BeforeEach(func() {
+		var err error
+		contract = newEventBinder()
+		storer, err = leveldb.NewStore("./tmp/events.out", 24*time.Hour, time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		syncer = NewEventSyncer(storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), contract, 10)
+	})
+
+	AfterEach(func() {
+		storer.Release()
+		os.RemoveAll("./tmp")
+	})
+
+	Context("when subscriptions are unavailable", func() {
+
+		It("should poll for order events and checkpoint the last block", func() {
+			contract.subscribeErr = errors.New("notifications not supported")
+			ord := testutils.RandomOrder()
+			contract.logEvent(OrderEvent{OrderID: ord.ID, Status: order.Open, Trader: "trader", Priority: 1}, 5)
+			contract.logEvent(OrderEvent{OrderID: ord.ID, Status: order.Confirmed}, 25)
+
+			// The first poll is limited to 10 blocks
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(1))
+			Expect(notifications[0]).Should(Equal(NotificationOpenOrder{OrderID: ord.ID, Trader: "trader", Priority: 1}))
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(10)))
+
+			notifications, err = syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(BeEmpty())
+
+			notifications, err = syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(1))
+			Expect(notifications[0]).Should(Equal(NotificationConfirmOrder{OrderID: ord.ID}))
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(25)))
+
+			notifications, err = syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(BeEmpty())
+		})
+
+		It("should resume from the checkpoint after a reboot", func() {
+			contract.subscribeErr = errors.New("notifications not supported")
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Open}, 2)
+			Expect(syncer.Sync()).Should(HaveLen(1))
+
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Canceled}, 3)
+			syncer = NewEventSyncer(storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), contract, 10)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(1))
+			Expect(notifications[0]).Should(BeAssignableToTypeOf(NotificationCancelOrder{}))
+		})
+	})
+
+	Context("when subscriptions are available", func() {
+
+		It("should receive order events from the subscription without polling", func() {
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Open}, 1)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(1))
+			Expect(contract.subscribed()).Should(BeTrue())
+
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Open}, 2)
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Canceled}, 3)
+			polls := contract.polls
+			notifications, err = syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(2))
+			Expect(contract.polls).Should(Equal(polls))
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(2)))
+		})
+
+		It("should fall back to polling when the subscription fails", func() {
+			Expect(syncer.Sync()).Should(BeEmpty())
+			Expect(contract.subscribed()).Should(BeTrue())
+
+			contract.subscribeErr = errors.New("connection lost")
+			contract.failSubscription(errors.New("connection lost"))
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Open}, 1)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(HaveLen(1))
+			Expect(contract.subscribed()).Should(BeFalse())
+		})
+	})
+
+	Context("when the chain is reorganised", func() {
+
+		It("should roll back the order events in the replaced blocks", func() {
+			contract.subscribeErr = errors.New("notifications not supported")
+			ordA, ordB, ordC := testutils.RandomOrder(), testutils.RandomOrder(), testutils.RandomOrder()
+			contract.logEvent(OrderEvent{OrderID: ordA.ID, Status: order.Open}, 3)
+			contract.logEvent(OrderEvent{OrderID: ordA.ID, Status: order.Confirmed}, 8)
+			contract.logEvent(OrderEvent{OrderID: ordB.ID, Status: order.Open}, 9)
+			Expect(syncer.Sync()).Should(HaveLen(3))
+
+			// Replace blocks 8 and 9, and extend the chain to block 10
+			contract.fork(8)
+			contract.logEvent(OrderEvent{OrderID: ordC.ID, Status: order.Open}, 8)
+			contract.logEvent(OrderEvent{OrderID: ordB.ID, Status: order.Open}, 10)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(Equal(Notifications{
+				NotificationRollbackOrder{OrderID: ordB.ID, Status: order.Nil},
+				NotificationRollbackOrder{OrderID: ordA.ID, Status: order.Open},
+				NotificationOpenOrder{OrderID: ordC.ID},
+				NotificationOpenOrder{OrderID: ordB.ID},
+			}))
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(10)))
+
+			notifications, err = syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(BeEmpty())
+		})
+
+		It("should roll back the order events received from the subscription", func() {
+			ordA, ordB, ordC := testutils.RandomOrder(), testutils.RandomOrder(), testutils.RandomOrder()
+			contract.logEvent(OrderEvent{OrderID: ordA.ID, Status: order.Open}, 1)
+			Expect(syncer.Sync()).Should(HaveLen(1))
+			Expect(contract.subscribed()).Should(BeTrue())
+			contract.logEvent(OrderEvent{OrderID: ordB.ID, Status: order.Open}, 2)
+			Expect(syncer.Sync()).Should(HaveLen(1))
+
+			// Replace block 2, the replacement is delivered by the
+			// subscription but must not be handled before the rollback
+			contract.fork(2)
+			contract.logEvent(OrderEvent{OrderID: ordC.ID, Status: order.Open}, 2)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(Equal(Notifications{
+				NotificationRollbackOrder{OrderID: ordB.ID, Status: order.Nil},
+				NotificationOpenOrder{OrderID: ordC.ID},
+			}))
+			Expect(contract.subscribed()).Should(BeTrue())
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(2)))
+		})
+
+		It("should resync from before the checkpoint after a reboot", func() {
+			contract.subscribeErr = errors.New("notifications not supported")
+			ordA, ordB, ordC := testutils.RandomOrder(), testutils.RandomOrder(), testutils.RandomOrder()
+			contract.logEvent(OrderEvent{OrderID: ordA.ID, Status: order.Open}, 2)
+			contract.logEvent(OrderEvent{OrderID: ordB.ID, Status: order.Open}, 5)
+			Expect(syncer.Sync()).Should(HaveLen(2))
+
+			contract.fork(4)
+			contract.logEvent(OrderEvent{OrderID: ordC.ID, Status: order.Open}, 6)
+			syncer = NewEventSyncer(storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), contract, 10)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(Equal(Notifications{
+				NotificationOpenOrder{OrderID: ordA.ID},
+				NotificationOpenOrder{OrderID: ordC.ID},
+			}))
+		})
+
+		It("should anchor a checkpoint that was stored without a hash", func() {
+			contract.subscribeErr = errors.New("notifications not supported")
+			contract.logEvent(OrderEvent{OrderID: testutils.RandomOrder().ID, Status: order.Open}, 5)
+			Expect(storer.OrderbookPointerStore().PutBlockPointer(BlockPointer{Number: 5})).ShouldNot(HaveOccurred())
+
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notifications).Should(BeEmpty())
+			Expect(storer.OrderbookPointerStore().BlockPointer()).Should(Equal(contract.pointer(5)))
+		})
+	})
+})
+
 // Send encrypted order fragments to the orderbook
 func sendOrdersToOrderbook(orders []order.Order, key crypto.RsaKey, orderbook Orderbook, depth order.FragmentEpochDepth) error {
 
 	for _, ord := range orders {
-		fragments, err := ord.Split(5, 4)
+		fragments, err := ord.SplitWithCommitments(rand.Reader, 5, 4)
 		if err != nil {
 			return err
 		}
@@ -268,3 +451,145 @@ func sendOrdersToOrderbook(orders []order.Order, key crypto.RsaKey, orderbook Or
 	}
 	return nil
 }
+
+// eventBinder is a mock ContractEventBinder that logs OrderEvents in blocks,
+// and delivers them to a subscription. The chain can be forked to simulate a
+// reorganisation.
+type eventBinder struct {
+	mu           *sync.Mutex
+	latest       uint64
+	events       []OrderEvent
+	forks        []uint64
+	polls        int
+	subscribeErr error
+	subscription *eventSubscription
+}
+
+func newEventBinder() *eventBinder {
+	return &eventBinder{
+		mu:     new(sync.Mutex),
+		events: []OrderEvent{},
+	}
+}
+
+func (binder *eventBinder) logEvent(event OrderEvent, blockNumber uint64) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	event.BlockNumber = blockNumber
+	event.BlockHash = binder.blockHash(blockNumber)
+	binder.events = append(binder.events, event)
+	if blockNumber > binder.latest {
+		binder.latest = blockNumber
+	}
+	if binder.subscription != nil {
+		binder.subscription.events <- event
+	}
+}
+
+// fork the chain at a block, replacing the block and all later blocks, and
+// removing the OrderEvents that were logged in them.
+func (binder *eventBinder) fork(blockNumber uint64) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	events := []OrderEvent{}
+	for _, event := range binder.events {
+		if event.BlockNumber < blockNumber {
+			events = append(events, event)
+		}
+	}
+	binder.events = events
+	binder.forks = append(binder.forks, blockNumber)
+}
+
+// pointer returns the BlockPointer to a block in the current chain.
+func (binder *eventBinder) pointer(blockNumber uint64) BlockPointer {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	return BlockPointer{Number: blockNumber, Hash: binder.blockHash(blockNumber)}
+}
+
+func (binder *eventBinder) blockHash(blockNumber uint64) [32]byte {
+	hash := [32]byte{}
+	binary.BigEndian.PutUint64(hash[:8], blockNumber)
+	for _, fork := range binder.forks {
+		if fork <= blockNumber {
+			hash[8]++
+		}
+	}
+	return hash
+}
+
+func (binder *eventBinder) failSubscription(err error) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	binder.subscription.errs <- err
+	binder.subscription = nil
+}
+
+func (binder *eventBinder) subscribed() bool {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	return binder.subscription != nil
+}
+
+func (binder *eventBinder) LatestBlockNumber() (uint64, error) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	return binder.latest, nil
+}
+
+func (binder *eventBinder) BlockHash(blockNumber uint64) ([32]byte, error) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	if blockNumber > binder.latest {
+		return [32]byte{}, fmt.Errorf("block = %v not found", blockNumber)
+	}
+	return binder.blockHash(blockNumber), nil
+}
+
+func (binder *eventBinder) OrderEvents(fromBlock, toBlock uint64) ([]OrderEvent, error) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	binder.polls++
+	events := []OrderEvent{}
+	for _, event := range binder.events {
+		if event.BlockNumber >= fromBlock && event.BlockNumber <= toBlock {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (binder *eventBinder) SubscribeOrderEvents(events chan<- OrderEvent) (OrderEventSubscription, error) {
+	binder.mu.Lock()
+	defer binder.mu.Unlock()
+
+	if binder.subscribeErr != nil {
+		return nil, binder.subscribeErr
+	}
+	binder.subscription = &eventSubscription{
+		events: events,
+		errs:   make(chan error, 1),
+	}
+	return binder.subscription, nil
+}
+
+type eventSubscription struct {
+	events chan<- OrderEvent
+	errs   chan error
+}
+
+func (subscription *eventSubscription) Unsubscribe() {
+}
+
+func (subscription *eventSubscription) Err() <-chan error {
+	return subscription.errs
+}
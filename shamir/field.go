@@ -0,0 +1,190 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/crypto"
+)
+
+// ErrMalformedBigShare is returned when a BigShare cannot be marshaled to, or
+// unmarshaled from, binary.
+var ErrMalformedBigShare = errors.New("malformed big share")
+
+// BigShareLength is the number of bytes in a BigShare that has been marshaled
+// to binary.
+const BigShareLength = 8 + 32
+
+// Prime256 is the 256-bit prime number used to define the larger finite field.
+// Values in this field are large enough to represent prices and volumes
+// exactly.
+var Prime256, _ = big.NewInt(0).SetString("115792089237316195423570985008687907853269984665640564039457584007908834671663", 10)
+
+// A BigShare is a Share of a secret in the finite field defined by Prime256.
+type BigShare struct {
+	Index uint64
+	Value *big.Int
+}
+
+// BigShares are a slice of BigShare structs.
+type BigShares []BigShare
+
+// Sub one BigShare from another within the finite field and return the result.
+// The index of the result will always be set to the receiver index.
+func (share *BigShare) Sub(arg *BigShare) BigShare {
+	value := big.NewInt(0).Sub(share.Value, arg.Value)
+	return BigShare{
+		Index: share.Index,
+		Value: value.Mod(value, Prime256),
+	}
+}
+
+// Equal returns true when both BigShares are equal. Otherwise, it returns
+// false.
+func (share *BigShare) Equal(other *BigShare) bool {
+	if share.Value == nil || other.Value == nil {
+		return share.Index == other.Index && share.Value == other.Value
+	}
+	return share.Index == other.Index &&
+		share.Value.Cmp(other.Value) == 0
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (share BigShare) MarshalJSON() ([]byte, error) {
+	bytes, err := share.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(bytes)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (share *BigShare) UnmarshalJSON(data []byte) error {
+	var bytes []byte
+	if err := json.Unmarshal(data, &bytes); err != nil {
+		return err
+	}
+	return share.UnmarshalBinary(bytes)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The uint64
+// index is encoded using binary.BigEndian and then the value is encoded as 32
+// big-endian bytes.
+func (share BigShare) MarshalBinary() ([]byte, error) {
+	if share.Value == nil || share.Value.Sign() < 0 || share.Value.Cmp(Prime256) >= 0 {
+		return nil, ErrMalformedBigShare
+	}
+	data := make([]byte, BigShareLength)
+	binary.BigEndian.PutUint64(data[:8], share.Index)
+	valueData := share.Value.Bytes()
+	copy(data[BigShareLength-len(valueData):], valueData)
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (share *BigShare) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrUnmarshalNilBytes
+	}
+	if len(data) != BigShareLength {
+		return ErrMalformedBigShare
+	}
+	share.Index = binary.BigEndian.Uint64(data[:8])
+	share.Value = big.NewInt(0).SetBytes(data[8:])
+	if share.Value.Cmp(Prime256) >= 0 {
+		return ErrMalformedBigShare
+	}
+	return nil
+}
+
+// Encrypt a BigShare using an rsa.PublicKey.
+func (share *BigShare) Encrypt(pubKey rsa.PublicKey) ([]byte, error) {
+	rsaKey := crypto.RsaKey{PrivateKey: &rsa.PrivateKey{PublicKey: pubKey}}
+	data, err := share.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return rsaKey.Encrypt(data)
+}
+
+// Decrypt cipher text into a BigShare using an rsa.PrivateKey.
+func (share *BigShare) Decrypt(privKey *rsa.PrivateKey, cipherText []byte) error {
+	rsaKey := crypto.RsaKey{PrivateKey: privKey}
+	plainText, err := rsaKey.Decrypt(cipherText)
+	if err != nil {
+		return err
+	}
+	return share.UnmarshalBinary(plainText)
+}
+
+// SplitBig splits a secret into BigShares in the finite field defined by
+// Prime256. N represents the number of BigShares that the secret will be split
+// into, and K represents the number of BigShares required to reconstruct the
+// secret.
+func SplitBig(n, k int64, secret *big.Int) (BigShares, error) {
+	return SplitBigWithReader(rand.Reader, n, k, secret)
+}
+
+// SplitBigWithReader splits a secret into BigShares, the same as SplitBig, but
+// draws polynomial coefficients from the io.Reader.
+func SplitBigWithReader(reader io.Reader, n, k int64, secret *big.Int) (BigShares, error) {
+	if n < k {
+		return nil, ErrNKError
+	}
+	if secret == nil || secret.Sign() < 0 || secret.Cmp(Prime256) >= 0 {
+		return nil, ErrFiniteField
+	}
+
+	coefficients := make([]*big.Int, k)
+	coefficients[0] = big.NewInt(0).Set(secret)
+	for i := int64(1); i < k; i++ {
+		coefficient, err := randomInt(reader, Prime256)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = coefficient
+	}
+
+	shares := make(BigShares, n)
+	for x := int64(1); x <= n; x++ {
+		value := evaluateInt(coefficients, x)
+		shares[x-1] = BigShare{
+			Index: uint64(x),
+			Value: value.Mod(value, Prime256),
+		}
+	}
+	return shares, nil
+}
+
+// JoinBig reconstructs a secret from BigShares, using Lagrange interpolation
+// in the finite field defined by Prime256.
+func JoinBig(shares BigShares) *big.Int {
+	secret := big.NewInt(0)
+	for i := range shares {
+		num := big.NewInt(1)
+		den := big.NewInt(1)
+		start := big.NewInt(0).SetUint64(shares[i].Index)
+		for j := range shares {
+			if i == j {
+				continue
+			}
+			next := big.NewInt(0).SetUint64(shares[j].Index)
+			num.Mul(num, next.Neg(next))
+			num.Mod(num, Prime256)
+			next.SetUint64(shares[j].Index)
+			den.Mul(den, next.Sub(start, next))
+			den.Mod(den, Prime256)
+		}
+		den.ModInverse(den, Prime256)
+		value := big.NewInt(0).Mul(shares[i].Value, num)
+		value.Mul(value, den)
+		secret.Add(secret, value)
+		secret.Mod(secret, Prime256)
+	}
+	return secret
+}
@@ -0,0 +1,173 @@
+package shamir
+
+import (
+	"errors"
+)
+
+// ErrTooManyFaults is returned when a set of Shares contains more faulty
+// Shares than can be corrected.
+var ErrTooManyFaults = errors.New("too many faulty shares")
+
+// RobustJoin Shares into a secret, correcting faulty Shares using the
+// Berlekamp-Welch algorithm. K represents the number of Shares required to
+// reconstruct the secret, and up to (len(shares) - k) / 2 faulty Shares can be
+// corrected. The reconstructed secret and the positions of the faulty Shares
+// in the slice, or an error, are returned.
+func RobustJoin(k int64, shares Shares) (uint64, []int, error) {
+	n := int64(len(shares))
+	if n < k {
+		return 0, nil, ErrNKError
+	}
+	e := (n - k) / 2
+
+	// Find the error locator polynomial E, which is monic with degree e, and
+	// the polynomial Q, with degree less than k+e, such that Q(x) = y·E(x) for
+	// every Share. This gives n linear equations in k+2e unknowns.
+	numQ := k + e
+	numCols := numQ + e
+	matrix := make([][]uint64, n)
+	for i, share := range shares {
+		x := share.Index % Prime
+		y := share.Value % Prime
+
+		row := make([]uint64, numCols+1)
+		pow := uint64(1)
+		for j := int64(0); j < numQ; j++ {
+			row[j] = pow
+			pow = mulMod(pow, x, Prime)
+		}
+		pow = uint64(1)
+		for j := int64(0); j < e; j++ {
+			row[numQ+j] = subMod(0, mulMod(y, pow, Prime), Prime)
+			pow = mulMod(pow, x, Prime)
+		}
+		row[numCols] = mulMod(y, pow, Prime)
+		matrix[i] = row
+	}
+	solution, ok := solve(matrix, numCols)
+	if !ok {
+		return 0, nil, ErrTooManyFaults
+	}
+
+	// The secret polynomial is P = Q / E, and the division must be exact
+	q := solution[:numQ]
+	errLocator := append(append([]uint64{}, solution[numQ:]...), 1)
+	p, ok := polyDivExact(q, errLocator)
+	if !ok || int64(len(p)) > k {
+		return 0, nil, ErrTooManyFaults
+	}
+
+	// Shares that are not on the secret polynomial are faulty
+	faults := []int{}
+	for i, share := range shares {
+		if polyEval(p, share.Index%Prime) != share.Value%Prime {
+			faults = append(faults, i)
+		}
+	}
+	if int64(len(faults)) > e {
+		return 0, nil, ErrTooManyFaults
+	}
+	return polyEval(p, 0), faults, nil
+}
+
+// solve a system of linear equations in the finite field using Gaussian
+// elimination. Each row of the matrix holds the coefficients of the numCols
+// unknowns followed by the constant term. If the system is underdetermined
+// then free unknowns are set to zero. Returns false if the system is
+// inconsistent.
+func solve(matrix [][]uint64, numCols int64) ([]uint64, bool) {
+	pivots := make([]int64, 0, numCols)
+	row := 0
+	for col := int64(0); col < numCols && row < len(matrix); col++ {
+		// Find a row with a non-zero coefficient for this column
+		pivot := -1
+		for i := row; i < len(matrix); i++ {
+			if matrix[i][col] != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		matrix[row], matrix[pivot] = matrix[pivot], matrix[row]
+
+		// Normalise the pivot row and eliminate the column from all other rows
+		inv := invMod(matrix[row][col], Prime)
+		for j := col; j <= numCols; j++ {
+			matrix[row][j] = mulMod(matrix[row][j], inv, Prime)
+		}
+		for i := range matrix {
+			if i == row || matrix[i][col] == 0 {
+				continue
+			}
+			factor := matrix[i][col]
+			for j := col; j <= numCols; j++ {
+				matrix[i][j] = subMod(matrix[i][j], mulMod(factor, matrix[row][j], Prime), Prime)
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+
+	// Any remaining row with a non-zero constant term is inconsistent
+	for i := row; i < len(matrix); i++ {
+		if matrix[i][numCols] != 0 {
+			return nil, false
+		}
+	}
+
+	solution := make([]uint64, numCols)
+	for i, col := range pivots {
+		solution[col] = matrix[i][numCols]
+	}
+	return solution, true
+}
+
+// polyDivExact divides the polynomial num by the monic polynomial den, where
+// polynomials are stored as coefficients in order of increasing degree. The
+// quotient is returned with trailing zero coefficients removed, and false is
+// returned if the remainder is non-zero.
+func polyDivExact(num, den []uint64) ([]uint64, bool) {
+	rem := append([]uint64{}, num...)
+	degDen := len(den) - 1
+	if len(rem) <= degDen {
+		for _, coefficient := range rem {
+			if coefficient != 0 {
+				return nil, false
+			}
+		}
+		return []uint64{}, true
+	}
+
+	quot := make([]uint64, len(rem)-degDen)
+	for i := len(quot) - 1; i >= 0; i-- {
+		coefficient := rem[i+degDen]
+		quot[i] = coefficient
+		if coefficient == 0 {
+			continue
+		}
+		for j := 0; j <= degDen; j++ {
+			rem[i+j] = subMod(rem[i+j], mulMod(coefficient, den[j], Prime), Prime)
+		}
+	}
+	for _, coefficient := range rem[:degDen] {
+		if coefficient != 0 {
+			return nil, false
+		}
+	}
+
+	for len(quot) > 0 && quot[len(quot)-1] == 0 {
+		quot = quot[:len(quot)-1]
+	}
+	return quot, true
+}
+
+// polyEval evaluates a polynomial at x using Horner's method.
+func polyEval(coefficients []uint64, x uint64) uint64 {
+	accum := uint64(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		accum = addMod(mulMod(accum, x, Prime), coefficients[i], Prime)
+	}
+	return accum
+}
@@ -2,12 +2,13 @@ package shamir
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/rsa"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"io"
 	"math/big"
-	"math/rand"
 
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/stackint"
@@ -50,6 +51,33 @@ func (share *Share) Sub(arg *Share) Share {
 	}
 }
 
+// Add one share to another within the finite field and return the result.
+// The index of the result will always be set to the receiver index.
+func (share *Share) Add(arg *Share) Share {
+	return Share{
+		Index: share.Index,
+		Value: addMod(share.Value, arg.Value, Prime),
+	}
+}
+
+// AddConstant adds a public constant to the secret of a share within the
+// finite field and returns the result.
+func (share *Share) AddConstant(constant uint64) Share {
+	return Share{
+		Index: share.Index,
+		Value: addMod(share.Value, constant%Prime, Prime),
+	}
+}
+
+// Mul multiplies the secret of a share by a public scalar within the finite
+// field and returns the result.
+func (share *Share) Mul(scalar uint64) Share {
+	return Share{
+		Index: share.Index,
+		Value: mulMod(share.Value, scalar%Prime, Prime),
+	}
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (share Share) MarshalJSON() ([]byte, error) {
 	bytes, err := share.MarshalBinary()
@@ -127,8 +155,17 @@ type Shares []Share
 
 // Split a secret into Shares. N represents the number of Shares that the
 // secret will be split into, and K represents the number of Share required to
-// reconstruct the secret. A slice of Shares, or an error, is returned.
+// reconstruct the secret. Polynomial coefficients are drawn from a
+// cryptographically secure source of randomness. A slice of Shares, or an
+// error, is returned.
 func Split(n, k int64, secret uint64) (Shares, error) {
+	return SplitWithReader(rand.Reader, n, k, secret)
+}
+
+// SplitWithReader splits a secret into Shares, the same as Split, but draws
+// polynomial coefficients from the io.Reader. The io.Reader must be a
+// cryptographically secure source of randomness, except during testing.
+func SplitWithReader(reader io.Reader, n, k int64, secret uint64) (Shares, error) {
 	// Validate the encoding by checking that N is greater than K, and that the
 	// secret is within the finite field.
 	if n < k {
@@ -144,10 +181,11 @@ func Split(n, k int64, secret uint64) (Shares, error) {
 	coefficients[0] = secret
 
 	for i := int64(1); i < k; i++ {
-		coefficients[i] = rand.Uint64()
-		for coefficients[i] >= Prime {
-			coefficients[i] = rand.Uint64()
+		coefficient, err := RandomFieldElement(reader)
+		if err != nil {
+			return nil, err
 		}
+		coefficients[i] = coefficient
 	}
 
 	// Create N shares.
@@ -178,6 +216,21 @@ func Split(n, k int64, secret uint64) (Shares, error) {
 	return shares, nil
 }
 
+// RandomFieldElement returns a uniformly random element of the finite field,
+// drawn from the io.Reader. Values that are not in the finite field are
+// rejected and drawn again, to avoid biasing the result.
+func RandomFieldElement(reader io.Reader) (uint64, error) {
+	data := [8]byte{}
+	for {
+		if _, err := io.ReadFull(reader, data[:]); err != nil {
+			return 0, err
+		}
+		if value := binary.BigEndian.Uint64(data[:]); value < Prime {
+			return value, nil
+		}
+	}
+}
+
 // Join Shares into a secret. Prime is used to define the finite field from
 // which the secret was selected. The reconstructed secret, or an error, is
 // returned.
@@ -264,12 +317,54 @@ func invMod(x uint64, mod uint64) uint64 {
 	return uint64(r)
 }
 
+// Blindings are a slice of Blinding structs.
 type Blindings []Blinding
 
+// A Blinding is the random exponent used to hide a Share inside a Commitment.
 type Blinding struct {
 	*big.Int
 }
 
+// Sub one Blinding from another and return the result. Blindings are exponents
+// that are evaluated over the integers, so the result is not reduced and can
+// be negative. A nil Blinding is treated as zero.
+func (b *Blinding) Sub(arg *Blinding) Blinding {
+	result := big.NewInt(0)
+	if b.Int != nil {
+		result.Set(b.Int)
+	}
+	if arg.Int != nil {
+		result.Sub(result, arg.Int)
+	}
+	return Blinding{Int: result}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. A sign byte
+// is encoded first, followed by the big-endian bytes of the absolute value of
+// the Blinding.
+func (b Blinding) MarshalBinary() ([]byte, error) {
+	if b.Int == nil {
+		return []byte{0}, nil
+	}
+	sign := byte(0)
+	if b.Int.Sign() < 0 {
+		sign = 1
+	}
+	return append([]byte{sign}, b.Int.Bytes()...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *Blinding) UnmarshalBinary(data []byte) error {
+	if data == nil || len(data) == 0 {
+		return ErrUnmarshalNilBytes
+	}
+	b.Int = big.NewInt(0).SetBytes(data[1:])
+	if data[0] == 1 {
+		b.Int.Neg(b.Int)
+	}
+	return nil
+}
+
 func (b *Blinding) Encrypt(pubKey rsa.PublicKey) ([]byte, error) {
 	rsaKey := crypto.RsaKey{PrivateKey: &rsa.PrivateKey{PublicKey: pubKey}}
 	if b.Int == nil {
@@ -316,13 +411,76 @@ func (b *Blinding) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Commitments are a slice of Commitment structs.
+type Commitments []Commitment
+
+// A Commitment is a Pedersen commitment to a Share using a Blinding.
 type Commitment struct {
 	*big.Int
 }
 
+// NewCommitment returns the Pedersen commitment g^x h^s to the value of a Share
+// x using a Blinding s. A nil Blinding is treated as zero.
 func NewCommitment(x Share, s Blinding) Commitment {
+	if s.Int == nil {
+		s.Int = big.NewInt(0)
+	}
 	gˣ := big.NewInt(0).Exp(CommitG, big.NewInt(0).SetUint64(x.Value), CommitP)
 	hˢ := big.NewInt(0).Exp(CommitH, s.Int, CommitP)
 	gˣhˢ := big.NewInt(0).Mul(gˣ, hˢ)
 	return Commitment{gˣhˢ.Mod(gˣhˢ, CommitP)}
 }
+
+// MarshalJSON implements the json.Marshaler interface. A nil Commitment is
+// marshaled to null.
+func (commitment Commitment) MarshalJSON() ([]byte, error) {
+	if commitment.Int == nil {
+		return []byte("null"), nil
+	}
+	return commitment.Int.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Unmarshaling null
+// produces a nil Commitment.
+func (commitment *Commitment) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		commitment.Int = nil
+		return nil
+	}
+	commitment.Int = big.NewInt(0)
+	return commitment.Int.UnmarshalJSON(data)
+}
+
+// Sub one Commitment from another and return the result. Pedersen commitments
+// are homomorphic, so the result commits to the difference of the two values
+// using the difference of the two Blindings. If either Commitment is nil then
+// a nil Commitment is returned.
+func (commitment Commitment) Sub(arg Commitment) Commitment {
+	if commitment.Int == nil || arg.Int == nil {
+		return Commitment{}
+	}
+	inv := big.NewInt(0).ModInverse(arg.Int, CommitP)
+	if inv == nil {
+		return Commitment{}
+	}
+	result := big.NewInt(0).Mul(commitment.Int, inv)
+	return Commitment{result.Mod(result, CommitP)}
+}
+
+// Verify that the Commitment opens to a Share using a Blinding. The values of
+// Shares are reduced into the finite field, so when the Commitment was built
+// by subtracting a larger value from a smaller one the Share will hold the
+// committed value plus Prime. Both openings are accepted.
+func (commitment Commitment) Verify(share Share, blinding Blinding) bool {
+	if commitment.Int == nil || share.Value >= Prime {
+		return false
+	}
+	got := NewCommitment(share, blinding)
+	if got.Cmp(commitment.Int) == 0 {
+		return true
+	}
+	wrapped := big.NewInt(0).Exp(CommitG, big.NewInt(0).SetUint64(Prime), CommitP)
+	wrapped.Mul(wrapped, commitment.Int)
+	wrapped.Mod(wrapped, CommitP)
+	return got.Cmp(wrapped) == 0
+}
@@ -1,6 +1,7 @@
 package shamir_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"math/big"
 	"math/rand"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/stackint"
+	"github.com/republicprotocol/republic-go/testutils"
 )
 
 var _ = Describe("Shamir's secret sharing", func() {
@@ -71,6 +73,46 @@ var _ = Describe("Shamir's secret sharing", func() {
 			}
 		})
 
+		It("should equal addition on the secrets when done on shares", func() {
+			for i := uint64(0); i < 100; i++ {
+
+				secret := (uint64(rand.Int63()) % Prime) / 2
+				secretOther := (uint64(rand.Int63()) % Prime) / 2
+
+				shares, err := Split(72, 48, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesOther, err := Split(72, 48, secretOther)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesResult := make(Shares, 72)
+				for j := 0; j < 72; j++ {
+					sharesResult[j] = shares[j].Add(&sharesOther[j])
+				}
+
+				secretResult := Join(sharesResult)
+				Expect(secretResult).Should(Equal(secret + secretOther))
+			}
+		})
+
+		It("should equal affine operations on the secret when done on shares", func() {
+			for i := uint64(0); i < 100; i++ {
+
+				secret := uint64(rand.Int31())
+				scalar := uint64(rand.Int31())
+				constant := uint64(rand.Int31())
+
+				shares, err := Split(72, 48, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesResult := make(Shares, 72)
+				for j := 0; j < 72; j++ {
+					sharesResult[j] = shares[j].Mul(scalar)
+					sharesResult[j] = sharesResult[j].AddConstant(constant)
+				}
+
+				secretResult := Join(sharesResult)
+				Expect(secretResult).Should(Equal(secret*scalar + constant))
+			}
+		})
+
 	})
 
 	Context("when marshaling and unmarshaling", func() {
@@ -195,6 +237,20 @@ var _ = Describe("Shamir's secret sharing", func() {
 			Expect(int64(len(shares))).Should(Equal(n))
 		})
 
+		It("should return the same shares when using the same deterministic reader", func() {
+			shares, err := SplitWithReader(testutils.NewDeterministicReader(42), 24, 16, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			otherShares, err := SplitWithReader(testutils.NewDeterministicReader(42), 24, 16, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(shares).Should(Equal(otherShares))
+			Expect(Join(shares[:16])).Should(Equal(uint64(1234)))
+		})
+
+		It("should return an error when the reader fails", func() {
+			_, err := SplitWithReader(bytes.NewReader([]byte{}), 24, 16, 1234)
+			Expect(err).Should(HaveOccurred())
+		})
+
 		It("should return an error when k is greater than n", func() {
 			n := int64(50)
 			k := int64(100)
@@ -317,4 +373,231 @@ var _ = Describe("Shamir's secret sharing", func() {
 			}
 		})
 	})
+
+	Context("when robustly joining", func() {
+
+		It("should return the required secret and the faulty shares", func() {
+			N := int64(24)
+			K := int64(16)
+			for i := 0; i < 10; i++ {
+				secret := uint64(rand.Int63()) % Prime
+				shares, err := Split(N, K, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				// Corrupt the maximum number of shares that can be corrected
+				faulty := rand.Perm(int(N))[:(N-K)/2]
+				for _, j := range faulty {
+					shares[j].Value = (shares[j].Value + uint64(rand.Int63n(1000)) + 1) % Prime
+				}
+				decodedSecret, faults, err := RobustJoin(K, shares)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(decodedSecret).Should(Equal(secret))
+				Expect(faults).Should(ConsistOf(faulty))
+			}
+		})
+
+		It("should return an error when there are too many faulty shares", func() {
+			N := int64(24)
+			K := int64(16)
+			secret := uint64(1234)
+			shares, err := Split(N, K, secret)
+			Expect(err).ShouldNot(HaveOccurred())
+			for j := int64(0); j < (N-K)/2+1; j++ {
+				shares[j].Value = (shares[j].Value + 1) % Prime
+			}
+			_, _, err = RobustJoin(K, shares)
+			Expect(err).Should(Equal(ErrTooManyFaults))
+		})
+
+		It("should return an error when there are not enough shares", func() {
+			shares, err := Split(24, 16, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, _, err = RobustJoin(16, shares[:15])
+			Expect(err).Should(Equal(ErrNKError))
+		})
+	})
+
+	Context("when splitting with commitments", func() {
+
+		It("should verify every share against the commitments using its opening", func() {
+			shares, openings, commitments, err := SplitWithCommitments(rand.New(rand.NewSource(0)), 24, 16, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(commitments).Should(HaveLen(16))
+			Expect(Join(shares[:16])).Should(Equal(uint64(1234)))
+			for i := range shares {
+				Expect(commitments.VerifyShare(shares[i], openings[i])).Should(BeTrue())
+			}
+		})
+
+		It("should not verify a share using a different opening", func() {
+			shares, openings, commitments, err := SplitWithCommitments(rand.New(rand.NewSource(0)), 6, 4, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			otherShare := Share{Index: shares[0].Index, Value: (shares[0].Value + 1) % Prime}
+			Expect(commitments.VerifyShare(otherShare, openings[0])).Should(BeFalse())
+			Expect(commitments.VerifyShare(shares[0], openings[1])).Should(BeFalse())
+		})
+
+		It("should open the difference of two commitments using the difference of two openings", func() {
+			lhsShares, lhsOpenings, lhsCommitments, err := SplitWithCommitments(rand.New(rand.NewSource(0)), 6, 4, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			rhsShares, rhsOpenings, rhsCommitments, err := SplitWithCommitments(rand.New(rand.NewSource(1)), 6, 4, 4321)
+			Expect(err).ShouldNot(HaveOccurred())
+			for i := range lhsShares {
+				commitment := lhsCommitments.Evaluate(lhsShares[i].Index).Sub(rhsCommitments.Evaluate(rhsShares[i].Index))
+				share := lhsShares[i].Sub(&rhsShares[i])
+				opening := lhsOpenings[i].Sub(&rhsOpenings[i])
+				Expect(commitment.VerifyOpening(share, opening)).Should(BeTrue())
+				Expect(commitment.VerifyOpening(share, lhsOpenings[i])).Should(BeFalse())
+			}
+		})
+
+		It("should equal itself after marshaling and unmarshaling an opening to binary", func() {
+			_, openings, _, err := SplitWithCommitments(rand.New(rand.NewSource(0)), 6, 4, 1234)
+			Expect(err).ShouldNot(HaveOccurred())
+			data, err := openings[0].MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+			opening := Opening{}
+			Expect(opening.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+			Expect(opening.Value.Cmp(openings[0].Value)).Should(Equal(0))
+			Expect(opening.Blinding.Cmp(openings[0].Blinding.Int)).Should(Equal(0))
+		})
+	})
+
+	Context("when committing to shares", func() {
+
+		It("should verify a commitment using the committed share and blinding", func() {
+			for i := uint64(0); i < 100; i++ {
+				share := Share{
+					Index: uint64(rand.Int63()),
+					Value: uint64(rand.Int63()) % Prime,
+				}
+				blinding := Blinding{big.NewInt(rand.Int63())}
+				commitment := NewCommitment(share, blinding)
+				Expect(commitment.Verify(share, blinding)).Should(BeTrue())
+			}
+		})
+
+		It("should not verify a commitment using a different share or blinding", func() {
+			for i := uint64(0); i < 100; i++ {
+				share := Share{
+					Index: uint64(rand.Int63()),
+					Value: uint64(rand.Int63()) % Prime,
+				}
+				blinding := Blinding{big.NewInt(rand.Int63())}
+				commitment := NewCommitment(share, blinding)
+
+				otherShare := Share{Index: share.Index, Value: (share.Value + 1) % Prime}
+				Expect(commitment.Verify(otherShare, blinding)).Should(BeFalse())
+				otherBlinding := Blinding{big.NewInt(0).Add(blinding.Int, big.NewInt(1))}
+				Expect(commitment.Verify(share, otherBlinding)).Should(BeFalse())
+			}
+		})
+
+		It("should verify the subtraction of shares using the subtraction of commitments", func() {
+			for i := uint64(0); i < 100; i++ {
+				lhs := Share{Index: 1, Value: uint64(rand.Int63()) % Prime}
+				rhs := Share{Index: 1, Value: uint64(rand.Int63()) % Prime}
+				lhsBlinding := Blinding{big.NewInt(rand.Int63())}
+				rhsBlinding := Blinding{big.NewInt(rand.Int63())}
+				lhsCommitment := NewCommitment(lhs, lhsBlinding)
+				rhsCommitment := NewCommitment(rhs, rhsBlinding)
+
+				// Both orderings are checked so that the subtraction wraps
+				// around the finite field in one of them
+				commitment := lhsCommitment.Sub(rhsCommitment)
+				Expect(commitment.Verify(lhs.Sub(&rhs), lhsBlinding.Sub(&rhsBlinding))).Should(BeTrue())
+				commitment = rhsCommitment.Sub(lhsCommitment)
+				Expect(commitment.Verify(rhs.Sub(&lhs), rhsBlinding.Sub(&lhsBlinding))).Should(BeTrue())
+			}
+		})
+
+		It("should equal itself after marshaling and unmarshaling a negative blinding to binary", func() {
+			for i := uint64(0); i < 100; i++ {
+				blinding := Blinding{big.NewInt(rand.Int63() - rand.Int63())}
+				data, err := blinding.MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				unmarshaledBlinding := Blinding{}
+				Expect(unmarshaledBlinding.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(blinding.Cmp(unmarshaledBlinding.Int)).Should(Equal(0))
+			}
+		})
+
+		It("should equal itself after marshaling and unmarshaling to JSON", func() {
+			commitments := Commitments{NewCommitment(Share{Index: 1, Value: 42}, Blinding{big.NewInt(1234)}), Commitment{}}
+			data, err := json.Marshal(commitments)
+			Expect(err).ShouldNot(HaveOccurred())
+			newCommitments := Commitments{}
+			Expect(json.Unmarshal(data, &newCommitments)).ShouldNot(HaveOccurred())
+			Expect(newCommitments).Should(HaveLen(2))
+			Expect(newCommitments[0].Cmp(commitments[0].Int)).Should(Equal(0))
+			Expect(newCommitments[1].Int).Should(BeNil())
+		})
+	})
+
+	Context("when splitting and joining in the 256-bit field", func() {
+
+		var n = int64(24)
+		var k = 2 * (n + 1) / 3
+		var secrets = []*big.Int{
+			big.NewInt(0),
+			big.NewInt(0).SetUint64(Prime),
+			big.NewInt(0).Sub(Prime256, big.NewInt(1)),
+		}
+
+		It("should return the required secret from the threshold of shares", func() {
+			for _, secret := range secrets {
+				shares, err := SplitBig(n, k, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(shares).Should(HaveLen(int(n)))
+				Expect(JoinBig(shares[n-k:]).Cmp(secret)).Should(Equal(0))
+			}
+		})
+
+		It("should not return the required secret from less than the threshold of shares", func() {
+			secret := big.NewInt(0).SetUint64(Prime)
+			shares, err := SplitBig(n, k, secret)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(JoinBig(shares[:k-1]).Cmp(secret)).ShouldNot(Equal(0))
+		})
+
+		It("should equal subtraction on the secrets when done on shares", func() {
+			lhs, err := SplitBig(n, k, big.NewInt(1337))
+			Expect(err).ShouldNot(HaveOccurred())
+			rhs, err := SplitBig(n, k, big.NewInt(42))
+			Expect(err).ShouldNot(HaveOccurred())
+			shares := make(BigShares, n)
+			for i := range shares {
+				shares[i] = lhs[i].Sub(&rhs[i])
+			}
+			Expect(JoinBig(shares[:k]).Cmp(big.NewInt(1295))).Should(Equal(0))
+		})
+
+		It("should return an error when the secret is greater than the prime", func() {
+			_, err := SplitBig(n, k, Prime256)
+			Expect(err).Should(Equal(ErrFiniteField))
+		})
+
+		It("should equal itself after marshaling and unmarshaling in binary and JSON", func() {
+			shares, err := SplitBig(n, k, big.NewInt(42))
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, share := range shares {
+				data, err := share.MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(data).Should(HaveLen(BigShareLength))
+				unmarshaledShare := BigShare{}
+				Expect(unmarshaledShare.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(share.Equal(&unmarshaledShare)).Should(BeTrue())
+
+				data, err = json.Marshal(share)
+				Expect(err).ShouldNot(HaveOccurred())
+				unmarshaledShare = BigShare{}
+				Expect(json.Unmarshal(data, &unmarshaledShare)).ShouldNot(HaveOccurred())
+				Expect(share.Equal(&unmarshaledShare)).Should(BeTrue())
+			}
+		})
+
+		It("should return an error when unmarshaling a value outside of the field", func() {
+			data := bytes.Repeat([]byte{0xFF}, BigShareLength)
+			Expect(new(BigShare).UnmarshalBinary(data)).Should(Equal(ErrMalformedBigShare))
+		})
+	})
 })
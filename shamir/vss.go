@@ -0,0 +1,277 @@
+package shamir
+
+import (
+	"crypto/rsa"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/crypto"
+)
+
+// ErrMalformedOpening is returned when an Opening cannot be marshaled to, or
+// unmarshaled from, binary.
+var ErrMalformedOpening = errors.New("malformed opening")
+
+// maskBits is the number of additional random bits used to statistically hide
+// values that are evaluated over the integers, instead of being reduced
+// modulo the order of the commitment group.
+const maskBits = 128
+
+// An Opening allows the holder of a Share to verify it against the Commitments
+// to the coefficients of the polynomial that created the Share. Commitments
+// are computed in the multiplicative group of integers modulo CommitP. Its
+// order, CommitP - 1, is public, but it is not a multiple of Prime, so the
+// arithmetic of Shares cannot be done in the exponent and the polynomials are
+// evaluated over the integers. Value is the integer evaluation of the
+// polynomial at the index of the Share, and is equal to the value of the Share
+// modulo Prime. Blinding is the integer evaluation of the blinding polynomial.
+type Opening struct {
+	Value    *big.Int
+	Blinding Blinding
+}
+
+// Openings are a slice of Opening structs.
+type Openings []Opening
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (opening Opening) MarshalBinary() ([]byte, error) {
+	if opening.Value == nil || opening.Value.Sign() < 0 {
+		return nil, ErrMalformedOpening
+	}
+	blindingData, err := opening.Blinding.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	valueData := opening.Value.Bytes()
+	if len(valueData) > 255 {
+		return nil, ErrMalformedOpening
+	}
+	data := make([]byte, 0, 1+len(valueData)+len(blindingData))
+	data = append(data, byte(len(valueData)))
+	data = append(data, valueData...)
+	return append(data, blindingData...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (opening *Opening) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return ErrUnmarshalNilBytes
+	}
+	numValueBytes := int(data[0])
+	if len(data) < 1+numValueBytes {
+		return ErrMalformedOpening
+	}
+	opening.Value = big.NewInt(0).SetBytes(data[1 : 1+numValueBytes])
+	return opening.Blinding.UnmarshalBinary(data[1+numValueBytes:])
+}
+
+// Encrypt an Opening using an rsa.PublicKey.
+func (opening *Opening) Encrypt(pubKey rsa.PublicKey) ([]byte, error) {
+	rsaKey := crypto.RsaKey{PrivateKey: &rsa.PrivateKey{PublicKey: pubKey}}
+	data, err := opening.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return rsaKey.Encrypt(data)
+}
+
+// Decrypt a cipher text into an Opening using an rsa.PrivateKey.
+func (opening *Opening) Decrypt(privKey *rsa.PrivateKey, cipherText []byte) error {
+	rsaKey := crypto.RsaKey{PrivateKey: privKey}
+	data, err := rsaKey.Decrypt(cipherText)
+	if err != nil {
+		return err
+	}
+	return opening.UnmarshalBinary(data)
+}
+
+// SplitWithCommitments splits a secret into Shares, the same as
+// SplitWithReader, and returns Pedersen commitments to the coefficients of
+// the polynomial, and an Opening for each Share. The Commitments can be made
+// public, but each Opening must only be given to the holder of its Share.
+func SplitWithCommitments(reader io.Reader, n, k int64, secret uint64) (Shares, Openings, Commitments, error) {
+	if n < k {
+		return nil, nil, nil, ErrNKError
+	}
+	if Prime <= secret {
+		return nil, nil, nil, ErrFiniteField
+	}
+
+	// Generate K integer coefficients for the polynomial, where the first
+	// coefficient is equal to the secret modulo Prime. Each coefficient is
+	// offset by a random multiple of Prime so that the integer evaluation of
+	// the polynomial does not reveal anything about the other coefficients.
+	prime := big.NewInt(0).SetUint64(Prime)
+	maskBound := big.NewInt(0).Lsh(big.NewInt(1), maskBits)
+	coefficients := make([]*big.Int, k)
+	for i := range coefficients {
+		value := uint64(0)
+		if i == 0 {
+			value = secret
+		} else {
+			coefficient, err := RandomFieldElement(reader)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			value = coefficient
+		}
+		mask, err := randomInt(reader, maskBound)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		coefficients[i] = mask.Mul(mask, prime)
+		coefficients[i].Add(coefficients[i], big.NewInt(0).SetUint64(value))
+	}
+
+	// Generate K coefficients for the blinding polynomial, large enough to
+	// statistically hide the coefficients of the polynomial
+	blindingBound := big.NewInt(0).Lsh(big.NewInt(1), uint(CommitP.BitLen()+maskBits))
+	blindingCoefficients := make([]*big.Int, k)
+	for i := range blindingCoefficients {
+		blindingCoefficient, err := randomInt(reader, blindingBound)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		blindingCoefficients[i] = blindingCoefficient
+	}
+
+	commitments := make(Commitments, k)
+	for i := range commitments {
+		gᵃ := big.NewInt(0).Exp(CommitG, coefficients[i], CommitP)
+		hᵇ := big.NewInt(0).Exp(CommitH, blindingCoefficients[i], CommitP)
+		gᵃhᵇ := gᵃ.Mul(gᵃ, hᵇ)
+		commitments[i] = Commitment{gᵃhᵇ.Mod(gᵃhᵇ, CommitP)}
+	}
+
+	shares := make(Shares, n)
+	openings := make(Openings, n)
+	for x := int64(1); x <= n; x++ {
+		value := evaluateInt(coefficients, x)
+		shares[x-1] = Share{
+			Index: uint64(x),
+			Value: big.NewInt(0).Mod(value, prime).Uint64(),
+		}
+		openings[x-1] = Opening{
+			Value:    value,
+			Blinding: Blinding{evaluateInt(blindingCoefficients, x)},
+		}
+	}
+	return shares, openings, commitments, nil
+}
+
+// VerifyShare returns true if the Share was created by the polynomial whose
+// coefficients are committed to by the Commitments, using the Opening for the
+// Share.
+func (commitments Commitments) VerifyShare(share Share, opening Opening) bool {
+	if len(commitments) == 0 || opening.Value == nil || opening.Value.Sign() < 0 || opening.Blinding.Int == nil {
+		return false
+	}
+	if share.Value >= Prime || big.NewInt(0).Mod(opening.Value, big.NewInt(0).SetUint64(Prime)).Uint64() != share.Value {
+		return false
+	}
+
+	return commitments.Evaluate(share.Index).VerifyOpening(share, opening)
+}
+
+// Evaluate the polynomial committed to by the Commitments in the exponent, at
+// an index. The result is a Commitment to the integer evaluation of the
+// polynomial, and of its blinding polynomial, at the index. A nil Commitment
+// is returned if any of the Commitments are nil.
+func (commitments Commitments) Evaluate(index uint64) Commitment {
+	expected := big.NewInt(1)
+	x := big.NewInt(0).SetUint64(index)
+	pow := big.NewInt(1)
+	for _, commitment := range commitments {
+		if commitment.Int == nil {
+			return Commitment{}
+		}
+		term := big.NewInt(0).Exp(commitment.Int, pow, CommitP)
+		expected.Mul(expected, term)
+		expected.Mod(expected, CommitP)
+		pow.Mul(pow, x)
+	}
+	return Commitment{expected}
+}
+
+// VerifyOpening returns true if the Commitment opens to the integer value, and
+// blinding, of the Opening, and the value of the Share is equal to the value
+// of the Opening modulo Prime. Openings that have been subtracted from each
+// other can be negative.
+func (commitment Commitment) VerifyOpening(share Share, opening Opening) bool {
+	if commitment.Int == nil || opening.Value == nil || opening.Blinding.Int == nil {
+		return false
+	}
+	if share.Value >= Prime || big.NewInt(0).Mod(opening.Value, big.NewInt(0).SetUint64(Prime)).Uint64() != share.Value {
+		return false
+	}
+
+	gᵛ := big.NewInt(0).Exp(CommitG, opening.Value, CommitP)
+	hʳ := big.NewInt(0).Exp(CommitH, opening.Blinding.Int, CommitP)
+	if gᵛ == nil || hʳ == nil {
+		return false
+	}
+	gᵛhʳ := gᵛ.Mul(gᵛ, hʳ)
+	return gᵛhʳ.Mod(gᵛhʳ, CommitP).Cmp(commitment.Int) == 0
+}
+
+// Sub one Opening from another and return the result. The result opens the
+// Commitment to the difference of the two values, and like Blindings, it is
+// not reduced and can be negative. A nil value is treated as zero.
+func (opening *Opening) Sub(arg *Opening) Opening {
+	value := big.NewInt(0)
+	if opening.Value != nil {
+		value.Set(opening.Value)
+	}
+	if arg.Value != nil {
+		value.Sub(value, arg.Value)
+	}
+	return Opening{
+		Value:    value,
+		Blinding: opening.Blinding.Sub(&arg.Blinding),
+	}
+}
+
+// RandomBlinding returns a Blinding that is large enough to statistically
+// hide a value committed to using it, drawn from the io.Reader.
+func RandomBlinding(reader io.Reader) (Blinding, error) {
+	blindingBound := big.NewInt(0).Lsh(big.NewInt(1), uint(CommitP.BitLen()+maskBits))
+	blinding, err := randomInt(reader, blindingBound)
+	if err != nil {
+		return Blinding{}, err
+	}
+	return Blinding{blinding}, nil
+}
+
+// evaluateInt evaluates a polynomial with integer coefficients at x, without
+// any modular reduction.
+func evaluateInt(coefficients []*big.Int, x int64) *big.Int {
+	accum := big.NewInt(0)
+	base := big.NewInt(x)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		accum.Mul(accum, base)
+		accum.Add(accum, coefficients[i])
+	}
+	return accum
+}
+
+// randomInt returns a uniformly random integer in the range [0, max), drawn
+// from the io.Reader using rejection sampling.
+func randomInt(reader io.Reader, max *big.Int) (*big.Int, error) {
+	numBits := max.BitLen()
+	data := make([]byte, (numBits+7)/8)
+	for {
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		// Clear the bits above the bit length of the maximum to reduce the
+		// number of rejections
+		if excess := uint(len(data)*8 - numBits); excess > 0 {
+			data[0] &= byte(0xFF >> excess)
+		}
+		value := big.NewInt(0).SetBytes(data)
+		if value.Cmp(max) < 0 {
+			return value, nil
+		}
+	}
+}
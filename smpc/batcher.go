@@ -0,0 +1,93 @@
+package smpc
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultJoinBatchWindow is the default duration for which Joins in the same
+// network are collected before they are sent in a single MessageJoinBatch.
+const DefaultJoinBatchWindow = 20 * time.Millisecond
+
+// A joinBatcher collects the Joins sent to a network over a short window, and
+// sends them in a single batch. A batch is sent when the window has passed
+// since its first Join, or as soon as it holds MaxJoinBatchLength Joins. Joins
+// that are sent with a delay are batched separately from Joins that are not.
+type joinBatcher struct {
+	window time.Duration
+	send   func(networkID NetworkID, joins []Join, useDelay bool)
+
+	mu      *sync.Mutex
+	batches map[joinBatchKey]*joinBatch
+}
+
+type joinBatchKey struct {
+	networkID NetworkID
+	useDelay  bool
+}
+
+type joinBatch struct {
+	joins []Join
+}
+
+func newJoinBatcher(window time.Duration, send func(networkID NetworkID, joins []Join, useDelay bool)) *joinBatcher {
+	return &joinBatcher{
+		window: window,
+		send:   send,
+
+		mu:      new(sync.Mutex),
+		batches: map[joinBatchKey]*joinBatch{},
+	}
+}
+
+// insert a Join into the batch for a network. The batch is sent immediately
+// if it is full.
+func (batcher *joinBatcher) insert(networkID NetworkID, join Join, useDelay bool) {
+	key := joinBatchKey{networkID: networkID, useDelay: useDelay}
+
+	full := func() *joinBatch {
+		batcher.mu.Lock()
+		defer batcher.mu.Unlock()
+
+		batch, ok := batcher.batches[key]
+		if !ok {
+			batch = &joinBatch{joins: make([]Join, 0, 1)}
+			batcher.batches[key] = batch
+			time.AfterFunc(batcher.window, func() {
+				batcher.flush(key, batch)
+			})
+		}
+		batch.joins = append(batch.joins, join)
+		if len(batch.joins) < MaxJoinBatchLength {
+			return nil
+		}
+		delete(batcher.batches, key)
+		return batch
+	}()
+
+	if full != nil {
+		batcher.send(networkID, full.joins, useDelay)
+	}
+}
+
+// flush sends a batch, if it has not already been sent.
+func (batcher *joinBatcher) flush(key joinBatchKey, batch *joinBatch) {
+	batcher.mu.Lock()
+	if batcher.batches[key] != batch {
+		batcher.mu.Unlock()
+		return
+	}
+	delete(batcher.batches, key)
+	batcher.mu.Unlock()
+
+	batcher.send(key.networkID, batch.joins, key.useDelay)
+}
+
+// drop all batches for a network without sending them.
+func (batcher *joinBatcher) drop(networkID NetworkID) {
+	batcher.mu.Lock()
+	defer batcher.mu.Unlock()
+
+	delete(batcher.batches, joinBatchKey{networkID: networkID, useDelay: false})
+	delete(batcher.batches, joinBatchKey{networkID: networkID, useDelay: true})
+}
@@ -0,0 +1,147 @@
+package smpc_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Batched joins", func() {
+
+	n := 6
+	k := int64(2 * (n + 1) / 3)
+
+	var networkID NetworkID
+
+	BeforeEach(func() {
+		networkID = NetworkID(testutils.Random32Bytes())
+	})
+
+	Context("when joins are inserted within the batch window", func() {
+
+		It("should send them in one batch and route the responses to each callback", func() {
+			pod, err := newInProcessPodWithOptions(n, SmpcerOptions{FaultTolerance: 1, JoinBatchWindow: time.Second})
+			Expect(err).ShouldNot(HaveOccurred())
+			pod.connect(networkID)
+			defer pod.disconnect(networkID)
+
+			values := [][]uint64{{1, 2}, {3, 4}, {5, 6}}
+			results := make([]*batchedJoinResult, len(values))
+			for i := range values {
+				results[i] = joinOnAllNodes(pod, networkID, randomJoinID(), splitAll(int64(n), k, values[i]))
+			}
+			for i := range results {
+				results[i].wait()
+				for j := range pod.smpcers {
+					Expect(results[i].errs[j]).ShouldNot(HaveOccurred())
+					Expect(results[i].values[j]).Should(Equal(values[i]))
+				}
+			}
+
+			// Each node sends one batch to each of its peers, and no single
+			// Joins are sent
+			Expect(pod.sent(MessageTypeJoin)).Should(Equal(0))
+			Expect(pod.sent(MessageTypeJoinBatch)).Should(Equal(n * (n - 1)))
+		})
+	})
+
+	Context("when a batch is full", func() {
+
+		It("should send the batch before the window has passed", func() {
+			pod, err := newInProcessPodWithOptions(n, SmpcerOptions{FaultTolerance: 1, JoinBatchWindow: time.Hour})
+			Expect(err).ShouldNot(HaveOccurred())
+			pod.connect(networkID)
+			defer pod.disconnect(networkID)
+
+			results := make([]*batchedJoinResult, MaxJoinBatchLength)
+			for i := range results {
+				results[i] = joinOnAllNodes(pod, networkID, randomJoinID(), splitAll(int64(n), k, []uint64{uint64(i)}))
+			}
+			for i := range results {
+				results[i].wait()
+				for j := range pod.smpcers {
+					Expect(results[i].errs[j]).ShouldNot(HaveOccurred())
+					Expect(results[i].values[j]).Should(Equal([]uint64{uint64(i)}))
+				}
+			}
+			Expect(pod.sent(MessageTypeJoinBatch)).Should(Equal(n * (n - 1)))
+		})
+	})
+
+	Context("when disconnecting before the batch window has passed", func() {
+
+		It("should drop the batch and fail the joins", func() {
+			pod, err := newInProcessPodWithOptions(n, SmpcerOptions{FaultTolerance: 1, JoinBatchWindow: time.Second})
+			Expect(err).ShouldNot(HaveOccurred())
+			pod.connect(networkID)
+
+			result := joinOnAllNodes(pod, networkID, randomJoinID(), splitAll(int64(n), k, []uint64{42}))
+			pod.disconnect(networkID)
+			result.wait()
+			for j := range pod.smpcers {
+				Expect(result.errs[j]).Should(Equal(ErrJoinOnDisconnectedNetwork))
+			}
+
+			time.Sleep(2 * time.Second)
+			Expect(pod.sent(MessageTypeJoin)).Should(Equal(0))
+			Expect(pod.sent(MessageTypeJoinBatch)).Should(Equal(0))
+		})
+	})
+})
+
+// batchedJoinResult holds the values, or errors, returned to the callbacks of
+// a Join on each node in an inProcessPod.
+type batchedJoinResult struct {
+	wg     *sync.WaitGroup
+	values [][]uint64
+	errs   []error
+}
+
+func (result *batchedJoinResult) wait() {
+	result.wg.Wait()
+}
+
+// joinOnAllNodes joins the shares held by each node for a JoinID as masked
+// values, without waiting for the values to be reconstructed.
+func joinOnAllNodes(pod *inProcessPod, networkID NetworkID, id JoinID, shares []shamir.Shares) *batchedJoinResult {
+	result := &batchedJoinResult{
+		wg:     new(sync.WaitGroup),
+		values: make([][]uint64, len(pod.smpcers)),
+		errs:   make([]error, len(pod.smpcers)),
+	}
+	for i := range pod.smpcers {
+		i := i
+		result.wg.Add(1)
+		join := Join{
+			ID:     id,
+			Index:  JoinIndex(shares[i][0].Index),
+			Shares: shares[i],
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := pod.smpcers[i].JoinMasked(ctx, networkID, join, func(joinID JoinID, values []uint64) {
+			defer result.wg.Done()
+			defer cancel()
+			result.values[i] = values
+		}, func(joinID JoinID, err error) {
+			defer result.wg.Done()
+			defer cancel()
+			result.errs[i] = err
+		}, false)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	return result
+}
+
+func randomJoinID() JoinID {
+	id := JoinID{}
+	random := testutils.Random32Bytes()
+	copy(id[:], random[:])
+	return id
+}
@@ -0,0 +1,242 @@
+package smpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// ErrMalformedComparisonMaterial is returned when ComparisonMaterial does not
+// contain the expected number of shamir.Shares and Triples, or when its
+// shamir.Shares do not have the same index as the values being compared.
+var ErrMalformedComparisonMaterial = errors.New("malformed comparison material")
+
+// ErrComparisonDone is returned when opening values for a Comparison that has
+// already produced a result.
+var ErrComparisonDone = errors.New("comparison done")
+
+// ErrUnexpectedComparisonResult is returned when the opened result of a
+// Comparison is not a bit. This happens when the ComparisonMaterial used by
+// the nodes is inconsistent, or when the compared values are out of range.
+var ErrUnexpectedComparisonResult = errors.New("unexpected comparison result")
+
+// CompareBits is the number of bits in the values that can be compared. The
+// values must be less than 2^CompareBits.
+const CompareBits = 20
+
+// CompareMaskBits is the number of bits in the random mask used to hide the
+// difference between the compared values. It is the statistical security
+// parameter of a Comparison.
+const CompareMaskBits = 40
+
+// CompareID uniquely identifies a Comparison. All nodes comparing the same
+// values must use the same CompareID.
+type CompareID [32]byte
+
+// String returns a human-readable representation of a CompareID.
+func (id CompareID) String() string {
+	return base64.StdEncoding.EncodeToString(id[:8])
+}
+
+// A Triple holds shares of random values A and B, and of their product C. It
+// is consumed when multiplying two shared values.
+type Triple struct {
+	A shamir.Share
+	B shamir.Share
+	C shamir.Share
+}
+
+// ComparisonMaterial is the random material, generated before the values are
+// known, that is consumed by one Comparison. Bits are shares of CompareBits
+// random bits, Mask is a share of a random value less than 2^CompareMaskBits,
+// and Triples are CompareBits-1 Triples. ComparisonMaterial must never be
+// used for more than one CompareID.
+type ComparisonMaterial struct {
+	Bits    shamir.Shares
+	Mask    shamir.Share
+	Triples []Triple
+}
+
+// A Preprocessor provides the ComparisonMaterial for Comparisons. All nodes in
+// a network must receive shares of the same ComparisonMaterial for the same
+// CompareID. The ComparisonMaterialCallback is called with the
+// ComparisonMaterial, unless it cannot be provided before the context is done,
+// in which case the CompareErrorCallback is called.
+type Preprocessor interface {
+	ComparisonMaterial(ctx context.Context, networkID NetworkID, id CompareID, callback ComparisonMaterialCallback, errCallback CompareErrorCallback) error
+}
+
+// A Comparison computes whether or not one shared value is greater than, or
+// equal to, another shared value, without revealing anything else about the
+// values. The values must be less than 2^CompareBits.
+//
+// Each round, the shamir.Shares returned by Comparison.Shares must be opened
+// by all nodes in the network and the opened values passed to
+// Comparison.Open. The first round opens the difference of the values hidden
+// by a random mask, the next CompareBits-1 rounds compare the opened value to
+// the mask bit by bit using a Triple each, and the last round opens the
+// result.
+type Comparison struct {
+	material ComparisonMaterial
+	z        shamir.Share
+	r        shamir.Share
+	rLow     shamir.Share
+
+	round  int
+	c      uint64
+	lt     shamir.Share
+	result bool
+}
+
+// NewComparison returns a Comparison of lhs >= rhs that consumes the
+// ComparisonMaterial.
+func NewComparison(lhs, rhs shamir.Share, material ComparisonMaterial) (*Comparison, error) {
+	if lhs.Index != rhs.Index || len(material.Bits) != CompareBits || len(material.Triples) != CompareBits-1 || material.Mask.Index != lhs.Index {
+		return nil, ErrMalformedComparisonMaterial
+	}
+	for _, bit := range material.Bits {
+		if bit.Index != lhs.Index {
+			return nil, ErrMalformedComparisonMaterial
+		}
+	}
+	for _, triple := range material.Triples {
+		if triple.A.Index != lhs.Index || triple.B.Index != lhs.Index || triple.C.Index != lhs.Index {
+			return nil, ErrMalformedComparisonMaterial
+		}
+	}
+
+	// The value z = 2^CompareBits + lhs - rhs has its most significant bit set
+	// if, and only if, lhs >= rhs
+	z := lhs.Sub(&rhs)
+	z = z.AddConstant(1 << CompareBits)
+
+	// The mask r = 2^CompareBits * mask + rLow where rLow is composed from the
+	// random bits
+	rLow := shamir.Share{Index: lhs.Index}
+	for i := len(material.Bits) - 1; i >= 0; i-- {
+		rLow = rLow.Mul(2)
+		rLow = rLow.Add(&material.Bits[i])
+	}
+	r := material.Mask.Mul(1 << CompareBits)
+	r = r.Add(&rLow)
+
+	return &Comparison{
+		material: material,
+		z:        z,
+		r:        r,
+		rLow:     rLow,
+	}, nil
+}
+
+// Shares returns the shamir.Shares that must be opened in the current round.
+// It returns nil when the Comparison is done.
+func (comparison *Comparison) Shares() shamir.Shares {
+	switch {
+	case comparison.round == 0:
+		c := comparison.z.Add(&comparison.r)
+		return shamir.Shares{c}
+
+	case comparison.round < CompareBits:
+		i := comparison.round
+		triple := comparison.material.Triples[i-1]
+		e := comparison.equalBit(i)
+		return shamir.Shares{e.Sub(&triple.A), comparison.lt.Sub(&triple.B)}
+
+	case comparison.round == CompareBits:
+		return shamir.Shares{comparison.resultShare()}
+	}
+	return nil
+}
+
+// Open the values of the shamir.Shares returned by Comparison.Shares, and
+// progress to the next round.
+func (comparison *Comparison) Open(values []uint64) error {
+	if comparison.Done() {
+		return ErrComparisonDone
+	}
+	if len(values) != len(comparison.Shares()) {
+		return ErrJoinLengthUnequal
+	}
+
+	switch {
+	case comparison.round == 0:
+		comparison.c = values[0]
+		comparison.lt = comparison.lessThanBit(0)
+
+	case comparison.round < CompareBits:
+		// Multiply the equality bit by the previous less-than bit using a
+		// Triple, and add the less-than bit for this position
+		i := comparison.round
+		product := beaverProduct(comparison.material.Triples[i-1], values[0], values[1])
+		lt := comparison.lessThanBit(i)
+		comparison.lt = lt.Add(&product)
+
+	case comparison.round == CompareBits:
+		if values[0] > 1 {
+			return ErrUnexpectedComparisonResult
+		}
+		comparison.result = values[0] == 1
+	}
+
+	comparison.round++
+	return nil
+}
+
+// Done returns true when the Comparison has produced a result.
+func (comparison *Comparison) Done() bool {
+	return comparison.round > CompareBits
+}
+
+// Result returns true when lhs >= rhs. It is only meaningful once the
+// Comparison is done.
+func (comparison *Comparison) Result() bool {
+	return comparison.result
+}
+
+// Round returns the current round of the Comparison.
+func (comparison *Comparison) Round() int {
+	return comparison.round
+}
+
+// lessThanBit returns a share of the bit that is set when the opened bit at
+// position i is less than the random bit at position i.
+func (comparison *Comparison) lessThanBit(i int) shamir.Share {
+	if (comparison.c>>uint(i))&1 == 1 {
+		return shamir.Share{Index: comparison.z.Index}
+	}
+	return comparison.material.Bits[i]
+}
+
+// equalBit returns a share of the bit that is set when the opened bit at
+// position i is equal to the random bit at position i.
+func (comparison *Comparison) equalBit(i int) shamir.Share {
+	if (comparison.c>>uint(i))&1 == 1 {
+		return comparison.material.Bits[i]
+	}
+	one := shamir.Share{Index: comparison.z.Index, Value: 1}
+	return one.Sub(&comparison.material.Bits[i])
+}
+
+// resultShare returns a share of the most significant bit of z, which is
+// (z - (z mod 2^CompareBits)) / 2^CompareBits, where z mod 2^CompareBits is
+// computed from the opened value, the random bits, and the less-than bit.
+func (comparison *Comparison) resultShare() shamir.Share {
+	cLow := comparison.c & (1<<CompareBits - 1)
+	zLow := comparison.lt.Mul(1 << CompareBits)
+	zLow = zLow.Sub(&comparison.rLow)
+	zLow = zLow.AddConstant(cLow)
+	msb := comparison.z.Sub(&zLow)
+	return msb.Mul(invTwoCompareBits)
+}
+
+func mulField(x, y uint64) uint64 {
+	product := big.NewInt(0).Mul(big.NewInt(0).SetUint64(x), big.NewInt(0).SetUint64(y))
+	return product.Mod(product, prime).Uint64()
+}
+
+var prime = big.NewInt(0).SetUint64(shamir.Prime)
+
+var invTwoCompareBits = big.NewInt(0).ModInverse(big.NewInt(1<<CompareBits), prime).Uint64()
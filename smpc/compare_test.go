@@ -0,0 +1,197 @@
+package smpc_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+var _ = Describe("Comparisons", func() {
+
+	n := int64(24)
+	k := 2 * (n + 1) / 3
+
+	Context("when comparing shared values", func() {
+
+		It("should return true if, and only if, the lhs is greater than or equal to the rhs", func() {
+			max := uint64(1<<CompareBits - 1)
+			pairs := [][2]uint64{{0, 0}, {1, 0}, {0, 1}, {max, max}, {max, 0}, {0, max}, {max - 1, max}}
+			for i := 0; i < 20; i++ {
+				pairs = append(pairs, [2]uint64{uint64(rand.Int63n(1 << CompareBits)), uint64(rand.Int63n(1 << CompareBits))})
+			}
+
+			for _, pair := range pairs {
+				result, err := compare(n, k, pair[0], pair[1])
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(result).Should(Equal(pair[0] >= pair[1]))
+			}
+		})
+
+		It("should only open values that are hidden by the random material", func() {
+			lhs, err := shamir.Split(n, k, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			rhs, err := shamir.Split(n, k, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// The same values compared with different material must open
+			// different masked values
+			opened := map[uint64]struct{}{}
+			for i := 0; i < 10; i++ {
+				materials := generateComparisonMaterials(n, k)
+				comparison, err := NewComparison(lhs[0], rhs[0], materials[0])
+				Expect(err).ShouldNot(HaveOccurred())
+				comparisons := []*Comparison{comparison}
+				for j := int64(1); j < n; j++ {
+					comparison, err := NewComparison(lhs[j], rhs[j], materials[j])
+					Expect(err).ShouldNot(HaveOccurred())
+					comparisons = append(comparisons, comparison)
+				}
+				values := openComparisonRound(k, comparisons)
+				opened[values[0]] = struct{}{}
+			}
+			Expect(len(opened)).Should(BeNumerically(">", 1))
+		})
+
+		It("should error when opening values after the comparison is done", func() {
+			lhs, err := shamir.Split(n, k, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			rhs, err := shamir.Split(n, k, 0)
+			Expect(err).ShouldNot(HaveOccurred())
+			materials := generateComparisonMaterials(n, k)
+
+			comparisons := make([]*Comparison, n)
+			for i := range comparisons {
+				comparisons[i], err = NewComparison(lhs[i], rhs[i], materials[i])
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			for !comparisons[0].Done() {
+				values := openComparisonRound(k, comparisons)
+				for i := range comparisons {
+					Expect(comparisons[i].Open(values)).ShouldNot(HaveOccurred())
+				}
+			}
+			Expect(comparisons[0].Shares()).Should(BeNil())
+			Expect(comparisons[0].Open([]uint64{1})).Should(Equal(ErrComparisonDone))
+		})
+	})
+
+	Context("when using malformed material", func() {
+
+		It("should error when the material is missing bits or triples", func() {
+			lhs, err := shamir.Split(n, k, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			rhs, err := shamir.Split(n, k, 0)
+			Expect(err).ShouldNot(HaveOccurred())
+			materials := generateComparisonMaterials(n, k)
+
+			material := materials[0]
+			material.Bits = material.Bits[1:]
+			_, err = NewComparison(lhs[0], rhs[0], material)
+			Expect(err).Should(Equal(ErrMalformedComparisonMaterial))
+
+			material = materials[0]
+			material.Triples = material.Triples[1:]
+			_, err = NewComparison(lhs[0], rhs[0], material)
+			Expect(err).Should(Equal(ErrMalformedComparisonMaterial))
+		})
+
+		It("should error when the material has a different index", func() {
+			lhs, err := shamir.Split(n, k, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			rhs, err := shamir.Split(n, k, 0)
+			Expect(err).ShouldNot(HaveOccurred())
+			materials := generateComparisonMaterials(n, k)
+
+			_, err = NewComparison(lhs[0], rhs[0], materials[1])
+			Expect(err).Should(Equal(ErrMalformedComparisonMaterial))
+		})
+	})
+})
+
+// compare two values by running a Comparison at each of n nodes, opening the
+// shares of each round using the first k nodes.
+func compare(n, k int64, lhs, rhs uint64) (bool, error) {
+	lhsShares, err := shamir.Split(n, k, lhs)
+	if err != nil {
+		return false, err
+	}
+	rhsShares, err := shamir.Split(n, k, rhs)
+	if err != nil {
+		return false, err
+	}
+	materials := generateComparisonMaterials(n, k)
+
+	comparisons := make([]*Comparison, n)
+	for i := range comparisons {
+		if comparisons[i], err = NewComparison(lhsShares[i], rhsShares[i], materials[i]); err != nil {
+			return false, err
+		}
+	}
+	for !comparisons[0].Done() {
+		values := openComparisonRound(k, comparisons)
+		for i := range comparisons {
+			if err := comparisons[i].Open(values); err != nil {
+				return false, err
+			}
+		}
+	}
+	return comparisons[0].Result(), nil
+}
+
+func openComparisonRound(k int64, comparisons []*Comparison) []uint64 {
+	shares := make([]shamir.Shares, len(comparisons))
+	for i := range comparisons {
+		shares[i] = comparisons[i].Shares()
+	}
+	values := make([]uint64, len(shares[0]))
+	for j := range values {
+		valueShares := make(shamir.Shares, k)
+		for i := int64(0); i < k; i++ {
+			valueShares[i] = shares[i][j]
+		}
+		values[j] = shamir.Join(valueShares)
+	}
+	return values
+}
+
+// generateComparisonMaterials deals shares of ComparisonMaterial to n nodes.
+func generateComparisonMaterials(n, k int64) []ComparisonMaterial {
+	materials := make([]ComparisonMaterial, n)
+	for i := range materials {
+		materials[i].Bits = make(shamir.Shares, CompareBits)
+		materials[i].Triples = make([]Triple, CompareBits-1)
+	}
+
+	for j := 0; j < CompareBits; j++ {
+		bits, err := shamir.Split(n, k, uint64(rand.Intn(2)))
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := range materials {
+			materials[i].Bits[j] = bits[i]
+		}
+	}
+
+	masks, err := shamir.Split(n, k, uint64(rand.Int63n(1<<CompareMaskBits)))
+	Expect(err).ShouldNot(HaveOccurred())
+	for i := range materials {
+		materials[i].Mask = masks[i]
+	}
+
+	for j := 0; j < CompareBits-1; j++ {
+		a, b := uint64(rand.Int31()), uint64(rand.Int31())
+		as, err := shamir.Split(n, k, a)
+		Expect(err).ShouldNot(HaveOccurred())
+		bs, err := shamir.Split(n, k, b)
+		Expect(err).ShouldNot(HaveOccurred())
+		cs, err := shamir.Split(n, k, a*b)
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := range materials {
+			materials[i].Triples[j] = Triple{A: as[i], B: bs[i], C: cs[i]}
+		}
+	}
+
+	return materials
+}
@@ -0,0 +1,88 @@
+package smpc
+
+import (
+	"context"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// A CompareCallback is called when a Comparison is done. The result is true
+// when the lhs is greater than, or equal to, the rhs.
+type CompareCallback func(id CompareID, result bool)
+
+// A CompareErrorCallback is called when a Comparison cannot be completed. The
+// error is one of the errors passed to an ErrorCallback, or
+// ErrUnexpectedComparisonResult.
+type CompareErrorCallback func(id CompareID, err error)
+
+// A Comparer compares shared values in a network without revealing anything
+// other than the result of the comparison.
+type Comparer interface {
+
+	// Compare whether or not the value shared by lhs is greater than, or
+	// equal to, the value shared by rhs. Both values must be less than
+	// 2^CompareBits. The CompareCallback is called with the result, unless
+	// the Comparison fails, in which case the CompareErrorCallback is called.
+	// The context applies to the whole Comparison.
+	Compare(ctx context.Context, networkID NetworkID, id CompareID, lhs, rhs shamir.Share, callback CompareCallback, errCallback CompareErrorCallback) error
+}
+
+type comparer struct {
+	smpcer       Smpcer
+	preprocessor Preprocessor
+}
+
+// NewComparer returns a Comparer that opens the masked values of each round of
+// a Comparison by joining them with Smpcer.JoinMasked. The Preprocessor provides the
+// ComparisonMaterial for each Comparison.
+func NewComparer(smpcer Smpcer, preprocessor Preprocessor) Comparer {
+	return &comparer{
+		smpcer:       smpcer,
+		preprocessor: preprocessor,
+	}
+}
+
+// Compare implements the Comparer interface.
+func (comparer *comparer) Compare(ctx context.Context, networkID NetworkID, id CompareID, lhs, rhs shamir.Share, callback CompareCallback, errCallback CompareErrorCallback) error {
+	if lhs.Index != rhs.Index {
+		return ErrMalformedComparisonMaterial
+	}
+	return comparer.preprocessor.ComparisonMaterial(ctx, networkID, id, func(id CompareID, material ComparisonMaterial) {
+		comparison, err := NewComparison(lhs, rhs, material)
+		if err != nil {
+			errCallback(id, err)
+			return
+		}
+		if err := comparer.join(ctx, networkID, id, comparison, callback, errCallback); err != nil {
+			errCallback(id, err)
+		}
+	}, errCallback)
+}
+
+// join the shamir.Shares for the current round of a Comparison, and progress
+// to the next round once they have been opened.
+func (comparer *comparer) join(ctx context.Context, networkID NetworkID, id CompareID, comparison *Comparison, callback CompareCallback, errCallback CompareErrorCallback) error {
+	shares := comparison.Shares()
+	join := Join{
+		Index:  JoinIndex(shares[0].Index),
+		Shares: shares,
+	}
+	copy(join.ID[:], id[:])
+	join.ID[32] = byte(comparison.Round())
+
+	return comparer.smpcer.JoinMasked(ctx, networkID, join, func(joinID JoinID, values []uint64) {
+		if err := comparison.Open(values); err != nil {
+			errCallback(id, err)
+			return
+		}
+		if comparison.Done() {
+			callback(id, comparison.Result())
+			return
+		}
+		if err := comparer.join(ctx, networkID, id, comparison, callback, errCallback); err != nil {
+			errCallback(id, err)
+		}
+	}, func(joinID JoinID, err error) {
+		errCallback(id, err)
+	}, false)
+}
@@ -0,0 +1,99 @@
+package smpc
+
+import (
+	"time"
+)
+
+// DefaultJoinTTL is the default duration for which state associated with a
+// JoinID is held before it is evicted.
+const DefaultJoinTTL = time.Hour
+
+// DefaultMaxJoins is the default maximum number of JoinIDs for which state is
+// held. When this number is exceeded, the state associated with the oldest
+// JoinIDs is evicted.
+const DefaultMaxJoins = 1 << 16
+
+// An expiryQueue orders JoinIDs by the time at which they were inserted, so
+// that state associated with a JoinID can be evicted once it is older than a
+// TTL, or once too many JoinIDs are held. An expiryQueue is not safe for
+// concurrent use.
+type expiryQueue struct {
+	ttl      time.Duration
+	maxJoins int
+
+	entries []expiryEntry
+	times   map[JoinID]time.Time
+}
+
+type expiryEntry struct {
+	id   JoinID
+	time time.Time
+}
+
+func newExpiryQueue(ttl time.Duration, maxJoins int) *expiryQueue {
+	return &expiryQueue{
+		ttl:      ttl,
+		maxJoins: maxJoins,
+
+		entries: []expiryEntry{},
+		times:   map[JoinID]time.Time{},
+	}
+}
+
+// insert a JoinID into the queue, if it is not already in the queue.
+func (queue *expiryQueue) insert(id JoinID, now time.Time) {
+	if _, ok := queue.times[id]; ok {
+		return
+	}
+	queue.times[id] = now
+	queue.entries = append(queue.entries, expiryEntry{id: id, time: now})
+}
+
+// remove a JoinID from the queue. The entry is removed lazily, when it reaches
+// the front of the queue, or when the queue is compacted.
+func (queue *expiryQueue) remove(id JoinID) {
+	delete(queue.times, id)
+	if len(queue.entries) > 2*len(queue.times)+64 {
+		queue.compact()
+	}
+}
+
+// expire returns, and removes, all JoinIDs that are older than the TTL, and
+// the oldest JoinIDs that exceed the maximum number of JoinIDs.
+func (queue *expiryQueue) expire(now time.Time) []JoinID {
+	expired := []JoinID{}
+	for len(queue.entries) > 0 {
+		entry := queue.entries[0]
+		if !queue.live(entry) {
+			queue.entries = queue.entries[1:]
+			continue
+		}
+		if now.Sub(entry.time) < queue.ttl && len(queue.times) <= queue.maxJoins {
+			break
+		}
+		queue.entries = queue.entries[1:]
+		delete(queue.times, entry.id)
+		expired = append(expired, entry.id)
+	}
+	return expired
+}
+
+// len returns the number of JoinIDs in the queue.
+func (queue *expiryQueue) len() int {
+	return len(queue.times)
+}
+
+func (queue *expiryQueue) live(entry expiryEntry) bool {
+	t, ok := queue.times[entry.id]
+	return ok && t.Equal(entry.time)
+}
+
+func (queue *expiryQueue) compact() {
+	entries := make([]expiryEntry, 0, len(queue.times))
+	for _, entry := range queue.entries {
+		if queue.live(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	queue.entries = entries
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/shamir"
 )
@@ -28,6 +30,10 @@ var ErrJoinLengthExceedsMax = errors.New("join length exceeds max")
 // verified using JoinCommitments.
 var ErrUnverifiedJoin = errors.New("unverified join")
 
+// ErrUnmarshalJoinBlinding is returned when the length of an encoded
+// shamir.Blinding in a Join is out of range.
+var ErrUnmarshalJoinBlinding = errors.New("unmarshal join blinding")
+
 // A JoinID is used to identify an SMPC join over a network of SMPC nodes. For
 // a value to be joined, all SMPC nodes must use the same JoinID for that
 // value.
@@ -41,6 +47,11 @@ type Join struct {
 	Index     JoinIndex
 	Shares    shamir.Shares
 	Blindings shamir.Blindings
+
+	// Openings of the shamir.Commitments to the Shares, in the same order
+	// as the Shares. They are used to verify the Join against its
+	// JoinCommitments.
+	Openings shamir.Openings
 }
 
 // MarshalBinary implements the encoding.Marshaler interface.
@@ -64,18 +75,32 @@ func (join *Join) MarshalBinary() ([]byte, error) {
 			return nil, err
 		}
 	}
-	// if err := binary.Write(buf, binary.BigEndian, int64(len(join.Blindings))); err != nil {
-	// 	return nil, err
-	// }
-	// for _, blinding := range join.Blindings {
-	// 	blindingData := blinding.Bytes()
-	// 	if err := binary.Write(buf, binary.BigEndian, int64(len(blindingData))); err != nil {
-	// 		return nil, err
-	// 	}
-	// 	if err := binary.Write(buf, binary.BigEndian, blindingData); err != nil {
-	// 		return nil, err
-	// 	}
-	// }
+	if err := binary.Write(buf, binary.BigEndian, int64(len(join.Blindings))); err != nil {
+		return nil, err
+	}
+	for _, blinding := range join.Blindings {
+		if err := writeBlinding(buf, blinding); err != nil {
+			return nil, err
+		}
+	}
+
+	// Openings are encoded after the Blindings so that Joins without them
+	// can be decoded by nodes that do not support them. The values of
+	// Openings can be negative, so they are encoded the same as Blindings.
+	if len(join.Openings) == 0 {
+		return buf.Bytes(), nil
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(join.Openings))); err != nil {
+		return nil, err
+	}
+	for _, opening := range join.Openings {
+		if err := writeBlinding(buf, shamir.Blinding{Int: opening.Value}); err != nil {
+			return nil, err
+		}
+		if err := writeBlinding(buf, opening.Blinding); err != nil {
+			return nil, err
+		}
+	}
 	return buf.Bytes(), nil
 }
 
@@ -92,6 +117,9 @@ func (join *Join) UnmarshalBinary(data []byte) error {
 	if err := binary.Read(buf, binary.BigEndian, &numShares); err != nil {
 		return err
 	}
+	if numShares > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
 	join.Shares = make(shamir.Shares, numShares)
 	for i := int64(0); i < numShares; i++ {
 		shareData := [16]byte{}
@@ -102,30 +130,80 @@ func (join *Join) UnmarshalBinary(data []byte) error {
 			return err
 		}
 	}
-	// numBlindings := int64(0)
-	// if err := binary.Read(buf, binary.BigEndian, &numBlindings); err != nil {
-	// 	return err
-	// }
-	// join.Blindings = make(shamir.Blindings, numBlindings)
-	// for i := int64(0); i < numBlindings; i++ {
-	// 	numBlindingBytes := int64(0)
-	// 	if err := binary.Read(buf, binary.BigEndian, &numBlindingBytes); err != nil {
-	// 		return err
-	// 	}
-	// 	blindingData := make([]byte, numBlindingBytes)
-	// 	if _, err := buf.Read(blindingData[:]); err != nil {
-	// 		return err
-	// 	}
-	// 	join.Blindings[i] = shamir.Blinding{Int: big.NewInt(0).SetBytes(blindingData)}
-	// }
+	numBlindings := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numBlindings); err != nil {
+		return err
+	}
+	if numBlindings > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	join.Blindings = make(shamir.Blindings, numBlindings)
+	for i := int64(0); i < numBlindings; i++ {
+		if err := readBlinding(buf, &join.Blindings[i]); err != nil {
+			return err
+		}
+	}
+
+	// A Join without Openings ends after the Blindings
+	join.Openings = nil
+	if buf.Len() == 0 {
+		return nil
+	}
+	numOpenings := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numOpenings); err != nil {
+		return err
+	}
+	if numOpenings < 0 || numOpenings > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	join.Openings = make(shamir.Openings, numOpenings)
+	for i := int64(0); i < numOpenings; i++ {
+		value := shamir.Blinding{}
+		if err := readBlinding(buf, &value); err != nil {
+			return err
+		}
+		join.Openings[i].Value = value.Int
+		if err := readBlinding(buf, &join.Openings[i].Blinding); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-type JoinCommitments struct {
-	LHS map[uint64]shamir.Commitment
-	RHS map[uint64]shamir.Commitment
+// writeBlinding writes a length prefixed shamir.Blinding to a buffer.
+func writeBlinding(buf *bytes.Buffer, blinding shamir.Blinding) error {
+	blindingData, err := blinding.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(blindingData))); err != nil {
+		return err
+	}
+	return binary.Write(buf, binary.BigEndian, blindingData)
+}
+
+// readBlinding reads a length prefixed shamir.Blinding from a buffer.
+func readBlinding(buf *bytes.Buffer, blinding *shamir.Blinding) error {
+	numBlindingBytes := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numBlindingBytes); err != nil {
+		return err
+	}
+	if numBlindingBytes < 0 || numBlindingBytes > int64(buf.Len()) {
+		return ErrUnmarshalJoinBlinding
+	}
+	blindingData := make([]byte, numBlindingBytes)
+	if _, err := buf.Read(blindingData[:]); err != nil {
+		return err
+	}
+	return blinding.UnmarshalBinary(blindingData)
 }
 
+// JoinCommitments store the expected shamir.Commitments for the shamir.Shares
+// of the Join sent by each JoinIndex. The shamir.Commitments are in the same
+// order as the shamir.Shares. A JoinIndex that has no shamir.Commitments
+// cannot be verified.
+type JoinCommitments map[JoinIndex]shamir.Commitments
+
 // JoinIndex is the index of all shamir.Shares in a Join.
 type JoinIndex uint64
 
@@ -134,6 +212,19 @@ type JoinIndex uint64
 // concurrent safety.
 type Callback func(JoinID, []uint64)
 
+// A Fault identifies a Join that was inconsistent with the values
+// reconstructed by a Joiner. The address of the node that sent the Join is
+// only known to an Smpcer, and is empty when the Fault is reported by a Joiner.
+type Fault struct {
+	ID    JoinID
+	Index JoinIndex
+	From  identity.Address
+}
+
+// FaultCallback is called with the Faults found when reconstructing a JoinSet.
+// FaultCallbacks must ensure their own concurrent safety.
+type FaultCallback func([]Fault)
+
 // JoinSet is a set of Joins, with different JoinIndices, but with the same
 // JoinID.
 type JoinSet struct {
@@ -148,33 +239,98 @@ type JoinSet struct {
 	ValuesOk  bool
 	ValuesLen int
 
+	// Faults are the JoinIndices of Joins that were inconsistent with the
+	// reconstructed Values.
+	Faults []JoinIndex
+
 	// Callback for when the reconstruction happens.
 	Callback Callback
+
+	// Timestamp at which the first Join was inserted into the Set.
+	Timestamp time.Time
 }
 
 // A Joiner received Joins and groups them together based on their JoinID. Once
 // a sufficient number of Joins have been collected, the shamir.Shares are
 // zipped across all Joins, and each zip is reconstructed into a value.
+//
+// A JoinSet is dropped once its values have been passed to a Callback, once it
+// is older than the TTL of the Joiner, or once it is the oldest JoinSet and
+// the Joiner holds too many JoinSets.
 type Joiner struct {
 	k     int64
+	t     int64
 	cache shamir.Shares
 
+	faultCallback FaultCallback
+
 	joinSetsMu *sync.Mutex
 	joinSets   map[JoinID]JoinSet
+	expiry     *expiryQueue
+	evicted    uint64
 }
 
 // NewJoiner returns an empty Joiner that needs k shamir.Shares before it can
 // reconstruct a value.
 func NewJoiner(k int64) *Joiner {
+	return NewRobustJoiner(k, 0, nil)
+}
+
+// NewRobustJoiner returns an empty Joiner that needs k shamir.Shares to
+// reconstruct a value, and tolerates up to t faulty Joins. It waits for k+2t
+// Joins before reconstructing values, and retries the reconstruction with
+// every new Join until it succeeds. Joins that are inconsistent with the
+// reconstructed values are passed to the FaultCallback, if it is not nil.
+func NewRobustJoiner(k, t int64, faultCallback FaultCallback) *Joiner {
+	return NewBoundedJoiner(k, t, faultCallback, DefaultJoinTTL, DefaultMaxJoins)
+}
+
+// NewBoundedJoiner returns an empty Joiner that is the same as a Joiner
+// returned by NewRobustJoiner, but that evicts JoinSets that are older than
+// the TTL, and evicts the oldest JoinSets when it holds more than maxJoinSets.
+func NewBoundedJoiner(k, t int64, faultCallback FaultCallback, ttl time.Duration, maxJoinSets int) *Joiner {
 	return &Joiner{
 		k:     k,
+		t:     t,
 		cache: make(shamir.Shares, k),
 
+		faultCallback: faultCallback,
+
 		joinSetsMu: new(sync.Mutex),
 		joinSets:   map[JoinID]JoinSet{},
+		expiry:     newExpiryQueue(ttl, maxJoinSets),
+		evicted:    0,
 	}
 }
 
+// CancelJoin drops the JoinSet for a JoinID. Callbacks that have been set for
+// the JoinID will not be called. Joins inserted after cancelling will create a
+// new JoinSet.
+func (joiner *Joiner) CancelJoin(joinID JoinID) {
+	joiner.joinSetsMu.Lock()
+	defer joiner.joinSetsMu.Unlock()
+
+	delete(joiner.joinSets, joinID)
+	joiner.expiry.remove(joinID)
+}
+
+// Len returns the number of JoinSets held by the Joiner.
+func (joiner *Joiner) Len() int {
+	joiner.joinSetsMu.Lock()
+	defer joiner.joinSetsMu.Unlock()
+
+	return len(joiner.joinSets)
+}
+
+// Evicted returns the total number of JoinSets that have been evicted, because
+// they were too old or the Joiner held too many JoinSets.
+func (joiner *Joiner) Evicted() uint64 {
+	joiner.joinSetsMu.Lock()
+	defer joiner.joinSetsMu.Unlock()
+
+	return joiner.evicted
+}
+
 // InsertJoinAndSetCallback for a JoinID. If a Callback has been set for this
 // JoinID it will be replaced. The Callback will only be called once per call
 // to Joiner.InsertJoinAndSetCallback; it will be automatically set to nil
@@ -198,15 +354,25 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 	maybeCallback := Callback(nil)
 	maybeValues := [MaxJoinLength]uint64{}
 	maybeValuesLen := 0
+	maybeFaults := []Fault{}
 
 	err := func() error {
 		joiner.joinSetsMu.Lock()
 		defer joiner.joinSetsMu.Unlock()
 
-		// Load the JoinSet and store any mutations when this function returns
+		// Load the JoinSet and store any mutations when this function returns,
+		// unless the JoinSet has been dropped
+		now := time.Now()
 		joinSet, ok := joiner.joinSets[join.ID]
+		drop := false
 		defer func() {
-			joiner.joinSets[join.ID] = joinSet
+			if drop {
+				delete(joiner.joinSets, join.ID)
+				joiner.expiry.remove(join.ID)
+			} else {
+				joiner.joinSets[join.ID] = joinSet
+			}
+			joiner.evict(now)
 		}()
 
 		// Initialize the JoinSet for this JoinID if it has not been initialized
@@ -215,7 +381,9 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 				Set:       map[JoinIndex]Join{},
 				Values:    [MaxJoinLength]uint64{},
 				ValuesLen: len(join.Shares),
+				Timestamp: now,
 			}
+			joiner.expiry.insert(join.ID, now)
 		}
 
 		// Insert this join, if it is needed, and set the callback
@@ -232,24 +400,33 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 
 		// Short circuit if there are not enough Joins to successfully perform a
 		// reconstruction
-		if int64(len(joinSet.Set)) < joiner.k {
+		if int64(len(joinSet.Set)) < joiner.k+2*joiner.t {
 			return nil
 		}
 
 		// If the reconstruction has not happened, perform the reconstruction
 		if !joinSet.ValuesOk {
-			for i := 0; i < joinSet.ValuesLen; i++ {
-				k := int64(0)
-				for _, join := range joinSet.Set {
-					joiner.cache[k] = join.Shares[i]
-					k++
-					if k >= joiner.k {
-						break
+			if joiner.t == 0 {
+				for i := 0; i < joinSet.ValuesLen; i++ {
+					k := int64(0)
+					for _, join := range joinSet.Set {
+						joiner.cache[k] = join.Shares[i]
+						k++
+						if k >= joiner.k {
+							break
+						}
 					}
+					joinSet.Values[i] = shamir.Join(joiner.cache)
 				}
-				joinSet.Values[i] = shamir.Join(joiner.cache)
+			} else if !joiner.robustJoin(&joinSet) {
+				// There are too many faults to reconstruct the values so wait
+				// for more Joins
+				return nil
 			}
 			joinSet.ValuesOk = true
+			for _, index := range joinSet.Faults {
+				maybeFaults = append(maybeFaults, Fault{ID: join.ID, Index: index})
+			}
 		}
 
 		// Copy values to ensure that future mutations do not interfere
@@ -259,8 +436,10 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 		maybeValues = joinSet.Values
 		maybeValuesLen = joinSet.ValuesLen
 
-		// Ensure that the Callback is only ever called once
+		// Ensure that the Callback is only ever called once, and drop the
+		// JoinSet once its values have been passed to a Callback
 		joinSet.Callback = nil
+		drop = maybeCallback != nil
 
 		return nil
 	}()
@@ -268,9 +447,58 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 		return err
 	}
 
+	if joiner.faultCallback != nil && len(maybeFaults) > 0 {
+		joiner.faultCallback(maybeFaults)
+	}
 	if maybeCallback != nil {
 		maybeCallback(join.ID, maybeValues[:maybeValuesLen])
 	}
 
 	return nil
 }
+
+// evict JoinSets that are too old, or that exceed the maximum number of
+// JoinSets. The JoinSetsMu must be locked.
+func (joiner *Joiner) evict(now time.Time) {
+	for _, joinID := range joiner.expiry.expire(now) {
+		if _, ok := joiner.joinSets[joinID]; ok {
+			delete(joiner.joinSets, joinID)
+			joiner.evicted++
+		}
+	}
+}
+
+// robustJoin reconstructs the values of a JoinSet using all of its Joins,
+// correcting any faulty shamir.Shares. The JoinIndices of all Joins that
+// contributed a faulty shamir.Share are stored in the JoinSet. Returns false
+// if there are too many faults to reconstruct the values.
+func (joiner *Joiner) robustJoin(joinSet *JoinSet) bool {
+	indices := make([]JoinIndex, 0, len(joinSet.Set))
+	for index := range joinSet.Set {
+		indices = append(indices, index)
+	}
+	shares := make(shamir.Shares, len(indices))
+
+	values := [MaxJoinLength]uint64{}
+	faulty := map[JoinIndex]struct{}{}
+	for i := 0; i < joinSet.ValuesLen; i++ {
+		for j, index := range indices {
+			shares[j] = joinSet.Set[index].Shares[i]
+		}
+		value, faults, err := shamir.RobustJoin(joiner.k, shares)
+		if err != nil {
+			return false
+		}
+		values[i] = value
+		for _, j := range faults {
+			faulty[indices[j]] = struct{}{}
+		}
+	}
+
+	joinSet.Values = values
+	joinSet.Faults = make([]JoinIndex, 0, len(faulty))
+	for index := range faulty {
+		joinSet.Faults = append(joinSet.Faults, index)
+	}
+	return true
+}
@@ -2,7 +2,9 @@ package smpc_test
 
 import (
 	"bytes"
+	"math/big"
 	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -120,6 +122,53 @@ var _ = Describe("Joiner", func() {
 		})
 	})
 
+	Context("when robustly joining", func() {
+		It("should reconstruct the values and report the faulty joins", func() {
+			t := (n - k) / 2
+			faults := []Fault{}
+			robustJoiner := NewRobustJoiner(k, t, func(fs []Fault) {
+				faults = append(faults, fs...)
+			})
+
+			ord, joins := generateJoins(n, k)
+			for i := int64(0); i < t; i++ {
+				joins[i].Shares[0].Value = (joins[i].Shares[0].Value + 1) % shamir.Prime
+			}
+			values := []uint64{}
+			callback := func(id JoinID, vs []uint64) {
+				values = vs
+			}
+
+			for i := int64(0); i < n; i++ {
+				if i == 0 {
+					Expect(robustJoiner.InsertJoinAndSetCallback(joins[i], callback)).ShouldNot(HaveOccurred())
+				} else {
+					Expect(robustJoiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+				}
+				if i < k+2*t-1 {
+					Expect(values).Should(BeEmpty())
+				}
+			}
+			Expect(values).Should(HaveLen(7))
+			Expect(values[0]).Should(Equal(order.PriceToCoExp(ord.Price).Co))
+			Expect(faults).Should(HaveLen(int(t)))
+			for _, fault := range faults {
+				Expect(fault.Index).Should(BeNumerically("<=", t))
+			}
+		})
+
+		It("should score the nodes that sent faulty joins", func() {
+			scorer := NewFaultScorer()
+			faulty, err := testutils.RandomMultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			scorer.Record([]Fault{{Index: 1, From: faulty.Address()}, {Index: 2}})
+			scorer.Record([]Fault{{Index: 1, From: faulty.Address()}})
+			Expect(scorer.Score(faulty.Address())).Should(Equal(uint64(2)))
+			Expect(scorer.Scores()).Should(HaveLen(1))
+		})
+	})
+
 	Context("when marshaling and unmarshaling joins", func() {
 		It("should get the same join after marshal and unmarshal", func() {
 			_, joins := generateJoins(n, k)
@@ -139,6 +188,108 @@ var _ = Describe("Joiner", func() {
 				}
 			}
 		})
+
+		It("should get the same blindings after marshal and unmarshal", func() {
+			_, joins := generateJoins(n, k)
+			for i := range joins {
+				joins[i].Blindings = make(shamir.Blindings, len(joins[i].Shares))
+				for j := range joins[i].Blindings {
+					joins[i].Blindings[j] = shamir.Blinding{Int: big.NewInt(int64(i*j) - int64(j))}
+				}
+				data, err := joins[i].MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+
+				newJoin := new(Join)
+				err = newJoin.UnmarshalBinary(data)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(len(joins[i].Blindings)).Should(Equal(len(newJoin.Blindings)))
+				for j := range joins[i].Blindings {
+					Expect(joins[i].Blindings[j].Cmp(newJoin.Blindings[j].Int)).Should(Equal(0))
+				}
+			}
+		})
+
+		It("should get the same openings after marshal and unmarshal", func() {
+			_, joins := generateJoins(n, k)
+			for i := range joins {
+				joins[i].Openings = make(shamir.Openings, len(joins[i].Shares))
+				for j := range joins[i].Openings {
+					joins[i].Openings[j] = shamir.Opening{
+						Value:    big.NewInt(int64(j) - int64(i)),
+						Blinding: shamir.Blinding{Int: big.NewInt(int64(i*j) - int64(j))},
+					}
+				}
+				data, err := joins[i].MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+
+				newJoin := new(Join)
+				err = newJoin.UnmarshalBinary(data)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(len(joins[i].Openings)).Should(Equal(len(newJoin.Openings)))
+				for j := range joins[i].Openings {
+					Expect(joins[i].Openings[j].Value.Cmp(newJoin.Openings[j].Value)).Should(Equal(0))
+					Expect(joins[i].Openings[j].Blinding.Cmp(newJoin.Openings[j].Blinding.Int)).Should(Equal(0))
+				}
+			}
+		})
+	})
+
+	Context("when bounding the join sets", func() {
+		It("should drop the join set after the callback is called", func() {
+			ord, joins := generateJoins(n, n)
+			called := int64(0)
+			Expect(joiner.InsertJoinAndSetCallback(joins[0], generateCallback(&called, ord))).ShouldNot(HaveOccurred())
+			Expect(joiner.Len()).Should(Equal(1))
+			for i := int64(1); i < n; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
+			Expect(joiner.Len()).Should(Equal(0))
+		})
+
+		It("should not drop the join set when no callback has been set", func() {
+			_, joins := generateJoins(n, n)
+			for i := int64(0); i < n; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(joiner.Len()).Should(Equal(1))
+		})
+
+		It("should evict join sets that are older than the ttl", func() {
+			joiner = NewBoundedJoiner(k, 0, nil, 100*time.Millisecond, DefaultMaxJoins)
+			_, joins := generateJoins(n, k)
+			Expect(joiner.InsertJoin(joins[0])).ShouldNot(HaveOccurred())
+			time.Sleep(200 * time.Millisecond)
+
+			_, otherJoins := generateJoins(n, k)
+			Expect(joiner.InsertJoin(otherJoins[0])).ShouldNot(HaveOccurred())
+			Expect(joiner.Len()).Should(Equal(1))
+			Expect(joiner.Evicted()).Should(Equal(uint64(1)))
+		})
+
+		It("should evict the oldest join sets when there are too many", func() {
+			joiner = NewBoundedJoiner(k, 0, nil, time.Hour, 4)
+			for i := 0; i < 10; i++ {
+				_, joins := generateJoins(n, k)
+				Expect(joiner.InsertJoin(joins[0])).ShouldNot(HaveOccurred())
+			}
+			Expect(joiner.Len()).Should(Equal(4))
+			Expect(joiner.Evicted()).Should(Equal(uint64(6)))
+		})
+
+		It("should not call the callback after cancelling the join", func() {
+			ord, joins := generateJoins(n, n)
+			called := int64(0)
+			Expect(joiner.InsertJoinAndSetCallback(joins[0], generateCallback(&called, ord))).ShouldNot(HaveOccurred())
+			joiner.CancelJoin(joins[0].ID)
+			Expect(joiner.Len()).Should(Equal(0))
+			for i := int64(1); i < n; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(0)))
+		})
 	})
 
 	Context("when inserting joins with shares that exceed the maximum", func() {
@@ -245,7 +396,7 @@ func generateCallback(called *int64, ord order.Order) func(id JoinID, values []u
 	return func(id JoinID, values []uint64) {
 		atomic.AddInt64(called, 1)
 		Expect(len(values)).Should(Equal(7))
-		// Expect(values[0]).Should(Equal(ord.Price.Co))
+		// Expect(values[0]).Should(Equal(order.PriceToCoExp(ord.Price).Co))
 		// Expect(values[1]).Should(Equal(ord.Price.Exp))
 		// Expect(values[2]).Should(Equal(ord.Volume.Co))
 		// Expect(values[3]).Should(Equal(uint64(ord.Volume.Exp)))
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"io"
 	"io/ioutil"
 	"math/big"
 )
@@ -26,8 +27,27 @@ type MessageType byte
 const (
 	MessageTypeJoin         = MessageType(1)
 	MessageTypeJoinResponse = MessageType(2)
+	MessageTypeReshare      = MessageType(3)
+
+	// MessageTypeJoinBatch and MessageTypeJoinResponseBatch carry many Joins
+	// for the same network in a single message. They are handled the same as
+	// their unbatched counterparts.
+	MessageTypeJoinBatch         = MessageType(4)
+	MessageTypeJoinResponseBatch = MessageType(5)
+
+	// MessageTypePreprocess carries a Reshare of random values, or of
+	// products, used to generate Triples and random bits.
+	MessageTypePreprocess = MessageType(6)
 )
 
+// MaxJoinBatchLength restricts the maximum number of Joins that can be sent in
+// a single MessageJoinBatch.
+const MaxJoinBatchLength = 256
+
+// ErrJoinBatchLengthExceedsMax is returned when a MessageJoinBatch contains
+// more Joins than the MaxJoinBatchLength.
+var ErrJoinBatchLengthExceedsMax = errors.New("join batch length exceeds max")
+
 // A Message is sent internally between nodes. It is not intended for direct
 // use when interacting with an Smpcer.
 type Message struct {
@@ -35,6 +55,12 @@ type Message struct {
 
 	MessageJoin         *MessageJoin
 	MessageJoinResponse *MessageJoinResponse
+	MessageReshare      *MessageReshare
+
+	MessageJoinBatch         *MessageJoinBatch
+	MessageJoinResponseBatch *MessageJoinBatch
+
+	MessagePreprocess *MessageReshare
 }
 
 // MarshalBinary implements the stream.Message interface.
@@ -61,6 +87,50 @@ func (message *Message) MarshalBinary() ([]byte, error) {
 		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
 			return nil, err
 		}
+	case MessageTypeReshare:
+		if message.MessageReshare == nil {
+			return nil, ErrUnexpectedMessageType
+		}
+		bytes, err := message.MessageReshare.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
+	case MessageTypeJoinBatch:
+		if message.MessageJoinBatch == nil {
+			return nil, ErrUnexpectedMessageType
+		}
+		bytes, err := message.MessageJoinBatch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
+	case MessageTypeJoinResponseBatch:
+		if message.MessageJoinResponseBatch == nil {
+			return nil, ErrUnexpectedMessageType
+		}
+		bytes, err := message.MessageJoinResponseBatch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
+	case MessageTypePreprocess:
+		if message.MessagePreprocess == nil {
+			return nil, ErrUnexpectedMessageType
+		}
+		bytes, err := message.MessagePreprocess.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, ErrUnexpectedMessageType
 	}
@@ -90,6 +160,34 @@ func (message *Message) UnmarshalBinary(data []byte) error {
 		}
 		message.MessageJoinResponse = new(MessageJoinResponse)
 		return message.MessageJoinResponse.UnmarshalBinary(bytes)
+	case MessageTypeReshare:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageReshare = new(MessageReshare)
+		return message.MessageReshare.UnmarshalBinary(bytes)
+	case MessageTypeJoinBatch:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageJoinBatch = new(MessageJoinBatch)
+		return message.MessageJoinBatch.UnmarshalBinary(bytes)
+	case MessageTypeJoinResponseBatch:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageJoinResponseBatch = new(MessageJoinBatch)
+		return message.MessageJoinResponseBatch.UnmarshalBinary(bytes)
+	case MessageTypePreprocess:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessagePreprocess = new(MessageReshare)
+		return message.MessagePreprocess.UnmarshalBinary(bytes)
 	default:
 		return ErrUnexpectedMessageType
 	}
@@ -110,6 +208,12 @@ func (message *Message) Rotation(n uint64) uint64 {
 			joinID := big.NewInt(0).SetBytes(message.MessageJoinResponse.Join.ID[:8])
 			return joinID.Mod(joinID, big.NewInt(0).SetUint64(n)).Uint64()
 		}
+	case MessageTypeJoinBatch:
+		// Batches are rotated using the first Join in the batch
+		if message.MessageJoinBatch != nil && len(message.MessageJoinBatch.Joins) > 0 {
+			joinID := big.NewInt(0).SetBytes(message.MessageJoinBatch.Joins[0].ID[:8])
+			return joinID.Mod(joinID, big.NewInt(0).SetUint64(n)).Uint64()
+		}
 	}
 
 	// By default no rotation is used
@@ -124,10 +228,10 @@ func (message *Message) IsNil() bool {
 	if message == nil {
 		return true
 	}
-	if message.MessageType != MessageTypeJoin && message.MessageType != MessageTypeJoinResponse {
+	if message.MessageType < MessageTypeJoin || message.MessageType > MessageTypePreprocess {
 		return true
 	}
-	return message.MessageJoin == nil && message.MessageJoinResponse == nil
+	return message.MessageJoin == nil && message.MessageJoinResponse == nil && message.MessageReshare == nil && message.MessageJoinBatch == nil && message.MessageJoinResponseBatch == nil && message.MessagePreprocess == nil
 }
 
 // A MessageJoin is used to broadcast a Join between nodes in the same network.
@@ -201,3 +305,102 @@ func (message *MessageJoinResponse) UnmarshalBinary(data []byte) error {
 	}
 	return message.Join.UnmarshalBinary(joinData)
 }
+
+// A MessageReshare is used to send a Reshare to a single node in a network.
+type MessageReshare struct {
+	NetworkID
+	Reshare Reshare
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (message *MessageReshare) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, message.NetworkID); err != nil {
+		return nil, err
+	}
+	reshareData, err := message.Reshare.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, reshareData); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (message *MessageReshare) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &message.NetworkID); err != nil {
+		return err
+	}
+	reshareData, err := ioutil.ReadAll(buf)
+	if err != nil {
+		return err
+	}
+	return message.Reshare.UnmarshalBinary(reshareData)
+}
+
+// A MessageJoinBatch is used to send many Joins for the same network in a
+// single message. It is used for both MessageTypeJoinBatch and
+// MessageTypeJoinResponseBatch.
+type MessageJoinBatch struct {
+	NetworkID
+	Joins []Join
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (message *MessageJoinBatch) MarshalBinary() ([]byte, error) {
+	if len(message.Joins) > MaxJoinBatchLength {
+		return nil, ErrJoinBatchLengthExceedsMax
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, message.NetworkID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(message.Joins))); err != nil {
+		return nil, err
+	}
+	for _, join := range message.Joins {
+		joinData, err := join.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, int64(len(joinData))); err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, joinData); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (message *MessageJoinBatch) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &message.NetworkID); err != nil {
+		return err
+	}
+	numJoins := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numJoins); err != nil {
+		return err
+	}
+	if numJoins < 0 || numJoins > MaxJoinBatchLength {
+		return ErrJoinBatchLengthExceedsMax
+	}
+	message.Joins = make([]Join, numJoins)
+	for i := int64(0); i < numJoins; i++ {
+		numBytes := int64(0)
+		if err := binary.Read(buf, binary.BigEndian, &numBytes); err != nil {
+			return err
+		}
+		if numBytes < 0 || numBytes > int64(buf.Len()) {
+			return io.ErrUnexpectedEOF
+		}
+		if err := message.Joins[i].UnmarshalBinary(buf.Next(int(numBytes))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
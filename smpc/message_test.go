@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/shamir"
 )
 
 var (
@@ -145,6 +146,93 @@ var _ = Describe("Messages", func() {
 			}
 		})
 	})
+
+	Context("when marshaling and unmarshaling message of type MessageTypeJoinBatch", func() {
+		It("should equal itself after marshaling and unmarshaling to binary", func() {
+			var networkID [32]byte
+			copy(networkID[:], crypto.Keccak256([]byte{uint8(math.MaxUint8)}))
+			_, joins := generateJoins(n, k)
+			for _, messageType := range []MessageType{MessageTypeJoinBatch, MessageTypeJoinResponseBatch} {
+				message := Message{MessageType: messageType}
+				batch := &MessageJoinBatch{
+					NetworkID: networkID,
+					Joins:     joins,
+				}
+				if messageType == MessageTypeJoinBatch {
+					message.MessageJoinBatch = batch
+				} else {
+					message.MessageJoinResponseBatch = batch
+				}
+				Expect(message.IsNil()).Should(BeFalse())
+
+				data, err := message.MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				newMessage := Message{}
+				Expect(newMessage.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(newMessage.MessageType).Should(Equal(messageType))
+
+				newBatch := newMessage.MessageJoinBatch
+				if messageType == MessageTypeJoinResponseBatch {
+					newBatch = newMessage.MessageJoinResponseBatch
+				}
+				Expect(newBatch.NetworkID).Should(Equal(batch.NetworkID))
+				Expect(newBatch.Joins).Should(HaveLen(len(joins)))
+				for i := range joins {
+					Expect(newBatch.Joins[i].ID).Should(Equal(joins[i].ID))
+					Expect(newBatch.Joins[i].Index).Should(Equal(joins[i].Index))
+					Expect(newBatch.Joins[i].Shares).Should(Equal(joins[i].Shares))
+				}
+			}
+		})
+
+		It("should error when the batch is too long", func() {
+			_, joins := generateJoins(n, k)
+			batch := MessageJoinBatch{}
+			for len(batch.Joins) <= MaxJoinBatchLength {
+				batch.Joins = append(batch.Joins, joins...)
+			}
+			_, err := batch.MarshalBinary()
+			Expect(err).Should(Equal(ErrJoinBatchLengthExceedsMax))
+		})
+
+		It("should error when unmarshaling truncated data", func() {
+			_, joins := generateJoins(n, k)
+			batch := MessageJoinBatch{Joins: joins}
+			data, err := batch.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(batch.UnmarshalBinary(data[:len(data)-1])).Should(HaveOccurred())
+		})
+	})
+
+	Context("when marshaling and unmarshaling message of type MessageTypePreprocess", func() {
+		It("should equal itself after marshaling and unmarshaling to binary", func() {
+			var networkID [32]byte
+			copy(networkID[:], crypto.Keccak256([]byte{uint8(math.MaxUint8)}))
+			shares, err := shamir.Split(n, k, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			message := Message{
+				MessageType: MessageTypePreprocess,
+				MessagePreprocess: &MessageReshare{
+					NetworkID: networkID,
+					Reshare: Reshare{
+						Index:  1,
+						N:      n,
+						K:      k,
+						Shares: shares[:1],
+						Data:   []byte("preprocess"),
+					},
+				},
+			}
+			Expect(message.IsNil()).Should(BeFalse())
+
+			data, err := message.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+			newMessage := Message{}
+			Expect(newMessage.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+			Expect(newMessage.MessageType).Should(Equal(MessageTypePreprocess))
+			Expect(*newMessage.MessagePreprocess).Should(Equal(*message.MessagePreprocess))
+		})
+	})
 })
 
 func generateMessageJoin(n, k int64) []MessageJoin {
@@ -0,0 +1,154 @@
+package smpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// ErrMultiplyLengthExceedsMax is returned when multiplying more than
+// MaxMultiplyLength pairs of shamir.Shares at once.
+var ErrMultiplyLengthExceedsMax = errors.New("multiply length exceeds max")
+
+// ErrUnexpectedShareIndex is returned when the shamir.Shares used in a
+// computation do not have the index of this node in the network.
+var ErrUnexpectedShareIndex = errors.New("unexpected share index")
+
+// MaxMultiplyLength is the maximum number of pairs of shamir.Shares that can
+// be multiplied at once. Each multiplication opens two values.
+const MaxMultiplyLength = MaxJoinLength / 2
+
+// MultiplyID uniquely identifies a multiplication. All nodes multiplying the
+// same values must use the same MultiplyID.
+type MultiplyID [32]byte
+
+// String returns a human-readable representation of a MultiplyID.
+func (id MultiplyID) String() string {
+	return base64.StdEncoding.EncodeToString(id[:8])
+}
+
+// A MultiplyCallback is called with shares of the products of a
+// multiplication. The products are shared with a threshold of (n-1)/2 + 1, and
+// can be opened by joining them.
+type MultiplyCallback func(id MultiplyID, products shamir.Shares)
+
+// A MultiplyErrorCallback is called when a multiplication cannot be completed.
+// The error is one of the errors passed to an ErrorCallback, or an error from
+// generating the Triples for the multiplication.
+type MultiplyErrorCallback func(id MultiplyID, err error)
+
+// A ComparisonMaterialCallback is called with the ComparisonMaterial generated
+// for a Comparison.
+type ComparisonMaterialCallback func(id CompareID, material ComparisonMaterial)
+
+// Multiply implements the Smpcer interface. Triples are generated for the
+// MultiplyID, and then consumed to multiply the shared values without
+// revealing them.
+func (smpc *smpcer) Multiply(ctx context.Context, networkID NetworkID, id MultiplyID, xs, ys shamir.Shares, callback MultiplyCallback, errCallback MultiplyErrorCallback) error {
+	if len(xs) != len(ys) {
+		return ErrJoinLengthUnequal
+	}
+	if len(xs) == 0 || len(xs) > MaxMultiplyLength {
+		return ErrMultiplyLengthExceedsMax
+	}
+	index, err := smpc.networkIndex(networkID)
+	if err != nil {
+		return err
+	}
+	for i := range xs {
+		if xs[i].Index != uint64(index) || ys[i].Index != uint64(index) {
+			return ErrUnexpectedShareIndex
+		}
+	}
+
+	preprocessingID := crypto.Keccak256([]byte("multiply"), id[:])
+	return smpc.preprocess(ctx, networkID, preprocessingIDFromBytes(preprocessingID), len(xs), 0, func(triples []Triple, bits shamir.Shares) {
+		shares := make(shamir.Shares, 2*len(xs))
+		for i := range xs {
+			shares[i], shares[len(xs)+i] = beaverMask(xs[i], ys[i], triples[i])
+		}
+		join := Join{
+			Index:  index,
+			Shares: shares,
+		}
+		copy(join.ID[:], id[:])
+
+		err := smpc.JoinMasked(ctx, networkID, join, func(joinID JoinID, values []uint64) {
+			products := make(shamir.Shares, len(xs))
+			for i := range products {
+				products[i] = beaverProduct(triples[i], values[i], values[len(xs)+i])
+			}
+			callback(id, products)
+		}, func(joinID JoinID, err error) {
+			errCallback(id, err)
+		}, false)
+		if err != nil {
+			errCallback(id, err)
+		}
+	}, func(err error) {
+		errCallback(id, err)
+	})
+}
+
+// ComparisonMaterial implements the Preprocessor interface. The Triples, bits,
+// and mask, of the ComparisonMaterial are generated for the CompareID by all
+// nodes in the network.
+func (smpc *smpcer) ComparisonMaterial(ctx context.Context, networkID NetworkID, id CompareID, callback ComparisonMaterialCallback, errCallback CompareErrorCallback) error {
+	preprocessingID := crypto.Keccak256([]byte("compare"), id[:])
+	return smpc.preprocess(ctx, networkID, preprocessingIDFromBytes(preprocessingID), CompareBits-1, CompareBits+CompareMaskBits, func(triples []Triple, bits shamir.Shares) {
+		mask := shamir.Share{Index: bits[0].Index}
+		for i := len(bits) - 1; i >= CompareBits; i-- {
+			mask = mask.Mul(2)
+			mask = mask.Add(&bits[i])
+		}
+		callback(id, ComparisonMaterial{
+			Bits:    bits[:CompareBits],
+			Mask:    mask,
+			Triples: triples,
+		})
+	}, func(err error) {
+		errCallback(id, err)
+	})
+}
+
+// networkIndex returns the index of the shamir.Shares held by this node in a
+// connected network.
+func (smpc *smpcer) networkIndex(networkID NetworkID) (JoinIndex, error) {
+	smpc.joinersMu.RLock()
+	addrs, ok := smpc.networkAddrs[networkID]
+	smpc.joinersMu.RUnlock()
+	if !ok {
+		return 0, ErrJoinOnDisconnectedNetwork
+	}
+	for i, addr := range addrs {
+		if addr == smpc.swarmer.MultiAddress().Address() {
+			return JoinIndex(i + 1), nil
+		}
+	}
+	return 0, ErrPreprocessingOutsideNetwork
+}
+
+// beaverMask returns the shamir.Shares of x - A and y - B, which must be
+// opened to multiply x and y using a Triple.
+func beaverMask(x, y shamir.Share, triple Triple) (shamir.Share, shamir.Share) {
+	return x.Sub(&triple.A), y.Sub(&triple.B)
+}
+
+// beaverProduct returns a share of xy from the opened values d = x - A and
+// e = y - B, which is C + dB + eA + de.
+func beaverProduct(triple Triple, d, e uint64) shamir.Share {
+	product := triple.B.Mul(d)
+	product = product.Add(&triple.C)
+	ea := triple.A.Mul(e)
+	product = product.Add(&ea)
+	return product.AddConstant(mulField(d, e))
+}
+
+func preprocessingIDFromBytes(data []byte) [32]byte {
+	id := [32]byte{}
+	copy(id[:], data)
+	return id
+}
@@ -0,0 +1,428 @@
+package smpc_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Multiplications", func() {
+
+	n := 6
+	k := int64(2 * (n + 1) / 3)
+
+	var pod *inProcessPod
+	var networkID NetworkID
+
+	BeforeEach(func() {
+		var err error
+		pod, err = newInProcessPod(n)
+		Expect(err).ShouldNot(HaveOccurred())
+		networkID = NetworkID(testutils.Random32Bytes())
+		pod.connect(networkID)
+	})
+
+	AfterEach(func() {
+		pod.disconnect(networkID)
+	})
+
+	Context("when all nodes multiply", func() {
+
+		It("should produce shares of the products", func() {
+			xs := []uint64{0, 1, 42, shamir.Prime - 1}
+			ys := []uint64{7, 1, 1 << 40, 2}
+			xShares, yShares := splitAll(int64(n), k, xs), splitAll(int64(n), k, ys)
+
+			products := make([]shamir.Shares, n)
+			errs := make([]error, n)
+			id := MultiplyID(testutils.Random32Bytes())
+			wg := new(sync.WaitGroup)
+			for i := range pod.smpcers {
+				i := i
+				wg.Add(1)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				err := pod.smpcers[i].Multiply(ctx, networkID, id, xShares[i], yShares[i], func(id MultiplyID, shares shamir.Shares) {
+					defer wg.Done()
+					products[i] = shares
+				}, func(id MultiplyID, err error) {
+					defer wg.Done()
+					errs[i] = err
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			wg.Wait()
+			for i := range errs {
+				Expect(errs[i]).ShouldNot(HaveOccurred())
+			}
+
+			values := pod.open(networkID, products)
+			for i := range xs {
+				Expect(values[i]).Should(Equal(fieldProduct(xs[i], ys[i])))
+			}
+		})
+
+		It("should generate comparison material that can be used by a comparer", func() {
+			pairs := [][2]uint64{{1, 0}, {0, 1}, {512, 512}}
+			for _, pair := range pairs {
+				lhs := splitAll(int64(n), k, []uint64{pair[0]})
+				rhs := splitAll(int64(n), k, []uint64{pair[1]})
+
+				results := make([]bool, n)
+				errs := make([]error, n)
+				id := CompareID(testutils.Random32Bytes())
+				wg := new(sync.WaitGroup)
+				for i := range pod.smpcers {
+					i := i
+					wg.Add(1)
+					ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+					defer cancel()
+					comparer := NewComparer(pod.smpcers[i], pod.smpcers[i])
+					err := comparer.Compare(ctx, networkID, id, lhs[i][0], rhs[i][0], func(id CompareID, result bool) {
+						defer wg.Done()
+						results[i] = result
+					}, func(id CompareID, err error) {
+						defer wg.Done()
+						errs[i] = err
+					})
+					Expect(err).ShouldNot(HaveOccurred())
+				}
+				wg.Wait()
+				for i := range results {
+					Expect(errs[i]).ShouldNot(HaveOccurred())
+					Expect(results[i]).Should(Equal(pair[0] >= pair[1]))
+				}
+			}
+		})
+	})
+
+	Context("when a node reshares incorrect products", func() {
+
+		It("should call the error callback on all nodes", func() {
+			pod.mu.Lock()
+			pod.tamper = func(from identity.Address, message *Message) {
+				if from != pod.addrs[0] || message.MessageType != MessageTypePreprocess {
+					return
+				}
+				reshare := &message.MessagePreprocess.Reshare
+				if reshare.Data[32] != 2 {
+					return
+				}
+				// Adding the same value to all pieces reshares a different
+				// product, without making the pieces inconsistent
+				for i := range reshare.Shares {
+					reshare.Shares[i] = reshare.Shares[i].AddConstant(1)
+				}
+			}
+			pod.mu.Unlock()
+
+			xShares := splitAll(int64(n), k, []uint64{6})
+			yShares := splitAll(int64(n), k, []uint64{7})
+
+			errs := make([]error, n)
+			id := MultiplyID(testutils.Random32Bytes())
+			wg := new(sync.WaitGroup)
+			for i := range pod.smpcers {
+				i := i
+				wg.Add(1)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				err := pod.smpcers[i].Multiply(ctx, networkID, id, xShares[i], yShares[i], func(id MultiplyID, shares shamir.Shares) {
+					defer wg.Done()
+				}, func(id MultiplyID, err error) {
+					defer wg.Done()
+					errs[i] = err
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			wg.Wait()
+			for i := range errs {
+				Expect(errs[i]).Should(Equal(ErrUnverifiedTriple))
+			}
+		})
+	})
+
+	Context("when multiplying invalid shares", func() {
+
+		It("should error when the shares do not have the index of the node", func() {
+			xShares := splitAll(int64(n), k, []uint64{1})
+			err := pod.smpcers[0].Multiply(context.Background(), networkID, MultiplyID{}, xShares[1], xShares[1], nil, nil)
+			Expect(err).Should(Equal(ErrUnexpectedShareIndex))
+		})
+
+		It("should error when multiplying too many shares", func() {
+			xShares := splitAll(int64(n), k, make([]uint64, MaxMultiplyLength+1))
+			err := pod.smpcers[0].Multiply(context.Background(), networkID, MultiplyID{}, xShares[0], xShares[0], nil, nil)
+			Expect(err).Should(Equal(ErrMultiplyLengthExceedsMax))
+		})
+
+		It("should error when the network is not connected", func() {
+			xShares := splitAll(int64(n), k, []uint64{1})
+			err := pod.smpcers[0].Multiply(context.Background(), NetworkID{}, MultiplyID{}, xShares[0], xShares[0], nil, nil)
+			Expect(err).Should(Equal(ErrJoinOnDisconnectedNetwork))
+		})
+	})
+
+	Context("when some nodes do not multiply", func() {
+
+		It("should call the error callback when the context is done", func() {
+			xShares := splitAll(int64(n), k, []uint64{3})
+
+			var failed int64
+			id := MultiplyID(testutils.Random32Bytes())
+			for i := 1; i < n; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				err := pod.smpcers[i].Multiply(ctx, networkID, id, xShares[i], xShares[i], func(id MultiplyID, shares shamir.Shares) {
+					defer GinkgoRecover()
+					Fail("unexpected products")
+				}, func(id MultiplyID, err error) {
+					defer GinkgoRecover()
+					Expect(err).Should(Equal(ErrJoinTimeout))
+					atomic.AddInt64(&failed, 1)
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			Eventually(func() int64 {
+				return atomic.LoadInt64(&failed)
+			}, 5*time.Second).Should(Equal(int64(n - 1)))
+		})
+	})
+})
+
+// fieldProduct returns the product of two values in the finite field.
+func fieldProduct(x, y uint64) uint64 {
+	share := shamir.Share{Index: 1, Value: x}
+	return share.Mul(y).Value
+}
+
+// splitAll splits each value into n shares, and returns the shares held by
+// each node.
+func splitAll(n, k int64, values []uint64) []shamir.Shares {
+	shares := make([]shamir.Shares, n)
+	for i := range shares {
+		shares[i] = make(shamir.Shares, len(values))
+	}
+	for j, value := range values {
+		valueShares, err := shamir.Split(n, k, value)
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := range shares {
+			shares[i][j] = valueShares[i]
+		}
+	}
+	return shares
+}
+
+// inProcessPod is a pod of Smpcers that send messages to each other in the
+// same process. Messages are marshaled, and unmarshaled, as if they were sent
+// over the network.
+type inProcessPod struct {
+	addrs   identity.Addresses
+	smpcers []Smpcer
+
+	mu        *sync.Mutex
+	receivers map[identity.Address]Receiver
+	senders   int
+	messages  map[MessageType]int
+
+	// tamper is called with each message before it is received, and can be
+	// used to modify the messages sent by a faulty node
+	tamper func(from identity.Address, message *Message)
+}
+
+func newInProcessPod(n int) (*inProcessPod, error) {
+	return newInProcessPodWithOptions(n, SmpcerOptions{FaultTolerance: 1})
+}
+
+func newInProcessPodWithOptions(n int, options SmpcerOptions) (*inProcessPod, error) {
+	pod := &inProcessPod{
+		addrs:   make(identity.Addresses, n),
+		smpcers: make([]Smpcer, n),
+
+		mu:        new(sync.Mutex),
+		receivers: map[identity.Address]Receiver{},
+		messages:  map[MessageType]int{},
+	}
+	for i := range pod.smpcers {
+		multiAddr, err := testutils.RandomMultiAddress()
+		if err != nil {
+			return nil, err
+		}
+		pod.addrs[i] = multiAddr.Address()
+		pod.smpcers[i] = NewSmpcerWithOptions(&inProcessConn{pod: pod, addr: multiAddr.Address()}, &inProcessSwarmer{multiAddr: multiAddr}, options)
+	}
+	return pod, nil
+}
+
+// connect all Smpcers to a network, and wait for all connections to be
+// established.
+func (pod *inProcessPod) connect(networkID NetworkID) {
+	for i := range pod.smpcers {
+		pod.smpcers[i].Connect(networkID, pod.addrs)
+	}
+	Eventually(func() int {
+		pod.mu.Lock()
+		defer pod.mu.Unlock()
+		return pod.senders
+	}, 5*time.Second).Should(Equal(len(pod.addrs) * (len(pod.addrs) - 1)))
+	time.Sleep(100 * time.Millisecond)
+}
+
+func (pod *inProcessPod) disconnect(networkID NetworkID) {
+	for i := range pod.smpcers {
+		pod.smpcers[i].Disconnect(networkID)
+	}
+	pod.mu.Lock()
+	pod.senders = 0
+	pod.mu.Unlock()
+}
+
+// sent returns the number of messages of a MessageType that have been sent
+// between the Smpcers.
+func (pod *inProcessPod) sent(messageType MessageType) int {
+	pod.mu.Lock()
+	defer pod.mu.Unlock()
+	return pod.messages[messageType]
+}
+
+// open the shares held by each node by joining them as masked values, so that
+// they are reconstructed with the fault tolerance of the network.
+func (pod *inProcessPod) open(networkID NetworkID, shares []shamir.Shares) []uint64 {
+	id := JoinID{}
+	random := testutils.Random32Bytes()
+	copy(id[:], random[:])
+
+	values := make([][]uint64, len(pod.smpcers))
+	wg := new(sync.WaitGroup)
+	for i := range pod.smpcers {
+		i := i
+		wg.Add(1)
+		join := Join{
+			ID:     id,
+			Index:  JoinIndex(shares[i][0].Index),
+			Shares: shares[i],
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := pod.smpcers[i].JoinMasked(ctx, networkID, join, func(joinID JoinID, joinValues []uint64) {
+			defer wg.Done()
+			values[i] = joinValues
+		}, func(joinID JoinID, err error) {
+			defer wg.Done()
+		}, false)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	wg.Wait()
+	for i := range values {
+		Expect(values[i]).Should(Equal(values[0]))
+	}
+	return values[0]
+}
+
+// register the Receiver for an address, and wait for the Receiver of a peer to
+// be registered.
+func (pod *inProcessPod) sender(ctx context.Context, from, to identity.Address, receiver Receiver) (Sender, error) {
+	pod.mu.Lock()
+	pod.receivers[from] = receiver
+	pod.mu.Unlock()
+
+	for {
+		pod.mu.Lock()
+		peer, ok := pod.receivers[to]
+		if ok {
+			pod.senders++
+		}
+		pod.mu.Unlock()
+		if ok {
+			return &inProcessSender{pod: pod, from: from, to: peer}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("peer not found")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+type inProcessConn struct {
+	pod  *inProcessPod
+	addr identity.Address
+}
+
+func (conn *inProcessConn) Connect(ctx context.Context, networkID NetworkID, to identity.MultiAddress, receiver Receiver) (Sender, error) {
+	return conn.pod.sender(ctx, conn.addr, to.Address(), receiver)
+}
+
+func (conn *inProcessConn) Listen(ctx context.Context, networkID NetworkID, to identity.Address, receiver Receiver) (Sender, error) {
+	return conn.pod.sender(ctx, conn.addr, to, receiver)
+}
+
+type inProcessSender struct {
+	pod  *inProcessPod
+	from identity.Address
+	to   Receiver
+}
+
+func (sender *inProcessSender) Send(message Message) error {
+	sender.pod.mu.Lock()
+	sender.pod.messages[message.MessageType]++
+	sender.pod.mu.Unlock()
+
+	data, err := message.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	received := Message{}
+	if err := received.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	sender.pod.mu.Lock()
+	tamper := sender.pod.tamper
+	sender.pod.mu.Unlock()
+	if tamper != nil {
+		tamper(sender.from, &received)
+	}
+	sender.to.Receive(sender.from, received)
+	return nil
+}
+
+// inProcessSwarmer is a swarm.Swarmer that knows the identity.MultiAddress of
+// its own node, and can build the identity.MultiAddress of any other node.
+type inProcessSwarmer struct {
+	multiAddr identity.MultiAddress
+}
+
+func (swarmer *inProcessSwarmer) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (swarmer *inProcessSwarmer) Pong(ctx context.Context, to identity.MultiAddress) error {
+	return nil
+}
+
+func (swarmer *inProcessSwarmer) BroadcastMultiAddress(ctx context.Context, multiAddr identity.MultiAddress) error {
+	return nil
+}
+
+func (swarmer *inProcessSwarmer) Query(ctx context.Context, query identity.Address) (identity.MultiAddress, error) {
+	return query.MultiAddress()
+}
+
+func (swarmer *inProcessSwarmer) MultiAddress() identity.MultiAddress {
+	return swarmer.multiAddr
+}
+
+func (swarmer *inProcessSwarmer) Peers() (identity.MultiAddresses, error) {
+	return identity.MultiAddresses{}, nil
+}
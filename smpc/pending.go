@@ -0,0 +1,173 @@
+package smpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+)
+
+// ErrorCallback is called when a Join fails to reconstruct its values. The
+// error is one of ErrJoinTimeout, ErrJoinCanceled,
+// ErrJoinOnDisconnectedNetwork, or ErrUnverifiedJoin. ErrorCallbacks must
+// ensure their own concurrent safety.
+type ErrorCallback func(JoinID, error)
+
+// A pendingJoin is a Join owned by this node that is waiting for its values
+// to be reconstructed. It is done once it has either succeeded or failed.
+type pendingJoin struct {
+	errCallback ErrorCallback
+	done        chan struct{}
+}
+
+// insertPendingJoin for a JoinID, replacing any existing pendingJoin for the
+// JoinID. The pendingJoin fails when the context is done.
+func (smpc *smpcer) insertPendingJoin(ctx context.Context, networkID NetworkID, joinID JoinID, errCallback ErrorCallback) *pendingJoin {
+	pending := &pendingJoin{
+		errCallback: errCallback,
+		done:        make(chan struct{}),
+	}
+
+	func() {
+		smpc.pendingMu.Lock()
+		defer smpc.pendingMu.Unlock()
+
+		pendingJoins, ok := smpc.pending[networkID]
+		if !ok {
+			return
+		}
+		if prev, ok := pendingJoins[joinID]; ok {
+			close(prev.done)
+		}
+		pendingJoins[joinID] = pending
+	}()
+
+	go func() {
+		select {
+		case <-pending.done:
+		case <-ctx.Done():
+			err := ErrJoinCanceled
+			if ctx.Err() == context.DeadlineExceeded {
+				err = ErrJoinTimeout
+			}
+			smpc.failPendingJoin(networkID, joinID, pending, err)
+		}
+	}()
+
+	return pending
+}
+
+// completePendingJoin marks a pendingJoin as done. It returns false if the
+// pendingJoin was already done, in which case the Callback for the Join must
+// not be called.
+func (smpc *smpcer) completePendingJoin(networkID NetworkID, joinID JoinID, pending *pendingJoin) bool {
+	smpc.pendingMu.Lock()
+	defer smpc.pendingMu.Unlock()
+
+	if smpc.pending[networkID][joinID] != pending {
+		return false
+	}
+	delete(smpc.pending[networkID], joinID)
+	close(pending.done)
+	return true
+}
+
+// failPendingJoin marks a pendingJoin as done, drops the JoinSet for its
+// JoinID so that the Callback is never called, and calls the ErrorCallback.
+// If pending is nil, whichever pendingJoin is held for the JoinID is failed.
+func (smpc *smpcer) failPendingJoin(networkID NetworkID, joinID JoinID, pending *pendingJoin, err error) {
+	failed := func() *pendingJoin {
+		smpc.pendingMu.Lock()
+		defer smpc.pendingMu.Unlock()
+
+		current, ok := smpc.pending[networkID][joinID]
+		if !ok || (pending != nil && current != pending) {
+			return nil
+		}
+		delete(smpc.pending[networkID], joinID)
+		close(current.done)
+		return current
+	}()
+	if failed == nil {
+		return
+	}
+
+	smpc.joinersMu.RLock()
+	if joiner, ok := smpc.joiners[networkID]; ok {
+		joiner.CancelJoin(joinID)
+	}
+	smpc.joinersMu.RUnlock()
+
+	logger.Compute(logger.LevelError, fmt.Sprintf("cannot join %v on network %v: %v", joinID, networkID, err))
+	if failed.errCallback != nil {
+		failed.errCallback(joinID, err)
+	}
+}
+
+// failPendingJoins fails all pendingJoins in a network, and stops tracking
+// pendingJoins for the network.
+func (smpc *smpcer) failPendingJoins(networkID NetworkID, err error) {
+	joinIDs := func() []JoinID {
+		smpc.pendingMu.Lock()
+		defer smpc.pendingMu.Unlock()
+
+		joinIDs := make([]JoinID, 0, len(smpc.pending[networkID]))
+		for joinID := range smpc.pending[networkID] {
+			joinIDs = append(joinIDs, joinID)
+		}
+		return joinIDs
+	}()
+
+	for _, joinID := range joinIDs {
+		smpc.failPendingJoin(networkID, joinID, nil, err)
+	}
+
+	smpc.pendingMu.Lock()
+	delete(smpc.pending, networkID)
+	smpc.pendingMu.Unlock()
+}
+
+// insertRejection records that a Join from another node could not be
+// verified. The node must already be authenticated as the holder of the
+// JoinIndex of the Join. Rejections are counted at most once per node, so
+// that one node cannot fail a Join by sending many unverifiable Joins.
+func (smpc *smpcer) insertRejection(networkID NetworkID, joinID JoinID, from identity.Address) {
+	smpc.commitmentsMu.Lock()
+	defer smpc.commitmentsMu.Unlock()
+
+	rejections, ok := smpc.rejections[networkID]
+	if !ok {
+		return
+	}
+	if _, ok := rejections[joinID]; !ok {
+		rejections[joinID] = map[identity.Address]struct{}{}
+	}
+	rejections[joinID][from] = struct{}{}
+	smpc.expireCommitments(networkID, joinID)
+}
+
+// checkRejections fails the pendingJoin for a JoinID with ErrUnverifiedJoin
+// when so many Joins have been rejected that the remaining Joins cannot
+// reconstruct the values.
+func (smpc *smpcer) checkRejections(networkID NetworkID, joinID JoinID) {
+	smpc.commitmentsMu.RLock()
+	numRejections := int64(len(smpc.rejections[networkID][joinID]))
+	smpc.commitmentsMu.RUnlock()
+	if numRejections == 0 {
+		return
+	}
+
+	smpc.joinersMu.RLock()
+	joiner, ok := smpc.joiners[networkID]
+	n := smpc.networkSizes[networkID]
+	smpc.joinersMu.RUnlock()
+	if !ok {
+		return
+	}
+	if n-numRejections >= joiner.k+2*joiner.t {
+		return
+	}
+	logger.Compute(logger.LevelError, fmt.Sprintf("cannot join %v on network %v: rejected %v of %v joins", joinID, networkID, numRejections, n))
+	smpc.failPendingJoin(networkID, joinID, nil, ErrUnverifiedJoin)
+}
@@ -0,0 +1,578 @@
+package smpc
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// ErrPreprocessingInProgress is returned when generating random material for
+// an ID that is already being used to generate random material.
+var ErrPreprocessingInProgress = errors.New("preprocessing in progress")
+
+// ErrPreprocessingLengthExceedsMax is returned when generating more random
+// material, in one preprocessing, than can be identified by a Reshare.
+var ErrPreprocessingLengthExceedsMax = errors.New("preprocessing length exceeds max")
+
+// ErrPreprocessingOutsideNetwork is returned when generating random material
+// for a network that does not include this node.
+var ErrPreprocessingOutsideNetwork = errors.New("preprocessing outside network")
+
+// ErrUnverifiedTriple is returned when a Triple generated by a preprocessing
+// does not pass the check against the Triple that is sacrificed for it. This
+// happens when a node deals shamir.Shares that are inconsistent with its
+// values.
+var ErrUnverifiedTriple = errors.New("unverified triple")
+
+// ErrUnexpectedSquare is returned when an opened square is zero, and so
+// cannot be used to generate a random bit. This happens with negligible
+// probability, and the preprocessing can be retried.
+var ErrUnexpectedSquare = errors.New("unexpected square")
+
+// MaxPreprocessingLength is the maximum number of random values that can be
+// generated in one preprocessing. Each Triple needs two random values, and
+// each random bit needs one. Each Triple, and each random bit, also needs two
+// random values for the Triple that is sacrificed to check it, and each
+// preprocessing needs one random value for its challenge.
+const MaxPreprocessingLength = 255 * MaxJoinLength
+
+// The rounds of a preprocessing. Random values are dealt by all nodes, then
+// the products of random values are reshared to reduce their degree. A random
+// challenge is opened, and used to check each product against a product that
+// is sacrificed for it, by opening the masks of the check, and then the check
+// itself. Finally, the squares of random values are opened to produce random
+// bits.
+const (
+	preprocessingRoundRandoms   = byte(1)
+	preprocessingRoundProducts  = byte(2)
+	preprocessingRoundSquares   = byte(3)
+	preprocessingRoundChallenge = byte(4)
+	preprocessingRoundMasks     = byte(5)
+	preprocessingRoundChecks    = byte(6)
+)
+
+// preprocessingDataLength is the length of the public data attached to the
+// Reshares of a preprocessing. It holds the ID of the preprocessing, the
+// round, and the chunk.
+const preprocessingDataLength = 34
+
+// A preprocessing generates Triples and random bits shared by all nodes in a
+// network. It is the offline phase of a secure multiplication, or of a
+// Comparison, and does not depend on the values that will be computed.
+//
+// The random material is shared with a threshold of (n-1)/2 + 1 rather than
+// the threshold used for order fragments, so that the product of two shares
+// can be reduced back to the same threshold by resharing. It remains secret
+// as long as fewer than half of the nodes collude.
+//
+// The products are not dealt with commitments, so a node can reshare a value
+// other than its product. Each product is checked against another product of
+// random values, which is sacrificed, using a random challenge that is opened
+// after all products are fixed. A product that is not correct fails the check,
+// except with negligible probability.
+type preprocessing struct {
+	networkID NetworkID
+	id        [32]byte
+	index     JoinIndex
+	n         int64
+	k         int64
+
+	numTriples int
+	numBits    int
+
+	round     byte
+	remaining int
+	randoms   shamir.Shares
+	products  shamir.Shares
+
+	callback    func(triples []Triple, bits shamir.Shares)
+	errCallback func(err error)
+	done        chan struct{}
+}
+
+// preprocessingThreshold returns the number of shamir.Shares needed to
+// reconstruct the random material generated by a network of n nodes.
+func preprocessingThreshold(n int64) int64 {
+	return (n-1)/2 + 1
+}
+
+// preprocess generates numTriples Triples and numBits random bits in a
+// network. All nodes in the network must preprocess with the same ID, and the
+// same number of Triples and bits. The callback is called with the random
+// material, unless the preprocessing fails, in which case the errCallback is
+// called. The errCallback is called with ErrJoinTimeout, or ErrJoinCanceled,
+// when the context is done before the random material is generated.
+func (smpc *smpcer) preprocess(ctx context.Context, networkID NetworkID, id [32]byte, numTriples, numBits int, callback func(triples []Triple, bits shamir.Shares), errCallback func(err error)) error {
+	if numTriples < 0 || numBits < 0 || numTriples+numBits == 0 || 4*numTriples+3*numBits+1 > MaxPreprocessingLength {
+		return ErrPreprocessingLengthExceedsMax
+	}
+
+	index, err := smpc.networkIndex(networkID)
+	if err != nil {
+		return err
+	}
+	smpc.joinersMu.RLock()
+	addrs := smpc.networkAddrs[networkID]
+	smpc.joinersMu.RUnlock()
+
+	n := int64(len(addrs))
+	session := &preprocessing{
+		networkID: networkID,
+		id:        id,
+		index:     index,
+		n:         n,
+		k:         preprocessingThreshold(n),
+
+		numTriples: numTriples,
+		numBits:    numBits,
+
+		round:    preprocessingRoundRandoms,
+		randoms:  make(shamir.Shares, 4*numTriples+3*numBits+1),
+		products: make(shamir.Shares, 2*(numTriples+numBits)),
+
+		callback:    callback,
+		errCallback: errCallback,
+		done:        make(chan struct{}),
+	}
+	session.remaining = numChunks(len(session.randoms))
+
+	// Deal a fresh random value for each random value that will be generated
+	dealt := make(shamir.Shares, len(session.randoms))
+	for i := range dealt {
+		value, err := shamir.RandomFieldElement(rand.Reader)
+		if err != nil {
+			return err
+		}
+		dealt[i] = shamir.Share{Index: uint64(index), Value: value}
+	}
+
+	err = func() error {
+		smpc.preprocessingsMu.Lock()
+		defer smpc.preprocessingsMu.Unlock()
+
+		sessions, ok := smpc.preprocessings[networkID]
+		if !ok {
+			return ErrJoinOnDisconnectedNetwork
+		}
+		if _, ok := sessions[id]; ok {
+			return ErrPreprocessingInProgress
+		}
+		sessions[id] = session
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-session.done:
+		case <-ctx.Done():
+			err := ErrJoinCanceled
+			if ctx.Err() == context.DeadlineExceeded {
+				err = ErrJoinTimeout
+			}
+			smpc.failPreprocessing(session, err)
+		}
+	}()
+
+	// Each random value is a combination of the values dealt by K nodes, at
+	// least one of which is honest when fewer than K nodes collude
+	if err := smpc.dealPreprocessing(session, preprocessingRoundRandoms, dealt, session.k); err != nil {
+		smpc.failPreprocessing(session, err)
+		return err
+	}
+	return nil
+}
+
+// dealPreprocessing reshares shamir.Shares to all nodes in the network of a
+// preprocessing, in chunks of at most MaxJoinLength shamir.Shares.
+// ResharingK is the number of Reshares that will be combined for each chunk.
+func (smpc *smpcer) dealPreprocessing(session *preprocessing, round byte, shares shamir.Shares, resharingK int64) error {
+	smpc.joinersMu.RLock()
+	addrs := smpc.networkAddrs[session.networkID]
+	smpc.joinersMu.RUnlock()
+
+	for chunk := 0; chunk < numChunks(len(shares)); chunk++ {
+		begin, end := chunkBounds(chunk, len(shares))
+		data := make([]byte, preprocessingDataLength)
+		copy(data, session.id[:])
+		data[32] = round
+		data[33] = byte(chunk)
+
+		reshares, err := ReshareShares(preprocessingReshareID(session.id, round, chunk), session.index, session.n, resharingK, shares[begin:end], session.n, session.k, data)
+		if err != nil {
+			return err
+		}
+		for j, addr := range addrs {
+			if addr == smpc.swarmer.MultiAddress().Address() {
+				if err := smpc.handlePreprocessReshare(addr, session.networkID, reshares[j]); err != nil {
+					return err
+				}
+				continue
+			}
+			smpc.network.SendTo(session.networkID, addr, Message{
+				MessageType: MessageTypePreprocess,
+				MessagePreprocess: &MessageReshare{
+					NetworkID: session.networkID,
+					Reshare:   reshares[j],
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// handlePreprocessReshare inserts a Reshare, received from a node, into the
+// preprocessing Resharer for its network. The Resharer only accepts a Reshare
+// from the node that deals with the index of the Reshare. Reshares for
+// networks that are not connected are ignored.
+func (smpc *smpcer) handlePreprocessReshare(from identity.Address, networkID NetworkID, reshare Reshare) error {
+	smpc.preprocessingsMu.Lock()
+	resharer, ok := smpc.preprocessResharers[networkID]
+	smpc.preprocessingsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return resharer.InsertReshare(from, reshare)
+}
+
+// handlePreprocessReshared stores the shamir.Shares produced by combining the
+// Reshares for a chunk of a preprocessing, and progresses the preprocessing
+// to the next round once all chunks of the current round are done.
+func (smpc *smpcer) handlePreprocessReshared(networkID NetworkID, shares shamir.Shares, data []byte) {
+	if len(data) != preprocessingDataLength {
+		logger.Compute(logger.LevelError, fmt.Sprintf("dropping preprocessing reshare on network %v: malformed data", networkID))
+		return
+	}
+	id := [32]byte{}
+	copy(id[:], data)
+	round, chunk := data[32], int(data[33])
+
+	session, next := func() (*preprocessing, bool) {
+		smpc.preprocessingsMu.Lock()
+		defer smpc.preprocessingsMu.Unlock()
+
+		session, ok := smpc.preprocessings[networkID][id]
+		if !ok || session.round != round {
+			return nil, false
+		}
+		dst := session.randoms
+		if round == preprocessingRoundProducts {
+			dst = session.products
+		}
+		begin, end := chunkBounds(chunk, len(dst))
+		if begin >= end || end-begin != len(shares) {
+			return nil, false
+		}
+		copy(dst[begin:end], shares)
+		session.remaining--
+		return session, session.remaining == 0
+	}()
+	if session == nil {
+		logger.Compute(logger.LevelDebugLow, fmt.Sprintf("dropping preprocessing reshare on network %v: unexpected chunk", networkID))
+		return
+	}
+	if !next {
+		return
+	}
+
+	switch round {
+	case preprocessingRoundRandoms:
+		smpc.reduceProducts(session)
+	case preprocessingRoundProducts:
+		smpc.openChallenge(session)
+	}
+}
+
+// reduceProducts multiplies the random values locally, which doubles the
+// degree of their shamir.Shares, and reshares the products so that they can
+// be combined back into shamir.Shares with the threshold of the network.
+func (smpc *smpcer) reduceProducts(session *preprocessing) {
+	numTriples, numChecks := session.numTriples, session.numTriples+session.numBits
+	products := make(shamir.Shares, 2*numChecks)
+	for i := 0; i < numTriples; i++ {
+		products[i] = session.randoms[i].Mul(session.randoms[numTriples+i].Value)
+	}
+	for i := 0; i < session.numBits; i++ {
+		u := session.randoms[2*numTriples+i]
+		products[numTriples+i] = u.Mul(u.Value)
+	}
+	for i := 0; i < numChecks; i++ {
+		sacrifice := session.sacrifice(i)
+		products[numChecks+i] = sacrifice.A.Mul(sacrifice.B.Value)
+	}
+
+	smpc.preprocessingsMu.Lock()
+	session.round = preprocessingRoundProducts
+	session.remaining = numChunks(len(products))
+	smpc.preprocessingsMu.Unlock()
+
+	if err := smpc.dealPreprocessing(session, preprocessingRoundProducts, products, 2*session.k-1); err != nil {
+		smpc.failPreprocessing(session, err)
+	}
+}
+
+// openChallenge opens the random challenge that is used to check the
+// products of a preprocessing. The challenge is only opened once the
+// products have been reshared, so that no node can choose its products
+// knowing the challenge.
+func (smpc *smpcer) openChallenge(session *preprocessing) {
+	challenge := session.randoms[len(session.randoms)-1]
+	smpc.openPreprocessing(session, preprocessingRoundChallenge, shamir.Shares{challenge}, func(values []uint64) {
+		smpc.openMasks(session, values[0])
+	})
+}
+
+// openMasks opens rA - X and B - Y for each product C = AB, where
+// Z = XY is the product that is sacrificed for it, and r is the challenge.
+func (smpc *smpcer) openMasks(session *preprocessing, challenge uint64) {
+	numChecks := session.numTriples + session.numBits
+	masks := make(shamir.Shares, 2*numChecks)
+	for i := 0; i < numChecks; i++ {
+		triple, sacrifice := session.triple(i), session.sacrifice(i)
+		rho := triple.A.Mul(challenge)
+		masks[i] = rho.Sub(&sacrifice.A)
+		masks[numChecks+i] = triple.B.Sub(&sacrifice.B)
+	}
+	smpc.openPreprocessing(session, preprocessingRoundMasks, masks, func(values []uint64) {
+		smpc.openChecks(session, challenge, values[:numChecks], values[numChecks:])
+	})
+}
+
+// openChecks opens rC - Z - sX - pY - sp for each product, where p = rA - X
+// and s = B - Y. The check is zero for all products when all products are
+// correct, and the preprocessing fails otherwise.
+func (smpc *smpcer) openChecks(session *preprocessing, challenge uint64, rhos, sigmas []uint64) {
+	checks := make(shamir.Shares, len(rhos))
+	for i := range checks {
+		triple, sacrifice := session.triple(i), session.sacrifice(i)
+		sx, py := sacrifice.A.Mul(sigmas[i]), sacrifice.B.Mul(rhos[i])
+		check := triple.C.Mul(challenge)
+		check = check.Sub(&sacrifice.C)
+		check = check.Sub(&sx)
+		check = check.Sub(&py)
+		checks[i] = check.AddConstant(shamir.Prime - mulField(sigmas[i], rhos[i]))
+	}
+	smpc.openPreprocessing(session, preprocessingRoundChecks, checks, func(values []uint64) {
+		for _, value := range values {
+			if value != 0 {
+				smpc.failPreprocessing(session, ErrUnverifiedTriple)
+				return
+			}
+		}
+		smpc.openSquares(session)
+	})
+}
+
+// openSquares joins the squares of the random values that are used to
+// generate random bits. If no random bits are needed, the preprocessing is
+// done.
+func (smpc *smpcer) openSquares(session *preprocessing) {
+	squares := session.products[session.numTriples : session.numTriples+session.numBits]
+	smpc.openPreprocessing(session, preprocessingRoundSquares, squares, func(values []uint64) {
+		smpc.completePreprocessing(session, values)
+	})
+}
+
+// openPreprocessing joins shamir.Shares for a round of a preprocessing, in
+// chunks of at most MaxJoinLength shamir.Shares, and calls the callback with
+// the opened values once all chunks have been joined.
+func (smpc *smpcer) openPreprocessing(session *preprocessing, round byte, shares shamir.Shares, callback func(values []uint64)) {
+	numChunks := numChunks(len(shares))
+	values := make([]uint64, len(shares))
+
+	smpc.preprocessingsMu.Lock()
+	session.round = round
+	session.remaining = numChunks
+	smpc.preprocessingsMu.Unlock()
+
+	if numChunks == 0 {
+		callback(values)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-session.done
+		cancel()
+	}()
+	for chunk := 0; chunk < numChunks; chunk++ {
+		begin, end := chunkBounds(chunk, len(shares))
+		id := preprocessingReshareID(session.id, round, chunk)
+		join := Join{
+			Index:  session.index,
+			Shares: shares[begin:end],
+		}
+		copy(join.ID[:], id[:])
+		join.ID[32] = byte(chunk)
+
+		err := smpc.JoinMasked(ctx, session.networkID, join, func(joinID JoinID, joinValues []uint64) {
+			done := func() bool {
+				smpc.preprocessingsMu.Lock()
+				defer smpc.preprocessingsMu.Unlock()
+
+				copy(values[begin:end], joinValues)
+				session.remaining--
+				return session.remaining == 0
+			}()
+			if done {
+				callback(values)
+			}
+		}, func(joinID JoinID, err error) {
+			smpc.failPreprocessing(session, err)
+		}, false)
+		if err != nil {
+			smpc.failPreprocessing(session, err)
+			return
+		}
+	}
+}
+
+// completePreprocessing builds the Triples and random bits of a preprocessing,
+// from the opened squares of its random values, and calls its callback.
+func (smpc *smpcer) completePreprocessing(session *preprocessing, squares []uint64) {
+	numTriples := session.numTriples
+	triples := make([]Triple, numTriples)
+	for i := range triples {
+		triples[i] = session.triple(i)
+	}
+
+	// The square root s of u^2 is either u or -u, so u/s is either 1 or -1 and
+	// (u/s + 1)/2 is a random bit that is unknown to all nodes
+	bits := make(shamir.Shares, session.numBits)
+	for i := range bits {
+		if squares[i] == 0 {
+			smpc.failPreprocessing(session, ErrUnexpectedSquare)
+			return
+		}
+		invRoot := big.NewInt(0).SetUint64(squares[i])
+		invRoot.Exp(invRoot, sqrtExp, prime)
+		invRoot.ModInverse(invRoot, prime)
+
+		u := session.randoms[2*numTriples+i]
+		bit := u.Mul(invRoot.Uint64())
+		bit = bit.AddConstant(1)
+		bits[i] = bit.Mul(invTwo)
+	}
+
+	if !smpc.removePreprocessing(session) {
+		return
+	}
+	session.callback(triples, bits)
+}
+
+// failPreprocessing stops a preprocessing and calls its errCallback, unless
+// the preprocessing is already done.
+func (smpc *smpcer) failPreprocessing(session *preprocessing, err error) {
+	if !smpc.removePreprocessing(session) {
+		return
+	}
+	logger.Compute(logger.LevelError, fmt.Sprintf("cannot preprocess on network %v: %v", session.networkID, err))
+	if session.errCallback != nil {
+		session.errCallback(err)
+	}
+}
+
+// removePreprocessing marks a preprocessing as done. It returns false if the
+// preprocessing was already done.
+func (smpc *smpcer) removePreprocessing(session *preprocessing) bool {
+	smpc.preprocessingsMu.Lock()
+	defer smpc.preprocessingsMu.Unlock()
+
+	select {
+	case <-session.done:
+		return false
+	default:
+	}
+	close(session.done)
+	if sessions, ok := smpc.preprocessings[session.networkID]; ok && sessions[session.id] == session {
+		delete(sessions, session.id)
+	}
+	return true
+}
+
+// failPreprocessings fails all preprocessings in a network, and stops
+// tracking preprocessings for the network.
+func (smpc *smpcer) failPreprocessings(networkID NetworkID, err error) {
+	smpc.preprocessingsMu.Lock()
+	sessions := make([]*preprocessing, 0, len(smpc.preprocessings[networkID]))
+	for _, session := range smpc.preprocessings[networkID] {
+		sessions = append(sessions, session)
+	}
+	delete(smpc.preprocessings, networkID)
+	delete(smpc.preprocessResharers, networkID)
+	smpc.preprocessingsMu.Unlock()
+
+	for _, session := range sessions {
+		smpc.failPreprocessing(session, err)
+	}
+}
+
+// triple returns the product of a preprocessing, at position i, as a Triple.
+// The first products are the products of the Triples, and the rest are the
+// squares of the random values used to generate random bits.
+func (session *preprocessing) triple(i int) Triple {
+	numTriples := session.numTriples
+	if i < numTriples {
+		return Triple{
+			A: session.randoms[i],
+			B: session.randoms[numTriples+i],
+			C: session.products[i],
+		}
+	}
+	u := session.randoms[numTriples+i]
+	return Triple{
+		A: u,
+		B: u,
+		C: session.products[i],
+	}
+}
+
+// sacrifice returns the Triple that is sacrificed to check the product of a
+// preprocessing at position i.
+func (session *preprocessing) sacrifice(i int) Triple {
+	numChecks := session.numTriples + session.numBits
+	offset := 2*session.numTriples + session.numBits
+	return Triple{
+		A: session.randoms[offset+i],
+		B: session.randoms[offset+numChecks+i],
+		C: session.products[numChecks+i],
+	}
+}
+
+func preprocessingReshareID(id [32]byte, round byte, chunk int) ReshareID {
+	hash := crypto.Keccak256(id[:], []byte{round, byte(chunk)})
+	reshareID := ReshareID{}
+	copy(reshareID[:], hash)
+	return reshareID
+}
+
+func numChunks(n int) int {
+	return (n + MaxJoinLength - 1) / MaxJoinLength
+}
+
+func chunkBounds(chunk, n int) (int, int) {
+	begin := chunk * MaxJoinLength
+	end := begin + MaxJoinLength
+	if begin > n {
+		begin = n
+	}
+	if end > n {
+		end = n
+	}
+	return begin, end
+}
+
+// sqrtExp is the exponent that computes square roots in the finite field,
+// which is possible because shamir.Prime is 3 mod 4.
+var sqrtExp = big.NewInt(0).Div(big.NewInt(0).Add(prime, big.NewInt(1)), big.NewInt(4))
+
+var invTwo = big.NewInt(0).ModInverse(big.NewInt(2), prime).Uint64()
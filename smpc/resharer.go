@@ -0,0 +1,374 @@
+package smpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// ErrReshareUnequal is returned when a Reshare is inconsistent with the other
+// Reshares that have been received for the same ReshareID.
+var ErrReshareUnequal = errors.New("reshare unequal")
+
+// ErrReshareUnauthenticated is returned when a Reshare is received from a node
+// that does not hold the shamir.Shares at the Index of the Reshare.
+var ErrReshareUnauthenticated = errors.New("reshare unauthenticated")
+
+// ErrReshareOnDisconnectedNetwork is returned when an Smpcer attempts to
+// access a Resharer for a NetworkID that has not been connected to.
+var ErrReshareOnDisconnectedNetwork = errors.New("reshare on disconnected network")
+
+// ErrUnmarshalReshareData is returned when the public data of a Reshare cannot
+// be unmarshaled from binary.
+var ErrUnmarshalReshareData = errors.New("unmarshal reshare data")
+
+// MaxReshareDataLength is the maximum number of bytes of public data that can
+// be attached to a Reshare.
+const MaxReshareDataLength = 4096
+
+// A ReshareID is used to identify the values being reshared. All nodes that
+// reshare the same values must use the same ReshareID.
+type ReshareID [32]byte
+
+// A Reshare is sent from a node holding shamir.Shares of some values to a node
+// that will hold new shamir.Shares of the same values. Each shamir.Share held
+// by the sender is split again and the Reshare carries the pieces destined for
+// the receiver. Index is the index of the shamir.Shares held by the sender,
+// and the shamir.Shares in the Reshare have the index of the receiver. K is
+// the number of Reshares needed to produce the new shamir.Shares, and N is the
+// number of nodes that are resharing.
+type Reshare struct {
+	ID     ReshareID
+	Index  JoinIndex
+	N      int64
+	K      int64
+	Shares shamir.Shares
+
+	// Data is public data about the values that is passed to the
+	// ReshareCallback. It is not secret shared.
+	Data []byte
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (reshare *Reshare) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, reshare.ID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, reshare.Index); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, reshare.N); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, reshare.K); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(reshare.Shares))); err != nil {
+		return nil, err
+	}
+	for _, share := range reshare.Shares {
+		shareData, err := share.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, shareData); err != nil {
+			return nil, err
+		}
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(reshare.Data))); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, reshare.Data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (reshare *Reshare) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &reshare.ID); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &reshare.Index); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &reshare.N); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &reshare.K); err != nil {
+		return err
+	}
+	numShares := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numShares); err != nil {
+		return err
+	}
+	if numShares < 0 || numShares > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	reshare.Shares = make(shamir.Shares, numShares)
+	for i := int64(0); i < numShares; i++ {
+		shareData := [16]byte{}
+		if _, err := buf.Read(shareData[:]); err != nil {
+			return err
+		}
+		if err := reshare.Shares[i].UnmarshalBinary(shareData[:]); err != nil {
+			return err
+		}
+	}
+	numDataBytes := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numDataBytes); err != nil {
+		return err
+	}
+	if numDataBytes < 0 || numDataBytes > MaxReshareDataLength || numDataBytes > int64(buf.Len()) {
+		return ErrUnmarshalReshareData
+	}
+	reshare.Data = make([]byte, numDataBytes)
+	if _, err := buf.Read(reshare.Data); err != nil && numDataBytes > 0 {
+		return err
+	}
+	return nil
+}
+
+// ReshareCallback is called with the new shamir.Shares, and the public data,
+// produced by a Resharer. ReshareCallbacks must ensure their own concurrent
+// safety.
+type ReshareCallback func(ReshareID, shamir.Shares, []byte)
+
+// ReshareSenders returns the identity.Addresses of the nodes that reshare the
+// values identified by a ReshareID. The node at position i holds the
+// shamir.Shares with index i+1, and is the only node that can send the Reshare
+// with JoinIndex i+1. No identity.Addresses are returned for values that are
+// not expected to be reshared.
+type ReshareSenders func(id ReshareID) identity.Addresses
+
+// ReshareSet is a set of Reshares, with different JoinIndices, but with the
+// same ReshareID.
+type ReshareSet struct {
+	Set  map[JoinIndex]Reshare
+	N    int64
+	K    int64
+	Len  int
+	Done bool
+}
+
+// A Resharer receives Reshares and groups them together based on their
+// ReshareID. Once Reshares have been received from all N resharing nodes, or
+// the timeout has passed since the first Reshare was received, the K Reshares
+// with the lowest JoinIndices are combined into new shamir.Shares.
+//
+// Using the lowest JoinIndices means that all receivers combine Reshares from
+// the same resharing nodes, as long as those nodes are live. Receivers that
+// combine Reshares from different nodes will produce new shamir.Shares that
+// are inconsistent with each other. Each Reshare is authenticated against the
+// ReshareSenders, so that a node cannot send a Reshare for a JoinIndex that it
+// does not hold.
+type Resharer struct {
+	timeout  time.Duration
+	senders  ReshareSenders
+	callback ReshareCallback
+
+	reshareSetsMu *sync.Mutex
+	reshareSets   map[ReshareID]ReshareSet
+}
+
+// NewResharer returns an empty Resharer that waits at most the timeout for
+// Reshares before combining them, and passes the new shamir.Shares to the
+// ReshareCallback. Reshares are only accepted from the ReshareSenders. A nil
+// ReshareSenders accepts Reshares from any node, and must only be used when
+// the sender of a Reshare is always trusted.
+func NewResharer(timeout time.Duration, senders ReshareSenders, callback ReshareCallback) *Resharer {
+	return &Resharer{
+		timeout:  timeout,
+		senders:  senders,
+		callback: callback,
+
+		reshareSetsMu: new(sync.Mutex),
+		reshareSets:   map[ReshareID]ReshareSet{},
+	}
+}
+
+// InsertReshare for a ReshareID, received from a node. If the insertion results
+// in Reshares from all resharing nodes being received, the ReshareCallback
+// will be called. All Reshares for a ReshareID must have the same public data.
+func (resharer *Resharer) InsertReshare(from identity.Address, reshare Reshare) error {
+	if len(reshare.Shares) > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	if reshare.K <= 0 || reshare.N < reshare.K || reshare.Index == 0 || uint64(reshare.Index) > uint64(reshare.N) {
+		return fmt.Errorf("%v: index %v is not valid for (%v, %v)", ErrReshareUnequal, reshare.Index, reshare.N, reshare.K)
+	}
+	for _, share := range reshare.Shares {
+		if share.Index != reshare.Shares[0].Index {
+			return fmt.Errorf("%v: shares have different indices", ErrReshareUnequal)
+		}
+	}
+	if resharer.senders != nil {
+		senders := resharer.senders(reshare.ID)
+		if int64(len(senders)) != reshare.N {
+			return fmt.Errorf("%v: expected %v resharing nodes, got %v", ErrReshareUnauthenticated, len(senders), reshare.N)
+		}
+		if senders[reshare.Index-1] != from {
+			return fmt.Errorf("%v: index %v does not belong to %v", ErrReshareUnauthenticated, reshare.Index, from)
+		}
+	}
+
+	complete, err := func() (bool, error) {
+		resharer.reshareSetsMu.Lock()
+		defer resharer.reshareSetsMu.Unlock()
+
+		reshareSet, ok := resharer.reshareSets[reshare.ID]
+		if !ok {
+			reshareSet = ReshareSet{
+				Set: map[JoinIndex]Reshare{},
+				N:   reshare.N,
+				K:   reshare.K,
+				Len: len(reshare.Shares),
+			}
+			time.AfterFunc(resharer.timeout, func() {
+				resharer.combine(reshare.ID)
+			})
+		}
+		if reshareSet.Done {
+			return false, nil
+		}
+		if reshare.N != reshareSet.N || reshare.K != reshareSet.K || len(reshare.Shares) != reshareSet.Len {
+			return false, fmt.Errorf("%v: expected (%v, %v) with %v shares, got (%v, %v) with %v shares", ErrReshareUnequal, reshareSet.N, reshareSet.K, reshareSet.Len, reshare.N, reshare.K, len(reshare.Shares))
+		}
+		for _, other := range reshareSet.Set {
+			if reshareSet.Len > 0 && other.Shares[0].Index != reshare.Shares[0].Index {
+				return false, fmt.Errorf("%v: expected index %v, got %v", ErrReshareUnequal, other.Shares[0].Index, reshare.Shares[0].Index)
+			}
+			// The public data of the lowest JoinIndex is passed to the
+			// ReshareCallback, so all senders must agree on it
+			if !bytes.Equal(other.Data, reshare.Data) {
+				return false, fmt.Errorf("%v: data from index %v differs from index %v", ErrReshareUnequal, reshare.Index, other.Index)
+			}
+			break
+		}
+		if _, ok := reshareSet.Set[reshare.Index]; ok {
+			return false, fmt.Errorf("%v: duplicate reshare from index %v", ErrReshareUnequal, reshare.Index)
+		}
+		reshareSet.Set[reshare.Index] = reshare
+		resharer.reshareSets[reshare.ID] = reshareSet
+
+		return int64(len(reshareSet.Set)) >= reshareSet.N, nil
+	}()
+	if err != nil {
+		return err
+	}
+	if complete {
+		resharer.combine(reshare.ID)
+	}
+	return nil
+}
+
+// combine the Reshares for a ReshareID into new shamir.Shares, and call the
+// ReshareCallback. The Reshares are only ever combined once.
+func (resharer *Resharer) combine(id ReshareID) {
+	shares, data, ok := func() (shamir.Shares, []byte, bool) {
+		resharer.reshareSetsMu.Lock()
+		defer resharer.reshareSetsMu.Unlock()
+
+		reshareSet, ok := resharer.reshareSets[id]
+		if !ok || reshareSet.Done {
+			return nil, nil, false
+		}
+
+		// Mark the ReshareSet as done, and drop the Reshares, regardless of
+		// whether or not the combination succeeds. The ReshareSet is deleted
+		// after another timeout, once late Reshares are no longer expected.
+		defer func() {
+			resharer.reshareSets[id] = ReshareSet{Done: true}
+			time.AfterFunc(resharer.timeout, func() {
+				resharer.reshareSetsMu.Lock()
+				defer resharer.reshareSetsMu.Unlock()
+				delete(resharer.reshareSets, id)
+			})
+		}()
+		if int64(len(reshareSet.Set)) < reshareSet.K {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot combine reshares for %v: expected %v, got %v", id, reshareSet.K, len(reshareSet.Set)))
+			return nil, nil, false
+		}
+
+		// Select the K Reshares with the lowest JoinIndices
+		indices := make([]JoinIndex, 0, len(reshareSet.Set))
+		for index := range reshareSet.Set {
+			indices = append(indices, index)
+		}
+		sort.Slice(indices, func(i, j int) bool {
+			return indices[i] < indices[j]
+		})
+		indices = indices[:reshareSet.K]
+
+		// Each new shamir.Share is the Lagrange interpolation, at zero, of the
+		// pieces received from the selected Reshares
+		shares := make(shamir.Shares, reshareSet.Len)
+		pieces := make(shamir.Shares, len(indices))
+		for i := range shares {
+			for j, index := range indices {
+				pieces[j] = shamir.Share{
+					Index: uint64(index),
+					Value: reshareSet.Set[index].Shares[i].Value,
+				}
+			}
+			shares[i] = shamir.Share{
+				Index: reshareSet.Set[indices[0]].Shares[i].Index,
+				Value: shamir.Join(pieces),
+			}
+		}
+		return shares, reshareSet.Set[indices[0]].Data, true
+	}()
+	if !ok {
+		return
+	}
+	if resharer.callback != nil {
+		resharer.callback(id, shares, data)
+	}
+}
+
+// ReshareShares splits each shamir.Share held by this node into n pieces, that
+// need k pieces to be reconstructed, and returns one Reshare for each of the n
+// nodes that will hold the new shamir.Shares. The Reshare at position i is for
+// the node with index i+1. Index is the index of the shamir.Shares held by this node,
+// and resharingN and resharingK are the number of nodes that hold the
+// shamir.Shares and the number of shamir.Shares needed to reconstruct them.
+func ReshareShares(id ReshareID, index JoinIndex, resharingN, resharingK int64, shares shamir.Shares, n, k int64, data []byte) ([]Reshare, error) {
+	if len(shares) > MaxJoinLength {
+		return nil, ErrJoinLengthExceedsMax
+	}
+	if len(data) > MaxReshareDataLength {
+		return nil, ErrUnmarshalReshareData
+	}
+
+	reshares := make([]Reshare, n)
+	for i := range reshares {
+		reshares[i] = Reshare{
+			ID:     id,
+			Index:  index,
+			N:      resharingN,
+			K:      resharingK,
+			Shares: make(shamir.Shares, len(shares)),
+			Data:   data,
+		}
+	}
+	for i, share := range shares {
+		pieces, err := shamir.Split(n, k, share.Value)
+		if err != nil {
+			return nil, err
+		}
+		for j := range reshares {
+			reshares[j].Shares[i] = pieces[j]
+		}
+	}
+	return reshares, nil
+}
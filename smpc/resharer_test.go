@@ -0,0 +1,200 @@
+package smpc_test
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Resharer", func() {
+
+	var n = int64(24)
+	var k = 2 * (n + 1) / 3
+	var newN = int64(18)
+	var newK = 2 * (newN + 1) / 3
+	var secrets = []uint64{42, 1337, shamir.Prime - 1}
+
+	Context("when resharing", func() {
+
+		It("should produce new shares of the same values", func() {
+			newShares, _ := reshareSecrets(secrets, n, k, newN, newK, n, time.Minute)
+			for i, secret := range secrets {
+				shares := make(shamir.Shares, newK)
+				for j := range shares {
+					shares[j] = newShares[j][i]
+				}
+				Expect(shamir.Join(shares)).Should(Equal(secret))
+			}
+		})
+
+		It("should produce new shares that cannot be combined with old shares", func() {
+			newShares, oldShares := reshareSecrets(secrets, n, k, newN, newK, n, time.Minute)
+			for i, secret := range secrets {
+				shares := make(shamir.Shares, k)
+				for j := range shares {
+					shares[j] = oldShares[j][i]
+				}
+				// Replace an old share with the new share that has the same
+				// index
+				shares[0] = newShares[0][i]
+				Expect(shamir.Join(shares)).ShouldNot(Equal(secret))
+			}
+		})
+
+		It("should combine the received reshares after the timeout", func() {
+			newShares, _ := reshareSecrets(secrets, n, k, newN, newK, k, 100*time.Millisecond)
+			for i, secret := range secrets {
+				shares := make(shamir.Shares, newK)
+				for j := range shares {
+					shares[j] = newShares[j][i]
+				}
+				Expect(shamir.Join(shares)).Should(Equal(secret))
+			}
+		})
+	})
+
+	Context("when inserting inconsistent reshares", func() {
+		It("should return an error", func() {
+			addrs := randomAddresses(int(n))
+			resharer := NewResharer(time.Minute, staticReshareSenders(addrs), nil)
+			reshares, err := ReshareShares(ReshareID{1}, 1, n, k, shamir.Shares{{Index: 1, Value: 1}}, newN, newK, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(resharer.InsertReshare(addrs[0], reshares[0])).ShouldNot(HaveOccurred())
+
+			reshares, err = ReshareShares(ReshareID{1}, 2, n, k-1, shamir.Shares{{Index: 2, Value: 1}}, newN, newK, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(resharer.InsertReshare(addrs[1], reshares[0])).Should(HaveOccurred())
+		})
+
+		It("should return an error when the senders disagree on the data", func() {
+			addrs := randomAddresses(int(n))
+			resharer := NewResharer(time.Minute, staticReshareSenders(addrs), nil)
+			reshares, err := ReshareShares(ReshareID{1}, 1, n, k, shamir.Shares{{Index: 1, Value: 1}}, newN, newK, []byte("data"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(resharer.InsertReshare(addrs[0], reshares[0])).ShouldNot(HaveOccurred())
+
+			reshares, err = ReshareShares(ReshareID{1}, 2, n, k, shamir.Shares{{Index: 2, Value: 1}}, newN, newK, []byte("other data"))
+			Expect(err).ShouldNot(HaveOccurred())
+			err = resharer.InsertReshare(addrs[1], reshares[0])
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(ErrReshareUnequal.Error()))
+		})
+	})
+
+	Context("when inserting reshares from unexpected senders", func() {
+		It("should return an error for an index that does not belong to the sender", func() {
+			addrs := randomAddresses(int(n))
+			resharer := NewResharer(time.Minute, staticReshareSenders(addrs), nil)
+			reshares, err := ReshareShares(ReshareID{1}, 2, n, k, shamir.Shares{{Index: 2, Value: 1}}, newN, newK, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = resharer.InsertReshare(addrs[0], reshares[0])
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(ErrReshareUnauthenticated.Error()))
+			Expect(resharer.InsertReshare(addrs[1], reshares[0])).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error for values that are not expected to be reshared", func() {
+			addrs := randomAddresses(int(n))
+			resharer := NewResharer(time.Minute, func(id ReshareID) identity.Addresses {
+				return nil
+			}, nil)
+			reshares, err := ReshareShares(ReshareID{1}, 1, n, k, shamir.Shares{{Index: 1, Value: 1}}, newN, newK, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = resharer.InsertReshare(addrs[0], reshares[0])
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(ErrReshareUnauthenticated.Error()))
+		})
+	})
+
+	Context("when marshaling and unmarshaling reshares", func() {
+		It("should get the same reshare after marshal and unmarshal", func() {
+			reshares, err := ReshareShares(ReshareID{1}, 3, n, k, shamir.Shares{{Index: 3, Value: 1}, {Index: 3, Value: 2}}, newN, newK, []byte("data"))
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, reshare := range reshares {
+				data, err := reshare.MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+
+				newReshare := Reshare{}
+				Expect(newReshare.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(newReshare).Should(Equal(reshare))
+			}
+		})
+	})
+})
+
+// reshareSecrets splits the secrets between n nodes, and reshares them from
+// the first numResharing nodes to newN nodes. It returns the new shares and
+// the old shares held by each node.
+func reshareSecrets(secrets []uint64, n, k, newN, newK, numResharing int64, timeout time.Duration) ([]shamir.Shares, []shamir.Shares) {
+	oldShares := make([]shamir.Shares, n)
+	for i := range oldShares {
+		oldShares[i] = make(shamir.Shares, len(secrets))
+	}
+	for i, secret := range secrets {
+		shares, err := shamir.Split(n, k, secret)
+		Expect(err).ShouldNot(HaveOccurred())
+		for j := range shares {
+			oldShares[j][i] = shares[j]
+		}
+	}
+
+	addrs := randomAddresses(int(n))
+	mu := new(sync.Mutex)
+	newShares := make([]shamir.Shares, newN)
+	resharers := make([]*Resharer, newN)
+	for j := range resharers {
+		j := j
+		resharers[j] = NewResharer(timeout, staticReshareSenders(addrs), func(id ReshareID, shares shamir.Shares, data []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			newShares[j] = shares
+		})
+	}
+
+	for i := int64(0); i < numResharing; i++ {
+		reshares, err := ReshareShares(ReshareID{}, JoinIndex(i+1), n, k, oldShares[i], newN, newK, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		for j := range reshares {
+			Expect(resharers[j].InsertReshare(addrs[i], reshares[j])).ShouldNot(HaveOccurred())
+		}
+	}
+
+	Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for j := range newShares {
+			if newShares[j] == nil {
+				return false
+			}
+		}
+		return true
+	}).Should(BeTrue())
+	return newShares, oldShares
+}
+
+// staticReshareSenders returns ReshareSenders that return the same
+// identity.Addresses for all ReshareIDs.
+func staticReshareSenders(addrs identity.Addresses) ReshareSenders {
+	return func(id ReshareID) identity.Addresses {
+		return addrs
+	}
+}
+
+func randomAddresses(n int) identity.Addresses {
+	addrs := make(identity.Addresses, n)
+	for i := range addrs {
+		addr, err := testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		addrs[i] = addr
+	}
+	return addrs
+}
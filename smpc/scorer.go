@@ -0,0 +1,58 @@
+package smpc
+
+import (
+	"sync"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// A FaultScorer counts the Faults found for each node. Its Record method can
+// be used as the FaultCallback of an Smpcer, so that nodes that repeatedly
+// send faulty Joins can be found. It is safe for concurrent use.
+type FaultScorer struct {
+	mu     *sync.RWMutex
+	scores map[identity.Address]uint64
+}
+
+// NewFaultScorer returns a FaultScorer that has not recorded any Faults.
+func NewFaultScorer() *FaultScorer {
+	return &FaultScorer{
+		mu:     new(sync.RWMutex),
+		scores: map[identity.Address]uint64{},
+	}
+}
+
+// Record the Faults found when reconstructing a JoinSet. Faults from unknown
+// nodes are ignored.
+func (scorer *FaultScorer) Record(faults []Fault) {
+	scorer.mu.Lock()
+	defer scorer.mu.Unlock()
+
+	for _, fault := range faults {
+		if fault.From == "" {
+			continue
+		}
+		scorer.scores[fault.From]++
+	}
+}
+
+// Score returns the number of Faults recorded for a node.
+func (scorer *FaultScorer) Score(addr identity.Address) uint64 {
+	scorer.mu.RLock()
+	defer scorer.mu.RUnlock()
+
+	return scorer.scores[addr]
+}
+
+// Scores returns the number of Faults recorded for each node that has sent at
+// least one faulty Join.
+func (scorer *FaultScorer) Scores() map[identity.Address]uint64 {
+	scorer.mu.RLock()
+	defer scorer.mu.RUnlock()
+
+	scores := make(map[identity.Address]uint64, len(scorer.scores))
+	for addr, score := range scorer.scores {
+		scores[addr] = score
+	}
+	return scores
+}
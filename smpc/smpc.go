@@ -1,11 +1,11 @@
 package smpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"math/big"
 	"sync"
+	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
@@ -17,6 +17,32 @@ import (
 // Joiner for a NetworkID that has not been connected to.
 var ErrJoinOnDisconnectedNetwork = errors.New("join on disconnected network")
 
+// ErrJoinTimeout is passed to an ErrorCallback when the deadline of a Join
+// passes before its values are reconstructed.
+var ErrJoinTimeout = errors.New("join timeout")
+
+// ErrJoinCanceled is passed to an ErrorCallback when the context of a Join is
+// canceled before its values are reconstructed.
+var ErrJoinCanceled = errors.New("join canceled")
+
+// ErrJoinWithoutCommitments is returned when joining before the
+// JoinCommitments for the Join have been inserted, unless the Smpcer allows
+// unverified Joins.
+var ErrJoinWithoutCommitments = errors.New("join without commitments")
+
+// ErrMaskedJoinWithoutFaultTolerance is returned when joining masked values
+// in a network that is too small to tolerate any faulty Joins, unless the
+// Smpcer allows unverified Joins.
+var ErrMaskedJoinWithoutFaultTolerance = errors.New("masked join without fault tolerance")
+
+// ErrJoinUnauthenticated is returned when a Join is received from a node that
+// does not hold the shamir.Shares at the JoinIndex of the Join.
+var ErrJoinUnauthenticated = errors.New("join unauthenticated")
+
+// ReshareTimeout is the maximum time that an Smpcer will wait for Reshares
+// from all resharing nodes before combining the Reshares it has received.
+const ReshareTimeout = 30 * time.Second
+
 // Smpcer is an interface for a secure multi-party computer. It asynchronously
 // consumes computation instructions and produces computation results.
 type Smpcer interface {
@@ -31,39 +57,207 @@ type Smpcer interface {
 
 	// Join a set of shamir.Shares for distinct values. This involves broadcast
 	// communication with the nodes in the network. On a success, the Callback
-	// is called.
-	Join(networkID NetworkID, join Join, callback Callback, useDelay bool) error
+	// is called. Otherwise, the ErrorCallback is called with ErrJoinTimeout,
+	// or ErrJoinCanceled, when the context is done, with
+	// ErrJoinOnDisconnectedNetwork when the network is disconnected, and with
+	// ErrUnverifiedJoin when too many Joins from other nodes cannot be
+	// verified. Exactly one of the callbacks is called, unless the Join is
+	// cancelled using Smpcer.CancelJoin. The ErrorCallback can be nil.
+	Join(ctx context.Context, networkID NetworkID, join Join, callback Callback, errCallback ErrorCallback, useDelay bool) error
+
+	// JoinMasked is the same as Join, but for shamir.Shares of values that
+	// have been masked by random values generated by the network, such as
+	// the values opened by multiplications and comparisons. These values
+	// cannot be committed to, so Joins from other nodes are not checked
+	// against JoinCommitments, and faulty Joins are found by reconstructing
+	// the values with the fault tolerance of the network. Joining fails with
+	// ErrMaskedJoinWithoutFaultTolerance in a network that cannot tolerate
+	// any faulty Joins.
+	JoinMasked(ctx context.Context, networkID NetworkID, join Join, callback Callback, errCallback ErrorCallback, useDelay bool) error
 
 	// InsertCommitments for the shamir.Shares inside a Join. These commitments
 	// are used to blind shamir.Shares while being able to verify that the
-	// computations performed have been done correctly.
+	// computations performed have been done correctly. They must be inserted
+	// before joining, otherwise joining will fail with
+	// ErrJoinWithoutCommitments.
 	InsertCommitments(networkID NetworkID, joinID JoinID, joinCommitments JoinCommitments)
+
+	// Reshare sends a Reshare to a node in a connected network. The node will
+	// combine it with the Reshares received from other nodes to produce new
+	// shamir.Shares, without ever reconstructing the values.
+	Reshare(networkID NetworkID, to identity.Address, reshare Reshare) error
+
+	// OnReshare sets the ReshareSenders, and the ReshareCallback, for a
+	// connected network. Reshares are only accepted from the ReshareSenders,
+	// and are rejected before the ReshareSenders are set. The ReshareCallback
+	// is called with the new shamir.Shares produced from the Reshares received
+	// by this node.
+	OnReshare(networkID NetworkID, senders ReshareSenders, callback ReshareCallback)
+
+	// Multiply pairs of shamir.Shares without reconstructing them. Triples are
+	// generated for the MultiplyID by all nodes in the network, and then
+	// consumed by joining the differences between the shamir.Shares and the
+	// Triples. On a success, the MultiplyCallback is called with shares of the
+	// products. Otherwise, the MultiplyErrorCallback is called. The context
+	// applies to the whole multiplication.
+	Multiply(ctx context.Context, networkID NetworkID, id MultiplyID, xs, ys shamir.Shares, callback MultiplyCallback, errCallback MultiplyErrorCallback) error
+
+	// ComparisonMaterial generates the ComparisonMaterial for a CompareID with
+	// all nodes in the network, so that an Smpcer can be used as the
+	// Preprocessor of a Comparer.
+	ComparisonMaterial(ctx context.Context, networkID NetworkID, id CompareID, callback ComparisonMaterialCallback, errCallback CompareErrorCallback) error
+
+	// CancelJoin drops all state held for a JoinID in a connected network.
+	// Callbacks that have been set for the JoinID will not be called.
+	CancelJoin(networkID NetworkID, joinID JoinID)
+
+	// JoinMetrics returns JoinMetrics for all connected networks.
+	JoinMetrics() map[NetworkID]JoinMetrics
+}
+
+// JoinMetrics describe the state held by an Smpcer for the Joins in a network.
+// State for a JoinID is evicted once it is older than DefaultJoinTTL, or once
+// more than DefaultMaxJoins JoinIDs are held.
+type JoinMetrics struct {
+	JoinSets        int
+	EvictedJoinSets uint64
+	SelfJoins       int
+	Commitments     int
+	DeferredJoins   int
 }
 
 type smpcer struct {
 	network Network
+	swarmer swarm.Swarmer
+
+	faultTolerance       int64
+	faultCallback        FaultCallback
+	allowUnverifiedJoins bool
+
+	joinersMu       *sync.RWMutex
+	joiners         map[NetworkID]*Joiner
+	networkSizes    map[NetworkID]int64
+	networkAddrs    map[NetworkID]identity.Addresses
+	faultTolerances map[NetworkID]int64
+
+	pendingMu *sync.Mutex
+	pending   map[NetworkID]map[JoinID]*pendingJoin
+
+	sendersMu *sync.RWMutex
+	senders   map[NetworkID]map[JoinIndex]identity.Address
 
-	joinersMu *sync.RWMutex
-	joiners   map[NetworkID]*Joiner
+	selfJoinsMu     *sync.RWMutex
+	selfJoins       map[NetworkID]map[JoinID]Join
+	selfJoinsExpiry map[NetworkID]*expiryQueue
 
-	selfJoinsMu *sync.RWMutex
-	selfJoins   map[JoinID]Join
+	commitmentsMu     *sync.RWMutex
+	commitments       map[NetworkID]map[JoinID]JoinCommitments
+	masked            map[NetworkID]map[JoinID]struct{}
+	deferredJoins     map[NetworkID]map[JoinID][]deferredJoin
+	rejections        map[NetworkID]map[JoinID]map[identity.Address]struct{}
+	commitmentsExpiry map[NetworkID]*expiryQueue
 
-	commitmentsMu *sync.RWMutex
-	commitments   map[NetworkID]map[JoinID]JoinCommitments
+	resharersMu      *sync.RWMutex
+	resharers        map[NetworkID]*Resharer
+	reshareSenders   map[NetworkID]ReshareSenders
+	reshareCallbacks map[NetworkID]ReshareCallback
+
+	preprocessingsMu    *sync.Mutex
+	preprocessings      map[NetworkID]map[[32]byte]*preprocessing
+	preprocessResharers map[NetworkID]*Resharer
+
+	batcher *joinBatcher
+}
+
+// A deferredJoin is a Join received from another node before the
+// JoinCommitments needed to verify it were available.
+type deferredJoin struct {
+	from    identity.Address
+	join    Join
+	respond bool
+}
+
+// SmpcerOptions configure an Smpcer. The zero value configures an Smpcer that
+// verifies every Join against JoinCommitments, does not tolerate faulty Joins,
+// and does not batch Joins.
+type SmpcerOptions struct {
+
+	// FaultTolerance is the number of faulty Joins that are tolerated when
+	// reconstructing values. The fault tolerance used for a network is
+	// reduced when the network is too small to support it.
+	FaultTolerance int64
+
+	// FaultCallback is called with the Faults found during reconstructions.
+	// It can be nil.
+	FaultCallback FaultCallback
+
+	// JoinBatchWindow is the duration for which Joins sent to a network are
+	// collected before they are sent in a single MessageJoinBatch. Batching
+	// is disabled when it is zero. All nodes in a network must understand a
+	// MessageJoinBatch before any of them enable batching.
+	JoinBatchWindow time.Duration
+
+	// AllowUnverifiedJoins accepts Joins from other nodes for which no
+	// JoinCommitments, or only some shamir.Commitments, were inserted, and
+	// masked Joins in networks that cannot tolerate faulty Joins. By default,
+	// every Join that cannot be verified against JoinCommitments is rejected,
+	// and Joins of masked values are reconstructed with the fault tolerance of
+	// the network instead.
+	//
+	// Deprecated: AllowUnverifiedJoins only exists to match orders that were
+	// opened without commitments, and will be removed.
+	AllowUnverifiedJoins bool
 }
 
 // NewSmpcer returns an Smpcer node that is not connected to a network.
 func NewSmpcer(conn ConnectorListener, swarmer swarm.Swarmer) Smpcer {
-	smpc := &smpcer{
-		joinersMu: new(sync.RWMutex),
-		joiners:   map[NetworkID]*Joiner{},
-
-		selfJoinsMu: new(sync.RWMutex),
-		selfJoins:   map[JoinID]Join{},
+	return NewSmpcerWithOptions(conn, swarmer, SmpcerOptions{})
+}
 
-		commitmentsMu: new(sync.RWMutex),
-		commitments:   map[NetworkID]map[JoinID]JoinCommitments{},
+// NewSmpcerWithOptions returns an Smpcer node that is not connected to a
+// network, and that is configured by the SmpcerOptions.
+func NewSmpcerWithOptions(conn ConnectorListener, swarmer swarm.Swarmer, options SmpcerOptions) Smpcer {
+	smpc := &smpcer{
+		swarmer: swarmer,
+
+		faultTolerance:       options.FaultTolerance,
+		faultCallback:        options.FaultCallback,
+		allowUnverifiedJoins: options.AllowUnverifiedJoins,
+
+		joinersMu:       new(sync.RWMutex),
+		joiners:         map[NetworkID]*Joiner{},
+		networkSizes:    map[NetworkID]int64{},
+		networkAddrs:    map[NetworkID]identity.Addresses{},
+		faultTolerances: map[NetworkID]int64{},
+
+		pendingMu: new(sync.Mutex),
+		pending:   map[NetworkID]map[JoinID]*pendingJoin{},
+
+		sendersMu: new(sync.RWMutex),
+		senders:   map[NetworkID]map[JoinIndex]identity.Address{},
+
+		selfJoinsMu:     new(sync.RWMutex),
+		selfJoins:       map[NetworkID]map[JoinID]Join{},
+		selfJoinsExpiry: map[NetworkID]*expiryQueue{},
+
+		commitmentsMu:     new(sync.RWMutex),
+		commitments:       map[NetworkID]map[JoinID]JoinCommitments{},
+		masked:            map[NetworkID]map[JoinID]struct{}{},
+		deferredJoins:     map[NetworkID]map[JoinID][]deferredJoin{},
+		rejections:        map[NetworkID]map[JoinID]map[identity.Address]struct{}{},
+		commitmentsExpiry: map[NetworkID]*expiryQueue{},
+
+		resharersMu:      new(sync.RWMutex),
+		resharers:        map[NetworkID]*Resharer{},
+		reshareSenders:   map[NetworkID]ReshareSenders{},
+		reshareCallbacks: map[NetworkID]ReshareCallback{},
+
+		preprocessingsMu:    new(sync.Mutex),
+		preprocessings:      map[NetworkID]map[[32]byte]*preprocessing{},
+		preprocessResharers: map[NetworkID]*Resharer{},
+	}
+	if options.JoinBatchWindow > 0 {
+		smpc.batcher = newJoinBatcher(options.JoinBatchWindow, smpc.sendJoins)
 	}
 	smpc.network = NewNetwork(conn, smpc, swarmer)
 	return smpc
@@ -72,36 +266,150 @@ func NewSmpcer(conn ConnectorListener, swarmer swarm.Swarmer) Smpcer {
 // Connect implements the Smpcer interface.
 func (smpc *smpcer) Connect(networkID NetworkID, addrs identity.Addresses) {
 	k := int64(2 * (len(addrs) + 1) / 3)
+	t := smpc.faultTolerance
+	if maxT := (int64(len(addrs)) - k) / 2; t > maxT {
+		t = maxT
+	}
+	if t < 0 {
+		t = 0
+	}
+
+	smpc.sendersMu.Lock()
+	smpc.senders[networkID] = map[JoinIndex]identity.Address{}
+	smpc.sendersMu.Unlock()
 
 	smpc.joinersMu.Lock()
-	smpc.joiners[networkID] = NewJoiner(k)
+	smpc.joiners[networkID] = NewRobustJoiner(k, t, func(faults []Fault) {
+		smpc.handleFaults(networkID, faults)
+	})
+	smpc.networkSizes[networkID] = int64(len(addrs))
+	smpc.networkAddrs[networkID] = addrs
+	smpc.faultTolerances[networkID] = t
 	smpc.joinersMu.Unlock()
 
+	smpc.pendingMu.Lock()
+	if _, ok := smpc.pending[networkID]; !ok {
+		smpc.pending[networkID] = map[JoinID]*pendingJoin{}
+	}
+	smpc.pendingMu.Unlock()
+
+	smpc.selfJoinsMu.Lock()
+	smpc.selfJoins[networkID] = map[JoinID]Join{}
+	smpc.selfJoinsExpiry[networkID] = newExpiryQueue(DefaultJoinTTL, DefaultMaxJoins)
+	smpc.selfJoinsMu.Unlock()
+
 	smpc.commitmentsMu.Lock()
 	smpc.commitments[networkID] = map[JoinID]JoinCommitments{}
+	smpc.masked[networkID] = map[JoinID]struct{}{}
+	smpc.deferredJoins[networkID] = map[JoinID][]deferredJoin{}
+	smpc.rejections[networkID] = map[JoinID]map[identity.Address]struct{}{}
+	smpc.commitmentsExpiry[networkID] = newExpiryQueue(DefaultJoinTTL, DefaultMaxJoins)
 	smpc.commitmentsMu.Unlock()
 
+	smpc.resharersMu.Lock()
+	smpc.resharers[networkID] = NewResharer(ReshareTimeout, func(id ReshareID) identity.Addresses {
+		return smpc.resharingAddresses(networkID, id)
+	}, func(id ReshareID, shares shamir.Shares, data []byte) {
+		smpc.handleReshared(networkID, id, shares, data)
+	})
+	smpc.resharersMu.Unlock()
+
+	smpc.preprocessingsMu.Lock()
+	if _, ok := smpc.preprocessings[networkID]; !ok {
+		smpc.preprocessings[networkID] = map[[32]byte]*preprocessing{}
+	}
+	// All nodes in the network deal random values, and the node at position i
+	// deals them with the index i+1
+	smpc.preprocessResharers[networkID] = NewResharer(ReshareTimeout, func(id ReshareID) identity.Addresses {
+		return addrs
+	}, func(id ReshareID, shares shamir.Shares, data []byte) {
+		smpc.handlePreprocessReshared(networkID, shares, data)
+	})
+	smpc.preprocessingsMu.Unlock()
+
 	smpc.network.Connect(networkID, addrs)
 }
 
 // Disconnect implements the Smpcer interface.
 func (smpc *smpcer) Disconnect(networkID NetworkID) {
 	smpc.network.Disconnect(networkID)
+	smpc.failPendingJoins(networkID, ErrJoinOnDisconnectedNetwork)
+	smpc.failPreprocessings(networkID, ErrJoinOnDisconnectedNetwork)
+	if smpc.batcher != nil {
+		smpc.batcher.drop(networkID)
+	}
 
 	smpc.joinersMu.Lock()
 	delete(smpc.joiners, networkID)
+	delete(smpc.networkSizes, networkID)
+	delete(smpc.networkAddrs, networkID)
+	delete(smpc.faultTolerances, networkID)
 	smpc.joinersMu.Unlock()
 
+	smpc.sendersMu.Lock()
+	delete(smpc.senders, networkID)
+	smpc.sendersMu.Unlock()
+
+	smpc.selfJoinsMu.Lock()
+	delete(smpc.selfJoins, networkID)
+	delete(smpc.selfJoinsExpiry, networkID)
+	smpc.selfJoinsMu.Unlock()
+
 	smpc.commitmentsMu.Lock()
 	delete(smpc.commitments, networkID)
+	delete(smpc.masked, networkID)
+	delete(smpc.deferredJoins, networkID)
+	delete(smpc.rejections, networkID)
+	delete(smpc.commitmentsExpiry, networkID)
 	smpc.commitmentsMu.Unlock()
+
+	smpc.resharersMu.Lock()
+	delete(smpc.resharers, networkID)
+	delete(smpc.reshareSenders, networkID)
+	delete(smpc.reshareCallbacks, networkID)
+	smpc.resharersMu.Unlock()
 }
 
 // Join implements the Smpcer interface.
-func (smpc *smpcer) Join(networkID NetworkID, join Join, callback Callback, useDelay bool) error {
-	smpc.selfJoinsMu.Lock()
-	smpc.selfJoins[join.ID] = join
-	smpc.selfJoinsMu.Unlock()
+func (smpc *smpcer) Join(ctx context.Context, networkID NetworkID, join Join, callback Callback, errCallback ErrorCallback, useDelay bool) error {
+	return smpc.join(ctx, networkID, join, errCallback, func(joiner *Joiner, pending *pendingJoin) error {
+		return joiner.InsertJoinAndSetCallback(join, func(joinID JoinID, values []uint64) {
+			if smpc.completePendingJoin(networkID, joinID, pending) {
+				callback(joinID, values)
+			}
+		})
+	}, false, useDelay)
+}
+
+// JoinMasked implements the Smpcer interface.
+func (smpc *smpcer) JoinMasked(ctx context.Context, networkID NetworkID, join Join, callback Callback, errCallback ErrorCallback, useDelay bool) error {
+	if !smpc.allowUnverifiedJoins {
+		smpc.joinersMu.RLock()
+		t, ok := smpc.faultTolerances[networkID]
+		smpc.joinersMu.RUnlock()
+		if ok && t == 0 {
+			return ErrMaskedJoinWithoutFaultTolerance
+		}
+	}
+	return smpc.join(ctx, networkID, join, errCallback, func(joiner *Joiner, pending *pendingJoin) error {
+		return joiner.InsertJoinAndSetCallback(join, func(joinID JoinID, values []uint64) {
+			if smpc.completePendingJoin(networkID, joinID, pending) {
+				callback(joinID, values)
+			}
+		})
+	}, true, useDelay)
+}
+
+// join inserts a Join owned by this node into the Joiner for its network, and
+// sends it to all nodes in the network. Joins of masked values are not checked
+// against JoinCommitments.
+func (smpc *smpcer) join(ctx context.Context, networkID NetworkID, join Join, errCallback ErrorCallback, insert func(joiner *Joiner, pending *pendingJoin) error, masked, useDelay bool) error {
+	if masked {
+		smpc.insertMasked(networkID, join.ID)
+	} else if !smpc.allowUnverifiedJoins && !smpc.hasCommitments(networkID, join.ID) {
+		return ErrJoinWithoutCommitments
+	}
+	smpc.insertSelfJoin(networkID, join)
 
 	smpc.joinersMu.RLock()
 	joiner, joinerOk := smpc.joiners[networkID]
@@ -109,71 +417,321 @@ func (smpc *smpcer) Join(networkID NetworkID, join Join, callback Callback, useD
 	if !joinerOk {
 		return ErrJoinOnDisconnectedNetwork
 	}
-	if err := joiner.InsertJoinAndSetCallback(join, callback); err != nil {
+
+	// The pending Join must exist before inserting the Join, because the
+	// insertion can result in the Callback being called
+	pending := smpc.insertPendingJoin(ctx, networkID, join.ID, errCallback)
+	if err := insert(joiner, pending); err != nil {
+		smpc.completePendingJoin(networkID, join.ID, pending)
 		return err
 	}
 
+	// If no JoinCommitments were inserted before joining then Joins from other
+	// nodes cannot be verified, and there is no reason to keep deferring them.
+	// Unless the Smpcer allows unverified Joins, they will be rejected.
+	smpc.insertCommitments(networkID, join.ID, JoinCommitments{}, false)
+	smpc.checkRejections(networkID, join.ID)
+
+	if smpc.batcher != nil {
+		smpc.batcher.insert(networkID, join, useDelay)
+		return nil
+	}
+	smpc.sendJoins(networkID, []Join{join}, useDelay)
+	return nil
+}
+
+// sendJoins to all nodes in a network. A single Join is sent in a MessageJoin,
+// and many Joins are sent in a MessageJoinBatch.
+func (smpc *smpcer) sendJoins(networkID NetworkID, joins []Join, useDelay bool) {
 	message := Message{
 		MessageType: MessageTypeJoin,
 		MessageJoin: &MessageJoin{
 			NetworkID: networkID,
-			Join:      join,
+			Join:      joins[0],
 		},
 	}
+	if len(joins) > 1 {
+		message = Message{
+			MessageType: MessageTypeJoinBatch,
+			MessageJoinBatch: &MessageJoinBatch{
+				NetworkID: networkID,
+				Joins:     joins,
+			},
+		}
+	}
 	if useDelay {
 		smpc.network.SendWithDelay(networkID, message)
 	} else {
 		smpc.network.Send(networkID, message)
 	}
+}
 
-	return nil
+// InsertCommitments implements the Smpcer interface. Joins from other nodes
+// that were received before the JoinCommitments are verified, and inserted,
+// once the JoinCommitments are available.
+func (smpc *smpcer) InsertCommitments(networkID NetworkID, joinID JoinID, joinCommitments JoinCommitments) {
+	smpc.insertCommitments(networkID, joinID, joinCommitments, true)
+}
+
+func (smpc *smpcer) insertCommitments(networkID NetworkID, joinID JoinID, joinCommitments JoinCommitments, override bool) {
+	if joinCommitments == nil {
+		joinCommitments = JoinCommitments{}
+	}
+	deferredJoins := func() []deferredJoin {
+		smpc.commitmentsMu.Lock()
+		defer smpc.commitmentsMu.Unlock()
+
+		commitments, ok := smpc.commitments[networkID]
+		if !ok {
+			return nil
+		}
+		if _, ok := commitments[joinID]; ok && !override {
+			return nil
+		}
+		commitments[joinID] = joinCommitments
+		smpc.expireCommitments(networkID, joinID)
+
+		deferredJoins := smpc.deferredJoins[networkID][joinID]
+		delete(smpc.deferredJoins[networkID], joinID)
+		return deferredJoins
+	}()
+
+	for _, deferredJoin := range deferredJoins {
+		if err := smpc.handleJoin(deferredJoin.from, networkID, deferredJoin.join, deferredJoin.respond); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling deferred join from smpc node %v: %v", deferredJoin.from, err))
+		}
+	}
+}
+
+// hasCommitments returns true when JoinCommitments for at least one JoinIndex
+// have been inserted for a JoinID.
+func (smpc *smpcer) hasCommitments(networkID NetworkID, joinID JoinID) bool {
+	smpc.commitmentsMu.RLock()
+	defer smpc.commitmentsMu.RUnlock()
+
+	return len(smpc.commitments[networkID][joinID]) > 0
 }
 
-func (smpc *smpcer) InsertCommitments(networkID NetworkID, joinID JoinID, joinCommitements JoinCommitments) {
+// insertMasked records that a JoinID is used to join masked values, so that
+// Joins from other nodes are not checked against JoinCommitments.
+func (smpc *smpcer) insertMasked(networkID NetworkID, joinID JoinID) {
 	smpc.commitmentsMu.Lock()
 	defer smpc.commitmentsMu.Unlock()
 
-	if _, ok := smpc.commitments[networkID]; !ok {
+	masked, ok := smpc.masked[networkID]
+	if !ok {
 		return
 	}
-	smpc.commitments[networkID][joinID] = joinCommitements
+	masked[joinID] = struct{}{}
+	smpc.expireCommitments(networkID, joinID)
+}
+
+// Reshare implements the Smpcer interface. A Reshare addressed to this node
+// is inserted directly into the Resharer for the network.
+func (smpc *smpcer) Reshare(networkID NetworkID, to identity.Address, reshare Reshare) error {
+	if to == smpc.swarmer.MultiAddress().Address() {
+		return smpc.handleReshare(to, networkID, reshare)
+	}
+
+	smpc.resharersMu.RLock()
+	_, ok := smpc.resharers[networkID]
+	smpc.resharersMu.RUnlock()
+	if !ok {
+		return ErrReshareOnDisconnectedNetwork
+	}
+
+	message := Message{
+		MessageType: MessageTypeReshare,
+		MessageReshare: &MessageReshare{
+			NetworkID: networkID,
+			Reshare:   reshare,
+		},
+	}
+	smpc.network.SendTo(networkID, to, message)
+	return nil
+}
+
+// OnReshare implements the Smpcer interface.
+func (smpc *smpcer) OnReshare(networkID NetworkID, senders ReshareSenders, callback ReshareCallback) {
+	smpc.resharersMu.Lock()
+	defer smpc.resharersMu.Unlock()
+
+	if _, ok := smpc.resharers[networkID]; ok {
+		smpc.reshareSenders[networkID] = senders
+		smpc.reshareCallbacks[networkID] = callback
+	}
+}
+
+// CancelJoin implements the Smpcer interface.
+func (smpc *smpcer) CancelJoin(networkID NetworkID, joinID JoinID) {
+	smpc.joinersMu.RLock()
+	if joiner, ok := smpc.joiners[networkID]; ok {
+		joiner.CancelJoin(joinID)
+	}
+	smpc.joinersMu.RUnlock()
+
+	smpc.selfJoinsMu.Lock()
+	if selfJoins, ok := smpc.selfJoins[networkID]; ok {
+		delete(selfJoins, joinID)
+		smpc.selfJoinsExpiry[networkID].remove(joinID)
+	}
+	smpc.selfJoinsMu.Unlock()
+
+	smpc.commitmentsMu.Lock()
+	if commitments, ok := smpc.commitments[networkID]; ok {
+		delete(commitments, joinID)
+		delete(smpc.masked[networkID], joinID)
+		delete(smpc.deferredJoins[networkID], joinID)
+		delete(smpc.rejections[networkID], joinID)
+		smpc.commitmentsExpiry[networkID].remove(joinID)
+	}
+	smpc.commitmentsMu.Unlock()
+
+	smpc.pendingMu.Lock()
+	if pending, ok := smpc.pending[networkID][joinID]; ok {
+		delete(smpc.pending[networkID], joinID)
+		close(pending.done)
+	}
+	smpc.pendingMu.Unlock()
+}
+
+// JoinMetrics implements the Smpcer interface.
+func (smpc *smpcer) JoinMetrics() map[NetworkID]JoinMetrics {
+	metrics := map[NetworkID]JoinMetrics{}
+
+	smpc.joinersMu.RLock()
+	for networkID, joiner := range smpc.joiners {
+		metrics[networkID] = JoinMetrics{
+			JoinSets:        joiner.Len(),
+			EvictedJoinSets: joiner.Evicted(),
+		}
+	}
+	smpc.joinersMu.RUnlock()
+
+	smpc.selfJoinsMu.RLock()
+	for networkID, selfJoins := range smpc.selfJoins {
+		if networkMetrics, ok := metrics[networkID]; ok {
+			networkMetrics.SelfJoins = len(selfJoins)
+			metrics[networkID] = networkMetrics
+		}
+	}
+	smpc.selfJoinsMu.RUnlock()
+
+	smpc.commitmentsMu.RLock()
+	for networkID, commitments := range smpc.commitments {
+		if networkMetrics, ok := metrics[networkID]; ok {
+			networkMetrics.Commitments = len(commitments)
+			networkMetrics.DeferredJoins = len(smpc.deferredJoins[networkID])
+			metrics[networkID] = networkMetrics
+		}
+	}
+	smpc.commitmentsMu.RUnlock()
+
+	return metrics
 }
 
 // Receive implements the Receiver interface.
 func (smpc *smpcer) Receive(from identity.Address, message Message) {
 	switch message.MessageType {
 	case MessageTypeJoin:
-		if err := smpc.handleMessageJoin(from, message.MessageJoin); err != nil {
+		if err := smpc.handleJoin(from, message.MessageJoin.NetworkID, message.MessageJoin.Join, true); err != nil {
 			logger.Network(logger.LevelError, fmt.Sprintf("error handling join message from smpc node %v: %v", from, err))
 		}
 	case MessageTypeJoinResponse:
-		if err := smpc.handleMessageJoinResponse(message.MessageJoinResponse); err != nil {
+		if err := smpc.handleJoin(from, message.MessageJoinResponse.NetworkID, message.MessageJoinResponse.Join, false); err != nil {
 			logger.Network(logger.LevelError, fmt.Sprintf("error handling joinResponse message from smpc node %v: %v", from, err))
 		}
+	case MessageTypeReshare:
+		if err := smpc.handleReshare(from, message.MessageReshare.NetworkID, message.MessageReshare.Reshare); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling reshare message from smpc node %v: %v", from, err))
+		}
+	case MessageTypeJoinBatch:
+		smpc.handleJoinBatch(from, message.MessageJoinBatch.NetworkID, message.MessageJoinBatch.Joins, true)
+	case MessageTypeJoinResponseBatch:
+		smpc.handleJoinBatch(from, message.MessageJoinResponseBatch.NetworkID, message.MessageJoinResponseBatch.Joins, false)
+	case MessageTypePreprocess:
+		if err := smpc.handlePreprocessReshare(from, message.MessagePreprocess.NetworkID, message.MessagePreprocess.Reshare); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling preprocess message from smpc node %v: %v", from, err))
+		}
 	default:
 		logger.Network(logger.LevelError, fmt.Sprintf("error receiving message from smpc node %v: %v", from, ErrUnexpectedMessageType))
 	}
 }
 
-func (smpc *smpcer) handleMessageJoin(from identity.Address, message *MessageJoin) error {
-	if !smpc.verifyJoin(message.NetworkID, message.Join) {
-		return ErrUnverifiedJoin
+// handleJoin verifies a Join received from another node and inserts it into
+// the Joiner for its network. If the JoinCommitments for the Join are not yet
+// available, the Join is deferred until they are. When respond is true, and
+// this node has its own Join for the JoinID, it will be sent back to the node.
+func (smpc *smpcer) handleJoin(from identity.Address, networkID NetworkID, join Join, respond bool) error {
+	// Only the node at the JoinIndex of a Join can send it, otherwise one node
+	// could send Joins for every JoinIndex
+	smpc.joinersMu.RLock()
+	addrs, ok := smpc.networkAddrs[networkID]
+	smpc.joinersMu.RUnlock()
+	if !ok {
+		// The network is not connected so the Join will be ignored
+		return nil
+	}
+	if join.Index == 0 || join.Index > JoinIndex(len(addrs)) {
+		return fmt.Errorf("%v: index %v is out of range", ErrJoinUnauthenticated, join.Index)
+	}
+	if addrs[join.Index-1] != from {
+		return fmt.Errorf("%v: index %v does not belong to %v", ErrJoinUnauthenticated, join.Index, from)
+	}
+
+	joinCommitments, masked, ok := func() (JoinCommitments, bool, bool) {
+		smpc.commitmentsMu.Lock()
+		defer smpc.commitmentsMu.Unlock()
+
+		commitments, ok := smpc.commitments[networkID]
+		if !ok {
+			// The network is not connected so the Join will be ignored
+			return nil, false, true
+		}
+		if _, ok := smpc.masked[networkID][join.ID]; ok {
+			return nil, true, true
+		}
+		joinCommitments, ok := commitments[join.ID]
+		if !ok {
+			smpc.deferredJoins[networkID][join.ID] = append(smpc.deferredJoins[networkID][join.ID], deferredJoin{
+				from:    from,
+				join:    join,
+				respond: respond,
+			})
+			smpc.expireCommitments(networkID, join.ID)
+			return nil, false, false
+		}
+		return joinCommitments, false, true
+	}()
+	if !ok {
+		return nil
 	}
 
+	if err := smpc.verifyJoin(join, joinCommitments, masked); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("✗ rejecting join from smpc node %v with index %v: %v", from, join.Index, err))
+		smpc.insertRejection(networkID, join.ID, from)
+		smpc.checkRejections(networkID, join.ID)
+		return err
+	}
+
+	smpc.sendersMu.Lock()
+	if senders, ok := smpc.senders[networkID]; ok {
+		senders[join.Index] = from
+	}
+	smpc.sendersMu.Unlock()
+
 	var err error
 	smpc.joinersMu.RLock()
-	if joiner, ok := smpc.joiners[message.NetworkID]; ok {
-		err = joiner.InsertJoin(message.Join)
+	if joiner, ok := smpc.joiners[networkID]; ok {
+		err = joiner.InsertJoin(join)
 	}
 	smpc.joinersMu.RUnlock()
-	if err != nil {
+	if err != nil || !respond {
 		return err
 	}
 
 	go func() {
-		smpc.selfJoinsMu.RLock()
-		join, ok := smpc.selfJoins[message.Join.ID]
-		smpc.selfJoinsMu.RUnlock()
+		selfJoin, ok := smpc.selfJoin(networkID, join.ID)
 		if !ok {
 			return
 		}
@@ -181,97 +739,193 @@ func (smpc *smpcer) handleMessageJoin(from identity.Address, message *MessageJoi
 		response := Message{
 			MessageType: MessageTypeJoinResponse,
 			MessageJoinResponse: &MessageJoinResponse{
-				NetworkID: message.NetworkID,
-				Join:      join,
+				NetworkID: networkID,
+				Join:      selfJoin,
 			},
 		}
-		smpc.network.SendTo(message.NetworkID, from, response)
+		smpc.network.SendTo(networkID, from, response)
 	}()
 
 	return nil
 }
 
-func (smpc *smpcer) handleMessageJoinResponse(message *MessageJoinResponse) error {
-	if !smpc.verifyJoin(message.NetworkID, message.Join) {
-		return ErrUnverifiedJoin
+// handleJoinBatch handles each Join in a batch received from another node.
+// When respond is true, the Joins owned by this node for the JoinIDs in the
+// batch are sent back to the node in a single batch.
+func (smpc *smpcer) handleJoinBatch(from identity.Address, networkID NetworkID, joins []Join, respond bool) {
+	selfJoins := make([]Join, 0, len(joins))
+	for _, join := range joins {
+		if err := smpc.handleJoin(from, networkID, join, false); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling batched join from smpc node %v: %v", from, err))
+			continue
+		}
+		if !respond {
+			continue
+		}
+		if selfJoin, ok := smpc.selfJoin(networkID, join.ID); ok {
+			selfJoins = append(selfJoins, selfJoin)
+		}
+	}
+	if len(selfJoins) == 0 {
+		return
 	}
 
-	var err error
-	smpc.joinersMu.RLock()
-	if joiner, ok := smpc.joiners[message.NetworkID]; ok {
-		err = joiner.InsertJoin(message.Join)
+	response := Message{
+		MessageType: MessageTypeJoinResponseBatch,
+		MessageJoinResponseBatch: &MessageJoinBatch{
+			NetworkID: networkID,
+			Joins:     selfJoins,
+		},
 	}
-	smpc.joinersMu.RUnlock()
+	go smpc.network.SendTo(networkID, from, response)
+}
+
+// selfJoin returns the Join owned by this node for a JoinID, if it exists.
+func (smpc *smpcer) selfJoin(networkID NetworkID, joinID JoinID) (Join, bool) {
+	smpc.selfJoinsMu.RLock()
+	defer smpc.selfJoinsMu.RUnlock()
 
-	return err
+	selfJoin, ok := smpc.selfJoins[networkID][joinID]
+	return selfJoin, ok
 }
 
-func (smpc *smpcer) verifyJoin(networkID NetworkID, join Join) bool {
-	// FIXME: Pedersen commitment verification has been disabled. This needs
-	// to be re-enabled.
-	return true
+// insertSelfJoin stores the Join owned by this node so that it can be sent in
+// response to Joins from other nodes, and evicts Joins that are too old.
+func (smpc *smpcer) insertSelfJoin(networkID NetworkID, join Join) {
+	smpc.selfJoinsMu.Lock()
+	defer smpc.selfJoinsMu.Unlock()
 
-	// Always require that each share has a blinding
-	if len(join.Shares) != len(join.Blindings) {
-		logger.Debug(fmt.Sprintf("share and blindings have different length, Blindings= %v, shares= %v", len(join.Blindings), len(join.Shares)))
-		return false
+	selfJoins, ok := smpc.selfJoins[networkID]
+	if !ok {
+		return
 	}
+	selfJoins[join.ID] = join
 
-	// Get the JoinCommitments stored for the network and join ID being
-	// verified
-	joinCommitments, ok := func() (JoinCommitments, bool) {
-		smpc.commitmentsMu.RLock()
-		defer smpc.commitmentsMu.RUnlock()
+	expiry := smpc.selfJoinsExpiry[networkID]
+	expiry.insert(join.ID, time.Now())
+	for _, joinID := range expiry.expire(time.Now()) {
+		delete(selfJoins, joinID)
+	}
+}
 
-		if _, ok := smpc.commitments[networkID]; !ok {
-			return JoinCommitments{}, false
-		}
-		if _, ok := smpc.commitments[networkID][join.ID]; !ok {
-			return JoinCommitments{}, false
-		}
+// expireCommitments records that state is held for a JoinID, and evicts the
+// JoinCommitments and deferred Joins that are too old. The CommitmentsMu must
+// be locked.
+func (smpc *smpcer) expireCommitments(networkID NetworkID, joinID JoinID) {
+	expiry, ok := smpc.commitmentsExpiry[networkID]
+	if !ok {
+		return
+	}
+	expiry.insert(joinID, time.Now())
+	for _, joinID := range expiry.expire(time.Now()) {
+		delete(smpc.commitments[networkID], joinID)
+		delete(smpc.masked[networkID], joinID)
+		delete(smpc.deferredJoins[networkID], joinID)
+		delete(smpc.rejections[networkID], joinID)
+	}
+}
 
-		return smpc.commitments[networkID][join.ID], true
-	}()
-	// We accept the join if we do not have the JoinCommitments to check for
-	// incorrectness
+// handleReshare inserts a Reshare, received from a node, into the Resharer for
+// its network. Reshares for networks that are not connected are ignored.
+func (smpc *smpcer) handleReshare(from identity.Address, networkID NetworkID, reshare Reshare) error {
+	smpc.resharersMu.RLock()
+	resharer, ok := smpc.resharers[networkID]
+	smpc.resharersMu.RUnlock()
 	if !ok {
-		return true
+		return nil
 	}
+	return resharer.InsertReshare(from, reshare)
+}
 
-	for i := range join.Shares {
+// resharingAddresses returns the identity.Addresses of the nodes that reshare
+// the values identified by a ReshareID, using the ReshareSenders for a
+// network. No identity.Addresses are returned before the ReshareSenders are
+// set, so that all Reshares are rejected.
+func (smpc *smpcer) resharingAddresses(networkID NetworkID, id ReshareID) identity.Addresses {
+	smpc.resharersMu.RLock()
+	senders := smpc.reshareSenders[networkID]
+	smpc.resharersMu.RUnlock()
+	if senders == nil {
+		return nil
+	}
+	return senders(id)
+}
 
-		// Get the relevant commitments for the LHS and RHS of the computation
-		// in the join
-		lhs, ok := joinCommitments.LHS[join.Shares[i].Index]
-		if !ok {
-			// We accept the join if we do not have the JoinCommitments to
-			// check for incorrectness
-			continue
+// handleReshared passes the new shamir.Shares produced by the Resharer for a
+// network to the ReshareCallback for the network.
+func (smpc *smpcer) handleReshared(networkID NetworkID, id ReshareID, shares shamir.Shares, data []byte) {
+	smpc.resharersMu.RLock()
+	callback := smpc.reshareCallbacks[networkID]
+	smpc.resharersMu.RUnlock()
+	if callback == nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("dropping reshare on network %v: no callback", networkID))
+		return
+	}
+	callback(id, shares, data)
+}
+
+// handleFaults found by the Joiner for a network by filling in the addresses
+// of the nodes that sent the faulty Joins, and passing them to the
+// FaultCallback.
+func (smpc *smpcer) handleFaults(networkID NetworkID, faults []Fault) {
+	smpc.sendersMu.RLock()
+	for i := range faults {
+		faults[i].From = smpc.senders[networkID][faults[i].Index]
+	}
+	smpc.sendersMu.RUnlock()
+
+	for _, fault := range faults {
+		logger.Compute(logger.LevelError, fmt.Sprintf("✗ faulty join from smpc node %v with index %v", fault.From, fault.Index))
+	}
+	if smpc.faultCallback != nil {
+		smpc.faultCallback(faults)
+	}
+}
+
+// verifyJoin returns an error if the shamir.Shares in a Join, and their
+// shamir.Openings, do not open the shamir.Commitments expected for the
+// JoinIndex of the Join. Joins of masked
+// values are always accepted. Otherwise, a Join is rejected when there are no
+// JoinCommitments to check it against, unless the Smpcer allows unverified
+// Joins.
+func (smpc *smpcer) verifyJoin(join Join, joinCommitments JoinCommitments, masked bool) error {
+	if masked {
+		return nil
+	}
+	if len(joinCommitments) == 0 {
+		if smpc.allowUnverifiedJoins {
+			return nil
 		}
-		rhs, ok := joinCommitments.RHS[join.Shares[i].Index]
-		if !ok {
-			// We accept the join if we do not have the JoinCommitments to
-			// check for incorrectness
-			continue
+		return fmt.Errorf("%v: no commitments", ErrUnverifiedJoin)
+	}
+
+	commitments, ok := joinCommitments[join.Index]
+	if !ok {
+		return fmt.Errorf("%v: no commitments for index %v", ErrUnverifiedJoin, join.Index)
+	}
+	if len(commitments) != len(join.Shares) {
+		return fmt.Errorf("%v: expected %v shares, got %v", ErrUnverifiedJoin, len(commitments), len(join.Shares))
+	}
+
+	// Always require that each share has an opening
+	if len(join.Shares) != len(join.Openings) {
+		return fmt.Errorf("%v: expected %v openings, got %v", ErrUnverifiedJoin, len(join.Shares), len(join.Openings))
+	}
+
+	for i := range join.Shares {
+		if join.Shares[i].Index != uint64(join.Index) {
+			return fmt.Errorf("%v: share %v has index %v", ErrUnverifiedJoin, i, join.Shares[i].Index)
 		}
-		if rhs.Int == nil {
-			continue
+		if commitments[i].Int == nil {
+			// The value was not committed to so it cannot be checked
+			if smpc.allowUnverifiedJoins {
+				continue
+			}
+			return fmt.Errorf("%v: no commitment for share %v", ErrUnverifiedJoin, i)
 		}
-		rhs.Int.ModInverse(rhs.Int, shamir.CommitP)
-
-		// Check the expected commitment against the commitment we actually
-		// received
-		expected := big.NewInt(0).Mul(lhs.Int, rhs.Int)
-		expected.Mod(expected, shamir.CommitP)
-		got := shamir.NewCommitment(join.Shares[i], join.Blindings[i])
-
-		if expected.Cmp(got.Int) != 0 {
-			// Reject the join
-			log.Printf("reject the join due to %vth share, expected=%v , got=%v", i, expected.Int64(), got.Int64())
-			return false
+		if !commitments[i].VerifyOpening(join.Shares[i], join.Openings[i]) {
+			return fmt.Errorf("%v: share %v does not open its commitment", ErrUnverifiedJoin, i)
 		}
 	}
-
-	// Accept the join
-	return true
+	return nil
 }
@@ -2,10 +2,12 @@ package smpc_test
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/republicprotocol/republic-go/grpc"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/swarm"
 	"github.com/republicprotocol/republic-go/testutils"
 )
@@ -88,7 +91,7 @@ var _ = Describe("Smpcer", func() {
 				callback := generateCallback(&called, ord)
 
 				dispatch.CoForAll(nodes, func(i int) {
-					err := nodes[i].Smpcer.Join(networkID, joins[i], callback, false)
+					err := nodes[i].Smpcer.Join(context.Background(), networkID, joins[i], callback, nil, false)
 					Expect(err).ShouldNot(HaveOccurred())
 				})
 				for atomic.LoadInt64(&called) < int64(numDarknodes) {
@@ -114,6 +117,236 @@ var _ = Describe("Smpcer", func() {
 	})
 })
 
+var _ = Describe("Smpcer commitments", func() {
+
+	n := 6
+	k := int64(2 * (n + 1) / 3)
+
+	var pod *inProcessPod
+	var networkID NetworkID
+
+	BeforeEach(func() {
+		var err error
+		pod, err = newInProcessPodWithOptions(n, SmpcerOptions{FaultTolerance: 1})
+		Expect(err).ShouldNot(HaveOccurred())
+		networkID = NetworkID(testutils.Random32Bytes())
+		pod.connect(networkID)
+	})
+
+	AfterEach(func() {
+		pod.disconnect(networkID)
+	})
+
+	Context("when joins are verified by default", func() {
+
+		It("should join shares that open their commitments", func() {
+			joins, joinCommitments := committedJoins(int64(n), k, 42)
+			results := joinCommittedOnAllNodes(pod, networkID, joins, joinCommitments)
+			for i := range results {
+				Expect(results[i]).Should(Equal([]uint64{42}))
+			}
+		})
+
+		It("should not join without commitments", func() {
+			joins, _ := committedJoins(int64(n), k, 42)
+			err := pod.smpcers[0].Join(context.Background(), networkID, joins[0], func(JoinID, []uint64) {}, nil, false)
+			Expect(err).Should(Equal(ErrJoinWithoutCommitments))
+		})
+
+		It("should reject joins with openings that do not open their commitments", func() {
+			joins, joinCommitments := committedJoins(int64(n), k, 42)
+			otherJoins, _ := committedJoins(int64(n), k, 42)
+			for i := range joins {
+				joins[i].Openings = otherJoins[i].Openings
+			}
+			results := joinCommittedOnAllNodes(pod, networkID, joins, joinCommitments)
+			for i := range results {
+				Expect(results[i]).Should(Equal(ErrUnverifiedJoin))
+			}
+		})
+
+		It("should reject joins for values that were not committed to", func() {
+			joins, joinCommitments := committedJoins(int64(n), k, 42)
+			for index := range joinCommitments {
+				joinCommitments[index] = shamir.Commitments{shamir.Commitment{}}
+			}
+			results := joinCommittedOnAllNodes(pod, networkID, joins, joinCommitments)
+			for i := range results {
+				Expect(results[i]).Should(Equal(ErrUnverifiedJoin))
+			}
+		})
+
+		It("should multiply masked values", func() {
+			xShares, yShares := splitAll(int64(n), k, []uint64{6}), splitAll(int64(n), k, []uint64{7})
+			products := make(shamir.Shares, n)
+			errs := make([]error, n)
+			id := MultiplyID(testutils.Random32Bytes())
+			wg := new(sync.WaitGroup)
+			for i := range pod.smpcers {
+				i := i
+				wg.Add(1)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				err := pod.smpcers[i].Multiply(ctx, networkID, id, xShares[i], yShares[i], func(id MultiplyID, shares shamir.Shares) {
+					defer wg.Done()
+					products[i] = shares[0]
+				}, func(id MultiplyID, err error) {
+					defer wg.Done()
+					errs[i] = err
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			wg.Wait()
+			for i := range errs {
+				Expect(errs[i]).ShouldNot(HaveOccurred())
+			}
+			Expect(shamir.Join(products)).Should(Equal(uint64(42)))
+		})
+	})
+
+	Context("when a node sends joins for the indices of other nodes", func() {
+
+		It("should reject the joins", func() {
+			shares := splitAll(int64(n), k, []uint64{42})
+			forgedShares := splitAll(int64(n), k, []uint64{7})
+			id := randomJoinID()
+
+			var result interface{}
+			done := make(chan struct{})
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err := pod.smpcers[0].JoinMasked(ctx, networkID, Join{ID: id, Index: 1, Shares: shares[0]}, func(joinID JoinID, values []uint64) {
+				defer close(done)
+				result = values
+			}, func(joinID JoinID, err error) {
+				defer close(done)
+				result = err
+			}, false)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// The last node sends a consistent set of forged joins that would
+			// be enough to reconstruct a different value on its own
+			pod.mu.Lock()
+			receiver := pod.receivers[pod.addrs[0]]
+			pod.mu.Unlock()
+			for i := 1; i < n; i++ {
+				receiver.Receive(pod.addrs[n-1], Message{
+					MessageType: MessageTypeJoin,
+					MessageJoin: &MessageJoin{
+						NetworkID: networkID,
+						Join:      Join{ID: id, Index: JoinIndex(i + 1), Shares: forgedShares[i]},
+					},
+				})
+			}
+			<-done
+			Expect(result).Should(Equal(ErrJoinTimeout))
+		})
+	})
+
+	Context("when a node sends many joins that cannot be verified", func() {
+
+		It("should count one rejection for the node", func() {
+			// Without a fault tolerance, the join can only be failed by
+			// rejecting the joins of more than n-k nodes
+			pod, err := newInProcessPodWithOptions(n, SmpcerOptions{})
+			Expect(err).ShouldNot(HaveOccurred())
+			networkID := NetworkID(testutils.Random32Bytes())
+			pod.connect(networkID)
+			defer pod.disconnect(networkID)
+
+			joins, joinCommitments := committedJoins(int64(n), k, 42)
+			otherJoins, _ := committedJoins(int64(n), k, 42)
+			pod.smpcers[0].InsertCommitments(networkID, joins[0].ID, joinCommitments)
+
+			var result interface{}
+			done := make(chan struct{})
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err = pod.smpcers[0].Join(ctx, networkID, joins[0], func(joinID JoinID, values []uint64) {
+				defer close(done)
+				result = values
+			}, func(joinID JoinID, err error) {
+				defer close(done)
+				result = err
+			}, false)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// The last node sends joins with openings that do not open the
+			// commitments for its own index, and for the indices of others
+			pod.mu.Lock()
+			receiver := pod.receivers[pod.addrs[0]]
+			pod.mu.Unlock()
+			for i := 1; i < n; i++ {
+				join := joins[n-1]
+				join.Openings = otherJoins[n-1].Openings
+				if i < n-1 {
+					join = joins[i]
+					join.Openings = otherJoins[i].Openings
+				}
+				for j := 0; j < 2; j++ {
+					receiver.Receive(pod.addrs[n-1], Message{
+						MessageType: MessageTypeJoin,
+						MessageJoin: &MessageJoin{
+							NetworkID: networkID,
+							Join:      join,
+						},
+					})
+				}
+			}
+			<-done
+			Expect(result).Should(Equal(ErrJoinTimeout))
+		})
+	})
+})
+
+// committedJoins splits a value into Joins with an opening for each share, and
+// returns the JoinCommitments that the Joins open.
+func committedJoins(n, k int64, value uint64) ([]Join, JoinCommitments) {
+	shares, openings, commitments, err := shamir.SplitWithCommitments(rand.Reader, n, k, value)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	id := JoinID{}
+	random := testutils.Random32Bytes()
+	copy(id[:], random[:])
+
+	joins := make([]Join, n)
+	joinCommitments := JoinCommitments{}
+	for i, share := range shares {
+		joins[i] = Join{
+			ID:       id,
+			Index:    JoinIndex(share.Index),
+			Shares:   shamir.Shares{share},
+			Openings: shamir.Openings{openings[i]},
+		}
+		joinCommitments[JoinIndex(share.Index)] = shamir.Commitments{commitments.Evaluate(share.Index)}
+	}
+	return joins, joinCommitments
+}
+
+// joinCommittedOnAllNodes inserts the JoinCommitments at all nodes, joins the
+// Joins, and returns the values, or the error, returned to each node.
+func joinCommittedOnAllNodes(pod *inProcessPod, networkID NetworkID, joins []Join, joinCommitments JoinCommitments) []interface{} {
+	results := make([]interface{}, len(pod.smpcers))
+	wg := new(sync.WaitGroup)
+	for i := range pod.smpcers {
+		i := i
+		wg.Add(1)
+		pod.smpcers[i].InsertCommitments(networkID, joins[i].ID, joinCommitments)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := pod.smpcers[i].Join(ctx, networkID, joins[i], func(joinID JoinID, values []uint64) {
+			defer wg.Done()
+			results[i] = values
+		}, func(joinID JoinID, err error) {
+			defer wg.Done()
+			results[i] = err
+		}, false)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	wg.Wait()
+	return results
+}
+
 type mockNode struct {
 	Address      identity.Address
 	Multiaddress identity.MultiAddress
@@ -0,0 +1,50 @@
+package testutils
+
+import (
+	"sync"
+
+	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// MockMidpointPriceStorer implements the oracle.MidpointPriceStorer interface
+// with an in-memory implementation.
+type MockMidpointPriceStorer struct {
+	mu     *sync.Mutex
+	prices oracle.MidpointPrice
+}
+
+// NewMockMidpointPriceStorer returns a new MockMidpointPriceStorer.
+func NewMockMidpointPriceStorer() *MockMidpointPriceStorer {
+	return &MockMidpointPriceStorer{
+		mu: new(sync.Mutex),
+	}
+}
+
+// PutMidpointPrice implements the oracle.MidpointPriceStorer interface.
+func (storer *MockMidpointPriceStorer) PutMidpointPrice(midpointPrice oracle.MidpointPrice) error {
+	storer.mu.Lock()
+	defer storer.mu.Unlock()
+
+	storer.prices = midpointPrice
+	return nil
+}
+
+// MidpointPrices implements the oracle.MidpointPriceStorer interface.
+func (storer *MockMidpointPriceStorer) MidpointPrices() (oracle.MidpointPrice, error) {
+	storer.mu.Lock()
+	defer storer.mu.Unlock()
+
+	return storer.prices, nil
+}
+
+// MidpointPrice implements the oracle.MidpointPriceStorer interface.
+func (storer *MockMidpointPriceStorer) MidpointPrice(tokens order.Tokens) (uint64, error) {
+	storer.mu.Lock()
+	defer storer.mu.Unlock()
+
+	if price, ok := storer.prices.Prices[uint64(tokens)]; ok {
+		return price, nil
+	}
+	return 0, oracle.ErrMidpointPriceNotFound
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/republicprotocol/republic-go/identity"
-	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/oracle"
 )
 
@@ -17,7 +16,7 @@ type MockOracleClient struct {
 }
 
 func NewMockOracleClient(addr identity.Address, hub map[identity.Address]oracle.Server) (oracle.Client, oracle.MidpointPriceStorer, error) {
-	storer := leveldb.NewMidpointPriceStorer()
+	storer := NewMockMidpointPriceStorer()
 	return &MockOracleClient{
 		addr:  addr,
 		store: storer,
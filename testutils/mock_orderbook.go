@@ -48,6 +48,11 @@ func (mock *EmptyOrderbook) Sync(done <-chan struct{}) (<-chan orderbook.Notific
 func (mock *EmptyOrderbook) OnChangeEpoch(epoch registry.Epoch) {
 }
 
+// InsertOrderFragment implements the orderbook.Orderbook interface.
+func (mock *EmptyOrderbook) InsertOrderFragment(ctx context.Context, orderFragment order.Fragment, trader string, priority uint) error {
+	return nil
+}
+
 // A RandOrderbook implements the orderbook.Orderbook interface and produces a
 // notification for every opened order.Fragment and randomly produces
 // cancelations and confirmations.
@@ -149,6 +154,12 @@ func (mock *RandOrderbook) Sync(done <-chan struct{}) (<-chan orderbook.Notifica
 func (mock *RandOrderbook) OnChangeEpoch(epoch registry.Epoch) {
 }
 
+// InsertOrderFragment implements the orderbook.Orderbook interface.
+func (mock *RandOrderbook) InsertOrderFragment(ctx context.Context, orderFragment order.Fragment, trader string, priority uint) error {
+	mock.orderFragmentsQueue = append(mock.orderFragmentsQueue, orderFragment)
+	return nil
+}
+
 type MockContractBinder struct {
 	ordersMu    *sync.RWMutex
 	orders      []order.ID
@@ -1,6 +1,7 @@
 package testutils
 
 import (
+	"context"
 	"math/rand"
 
 	"github.com/republicprotocol/republic-go/identity"
@@ -53,7 +54,7 @@ func (smpc *Smpc) Disconnect(networkID smpc.NetworkID) {
 }
 
 // Join implements smpc.Smpcer.
-func (smpc *Smpc) Join(networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, useDelay bool) error {
+func (smpc *Smpc) Join(ctx context.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, errCallback smpc.ErrorCallback, useDelay bool) error {
 	values := make([]uint64, len(join.Shares))
 	for i := range values {
 		if smpc.useRandomValue {
@@ -70,11 +71,68 @@ func (smpc *Smpc) Join(networkID smpc.NetworkID, join smpc.Join, callback smpc.C
 	return nil
 }
 
+// JoinMasked implements smpc.Smpcer.
+func (smpc *Smpc) JoinMasked(ctx context.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, errCallback smpc.ErrorCallback, useDelay bool) error {
+	return smpc.Join(ctx, networkID, join, callback, errCallback, useDelay)
+}
+
 // InsertCommitments implements smpc.Smpcer.
 func (smpc *Smpc) InsertCommitments(networkID smpc.NetworkID, join smpc.JoinID, joinCommitments smpc.JoinCommitments) {
 	// Do nothing
 }
 
+// Reshare implements smpc.Smpcer.
+func (smpc *Smpc) Reshare(networkID smpc.NetworkID, to identity.Address, reshare smpc.Reshare) error {
+	return nil
+}
+
+// OnReshare implements smpc.Smpcer.
+func (smpc *Smpc) OnReshare(networkID smpc.NetworkID, senders smpc.ReshareSenders, callback smpc.ReshareCallback) {
+	// Do nothing
+}
+
+// Multiply implements smpc.Smpcer. The mock does not compute products, and
+// calls the smpc.MultiplyCallback with the xs.
+func (smpc *Smpc) Multiply(ctx context.Context, networkID smpc.NetworkID, id smpc.MultiplyID, xs, ys shamir.Shares, callback smpc.MultiplyCallback, errCallback smpc.MultiplyErrorCallback) error {
+	callback(id, xs)
+	return nil
+}
+
+// ComparisonMaterial implements smpc.Smpcer. The mock calls the
+// smpc.ComparisonMaterialCallback with material where all values are zero, and
+// all shares have an index of one.
+func (smpc *Smpc) ComparisonMaterial(ctx context.Context, networkID smpc.NetworkID, id smpc.CompareID, callback smpc.ComparisonMaterialCallback, errCallback smpc.CompareErrorCallback) error {
+	material := zeroComparisonMaterial()
+	callback(id, material)
+	return nil
+}
+
+func zeroComparisonMaterial() smpc.ComparisonMaterial {
+	zero := shamir.Share{Index: 1}
+	material := smpc.ComparisonMaterial{
+		Bits:    make(shamir.Shares, smpc.CompareBits),
+		Mask:    zero,
+		Triples: make([]smpc.Triple, smpc.CompareBits-1),
+	}
+	for i := range material.Bits {
+		material.Bits[i] = zero
+	}
+	for i := range material.Triples {
+		material.Triples[i] = smpc.Triple{A: zero, B: zero, C: zero}
+	}
+	return material
+}
+
+// CancelJoin implements smpc.Smpcer.
+func (smpc *Smpc) CancelJoin(networkID smpc.NetworkID, joinID smpc.JoinID) {
+	// Do nothing
+}
+
+// JoinMetrics implements smpc.Smpcer.
+func (smpc *Smpc) JoinMetrics() map[smpc.NetworkID]smpc.JoinMetrics {
+	return nil
+}
+
 // Receiver is a mock implementation of the smpc.Receiver interface.
 type Receiver struct {
 }
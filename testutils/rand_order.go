@@ -27,7 +27,7 @@ func RandomOrder() order.Order {
 	}
 	token := tokens[rand.Intn(len(tokens))]
 
-	ord := order.NewOrder(parity, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, uint64(rand.Int63()))
+	ord := order.NewOrder(parity, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, RandomNonce())
 	return ord
 }
 
@@ -42,7 +42,7 @@ func RandomBuyOrder() order.Order {
 	}
 	token := tokens[rand.Intn(len(tokens))]
 
-	ord := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, uint64(rand.Int63()))
+	ord := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, RandomNonce())
 	return ord
 }
 
@@ -65,7 +65,7 @@ func RandomSellOrder() order.Order {
 	}
 	token := tokens[rand.Intn(len(tokens))]
 
-	ord := order.NewOrder(order.ParitySell, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, uint64(rand.Int63()))
+	ord := order.NewOrder(order.ParitySell, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, rand.Uint64(), rand.Uint64(), 0, RandomNonce())
 	return ord
 }
 
@@ -90,8 +90,8 @@ func RandomOrderMatch() (order.Order, order.Order) {
 
 	price := rand.Uint64()
 	volume := rand.Uint64()
-	buy := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, price, volume, 0, uint64(rand.Int63()))
-	sell := order.NewOrder(order.ParitySell, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, price, volume, 0, uint64(rand.Int63()))
+	buy := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, price, volume, 0, RandomNonce())
+	sell := order.NewOrder(order.ParitySell, order.TypeLimit, time.Now().Add(1*time.Hour), order.SettlementRenEx, token, price, volume, 0, RandomNonce())
 	return buy, sell
 }
 
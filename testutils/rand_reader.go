@@ -0,0 +1,26 @@
+package testutils
+
+import (
+	"crypto/rand"
+	"io"
+	mathRand "math/rand"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// NewDeterministicReader returns an io.Reader that produces the same stream of
+// bytes for the same seed. It is not cryptographically secure and must only be
+// used to make tests reproducible.
+func NewDeterministicReader(seed int64) io.Reader {
+	return mathRand.New(mathRand.NewSource(seed))
+}
+
+// RandomNonce will generate a random order nonce in the finite field, using a
+// cryptographically secure source of randomness.
+func RandomNonce() uint64 {
+	nonce, err := shamir.RandomFieldElement(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return nonce
+}